@@ -8,14 +8,22 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	httpAdapter "github.com/githubixx/vdradmin-go/internal/adapters/primary/http"
+	"github.com/githubixx/vdradmin-go/internal/adapters/primary/svdrpproxy"
+	"github.com/githubixx/vdradmin-go/internal/adapters/secondary/filestore"
 	"github.com/githubixx/vdradmin-go/internal/adapters/secondary/svdrp"
+	"github.com/githubixx/vdradmin-go/internal/application/events"
 	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
 	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/logos"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/metrics"
 	"github.com/githubixx/vdradmin-go/internal/infrastructure/theme"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/tracing"
 )
 
 var (
@@ -56,6 +64,16 @@ func main() {
 		slog.Int("server_port", cfg.Server.Port),
 	)
 
+	// Initialize tracing (no-op unless tracing.enabled)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, version, os.Stdout)
+	if err != nil {
+		logger.Error("failed to initialize tracing", slog.Any("error", err))
+		os.Exit(1)
+	}
+	if cfg.Tracing.Enabled {
+		logger.Info("tracing enabled", slog.String("exporter", cfg.Tracing.Exporter), slog.String("endpoint", cfg.Tracing.Endpoint))
+	}
+
 	// Initialize SVDRP client
 	vdrClient := svdrp.NewClient(
 		cfg.VDR.Host,
@@ -63,6 +81,19 @@ func main() {
 		cfg.VDR.Timeout,
 	)
 
+	// Metrics for /metrics; SVDRP commands are observed on the client
+	appMetrics := metrics.New()
+
+	// Event bus for live state changes (/events)
+	eventBus := events.NewBus()
+
+	// Initialize services
+	primaryBackend := newBackend(cfg, cfg.VDR.Name, cfg.VDR.VideoDir, cfg.VDR.DVBCards, vdrClient, appMetrics, eventBus)
+	epgService := primaryBackend.EPG
+	epgService.SetWantedChannels(cfg.VDR.WantedChannels)
+	timerService := primaryBackend.Timers
+	recordingService := primaryBackend.Recordings
+
 	// Attempt an early connect to VDR (non-fatal).
 	// The SVDRP client will also connect lazily on demand.
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -73,12 +104,72 @@ func main() {
 	}
 	cancel()
 
-	// Initialize services
-	epgService := services.NewEPGService(vdrClient, cfg.Cache.EPGExpiry)
-	epgService.SetWantedChannels(cfg.VDR.WantedChannels)
-	timerService := services.NewTimerService(vdrClient)
-	recordingService := services.NewRecordingService(vdrClient, cfg.Cache.RecordingExpiry)
+	dedupService := services.NewDedupService()
+	dedupService.SetRules(cfg.Dedup.Match, cfg.Dedup.Description)
+	dedupService.SetRecordingLister(recordingService)
+	doneFile := cfg.Dedup.File
+	if !filepath.IsAbs(doneFile) {
+		doneFile = filepath.Join(filepath.Dir(*configPath), doneFile)
+	}
+	if err := dedupService.SetStore(filestore.NewDoneStore(doneFile)); err != nil {
+		logger.Error("failed to load done list", slog.String("file", doneFile), slog.Any("error", err))
+		os.Exit(1)
+	}
 	autoTimerService := services.NewAutoTimerService(vdrClient, timerService, epgService)
+	autoTimerService.SetDedup(dedupService)
+	autoTimerFile := cfg.AutoTimer.File
+	if !filepath.IsAbs(autoTimerFile) {
+		autoTimerFile = filepath.Join(filepath.Dir(*configPath), autoTimerFile)
+	}
+	if err := autoTimerService.SetStore(filestore.NewAutoTimerStore(autoTimerFile)); err != nil {
+		logger.Error("failed to load autotimers", slog.String("file", autoTimerFile), slog.Any("error", err))
+		os.Exit(1)
+	}
+	autoTimerService.SetInterval(cfg.AutoTimer.Interval)
+	searchTimerService := services.NewSearchTimerService(timerService, epgService, func() []config.EPGSearch {
+		return append([]config.EPGSearch(nil), cfg.EPG.Searches...)
+	})
+	searchTimerService.SetDedup(dedupService)
+	recordingHistory := services.NewRecordingHistoryService(timerService, recordingService)
+	recordingHistory.SetChannelLister(epgService)
+	recordingHistory.SetKeepDays(cfg.History.KeepDays)
+	historyFile := cfg.History.File
+	if !filepath.IsAbs(historyFile) {
+		historyFile = filepath.Join(filepath.Dir(*configPath), historyFile)
+	}
+	if err := recordingHistory.SetStore(filestore.NewHistoryStore(historyFile)); err != nil {
+		logger.Error("failed to load recording history", slog.String("file", historyFile), slog.Any("error", err))
+		os.Exit(1)
+	}
+	recordingHistory.OnFinished(func(e domain.RecordingHistoryEntry) {
+		attrs := []any{slog.String("title", e.Title), slog.String("channel", e.ChannelID), slog.Time("start", e.Start), slog.String("result", string(e.Result))}
+		if e.Result.Failed() {
+			logger.Warn("timer did not record completely", append(attrs,
+				slog.String("recording", e.Recording), slog.Duration("length", e.Length), slog.Int("errors", e.Errors))...)
+			return
+		}
+		logger.Info("timer recorded", attrs...)
+	})
+	stateMonitor := services.NewStateMonitor(vdrClient, eventBus)
+	stateMonitor.SetRecordingService(recordingService)
+	stateMonitor.SetInterval(cfg.Events.PollInterval)
+
+	// Further VDR backends get their own connection and services; features not
+	// aware of several VDRs keep using the primary one above.
+	var extraBackends []*services.Backend
+	var extraClients []*svdrp.Client
+	for _, bc := range cfg.VDR.Backends {
+		client := svdrp.NewClient(bc.Host, bc.Port, bc.Timeout)
+		extraClients = append(extraClients, client)
+		extraBackends = append(extraBackends, newBackend(cfg, bc.Name, bc.VideoDir, bc.DVBCards, client, appMetrics, eventBus))
+		logger.Info("VDR backend configured", slog.String("name", bc.Name), slog.String("host", bc.Host), slog.Int("port", bc.Port))
+	}
+	backends := services.NewBackends(primaryBackend, extraBackends...)
+	// Timers follow the EPG of their own VDR.
+	for _, be := range backends.All() {
+		be.Watchdog = newTimerWatchdog(cfg, logger, be, backends.Multiple())
+	}
+	timerWatchdog := primaryBackend.Watchdog
 
 	// Initialize theme manager
 	themeManager := theme.NewManager("web/themes")
@@ -88,9 +179,15 @@ func main() {
 	}
 	logger.Info("themes discovered", slog.Any("themes", themeManager.GetAvailableThemes()))
 
+	// Channel logos are optional; without them the UI shows monograms.
+	logoStore := logos.NewStore(cfg.UI.LogoDir, cfg.UI.LogoMap)
+	if err := logoStore.Load(); err != nil {
+		logger.Warn("failed to load channel logos", slog.Any("error", err))
+	}
+
 	// Load templates - each page gets its own template set
 	templates := make(map[string]*template.Template)
-	pages := []string{"index.html", "epg.html", "playing.html", "watch.html", "timers.html", "timer_edit.html", "recordings.html", "recording_archive.html", "recording_archive_jobs.html", "recording_archive_job.html", "recording_archive_job_status.html", "archive_profiles.html", "search.html", "search_results.html", "epgsearch.html", "epgsearch_edit.html", "epgsearch_results.html", "event.html", "channels.html", "configurations.html"}
+	pages := []string{"index.html", "epg.html", "playing.html", "grid.html", "watch.html", "timers.html", "timer_edit.html", "timer_alternatives.html", "recording_history.html", "autotimers.html", "autotimer_edit.html", "recordings.html", "recording_marks.html", "recording_archive.html", "recording_archive_jobs.html", "recording_archive_job.html", "recording_archive_job_status.html", "archive_profiles.html", "favourites.html", "search.html", "search_results.html", "epgsearch.html", "epgsearch_edit.html", "epgsearch_results.html", "epgsearch_log.html", "epgsearch_done.html", "event.html", "channels.html", "channels_manage.html", "channel_logos.html", "configurations.html"}
 
 	for _, page := range pages {
 		tmpl := template.Must(template.ParseFiles("web/templates/_nav.html", "web/templates/"+page))
@@ -112,11 +209,20 @@ func main() {
 	)
 	httpHandler.SetConfig(cfg, *configPath)
 	httpHandler.SetVDRClient(vdrClient)
+	httpHandler.SetEventBus(eventBus)
+	httpHandler.SetStateMonitor(stateMonitor)
+	httpHandler.SetSearchTimerService(searchTimerService)
+	httpHandler.SetDedupService(dedupService)
+	httpHandler.SetTimerWatchdog(timerWatchdog)
+	httpHandler.SetRecordingHistory(recordingHistory)
+	httpHandler.SetMetrics(appMetrics)
+	httpHandler.SetBackends(backends)
 
 	// Set template map in handler
 	httpHandler.SetTemplates(templates)
 	httpHandler.SetUIThemeDefault(cfg.UI.Theme)
 	httpHandler.SetThemeManager(themeManager)
+	httpHandler.SetLogos(logoStore)
 
 	// Setup routes
 	mux := httpAdapter.SetupRoutes(httpHandler, &cfg.Auth, logger)
@@ -124,6 +230,16 @@ func main() {
 	// Create HTTP server
 	server := httpAdapter.NewServer(&cfg.Server, logger, httpHandler, mux)
 
+	// Process AutoTimers in the background
+	runCtx, runCancel := context.WithCancel(context.Background())
+	go autoTimerService.Run(runCtx)
+	go searchTimerService.Run(runCtx)
+	for _, be := range backends.All() {
+		go be.Watchdog.Run(runCtx)
+	}
+	go recordingHistory.Run(runCtx)
+	go stateMonitor.Run(runCtx)
+
 	// Start server in goroutine
 	go func() {
 		if err := server.Start(); err != nil {
@@ -136,12 +252,40 @@ func main() {
 		slog.String("addr", fmt.Sprintf("http://%s:%d", cfg.Server.Host, cfg.Server.Port)),
 	)
 
+	// SVDRP proxy letting other SVDRP clients share the VDR connection
+	var proxy *svdrpproxy.Server
+	if cfg.SVDRPProxy.Enabled {
+		proxy, err = svdrpproxy.NewServer(&cfg.SVDRPProxy, logger, vdrClient)
+		if err != nil {
+			logger.Error("failed to set up SVDRP proxy", slog.Any("error", err))
+			os.Exit(1)
+		}
+		proxy.SetOnWrite(func(verb string) {
+			switch verb {
+			case "DELR", "MOVR", "EDIT":
+				recordingService.InvalidateCache()
+			case "MODC", "MOVC", "NEWC", "DELC":
+				epgService.InvalidateAllCaches()
+			default:
+				// Timers are not cached; polling again picks up recordings the change started or stopped.
+				stateMonitor.Trigger()
+			}
+		})
+		go func() {
+			if err := proxy.Start(); err != nil {
+				logger.Error("SVDRP proxy error", slog.Any("error", err))
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
 
 	logger.Info("shutting down...")
+	runCancel()
 
 	// Graceful shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -150,10 +294,73 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("shutdown error", slog.Any("error", err))
 	}
+	if proxy != nil {
+		if err := proxy.Shutdown(shutdownCtx); err != nil {
+			logger.Error("SVDRP proxy shutdown error", slog.Any("error", err))
+		}
+	}
 
 	if err := vdrClient.Close(); err != nil {
 		logger.Error("failed to close VDR connection", slog.Any("error", err))
 	}
+	for i, client := range extraClients {
+		if err := client.Close(); err != nil {
+			logger.Error("failed to close VDR connection", slog.String("backend", cfg.VDR.Backends[i].Name), slog.Any("error", err))
+		}
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", slog.Any("error", err))
+	}
 
 	logger.Info("shutdown complete")
 }
+
+// newBackend wires the services of one VDR. The primary VDR and further
+// backends are built alike, so their commands are measured and their timer
+// changes published on the event bus.
+func newBackend(cfg *config.Config, name, videoDir string, dvbCards int, client *svdrp.Client, observer svdrp.Observer, bus *events.Bus) *services.Backend {
+	client.SetObserver(observer)
+	client.SetIdleTimeout(cfg.VDR.IdleTimeout)
+
+	epg := services.NewEPGService(client, cfg.Cache.EPGExpiry)
+	timers := services.NewTimerService(client)
+	timers.SetDVBCards(dvbCards)
+	timers.SetChannelLister(epg)
+	timers.SetConflictPolicy(cfg.Timer.ConflictCheck)
+	timers.SetEventBus(bus)
+	recordings := services.NewRecordingService(client, cfg.Cache.RecordingExpiry)
+	recordings.SetVideoDir(videoDir)
+	return &services.Backend{
+		Name:       name,
+		VideoDir:   videoDir,
+		Client:     client,
+		EPG:        epg,
+		Timers:     timers,
+		Recordings: recordings,
+	}
+}
+
+// newTimerWatchdog creates the watchdog moving the timers of be along with
+// their EPG events and logs what it does. named adds the backend to the log.
+func newTimerWatchdog(cfg *config.Config, logger *slog.Logger, be *services.Backend, named bool) *services.TimerWatchdog {
+	w := services.NewTimerWatchdog(be.Timers, be.EPG)
+	w.SetDefaultMargins(cfg.Timer.DefaultMarginStart, cfg.Timer.DefaultMarginEnd)
+	w.SetInterval(cfg.Timer.EPGWatch)
+	w.OnChange(func(c services.TimerWatchChange) {
+		attrs := []any{slog.Int("timer", c.TimerID), slog.String("title", c.Title), slog.Int("event", c.EventID)}
+		if named {
+			attrs = append(attrs, slog.String("backend", be.Name))
+		}
+		switch c.Action {
+		case services.TimerWatchMoved:
+			logger.Info("timer moved with its EPG event", append(attrs,
+				slog.Time("old_start", c.OldStart), slog.Time("new_start", c.NewStart), slog.Time("new_stop", c.NewStop))...)
+		case services.TimerWatchVanished:
+			logger.Warn("EPG event of timer vanished", attrs...)
+		default:
+			logger.Error("failed to move timer with its EPG event", append(attrs, slog.String("error", c.Err))...)
+		}
+	})
+	return w
+}
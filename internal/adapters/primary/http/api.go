@@ -0,0 +1,1249 @@
+package http
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/archive"
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+)
+
+// The JSON API lives under /api/v1. It exposes the same services as the HTML pages;
+// write operations require the admin role. All errors use apiErrorResponse.
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+// maxAPIBodyBytes limits the size of JSON request bodies.
+const maxAPIBodyBytes = 1 << 20
+
+type apiErrorResponse struct {
+	Error apiErrorBody `json:"error"`
+}
+
+type apiErrorBody struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiChannel struct {
+	ID       string `json:"id"`
+	Number   int    `json:"number"`
+	Name     string `json:"name"`
+	Provider string `json:"provider,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Group    string `json:"group,omitempty"`
+}
+
+type apiEvent struct {
+	EventID        int        `json:"event_id"`
+	ChannelID      string     `json:"channel_id"`
+	ChannelNumber  int        `json:"channel_number,omitempty"`
+	ChannelName    string     `json:"channel_name,omitempty"`
+	Title          string     `json:"title"`
+	Subtitle       string     `json:"subtitle,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	Start          time.Time  `json:"start"`
+	Stop           time.Time  `json:"stop"`
+	Duration       int        `json:"duration"`
+	VPS            *time.Time `json:"vps,omitempty"`
+	Genres         []string   `json:"genres,omitempty"`
+	ParentalRating int        `json:"parental_rating,omitempty"`
+	HD             bool       `json:"hd"`
+	VideoFormat    string     `json:"video_format,omitempty"`
+	AudioLanguages []string   `json:"audio_languages,omitempty"`
+}
+
+type apiTimer struct {
+	ID        int        `json:"id"`
+	Active    bool       `json:"active"`
+	ChannelID string     `json:"channel_id"`
+	Day       string     `json:"day,omitempty"`
+	Weekdays  string     `json:"weekdays,omitempty"`
+	StartTime string     `json:"start_time"`
+	StopTime  string     `json:"stop_time"`
+	Start     *time.Time `json:"start,omitempty"`
+	Stop      *time.Time `json:"stop,omitempty"`
+	Priority  int        `json:"priority"`
+	Lifetime  int        `json:"lifetime"`
+	Title     string     `json:"title"`
+	Aux       string     `json:"aux,omitempty"`
+	EventID   int        `json:"event_id,omitempty"`
+}
+
+// apiTimerInput is the request body for creating or updating a timer. Either
+// EventID (create from EPG) or ChannelID, Day/Weekdays and StartTime/StopTime are required.
+type apiTimerInput struct {
+	Active    *bool  `json:"active"`
+	ChannelID string `json:"channel_id"`
+	EventID   int    `json:"event_id"`
+	Day       string `json:"day"`
+	Weekdays  string `json:"weekdays"`
+	StartTime string `json:"start_time"`
+	StopTime  string `json:"stop_time"`
+	Priority  *int   `json:"priority"`
+	Lifetime  *int   `json:"lifetime"`
+	Title     string `json:"title"`
+	Aux       string `json:"aux"`
+}
+
+type apiTimerAllocation struct {
+	TimerID     int       `json:"timer_id"`
+	Start       time.Time `json:"start"`
+	Stop        time.Time `json:"stop"`
+	Transponder string    `json:"transponder"`
+	Device      int       `json:"device"`
+	Shared      bool      `json:"shared"`
+	Missed      int       `json:"missed"`
+	Failed      bool      `json:"failed"`
+}
+
+type apiConflictReport struct {
+	DVBCards    int                  `json:"dvb_cards"`
+	From        time.Time            `json:"from"`
+	To          time.Time            `json:"to"`
+	Allocations []apiTimerAllocation `json:"allocations"`
+	Collisions  []int                `json:"collisions"`
+	Critical    []int                `json:"critical"`
+	Failed      []int                `json:"failed"`
+}
+
+type apiRecording struct {
+	Path        string    `json:"path"`
+	Title       string    `json:"title"`
+	Subtitle    string    `json:"subtitle,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Channel     string    `json:"channel,omitempty"`
+	Date        time.Time `json:"date"`
+	Length      int       `json:"length"`
+	Size        int64     `json:"size"`
+	Folder      string    `json:"folder,omitempty"`
+	New         bool      `json:"new"`
+}
+
+// apiRecordingNode is a recording or a folder in the recording tree. Folders
+// carry totals of their subtree in Length, Size, Count and NewCount.
+type apiRecordingNode struct {
+	apiRecording
+	IsFolder bool               `json:"is_folder"`
+	Count    int                `json:"count,omitempty"`
+	NewCount int                `json:"new_count,omitempty"`
+	Children []apiRecordingNode `json:"children,omitempty"`
+}
+
+type apiSavedSearch struct {
+	ID          int      `json:"id"`
+	Active      bool     `json:"active"`
+	Pattern     string   `json:"pattern"`
+	Mode        string   `json:"mode"`
+	MatchCase   bool     `json:"match_case"`
+	InTitle     bool     `json:"in_title"`
+	InSubtitle  bool     `json:"in_subtitle"`
+	InDesc      bool     `json:"in_description"`
+	UseChannel  string   `json:"use_channel"`
+	ChannelID   string   `json:"channel_id,omitempty"`
+	ChannelFrom string   `json:"channel_from,omitempty"`
+	ChannelTo   string   `json:"channel_to,omitempty"`
+	StartFrom   string   `json:"start_from,omitempty"`
+	StartTo     string   `json:"start_to,omitempty"`
+	MinDuration int      `json:"min_duration,omitempty"`
+	MaxDuration int      `json:"max_duration,omitempty"`
+	Weekdays    []string `json:"weekdays,omitempty"`
+	Exclude     string   `json:"exclude,omitempty"`
+	HDOnly      bool     `json:"hd_only,omitempty"`
+	HorizonDays int      `json:"horizon_days,omitempty"`
+	NotRecorded bool     `json:"not_recorded,omitempty"`
+	AutoRecord  bool     `json:"auto_record,omitempty"`
+	Priority    int      `json:"priority"`
+	Lifetime    int      `json:"lifetime"`
+	MarginStart int      `json:"margin_start"`
+	MarginEnd   int      `json:"margin_end"`
+	Folder      string   `json:"folder,omitempty"`
+	Dedup       string   `json:"dedup,omitempty"`
+}
+
+type apiArchiveProfile struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	BaseDir string `json:"base_dir"`
+}
+
+type apiArchiveJob struct {
+	ID          string     `json:"id"`
+	RecordingID string     `json:"recording_id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	EndedAt     *time.Time `json:"ended_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	TargetDir   string     `json:"target_dir"`
+	VideoPath   string     `json:"video_path"`
+	Percent     float64    `json:"percent"`
+	Speed       string     `json:"speed,omitempty"`
+	LogCount    int        `json:"log_count"`
+	LogTail     string     `json:"log_tail,omitempty"`
+}
+
+type apiArchiveStartInput struct {
+	Path    string `json:"path"`
+	Profile string `json:"profile"`
+	Format  string `json:"format"`
+	Title   string `json:"title"`
+	Episode string `json:"episode"`
+	// MarkedOnly limits the archive to the parts between the cutting marks.
+	MarkedOnly bool `json:"marked_only"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// apiStatusForError maps domain errors to HTTP status codes and stable error codes.
+func apiStatusForError(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "not_found"
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest, "invalid_input"
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict, "conflict"
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized, "unauthorized"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "forbidden"
+	case errors.Is(err, domain.ErrConnection):
+		return http.StatusBadGateway, "vdr_unavailable"
+	case errors.Is(err, domain.ErrBusy):
+		return http.StatusServiceUnavailable, "vdr_busy"
+	case errors.Is(err, domain.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "timeout"
+	default:
+		return http.StatusInternalServerError, "internal"
+	}
+}
+
+func writeAPIErrorStatus(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, apiErrorResponse{Error: apiErrorBody{Status: status, Code: code, Message: message}})
+}
+
+// apiError writes err as a JSON error response. Internal errors are logged and not
+// exposed to the client.
+func (h *Handler) apiError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := apiStatusForError(err)
+	msg := err.Error()
+	if status == http.StatusInternalServerError {
+		h.logger.Error("api error", slog.Any("error", err), slog.String("path", r.URL.Path))
+		msg = "internal server error"
+	}
+	writeAPIErrorStatus(w, status, code, msg)
+}
+
+func decodeJSONBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxAPIBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid JSON body: %v", domain.ErrInvalidInput, err)
+	}
+	return nil
+}
+
+func apiPathID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid id %q", domain.ErrInvalidInput, r.PathValue("id"))
+	}
+	return id, nil
+}
+
+func toAPIChannel(ch domain.Channel) apiChannel {
+	return apiChannel{ID: ch.ID, Number: ch.Number, Name: ch.Name, Provider: ch.Provider, Source: ch.Source, Group: ch.Group}
+}
+
+func toAPIEvent(ev domain.EPGEvent) apiEvent {
+	out := apiEvent{
+		EventID:        ev.EventID,
+		ChannelID:      ev.ChannelID,
+		ChannelNumber:  ev.ChannelNumber,
+		ChannelName:    ev.ChannelName,
+		Title:          ev.Title,
+		Subtitle:       ev.Subtitle,
+		Description:    ev.Description,
+		Start:          ev.Start,
+		Stop:           ev.Stop,
+		Duration:       int(ev.Stop.Sub(ev.Start) / time.Second),
+		VPS:            ev.VPS,
+		Genres:         ev.Genres,
+		ParentalRating: ev.ParentalRating,
+		HD:             ev.Video.HD,
+		VideoFormat:    ev.Video.Format,
+		AudioLanguages: ev.AudioLanguages(),
+	}
+	if len(out.AudioLanguages) == 0 {
+		out.AudioLanguages = nil
+	}
+	return out
+}
+
+func toAPIEvents(events []domain.EPGEvent) []apiEvent {
+	out := make([]apiEvent, 0, len(events))
+	for _, ev := range events {
+		out = append(out, toAPIEvent(ev))
+	}
+	return out
+}
+
+func toAPITimer(t domain.Timer) apiTimer {
+	out := apiTimer{
+		ID:        t.ID,
+		Active:    t.Active,
+		ChannelID: t.ChannelID,
+		Priority:  t.Priority,
+		Lifetime:  t.Lifetime,
+		Title:     t.Title,
+		Aux:       t.Aux,
+		EventID:   t.EventID,
+	}
+	if isWeekdayMaskHTTP(t.DaySpec) {
+		out.Weekdays = strings.TrimSpace(t.DaySpec)
+		out.StartTime = formatClockFromMinutes(t.StartMinutes)
+		out.StopTime = formatClockFromMinutes(t.StopMinutes)
+		return out
+	}
+	if !t.Start.IsZero() {
+		start, stop := t.Start, t.Stop
+		out.Start, out.Stop = &start, &stop
+		out.Day = t.Start.In(time.Local).Format("2006-01-02")
+		out.StartTime = t.Start.In(time.Local).Format("15:04")
+		out.StopTime = t.Stop.In(time.Local).Format("15:04")
+	}
+	return out
+}
+
+// toDomain converts the request body into a timer. Priority and lifetime default to
+// the configured timer defaults.
+func (in apiTimerInput) toDomain(defaultPriority, defaultLifetime int) (domain.Timer, error) {
+	t := domain.Timer{
+		Active:    true,
+		ChannelID: strings.TrimSpace(in.ChannelID),
+		Priority:  defaultPriority,
+		Lifetime:  defaultLifetime,
+		Title:     strings.TrimSpace(in.Title),
+		Aux:       in.Aux,
+		EventID:   in.EventID,
+	}
+	if in.Active != nil {
+		t.Active = *in.Active
+	}
+	if in.Priority != nil {
+		t.Priority = *in.Priority
+	}
+	if in.Lifetime != nil {
+		t.Lifetime = *in.Lifetime
+	}
+	if t.ChannelID == "" {
+		return domain.Timer{}, fmt.Errorf("%w: channel_id required", domain.ErrInvalidInput)
+	}
+	if t.Title == "" {
+		return domain.Timer{}, fmt.Errorf("%w: title required", domain.ErrInvalidInput)
+	}
+
+	startClock, err1 := time.Parse("15:04", strings.TrimSpace(in.StartTime))
+	stopClock, err2 := time.Parse("15:04", strings.TrimSpace(in.StopTime))
+	if err1 != nil || err2 != nil {
+		return domain.Timer{}, fmt.Errorf("%w: start_time and stop_time must be HH:MM", domain.ErrInvalidInput)
+	}
+
+	if mask := strings.TrimSpace(in.Weekdays); mask != "" {
+		if !isWeekdayMaskHTTP(mask) || strings.Trim(mask, "-.") == "" {
+			return domain.Timer{}, fmt.Errorf("%w: invalid weekdays %q", domain.ErrInvalidInput, mask)
+		}
+		t.DaySpec = mask
+		t.StartMinutes = startClock.Hour()*60 + startClock.Minute()
+		t.StopMinutes = stopClock.Hour()*60 + stopClock.Minute()
+		return t, nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(in.Day), time.Local)
+	if err != nil {
+		return domain.Timer{}, fmt.Errorf("%w: day must be YYYY-MM-DD (or set weekdays)", domain.ErrInvalidInput)
+	}
+	t.Day = day
+	t.Start = time.Date(day.Year(), day.Month(), day.Day(), startClock.Hour(), startClock.Minute(), 0, 0, time.Local)
+	t.Stop = time.Date(day.Year(), day.Month(), day.Day(), stopClock.Hour(), stopClock.Minute(), 0, 0, time.Local)
+	if t.Stop.Before(t.Start) {
+		t.Stop = t.Stop.Add(24 * time.Hour)
+	}
+	t.StartMinutes, t.StopMinutes = -1, -1
+	return t, nil
+}
+
+func toAPIRecording(rec domain.Recording) apiRecording {
+	return apiRecording{
+		Path:        rec.Path,
+		Title:       rec.Title,
+		Subtitle:    rec.Subtitle,
+		Description: rec.Description,
+		Channel:     rec.Channel,
+		Date:        rec.Date,
+		Length:      int(rec.Length / time.Second),
+		Size:        rec.Size,
+		Folder:      rec.Folder,
+		New:         rec.New,
+	}
+}
+
+func toAPIRecordingNode(node *domain.Recording) apiRecordingNode {
+	out := apiRecordingNode{apiRecording: toAPIRecording(*node), IsFolder: node.IsFolder}
+	if !node.IsFolder {
+		return out
+	}
+	out.Count = node.Count
+	out.NewCount = node.NewCount
+	out.Children = make([]apiRecordingNode, 0, len(node.Children))
+	for _, child := range node.Children {
+		out.Children = append(out.Children, toAPIRecordingNode(child))
+	}
+	return out
+}
+
+func toAPISavedSearch(s config.EPGSearch) apiSavedSearch {
+	return apiSavedSearch{
+		ID:          s.ID,
+		Active:      s.Active,
+		Pattern:     s.Pattern,
+		Mode:        s.Mode,
+		MatchCase:   s.MatchCase,
+		InTitle:     s.InTitle,
+		InSubtitle:  s.InSubtitle,
+		InDesc:      s.InDesc,
+		UseChannel:  s.UseChannel,
+		ChannelID:   s.ChannelID,
+		ChannelFrom: s.ChannelFrom,
+		ChannelTo:   s.ChannelTo,
+		StartFrom:   s.StartFrom,
+		StartTo:     s.StartTo,
+		MinDuration: s.MinDuration,
+		MaxDuration: s.MaxDuration,
+		Weekdays:    s.Weekdays,
+		Exclude:     s.Exclude,
+		HDOnly:      s.HDOnly,
+		HorizonDays: s.HorizonDays,
+		NotRecorded: s.NotRecorded,
+		AutoRecord:  s.AutoRecord,
+		Priority:    s.Priority,
+		Lifetime:    s.Lifetime,
+		MarginStart: s.MarginStart,
+		MarginEnd:   s.MarginEnd,
+		Folder:      s.Folder,
+		Dedup:       s.Dedup,
+	}
+}
+
+func (s apiSavedSearch) toConfig() config.EPGSearch {
+	out := config.EPGSearch{
+		ID:          s.ID,
+		Active:      s.Active,
+		Pattern:     strings.TrimSpace(s.Pattern),
+		Mode:        s.Mode,
+		MatchCase:   s.MatchCase,
+		InTitle:     s.InTitle,
+		InSubtitle:  s.InSubtitle,
+		InDesc:      s.InDesc,
+		UseChannel:  s.UseChannel,
+		ChannelID:   strings.TrimSpace(s.ChannelID),
+		ChannelFrom: strings.TrimSpace(s.ChannelFrom),
+		ChannelTo:   strings.TrimSpace(s.ChannelTo),
+		StartFrom:   s.StartFrom,
+		StartTo:     s.StartTo,
+		MinDuration: s.MinDuration,
+		MaxDuration: s.MaxDuration,
+		Weekdays:    s.Weekdays,
+		Exclude:     s.Exclude,
+		HDOnly:      s.HDOnly,
+		HorizonDays: s.HorizonDays,
+		NotRecorded: s.NotRecorded,
+		AutoRecord:  s.AutoRecord,
+		Priority:    s.Priority,
+		Lifetime:    s.Lifetime,
+		MarginStart: s.MarginStart,
+		MarginEnd:   s.MarginEnd,
+		Folder:      s.Folder,
+		Dedup:       s.Dedup,
+	}
+	config.NormalizeEPGSearch(&out)
+	return out
+}
+
+func toAPIArchiveJob(snap archive.JobSnapshot, withLog bool) apiArchiveJob {
+	out := apiArchiveJob{
+		ID:          snap.ID,
+		RecordingID: snap.RecordingID,
+		Status:      string(snap.Status),
+		CreatedAt:   snap.CreatedAt,
+		Error:       snap.Error,
+		TargetDir:   snap.Preview.TargetDir,
+		VideoPath:   snap.Preview.VideoPath,
+		Percent:     snap.Progress.Percent,
+		Speed:       snap.Progress.Speed,
+		LogCount:    snap.LogCount,
+	}
+	if !snap.StartedAt.IsZero() {
+		t := snap.StartedAt
+		out.StartedAt = &t
+	}
+	if !snap.EndedAt.IsZero() {
+		t := snap.EndedAt
+		out.EndedAt = &t
+	}
+	if withLog {
+		out.LogTail = snap.LogTail
+	}
+	return out
+}
+
+// updateConfig applies fn to a copy of the configuration, validates it, and
+// persists it when a config path is set.
+func (h *Handler) updateConfig(fn func(*config.Config) error) error {
+	if h.cfg == nil {
+		return fmt.Errorf("configuration not available")
+	}
+	updated := *h.cfg
+	if err := fn(&updated); err != nil {
+		return err
+	}
+	if err := updated.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	*h.cfg = updated
+	if strings.TrimSpace(h.configPath) != "" {
+		if err := h.cfg.Save(h.configPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// APIOpenAPI serves the OpenAPI document describing /api/v1.
+func (h *Handler) APIOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_, _ = w.Write(openAPIDocument)
+}
+
+// APIChannels lists the wanted channels.
+func (h *Handler) APIChannels(w http.ResponseWriter, r *http.Request) {
+	channels, err := h.epgService.GetChannels(r.Context())
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	out := make([]apiChannel, 0, len(channels))
+	for _, ch := range channels {
+		out = append(out, toAPIChannel(ch))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// APIChannelEPG lists the EPG events of a single channel.
+func (h *Handler) APIChannelEPG(w http.ResponseWriter, r *http.Request) {
+	channelID := strings.TrimSpace(r.PathValue("id"))
+	if channelID == "" {
+		h.apiError(w, r, fmt.Errorf("%w: channel id required", domain.ErrInvalidInput))
+		return
+	}
+	events, err := h.epgService.GetEPG(r.Context(), channelID, time.Time{})
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIEvents(events))
+}
+
+// APIEPGNow lists the events running now on every channel.
+func (h *Handler) APIEPGNow(w http.ResponseWriter, r *http.Request) {
+	events, err := h.epgService.GetCurrentPrograms(r.Context())
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIEvents(events))
+}
+
+// APIEPGNext lists the event following the current one on every channel.
+func (h *Handler) APIEPGNext(w http.ResponseWriter, r *http.Request) {
+	events, err := h.epgService.GetEPG(r.Context(), "", time.Time{})
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	now := h.now()
+	next := map[string]domain.EPGEvent{}
+	order := []string{}
+	for _, ev := range events {
+		if !ev.Start.After(now) {
+			continue
+		}
+		cur, ok := next[ev.ChannelID]
+		if !ok {
+			order = append(order, ev.ChannelID)
+		}
+		if !ok || ev.Start.Before(cur.Start) {
+			next[ev.ChannelID] = ev
+		}
+	}
+	out := make([]apiEvent, 0, len(order))
+	for _, id := range order {
+		out = append(out, toAPIEvent(next[id]))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// APIEPGAt lists the events running at ?time= (RFC 3339 or local "2006-01-02T15:04").
+func (h *Handler) APIEPGAt(w http.ResponseWriter, r *http.Request) {
+	raw := strings.TrimSpace(r.URL.Query().Get("time"))
+	at, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		at, err = time.ParseInLocation("2006-01-02T15:04", raw, time.Local)
+	}
+	if err != nil {
+		h.apiError(w, r, fmt.Errorf("%w: time must be RFC 3339 or YYYY-MM-DDTHH:MM", domain.ErrInvalidInput))
+		return
+	}
+	events, err := h.epgService.GetProgramsAt(r.Context(), at)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIEvents(events))
+}
+
+// APIEPGSearch searches title, subtitle and description for ?q=.
+func (h *Handler) APIEPGSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		h.apiError(w, r, fmt.Errorf("%w: q required", domain.ErrInvalidInput))
+		return
+	}
+	events, err := h.epgService.SearchEPG(r.Context(), q)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIEvents(events))
+}
+
+// APITimers lists all timers.
+func (h *Handler) APITimers(w http.ResponseWriter, r *http.Request) {
+	timers, err := h.timerService.GetAllTimers(r.Context())
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	out := make([]apiTimer, 0, len(timers))
+	for _, t := range timers {
+		out = append(out, toAPITimer(t))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) apiFindTimer(ctx context.Context, id int) (domain.Timer, error) {
+	timers, err := h.timerService.GetAllTimers(ctx)
+	if err != nil {
+		return domain.Timer{}, err
+	}
+	for _, t := range timers {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return domain.Timer{}, fmt.Errorf("%w: timer %d", domain.ErrNotFound, id)
+}
+
+// APITimer returns a single timer.
+func (h *Handler) APITimer(w http.ResponseWriter, r *http.Request) {
+	id, err := apiPathID(r)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	t, err := h.apiFindTimer(r.Context(), id)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPITimer(t))
+}
+
+// APITimerCreate creates a timer, either from an EPG event or from explicit times.
+func (h *Handler) APITimerCreate(w http.ResponseWriter, r *http.Request) {
+	var in apiTimerInput
+	if err := decodeJSONBody(r, &in); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	priority, lifetime, marginStart, marginEnd := h.timerDefaults()
+
+	var timer domain.Timer
+	if in.EventID > 0 && strings.TrimSpace(in.StartTime) == "" {
+		ev, err := h.apiFindEvent(r.Context(), strings.TrimSpace(in.ChannelID), in.EventID)
+		if err != nil {
+			h.apiError(w, r, err)
+			return
+		}
+		if in.Priority != nil {
+			priority = *in.Priority
+		}
+		if in.Lifetime != nil {
+			lifetime = *in.Lifetime
+		}
+		timer = services.NewTimerFromEPG(ev, priority, lifetime, marginStart, marginEnd)
+	} else {
+		var err error
+		timer, err = in.toDomain(priority, lifetime)
+		if err != nil {
+			h.apiError(w, r, err)
+			return
+		}
+	}
+
+	if err := h.timerService.CreateTimer(r.Context(), &timer); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toAPITimer(timer))
+}
+
+func (h *Handler) apiFindEvent(ctx context.Context, channelID string, eventID int) (domain.EPGEvent, error) {
+	events, err := h.epgService.GetEPG(ctx, channelID, time.Time{})
+	if err != nil {
+		return domain.EPGEvent{}, err
+	}
+	for _, ev := range events {
+		if ev.EventID == eventID && (channelID == "" || ev.ChannelID == channelID) {
+			return ev, nil
+		}
+	}
+	return domain.EPGEvent{}, fmt.Errorf("%w: event %d", domain.ErrNotFound, eventID)
+}
+
+// APITimerUpdate replaces a timer.
+func (h *Handler) APITimerUpdate(w http.ResponseWriter, r *http.Request) {
+	id, err := apiPathID(r)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	existing, err := h.apiFindTimer(r.Context(), id)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	var in apiTimerInput
+	if err := decodeJSONBody(r, &in); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	timer, err := in.toDomain(existing.Priority, existing.Lifetime)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	timer.ID = id
+	if err := h.timerService.UpdateTimer(r.Context(), &timer); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPITimer(timer))
+}
+
+// APITimerDelete deletes a timer.
+func (h *Handler) APITimerDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := apiPathID(r)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	if err := h.timerService.DeleteTimer(r.Context(), id); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// APITimerConflicts reports the simulated device allocation for ?from=&to= (RFC 3339),
+// defaulting to the next 8 days.
+func (h *Handler) APITimerConflicts(w http.ResponseWriter, r *http.Request) {
+	from := h.now()
+	to := addCalendarDaysHTTP(from, 8)
+	if v := strings.TrimSpace(r.URL.Query().Get("from")); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			h.apiError(w, r, fmt.Errorf("%w: from must be RFC 3339", domain.ErrInvalidInput))
+			return
+		}
+		from = t
+	}
+	if v := strings.TrimSpace(r.URL.Query().Get("to")); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			h.apiError(w, r, fmt.Errorf("%w: to must be RFC 3339", domain.ErrInvalidInput))
+			return
+		}
+		to = t
+	}
+	if !to.After(from) {
+		h.apiError(w, r, fmt.Errorf("%w: to must be after from", domain.ErrInvalidInput))
+		return
+	}
+
+	report, err := h.timerService.SimulateConflicts(r.Context(), from, to)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	out := apiConflictReport{
+		DVBCards:    report.DVBCards,
+		From:        from,
+		To:          to,
+		Allocations: make([]apiTimerAllocation, 0, len(report.Allocations)),
+		Collisions:  sortedIDs(report.CollisionIDs),
+		Critical:    sortedIDs(report.CriticalIDs),
+		Failed:      sortedIDs(report.FailedIDs),
+	}
+	for _, a := range report.Allocations {
+		out.Allocations = append(out.Allocations, apiTimerAllocation{
+			TimerID:     a.TimerID,
+			Start:       a.Start,
+			Stop:        a.Stop,
+			Transponder: a.Transponder,
+			Device:      a.Device,
+			Shared:      a.Shared,
+			Missed:      int(a.Missed / time.Second),
+			Failed:      a.Failed(),
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func sortedIDs(set map[int]bool) []int {
+	out := make([]int, 0, len(set))
+	for id, ok := range set {
+		if ok {
+			out = append(out, id)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+// APIRecordings lists all recordings.
+func (h *Handler) APIRecordings(w http.ResponseWriter, r *http.Request) {
+	recordings, err := h.recordingService.GetAllRecordings(r.Context())
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	recordings = h.recordingService.SortRecordings(recordings, strings.TrimSpace(r.URL.Query().Get("sort")))
+	out := make([]apiRecording, 0, len(recordings))
+	for _, rec := range recordings {
+		out = append(out, toAPIRecording(rec))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// APIRecordingTree returns the recordings as a folder tree with totals per
+// folder. ?folder= selects a subtree, ?sort= orders the entries of every folder.
+func (h *Handler) APIRecordingTree(w http.ResponseWriter, r *http.Request) {
+	root, err := h.recordingService.GetRecordingsByFolder(r.Context())
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	folder := strings.TrimSpace(r.URL.Query().Get("folder"))
+	node, _, err := services.FindRecordingFolder(root, folder)
+	if err != nil {
+		h.apiError(w, r, fmt.Errorf("folder %q: %w", folder, err))
+		return
+	}
+	services.SortRecordingTree(node, strings.TrimSpace(r.URL.Query().Get("sort")))
+	writeJSON(w, http.StatusOK, toAPIRecordingNode(node))
+}
+
+// APIRecordingDelete deletes the recording given by ?path=.
+func (h *Handler) APIRecordingDelete(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSpace(r.URL.Query().Get("path"))
+	if path == "" {
+		h.apiError(w, r, fmt.Errorf("%w: path required", domain.ErrInvalidInput))
+		return
+	}
+	if err := h.validateRecordingPath(path); err != nil {
+		h.apiError(w, r, fmt.Errorf("%w: invalid path", domain.ErrInvalidInput))
+		return
+	}
+	if err := h.recordingService.DeleteRecording(r.Context(), path); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) savedSearches() []config.EPGSearch {
+	if h.cfg == nil {
+		return nil
+	}
+	return h.cfg.EPG.Searches
+}
+
+func (h *Handler) apiFindSavedSearch(id int) (config.EPGSearch, error) {
+	for _, s := range h.savedSearches() {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return config.EPGSearch{}, fmt.Errorf("%w: search %d", domain.ErrNotFound, id)
+}
+
+// APISavedSearches lists the saved EPG searches.
+func (h *Handler) APISavedSearches(w http.ResponseWriter, r *http.Request) {
+	searches := h.savedSearches()
+	out := make([]apiSavedSearch, 0, len(searches))
+	for _, s := range searches {
+		out = append(out, toAPISavedSearch(s))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// APISavedSearch returns a single saved EPG search.
+func (h *Handler) APISavedSearch(w http.ResponseWriter, r *http.Request) {
+	id, err := apiPathID(r)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	s, err := h.apiFindSavedSearch(id)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPISavedSearch(s))
+}
+
+// APISavedSearchResults executes a saved EPG search.
+func (h *Handler) APISavedSearchResults(w http.ResponseWriter, r *http.Request) {
+	id, err := apiPathID(r)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	s, err := h.apiFindSavedSearch(id)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	channels, _ := h.epgService.GetChannels(r.Context())
+	order := make(map[string]int, len(channels))
+	for i, ch := range channels {
+		if ch.ID != "" {
+			order[ch.ID] = i + 1
+		}
+	}
+	matches, err := h.epgService.ExecuteSavedSearch(r.Context(), s, order, h.savedSearchEnv(r.Context(), s))
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIEvents(matches))
+}
+
+// APISavedSearchCreate adds a saved EPG search.
+func (h *Handler) APISavedSearchCreate(w http.ResponseWriter, r *http.Request) {
+	// Searches without timer settings record with the timer defaults.
+	priority, lifetime, marginStart, marginEnd := h.timerDefaults()
+	in := apiSavedSearch{Priority: priority, Lifetime: lifetime, MarginStart: marginStart, MarginEnd: marginEnd}
+	if err := decodeJSONBody(r, &in); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	search := in.toConfig()
+	if search.Pattern == "" {
+		h.apiError(w, r, fmt.Errorf("%w: pattern required", domain.ErrInvalidInput))
+		return
+	}
+	if err := config.ValidateEPGSearch(search); err != nil {
+		h.apiError(w, r, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err))
+		return
+	}
+	err := h.updateConfig(func(c *config.Config) error {
+		search.ID = nextEPGSearchID(c.EPG.Searches)
+		c.EPG.Searches = append(append([]config.EPGSearch(nil), c.EPG.Searches...), search)
+		return nil
+	})
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	h.triggerSearchTimers(search)
+	writeJSON(w, http.StatusCreated, toAPISavedSearch(search))
+}
+
+// APISavedSearchUpdate replaces a saved EPG search.
+func (h *Handler) APISavedSearchUpdate(w http.ResponseWriter, r *http.Request) {
+	id, err := apiPathID(r)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	var in apiSavedSearch
+	if err := decodeJSONBody(r, &in); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	search := in.toConfig()
+	search.ID = id
+	if search.Pattern == "" {
+		h.apiError(w, r, fmt.Errorf("%w: pattern required", domain.ErrInvalidInput))
+		return
+	}
+	if err := config.ValidateEPGSearch(search); err != nil {
+		h.apiError(w, r, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err))
+		return
+	}
+	err = h.updateConfig(func(c *config.Config) error {
+		c.EPG.Searches = append([]config.EPGSearch(nil), c.EPG.Searches...)
+		for i := range c.EPG.Searches {
+			if c.EPG.Searches[i].ID == id {
+				c.EPG.Searches[i] = search
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: search %d", domain.ErrNotFound, id)
+	})
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	h.triggerSearchTimers(search)
+	writeJSON(w, http.StatusOK, toAPISavedSearch(search))
+}
+
+// APISavedSearchDelete removes a saved EPG search.
+func (h *Handler) APISavedSearchDelete(w http.ResponseWriter, r *http.Request) {
+	id, err := apiPathID(r)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	err = h.updateConfig(func(c *config.Config) error {
+		kept := make([]config.EPGSearch, 0, len(c.EPG.Searches))
+		for _, s := range c.EPG.Searches {
+			if s.ID != id {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) == len(c.EPG.Searches) {
+			return fmt.Errorf("%w: search %d", domain.ErrNotFound, id)
+		}
+		c.EPG.Searches = kept
+		return nil
+	})
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// APIArchiveProfiles lists the archive destination profiles.
+func (h *Handler) APIArchiveProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles := h.archiveProfilesFromConfig(h.cfg)
+	out := make([]apiArchiveProfile, 0, len(profiles))
+	for _, p := range profiles {
+		out = append(out, apiArchiveProfile{ID: p.ID, Name: p.Name, Kind: string(p.Kind), BaseDir: p.BaseDir})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// APIArchiveProfilesReplace replaces all archive destination profiles.
+// An empty list restores the profiles derived from archive.base_dir.
+func (h *Handler) APIArchiveProfilesReplace(w http.ResponseWriter, r *http.Request) {
+	var in []apiArchiveProfile
+	if err := decodeJSONBody(r, &in); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	profiles := make([]config.ArchiveProfileConfig, 0, len(in))
+	for _, p := range in {
+		profiles = append(profiles, config.ArchiveProfileConfig{
+			ID:      strings.TrimSpace(p.ID),
+			Name:    strings.TrimSpace(p.Name),
+			Kind:    strings.TrimSpace(p.Kind),
+			BaseDir: strings.TrimSpace(p.BaseDir),
+		})
+	}
+	err := h.updateConfig(func(c *config.Config) error {
+		c.Archive.Profiles = profiles
+		return nil
+	})
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	h.APIArchiveProfiles(w, r)
+}
+
+// APIArchiveJobs lists the archive jobs.
+func (h *Handler) APIArchiveJobs(w http.ResponseWriter, r *http.Request) {
+	jobs := h.archiveJobs.List()
+	out := make([]apiArchiveJob, 0, len(jobs))
+	for _, j := range jobs {
+		out = append(out, toAPIArchiveJob(j, false))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// APIArchiveJob returns a single archive job including the tail of its log.
+func (h *Handler) APIArchiveJob(w http.ResponseWriter, r *http.Request) {
+	snap, ok := h.archiveJobs.Get(r.PathValue("id"))
+	if !ok {
+		h.apiError(w, r, fmt.Errorf("%w: job %q", domain.ErrNotFound, r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIArchiveJob(snap, true))
+}
+
+// APIArchiveJobCancel cancels a running archive job.
+func (h *Handler) APIArchiveJobCancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, ok := h.archiveJobs.Get(id); !ok {
+		h.apiError(w, r, fmt.Errorf("%w: job %q", domain.ErrNotFound, id))
+		return
+	}
+	if !h.archiveJobs.Cancel(id) {
+		h.apiError(w, r, fmt.Errorf("%w: job %q is not running", domain.ErrConflict, id))
+		return
+	}
+	snap, _ := h.archiveJobs.Get(id)
+	writeJSON(w, http.StatusOK, toAPIArchiveJob(snap, false))
+}
+
+// APIArchiveJobStart starts archiving a recording with a destination profile.
+func (h *Handler) APIArchiveJobStart(w http.ResponseWriter, r *http.Request) {
+	var in apiArchiveStartInput
+	if err := decodeJSONBody(r, &in); err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	plan, err := h.apiArchivePlan(r.Context(), in)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	// The job outlives the request; keep only its values (e.g. the trace to link to).
+	jobID, err := h.archiveJobs.Start(context.WithoutCancel(r.Context()), plan, h.instanceID)
+	if err != nil {
+		h.apiError(w, r, err)
+		return
+	}
+	h.logger.Info("archive job started",
+		slog.String("job_id", jobID),
+		slog.String("instance_id", h.instanceID),
+		slog.String("target_dir", plan.Preview.TargetDir),
+		slog.String("video_path", plan.Preview.VideoPath),
+	)
+	snap, _ := h.archiveJobs.Get(jobID)
+	writeJSON(w, http.StatusAccepted, toAPIArchiveJob(snap, false))
+}
+
+// apiArchivePlan builds the archive plan for a recording like RecordingArchiveStart,
+// without the per-path overrides of the HTML form.
+func (h *Handler) apiArchivePlan(ctx context.Context, in apiArchiveStartInput) (archive.Plan, error) {
+	if h.cfg == nil || h.vdrClient == nil {
+		return archive.Plan{}, fmt.Errorf("archiving not available")
+	}
+	recID := strings.TrimSpace(in.Path)
+	if recID == "" {
+		return archive.Plan{}, fmt.Errorf("%w: path required", domain.ErrInvalidInput)
+	}
+	if err := h.validateRecordingPath(recID); err != nil {
+		return archive.Plan{}, fmt.Errorf("%w: invalid path", domain.ErrInvalidInput)
+	}
+	if jobID, ok := h.archiveJobs.ActiveJobIDForRecording(recID); ok {
+		return archive.Plan{}, fmt.Errorf("%w: recording is already being archived by job %s", domain.ErrConflict, jobID)
+	}
+
+	recDir, err := h.vdrClient.GetRecordingDir(ctx, recID)
+	if err != nil {
+		return archive.Plan{}, err
+	}
+	if strings.TrimSpace(recDir) == "" {
+		return archive.Plan{}, fmt.Errorf("%w: could not resolve recording directory", domain.ErrNotFound)
+	}
+	if err := h.validateRecordingDir(recDir); err != nil {
+		return archive.Plan{}, fmt.Errorf("%w: invalid recording directory", domain.ErrInvalidInput)
+	}
+	infoPath := filepath.Join(recDir, "info")
+	infoBytes, err := os.ReadFile(infoPath)
+	if err != nil {
+		return archive.Plan{}, fmt.Errorf("failed to read info file: %w", err)
+	}
+	parsed, err := archive.ParseVDRInfo(strings.NewReader(string(infoBytes)))
+	if err != nil {
+		return archive.Plan{}, fmt.Errorf("failed to parse info file: %w", err)
+	}
+
+	title := strings.TrimSpace(in.Title)
+	if title == "" {
+		title = parsed.Title
+	}
+	episode := strings.TrimSpace(in.Episode)
+	if episode == "" {
+		episode = parsed.Episode
+	}
+	format := strings.ToLower(strings.TrimSpace(in.Format))
+	if format != "mp4" {
+		format = "mkv"
+	}
+
+	profiles := h.archiveProfilesFromConfig(h.cfg)
+	profileID := strings.TrimSpace(in.Profile)
+	if profileID == "" {
+		profileID = h.defaultProfileIDForKind(profiles, parsed.Kind)
+	}
+	selected, ok := archive.FindProfile(profiles, profileID)
+	if !ok {
+		return archive.Plan{}, fmt.Errorf("%w: unknown profile %q", domain.ErrInvalidInput, profileID)
+	}
+	plan, err := archive.BuildPlan(recID, recDir, infoPath, selected, title, episode, format, archive.SplitArgs(h.cfg.Archive.FFMpegArgs))
+	if err != nil {
+		return archive.Plan{}, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+	if in.MarkedOnly {
+		if err := plan.LimitToMarks(); err != nil {
+			return archive.Plan{}, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+		}
+	}
+	if plan.Preview.VideoPath != "" {
+		if _, err := os.Stat(plan.Preview.VideoPath); err == nil {
+			return archive.Plan{}, fmt.Errorf("%w: output already exists: %s", domain.ErrConflict, plan.Preview.VideoPath)
+		}
+	}
+	return plan, nil
+}
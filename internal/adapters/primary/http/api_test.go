@@ -0,0 +1,335 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+var apiTestAuth = &config.AuthConfig{
+	Enabled:      true,
+	AdminUser:    "admin",
+	AdminPass:    "secret",
+	GuestEnabled: true,
+	GuestUser:    "guest",
+	GuestPass:    "guest",
+}
+
+func newAPITestServer(t *testing.T, mock *ports.MockVDRClient) (*Handler, http.Handler) {
+	t.Helper()
+	epgSvc := services.NewEPGService(mock, 0)
+	timerSvc := services.NewTimerService(mock)
+	recSvc := services.NewRecordingService(mock, 0)
+
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), template.New("empty"), epgSvc, timerSvc, recSvc, nil)
+	h.SetConfig(&config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		VDR:    config.VDRConfig{Host: "localhost", Port: 6419, DVBCards: 1},
+		Auth:   *apiTestAuth,
+		Timer:  config.TimerConfig{DefaultPriority: 50, DefaultLifetime: 99, DefaultMarginStart: 2, DefaultMarginEnd: 10},
+	}, "")
+	h.SetVDRClient(mock)
+	return h, SetupRoutes(h, apiTestAuth, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func apiRequest(t *testing.T, mux http.Handler, method, target, user string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		r = bytes.NewReader(b)
+	}
+	req := httptest.NewRequest(method, target, r)
+	req.RemoteAddr = "203.0.113.10:40000"
+	if user != "" {
+		pass := "secret"
+		if user == "guest" {
+			pass = "guest"
+		}
+		req.SetBasicAuth(user, pass)
+	}
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+	return rw
+}
+
+func decodeAPIError(t *testing.T, rw *httptest.ResponseRecorder) apiErrorBody {
+	t.Helper()
+	var resp apiErrorResponse
+	if err := json.Unmarshal(rw.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("expected JSON error body, got %q: %v", rw.Body.String(), err)
+	}
+	if resp.Error.Status != rw.Code {
+		t.Fatalf("error body status %d does not match response status %d", resp.Error.Status, rw.Code)
+	}
+	return resp.Error
+}
+
+func TestAPIStatusForError(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("%w: timer 3", domain.ErrNotFound), http.StatusNotFound, "not_found"},
+		{fmt.Errorf("%w: bad", domain.ErrInvalidInput), http.StatusBadRequest, "invalid_input"},
+		{&services.TimerConflictError{}, http.StatusConflict, "conflict"},
+		{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+		{fmt.Errorf("dial: %w", domain.ErrConnection), http.StatusBadGateway, "vdr_unavailable"},
+		{fmt.Errorf("welcome: %w", domain.ErrBusy), http.StatusServiceUnavailable, "vdr_busy"},
+		{domain.ErrTimeout, http.StatusGatewayTimeout, "timeout"},
+		{errors.New("boom"), http.StatusInternalServerError, "internal"},
+	}
+	for _, tc := range cases {
+		status, code := apiStatusForError(tc.err)
+		if status != tc.status || code != tc.code {
+			t.Errorf("%v: got %d/%s, want %d/%s", tc.err, status, code, tc.status, tc.code)
+		}
+	}
+}
+
+func TestAPI_TimerCRUD(t *testing.T) {
+	base := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	ev := domain.EPGEvent{EventID: 42, ChannelID: "S19.2E-1-1011-11110", Title: "Tatort", Start: base, Stop: base.Add(90 * time.Minute)}
+
+	var created, updated []domain.Timer
+	var deleted []int
+	mock := ports.NewMockVDRClient().
+		WithEPGEvents([]domain.EPGEvent{ev}).
+		WithTimers([]domain.Timer{{ID: 7, Active: true, ChannelID: ev.ChannelID, Start: base, Stop: base.Add(time.Hour), Priority: 50, Lifetime: 99, Title: "News"}})
+	mock.CreateTimerFunc = func(ctx context.Context, timer *domain.Timer) error {
+		created = append(created, *timer)
+		return nil
+	}
+	mock.UpdateTimerFunc = func(ctx context.Context, timer *domain.Timer) error {
+		updated = append(updated, *timer)
+		return nil
+	}
+	mock.DeleteTimerFunc = func(ctx context.Context, id int) error {
+		deleted = append(deleted, id)
+		return nil
+	}
+	_, mux := newAPITestServer(t, mock)
+
+	rw := apiRequest(t, mux, http.MethodGet, "/api/v1/timers/7", "guest", nil)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("GET timer: expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	var got apiTimer
+	if err := json.Unmarshal(rw.Body.Bytes(), &got); err != nil || got.ID != 7 || got.Title != "News" {
+		t.Fatalf("unexpected timer %+v (err=%v)", got, err)
+	}
+
+	rw = apiRequest(t, mux, http.MethodGet, "/api/v1/timers/99", "guest", nil)
+	if rw.Code != http.StatusNotFound || decodeAPIError(t, rw).Code != "not_found" {
+		t.Fatalf("expected 404 not_found, got %d: %s", rw.Code, rw.Body.String())
+	}
+
+	rw = apiRequest(t, mux, http.MethodPost, "/api/v1/timers", "admin", map[string]any{"event_id": 42, "channel_id": ev.ChannelID})
+	if rw.Code != http.StatusCreated {
+		t.Fatalf("POST timer: expected 201, got %d: %s", rw.Code, rw.Body.String())
+	}
+	if len(created) != 1 || created[0].EventID != 42 || !created[0].Start.Equal(base.Add(-2*time.Minute)) || created[0].Priority != 50 {
+		t.Fatalf("unexpected created timer %+v", created)
+	}
+
+	rw = apiRequest(t, mux, http.MethodPut, "/api/v1/timers/7", "admin", map[string]any{
+		"channel_id": ev.ChannelID, "title": "Renamed", "weekdays": "MTWTF--", "start_time": "20:00", "stop_time": "21:15",
+	})
+	if rw.Code != http.StatusOK {
+		t.Fatalf("PUT timer: expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	if len(updated) != 1 || updated[0].ID != 7 || updated[0].DaySpec != "MTWTF--" || updated[0].StartMinutes != 20*60 || updated[0].StopMinutes != 21*60+15 {
+		t.Fatalf("unexpected updated timer %+v", updated)
+	}
+
+	rw = apiRequest(t, mux, http.MethodPut, "/api/v1/timers/7", "admin", map[string]any{"channel_id": ev.ChannelID, "title": "x", "start_time": "25:00"})
+	if rw.Code != http.StatusBadRequest || decodeAPIError(t, rw).Code != "invalid_input" {
+		t.Fatalf("expected 400 invalid_input, got %d: %s", rw.Code, rw.Body.String())
+	}
+
+	rw = apiRequest(t, mux, http.MethodDelete, "/api/v1/timers/7", "admin", nil)
+	if rw.Code != http.StatusNoContent || len(deleted) != 1 || deleted[0] != 7 {
+		t.Fatalf("DELETE timer: got %d, deleted=%v", rw.Code, deleted)
+	}
+}
+
+func TestAPI_RequiresAdminForWrites(t *testing.T) {
+	_, mux := newAPITestServer(t, ports.NewMockVDRClient())
+
+	rw := apiRequest(t, mux, http.MethodDelete, "/api/v1/timers/1", "guest", nil)
+	if rw.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rw.Code)
+	}
+	if !strings.HasPrefix(rw.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("expected JSON content type, got %q", rw.Header().Get("Content-Type"))
+	}
+	if decodeAPIError(t, rw).Code != "forbidden" {
+		t.Fatalf("unexpected error body %s", rw.Body.String())
+	}
+
+	if rw := apiRequest(t, mux, http.MethodGet, "/api/v1/timers", "", nil); rw.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", rw.Code)
+	}
+	if rw := apiRequest(t, mux, http.MethodGet, "/api/v1/timers", "guest", nil); rw.Code != http.StatusOK {
+		t.Fatalf("expected guest read to succeed, got %d", rw.Code)
+	}
+}
+
+func TestAPI_VDRErrorsMapToGateway(t *testing.T) {
+	mock := ports.NewMockVDRClient()
+	mock.GetTimersFunc = func(ctx context.Context) ([]domain.Timer, error) {
+		return nil, fmt.Errorf("svdrp: %w", domain.ErrConnection)
+	}
+	_, mux := newAPITestServer(t, mock)
+
+	rw := apiRequest(t, mux, http.MethodGet, "/api/v1/timers", "guest", nil)
+	if rw.Code != http.StatusBadGateway || decodeAPIError(t, rw).Code != "vdr_unavailable" {
+		t.Fatalf("expected 502 vdr_unavailable, got %d: %s", rw.Code, rw.Body.String())
+	}
+}
+
+func TestAPI_SavedSearchCRUD(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	mock := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{{ID: "C1", Number: 1, Name: "One"}}).
+		WithEPGEvents([]domain.EPGEvent{
+			{EventID: 1, ChannelID: "C1", Title: "Tatort", Start: base, Stop: base.Add(time.Hour)},
+			{EventID: 2, ChannelID: "C1", Title: "News", Start: base.Add(time.Hour), Stop: base.Add(2 * time.Hour)},
+		})
+	h, mux := newAPITestServer(t, mock)
+
+	rw := apiRequest(t, mux, http.MethodPost, "/api/v1/searches", "admin", map[string]any{"active": true, "pattern": "tatort"})
+	if rw.Code != http.StatusCreated {
+		t.Fatalf("POST search: expected 201, got %d: %s", rw.Code, rw.Body.String())
+	}
+	var created apiSavedSearch
+	_ = json.Unmarshal(rw.Body.Bytes(), &created)
+	if created.ID != 1 || created.Mode != "phrase" || !created.InTitle || len(h.cfg.EPG.Searches) != 1 {
+		t.Fatalf("unexpected search %+v (stored %d)", created, len(h.cfg.EPG.Searches))
+	}
+
+	rw = apiRequest(t, mux, http.MethodGet, "/api/v1/searches/1/results", "guest", nil)
+	var events []apiEvent
+	if err := json.Unmarshal(rw.Body.Bytes(), &events); err != nil || len(events) != 1 || events[0].EventID != 1 {
+		t.Fatalf("unexpected results %s (err=%v)", rw.Body.String(), err)
+	}
+
+	rw = apiRequest(t, mux, http.MethodPut, "/api/v1/searches/1", "admin", map[string]any{"pattern": "x", "mode": "glob"})
+	if rw.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid mode to be rejected, got %d", rw.Code)
+	}
+	rw = apiRequest(t, mux, http.MethodPut, "/api/v1/searches/5", "admin", map[string]any{"pattern": "x"})
+	if rw.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown search, got %d", rw.Code)
+	}
+	rw = apiRequest(t, mux, http.MethodPost, "/api/v1/searches", "admin", map[string]any{"pattern": "x", "unknown": 1})
+	if rw.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown fields to be rejected, got %d", rw.Code)
+	}
+
+	rw = apiRequest(t, mux, http.MethodDelete, "/api/v1/searches/1", "admin", nil)
+	if rw.Code != http.StatusNoContent || len(h.cfg.EPG.Searches) != 0 {
+		t.Fatalf("DELETE search: got %d, stored %d", rw.Code, len(h.cfg.EPG.Searches))
+	}
+}
+
+func TestAPI_ArchiveJobNotFound(t *testing.T) {
+	_, mux := newAPITestServer(t, ports.NewMockVDRClient())
+
+	rw := apiRequest(t, mux, http.MethodPost, "/api/v1/archive/jobs/nope/cancel", "admin", nil)
+	if rw.Code != http.StatusNotFound || decodeAPIError(t, rw).Code != "not_found" {
+		t.Fatalf("expected 404, got %d: %s", rw.Code, rw.Body.String())
+	}
+}
+
+func TestAPI_RecordingTree(t *testing.T) {
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{
+		{Path: "1", Title: "Film", Name: "Film", Length: 90 * time.Minute, Size: 100},
+		{Path: "2", Title: "Folge 1", Name: "Serie~Folge 1", New: true, Length: 45 * time.Minute, Size: 10},
+		{Path: "3", Title: "Folge 2", Name: "Serie~Folge 2", Length: 45 * time.Minute, Size: 20},
+	})
+	_, mux := newAPITestServer(t, mock)
+
+	rw := apiRequest(t, mux, http.MethodGet, "/api/v1/recordings/tree?sort=name", "guest", nil)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	var root apiRecordingNode
+	if err := json.Unmarshal(rw.Body.Bytes(), &root); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !root.IsFolder || root.Count != 3 || root.NewCount != 1 || root.Size != 130 || root.Length != 180*60 || len(root.Children) != 2 {
+		t.Fatalf("unexpected root %+v", root)
+	}
+	serie := root.Children[0]
+	if !serie.IsFolder || serie.Path != "Serie" || serie.Count != 2 || len(serie.Children) != 2 || serie.Children[0].Folder != "Serie" || !serie.Children[0].New {
+		t.Fatalf("expected the Serie folder first, got %+v", serie)
+	}
+
+	rw = apiRequest(t, mux, http.MethodGet, "/api/v1/recordings/tree?folder=Serie", "guest", nil)
+	if err := json.Unmarshal(rw.Body.Bytes(), &root); err != nil || root.Path != "Serie" || root.Count != 2 {
+		t.Fatalf("unexpected folder %s (err=%v)", rw.Body.String(), err)
+	}
+
+	rw = apiRequest(t, mux, http.MethodGet, "/api/v1/recordings/tree?folder=Nope", "guest", nil)
+	if rw.Code != http.StatusNotFound || decodeAPIError(t, rw).Code != "not_found" {
+		t.Fatalf("expected 404 for an unknown folder, got %d: %s", rw.Code, rw.Body.String())
+	}
+}
+
+// Every /api/v1 route registered in SetupRoutes must be described in openapi.json.
+func TestAPI_OpenAPIDocumentCoversRoutes(t *testing.T) {
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPIDocument, &doc); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	if doc.OpenAPI == "" {
+		t.Fatalf("openapi.json: missing openapi version")
+	}
+
+	src, err := os.ReadFile(filepath.Join(repoRoot(t), "internal", "adapters", "primary", "http", "server.go"))
+	if err != nil {
+		t.Fatalf("read server.go: %v", err)
+	}
+	routes := regexp.MustCompile(`mux\.Handle\("([A-Z]+) (/api/v1/[^"]*)"`).FindAllStringSubmatch(string(src), -1)
+	if len(routes) == 0 {
+		t.Fatalf("no /api/v1 routes found in server.go")
+	}
+	for _, m := range routes {
+		method, path := strings.ToLower(m[1]), m[2]
+		if _, ok := doc.Paths[path][method]; !ok {
+			t.Errorf("route %s %s is missing from openapi.json", m[1], path)
+		}
+	}
+
+	_, mux := newAPITestServer(t, ports.NewMockVDRClient())
+	rw := apiRequest(t, mux, http.MethodGet, "/api/v1/openapi.json", "guest", nil)
+	if rw.Code != http.StatusOK || !json.Valid(rw.Body.Bytes()) {
+		t.Fatalf("expected served OpenAPI document, got %d", rw.Code)
+	}
+}
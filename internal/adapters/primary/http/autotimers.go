@@ -0,0 +1,443 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// autoTimerView is the template model for a single AutoTimer row/form.
+type autoTimerView struct {
+	domain.AutoTimer
+	SearchInValue string
+	TimeStartText string
+	TimeEndText   string
+	ChannelSet    map[string]bool
+	WeekdaySet    map[string]bool
+	ChannelLabel  string
+	WeekdayLabel  string
+	DoneCount     int
+}
+
+// autoTimerWeekdayKeys lists the weekday form keys in display order (Monday first).
+var autoTimerWeekdayKeys = []struct {
+	Key   string
+	Label string
+	Day   time.Weekday
+}{
+	{"wd_mon", "Mon", time.Monday},
+	{"wd_tue", "Tue", time.Tuesday},
+	{"wd_wed", "Wed", time.Wednesday},
+	{"wd_thu", "Thu", time.Thursday},
+	{"wd_fri", "Fri", time.Friday},
+	{"wd_sat", "Sat", time.Saturday},
+	{"wd_sun", "Sun", time.Sunday},
+}
+
+func autoTimerSearchInValue(scope domain.SearchScope) string {
+	switch scope {
+	case domain.SearchTitleSubtitle:
+		return "title_subtitle"
+	case domain.SearchAll:
+		return "all"
+	default:
+		return "title"
+	}
+}
+
+func newAutoTimerView(at domain.AutoTimer, nameByID map[string]string) autoTimerView {
+	v := autoTimerView{
+		AutoTimer:     at,
+		SearchInValue: autoTimerSearchInValue(at.SearchIn),
+		ChannelSet:    map[string]bool{},
+		WeekdaySet:    map[string]bool{},
+	}
+	if at.TimeStart != nil {
+		v.TimeStartText = at.TimeStart.Format("15:04")
+	}
+	if at.TimeEnd != nil {
+		v.TimeEndText = at.TimeEnd.Format("15:04")
+	}
+
+	labels := make([]string, 0, len(at.ChannelFilter))
+	for _, id := range at.ChannelFilter {
+		v.ChannelSet[id] = true
+		if n := nameByID[id]; n != "" {
+			labels = append(labels, n)
+		} else {
+			labels = append(labels, id)
+		}
+	}
+	if len(labels) == 0 {
+		v.ChannelLabel = "Any"
+	} else {
+		v.ChannelLabel = strings.Join(labels, ", ")
+	}
+
+	days := make([]string, 0, len(at.DayOfWeek))
+	for _, wd := range autoTimerWeekdayKeys {
+		for _, d := range at.DayOfWeek {
+			if d == wd.Day {
+				v.WeekdaySet[wd.Key] = true
+				days = append(days, wd.Label)
+				break
+			}
+		}
+	}
+	if len(days) == 0 {
+		v.WeekdayLabel = "Any"
+	} else {
+		v.WeekdayLabel = strings.Join(days, ", ")
+	}
+
+	return v
+}
+
+// autoTimerFromForm parses the AutoTimer edit form.
+func autoTimerFromForm(form url.Values) (domain.AutoTimer, error) {
+	at := domain.AutoTimer{}
+	if v := strings.TrimSpace(form.Get("id")); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return at, errors.New("invalid id")
+		}
+		at.ID = id
+	}
+	at.Active = form.Get("active") == "on"
+	at.Pattern = strings.TrimSpace(form.Get("pattern"))
+	at.UseRegex = form.Get("use_regex") == "on"
+
+	switch strings.TrimSpace(form.Get("search_in")) {
+	case "", "title":
+		at.SearchIn = domain.SearchTitle
+	case "title_subtitle":
+		at.SearchIn = domain.SearchTitleSubtitle
+	case "all":
+		at.SearchIn = domain.SearchAll
+	default:
+		return at, errors.New("invalid search scope")
+	}
+
+	switch scope := domain.DedupScope(strings.TrimSpace(form.Get("dedup"))); scope {
+	case "":
+		at.Dedup = domain.DedupTimers
+	case domain.DedupAll, domain.DedupRecordings, domain.DedupTimers, domain.DedupNone:
+		at.Dedup = scope
+	default:
+		return at, errors.New("invalid dedup scope")
+	}
+
+	for _, id := range form["channels"] {
+		id = strings.TrimSpace(id)
+		if id != "" {
+			at.ChannelFilter = append(at.ChannelFilter, id)
+		}
+	}
+
+	parseClock := func(name, label string) (*time.Time, error) {
+		v := strings.TrimSpace(form.Get(name))
+		if v == "" {
+			return nil, nil
+		}
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return nil, errors.New("invalid " + label + " (expected HH:MM)")
+		}
+		return &t, nil
+	}
+	var err error
+	if at.TimeStart, err = parseClock("time_start", "start time"); err != nil {
+		return at, err
+	}
+	if at.TimeEnd, err = parseClock("time_end", "end time"); err != nil {
+		return at, err
+	}
+
+	for _, wd := range autoTimerWeekdayKeys {
+		if strings.TrimSpace(form.Get(wd.Key)) != "" {
+			at.DayOfWeek = append(at.DayOfWeek, wd.Day)
+		}
+	}
+
+	parseInt := func(name, label string) (int, error) {
+		v := strings.TrimSpace(form.Get(name))
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, errors.New("invalid " + label)
+		}
+		return n, nil
+	}
+	if at.Priority, err = parseInt("priority", "priority"); err != nil {
+		return at, err
+	}
+	if at.Lifetime, err = parseInt("lifetime", "lifetime"); err != nil {
+		return at, err
+	}
+	if at.MarginStart, err = parseInt("margin_start", "margin start"); err != nil {
+		return at, err
+	}
+	if at.MarginEnd, err = parseInt("margin_end", "margin end"); err != nil {
+		return at, err
+	}
+
+	return at, nil
+}
+
+func (h *Handler) autoTimerChannelNames(r *http.Request) ([]domain.Channel, map[string]string) {
+	channels, err := h.epgService.GetChannels(r.Context())
+	if err != nil {
+		channels = []domain.Channel{}
+	}
+	nameByID := make(map[string]string, len(channels))
+	for _, ch := range channels {
+		if ch.ID != "" {
+			nameByID[ch.ID] = ch.Name
+		}
+	}
+	return channels, nameByID
+}
+
+func (h *Handler) renderAutoTimerForm(w http.ResponseWriter, r *http.Request, at domain.AutoTimer, errMsg string) {
+	channels, nameByID := h.autoTimerChannelNames(r)
+	data := map[string]any{
+		"AutoTimer": newAutoTimerView(at, nameByID),
+		"Channels":  channels,
+		"Weekdays":  autoTimerWeekdayKeys,
+	}
+	if at.ID > 0 {
+		data["Heading"] = "Edit AutoTimer"
+		data["FormAction"] = "/autotimers/edit"
+	} else {
+		data["Heading"] = "New AutoTimer"
+		data["FormAction"] = "/autotimers/new"
+	}
+	if errMsg != "" {
+		data["Error"] = errMsg
+	}
+	h.renderTemplate(w, r, "autotimer_edit.html", data)
+}
+
+// AutoTimerList shows all AutoTimers and the outcome of the last run.
+func (h *Handler) AutoTimerList(w http.ResponseWriter, r *http.Request) {
+	_, nameByID := h.autoTimerChannelNames(r)
+
+	doneBySource := map[string]int{}
+	if h.dedup != nil {
+		for _, e := range h.dedup.Done() {
+			doneBySource[e.Source]++
+		}
+	}
+
+	autoTimers := h.autoTimerService.GetAutoTimers()
+	views := make([]autoTimerView, 0, len(autoTimers))
+	for _, at := range autoTimers {
+		v := newAutoTimerView(at, nameByID)
+		v.DoneCount = doneBySource[services.AutoTimerDoneSource(at.ID)]
+		views = append(views, v)
+	}
+
+	data := map[string]any{
+		"AutoTimers": views,
+		"LastRun":    h.autoTimerService.LastRun(),
+		"Message":    strings.TrimSpace(r.URL.Query().Get("msg")),
+		"Error":      strings.TrimSpace(r.URL.Query().Get("err")),
+	}
+	if h.cfg != nil {
+		data["Interval"] = h.cfg.AutoTimer.Interval
+	}
+	h.renderTemplate(w, r, "autotimers.html", data)
+}
+
+// AutoTimerNew renders the form for a new AutoTimer prefilled with the timer defaults.
+func (h *Handler) AutoTimerNew(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	priority, lifetime, marginStart, marginEnd := h.timerDefaults()
+	at := domain.AutoTimer{
+		Active:      true,
+		SearchIn:    domain.SearchTitle,
+		Pattern:     strings.TrimSpace(r.URL.Query().Get("pattern")),
+		Priority:    priority,
+		Lifetime:    lifetime,
+		MarginStart: marginStart,
+		MarginEnd:   marginEnd,
+	}
+	h.renderAutoTimerForm(w, r, at, "")
+}
+
+// AutoTimerCreate stores a new AutoTimer.
+func (h *Handler) AutoTimerCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	at, err := autoTimerFromForm(r.PostForm)
+	if err != nil {
+		h.renderAutoTimerForm(w, r, at, err.Error())
+		return
+	}
+	at.ID = 0
+
+	if _, err := h.autoTimerService.AddAutoTimer(at); err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			h.renderAutoTimerForm(w, r, at, err.Error())
+			return
+		}
+		http.Redirect(w, r, "/autotimers?err="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/autotimers?msg="+url.QueryEscape("Saved AutoTimer."), http.StatusSeeOther)
+}
+
+// AutoTimerEdit renders the form for an existing AutoTimer.
+func (h *Handler) AutoTimerEdit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, _ := strconv.Atoi(r.URL.Query().Get("id"))
+	if id <= 0 {
+		http.Error(w, "Invalid AutoTimer id", http.StatusBadRequest)
+		return
+	}
+	at, err := h.autoTimerService.GetAutoTimer(id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	h.renderAutoTimerForm(w, r, at, "")
+}
+
+// AutoTimerUpdate saves changes to an existing AutoTimer.
+func (h *Handler) AutoTimerUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	at, err := autoTimerFromForm(r.PostForm)
+	if err != nil {
+		h.renderAutoTimerForm(w, r, at, err.Error())
+		return
+	}
+	if at.ID <= 0 {
+		http.Error(w, "Invalid AutoTimer id", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.autoTimerService.UpdateAutoTimer(at); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			h.renderAutoTimerForm(w, r, at, err.Error())
+		case errors.Is(err, domain.ErrNotFound):
+			http.Error(w, "AutoTimer not found", http.StatusNotFound)
+		default:
+			http.Redirect(w, r, "/autotimers?err="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		}
+		return
+	}
+
+	http.Redirect(w, r, "/autotimers?msg="+url.QueryEscape("Saved AutoTimer."), http.StatusSeeOther)
+}
+
+// AutoTimerToggle enables or disables an AutoTimer.
+func (h *Handler) AutoTimerToggle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	id, _ := strconv.Atoi(r.PostForm.Get("id"))
+	if id <= 0 {
+		http.Error(w, "Invalid AutoTimer id", http.StatusBadRequest)
+		return
+	}
+	at, err := h.autoTimerService.GetAutoTimer(id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	if err := h.autoTimerService.SetAutoTimerActive(id, !at.Active); err != nil {
+		http.Redirect(w, r, "/autotimers?err="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	msg := "AutoTimer enabled."
+	if at.Active {
+		msg = "AutoTimer disabled."
+	}
+	http.Redirect(w, r, "/autotimers?msg="+url.QueryEscape(msg), http.StatusSeeOther)
+}
+
+// AutoTimerDelete removes an AutoTimer.
+func (h *Handler) AutoTimerDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	id, _ := strconv.Atoi(r.PostForm.Get("id"))
+	if id <= 0 {
+		http.Error(w, "Invalid AutoTimer id", http.StatusBadRequest)
+		return
+	}
+	if err := h.autoTimerService.DeleteAutoTimer(id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "AutoTimer not found", http.StatusNotFound)
+			return
+		}
+		http.Redirect(w, r, "/autotimers?err="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/autotimers?msg="+url.QueryEscape("Deleted AutoTimer."), http.StatusSeeOther)
+}
+
+// AutoTimerRun processes all active AutoTimers immediately.
+func (h *Handler) AutoTimerRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	created, err := h.autoTimerService.ProcessAutoTimers(r.Context())
+	if err != nil {
+		http.Redirect(w, r, "/autotimers?err="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	msg := "AutoTimers processed: no new timers."
+	if created == 1 {
+		msg = "AutoTimers processed: created 1 timer."
+	} else if created > 1 {
+		msg = "AutoTimers processed: created " + strconv.Itoa(created) + " timers."
+	}
+	http.Redirect(w, r, "/autotimers?msg="+url.QueryEscape(msg), http.StatusSeeOther)
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func newAutoTimerTestHandler(t *testing.T) (*Handler, *services.AutoTimerService) {
+	t.Helper()
+	mock := ports.NewMockVDRClient().WithChannels([]domain.Channel{{ID: "C-1-2-3", Number: 1, Name: "Das Erste"}})
+	epgSvc := services.NewEPGService(mock, 0)
+	timerSvc := services.NewTimerService(mock)
+	recSvc := services.NewRecordingService(mock, 0)
+	autoSvc := services.NewAutoTimerService(mock, timerSvc, epgSvc)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	h := NewHandler(logger, template.New("test"), epgSvc, timerSvc, recSvc, autoSvc)
+
+	templates := map[string]*template.Template{}
+	for _, page := range []string{"autotimers.html", "autotimer_edit.html"} {
+		templates[page] = template.Must(template.ParseFiles(
+			filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+			filepath.Join(repoRoot(t), "web", "templates", page),
+		))
+	}
+	h.SetTemplates(templates)
+	return h, autoSvc
+}
+
+func TestAutoTimerCreate_ParsesForm(t *testing.T) {
+	h, autoSvc := newAutoTimerTestHandler(t)
+
+	form := url.Values{}
+	form.Set("active", "on")
+	form.Set("pattern", "Tatort")
+	form.Set("search_in", "title_subtitle")
+	form.Add("channels", "C-1-2-3")
+	form.Set("time_start", "20:00")
+	form.Set("time_end", "22:30")
+	form.Set("wd_sun", "1")
+	form.Set("priority", "60")
+	form.Set("lifetime", "90")
+	form.Set("margin_start", "3")
+	form.Set("margin_end", "12")
+
+	req := httptest.NewRequest(http.MethodPost, "/autotimers/new", bytes.NewBufferString(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.AutoTimerCreate(w, req)
+
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("expected %d, got %d: %s", http.StatusSeeOther, w.Code, w.Body.String())
+	}
+	ats := autoSvc.GetAutoTimers()
+	if len(ats) != 1 {
+		t.Fatalf("expected 1 AutoTimer, got %d", len(ats))
+	}
+	at := ats[0]
+	if !at.Active || at.Pattern != "Tatort" || at.SearchIn != domain.SearchTitleSubtitle {
+		t.Fatalf("unexpected AutoTimer: %+v", at)
+	}
+	if len(at.ChannelFilter) != 1 || at.ChannelFilter[0] != "C-1-2-3" {
+		t.Fatalf("unexpected channel filter: %v", at.ChannelFilter)
+	}
+	if at.TimeStart == nil || at.TimeStart.Format("15:04") != "20:00" || at.TimeEnd == nil || at.TimeEnd.Format("15:04") != "22:30" {
+		t.Fatalf("unexpected time window: %v - %v", at.TimeStart, at.TimeEnd)
+	}
+	if len(at.DayOfWeek) != 1 || at.DayOfWeek[0] != time.Sunday {
+		t.Fatalf("unexpected weekdays: %v", at.DayOfWeek)
+	}
+	if at.Priority != 60 || at.Lifetime != 90 || at.MarginStart != 3 || at.MarginEnd != 12 {
+		t.Fatalf("unexpected timer settings: %+v", at)
+	}
+}
+
+func TestAutoTimerCreate_InvalidRegexRerendersForm(t *testing.T) {
+	h, autoSvc := newAutoTimerTestHandler(t)
+
+	form := url.Values{}
+	form.Set("pattern", "(")
+	form.Set("use_regex", "on")
+
+	req := httptest.NewRequest(http.MethodPost, "/autotimers/new", bytes.NewBufferString(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.AutoTimerCreate(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected form re-render with %d, got %d", http.StatusOK, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Error:") {
+		t.Fatalf("expected error message in form")
+	}
+	if got := len(autoSvc.GetAutoTimers()); got != 0 {
+		t.Fatalf("expected no AutoTimers, got %d", got)
+	}
+}
+
+func TestAutoTimerToggleAndDelete(t *testing.T) {
+	h, autoSvc := newAutoTimerTestHandler(t)
+	id, err := autoSvc.AddAutoTimer(domain.AutoTimer{Pattern: "News", Active: true})
+	if err != nil {
+		t.Fatalf("AddAutoTimer: %v", err)
+	}
+
+	post := func(handler http.HandlerFunc, path string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("id", strconv.Itoa(id))
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	if w := post(h.AutoTimerToggle, "/autotimers/toggle"); w.Code != http.StatusSeeOther {
+		t.Fatalf("toggle: expected %d, got %d", http.StatusSeeOther, w.Code)
+	}
+	at, err := autoSvc.GetAutoTimer(id)
+	if err != nil || at.Active {
+		t.Fatalf("expected AutoTimer to be disabled, got %+v (err=%v)", at, err)
+	}
+
+	// The list renders for everyone.
+	req := httptest.NewRequest(http.MethodGet, "/autotimers", nil)
+	w := httptest.NewRecorder()
+	h.AutoTimerList(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "News") {
+		t.Fatalf("expected list to render AutoTimer, got %d", w.Code)
+	}
+
+	if w := post(h.AutoTimerDelete, "/autotimers/delete"); w.Code != http.StatusSeeOther {
+		t.Fatalf("delete: expected %d, got %d", http.StatusSeeOther, w.Code)
+	}
+	if got := len(autoSvc.GetAutoTimers()); got != 0 {
+		t.Fatalf("expected AutoTimer to be deleted, got %d", got)
+	}
+}
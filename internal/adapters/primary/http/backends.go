@@ -0,0 +1,138 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+)
+
+// SetBackends sets the VDR backends whose timers, recordings and channels the
+// UI shows. Without it, the handler's own services form the only backend.
+func (h *Handler) SetBackends(b *services.Backends) {
+	h.backends = b
+}
+
+// backendSet returns the configured backends, or a single backend built from
+// the handler's services.
+func (h *Handler) backendSet() *services.Backends {
+	if h.backends != nil {
+		return h.backends
+	}
+	name := "main"
+	videoDir := ""
+	if h.cfg != nil {
+		if h.cfg.VDR.Name != "" {
+			name = h.cfg.VDR.Name
+		}
+		videoDir = h.cfg.VDR.VideoDir
+	}
+	return services.NewBackends(&services.Backend{
+		Name:       name,
+		VideoDir:   videoDir,
+		Client:     h.vdrClient,
+		EPG:        h.epgService,
+		Timers:     h.timerService,
+		Recordings: h.recordingService,
+	})
+}
+
+// backendName returns the backend named by the "backend" form or query value.
+func backendName(r *http.Request) string {
+	return strings.TrimSpace(r.FormValue("backend"))
+}
+
+// backendFromRequest returns the backend a change request targets; an empty
+// name selects the primary backend.
+func (h *Handler) backendFromRequest(r *http.Request) (*services.Backend, error) {
+	return h.backendSet().Get(backendName(r))
+}
+
+// backendsForView returns the backends a list page shows: the selected one, or
+// all of them when none is selected.
+func (h *Handler) backendsForView(r *http.Request) ([]*services.Backend, error) {
+	return h.backendSet().Select(backendName(r))
+}
+
+// isPrimaryBackend reports whether b is the primary backend.
+func (h *Handler) isPrimaryBackend(b *services.Backend) bool {
+	return b.Name == h.backendSet().Primary().Name
+}
+
+// dvbCardsFor returns the number of recording devices of b. The primary backend
+// follows the runtime configuration, which can change on the configuration page.
+func (h *Handler) dvbCardsFor(b *services.Backend) int {
+	if h.isPrimaryBackend(b) {
+		if h.cfg != nil && h.cfg.VDR.DVBCards > 0 {
+			return h.cfg.VDR.DVBCards
+		}
+		return 1
+	}
+	return b.Timers.DVBCards()
+}
+
+// videoDirFor returns the video directory of b.
+func (h *Handler) videoDirFor(b *services.Backend) string {
+	if h.isPrimaryBackend(b) && h.cfg != nil {
+		return h.cfg.VDR.VideoDir
+	}
+	return b.VideoDir
+}
+
+// addBackendData adds the backend selector data used by the list pages. It adds
+// nothing with a single backend, which hides the selector.
+func (h *Handler) addBackendData(data map[string]any, r *http.Request) {
+	set := h.backendSet()
+	if !set.Multiple() {
+		return
+	}
+	names := make([]string, 0, len(set.All()))
+	for _, b := range set.All() {
+		names = append(names, b.Name)
+	}
+	data["Backends"] = names
+	data["SelectedBackend"] = backendName(r)
+	data["PrimaryBackend"] = set.Primary().Name
+}
+
+// isPartialBackendError reports whether err only says that some of several
+// backends could not be reached.
+func isPartialBackendError(err error) bool {
+	var partial *services.PartialError
+	return errors.As(err, &partial)
+}
+
+// watchdogFor returns the timer watchdog of b. The primary backend falls back
+// to the watchdog set with SetTimerWatchdog.
+func (h *Handler) watchdogFor(b *services.Backend) *services.TimerWatchdog {
+	if b.Watchdog != nil {
+		return b.Watchdog
+	}
+	if h.isPrimaryBackend(b) {
+		return h.timerWatchdog
+	}
+	return nil
+}
+
+// watchChanges returns the timer watchdog changes of all backends, newest
+// first. With several backends, each change names its backend.
+func (h *Handler) watchChanges() []services.TimerWatchChange {
+	set := h.backendSet()
+	var out []services.TimerWatchChange
+	for _, b := range set.All() {
+		w := h.watchdogFor(b)
+		if w == nil {
+			continue
+		}
+		for _, c := range w.Changes() {
+			if set.Multiple() {
+				c.Backend = b.Name
+			}
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.After(out[j].At) })
+	return out
+}
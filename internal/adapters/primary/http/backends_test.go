@@ -0,0 +1,201 @@
+package http
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func newTestBackend(name string, client *ports.MockVDRClient, dvbCards int) *services.Backend {
+	epg := services.NewEPGService(client, 0)
+	timers := services.NewTimerService(client)
+	timers.SetDVBCards(dvbCards)
+	timers.SetChannelLister(epg)
+	return &services.Backend{
+		Name:       name,
+		Client:     client,
+		EPG:        epg,
+		Timers:     timers,
+		Recordings: services.NewRecordingService(client, 0),
+	}
+}
+
+// timerItem returns the markup of the timer list entry with the given title.
+func timerItem(t *testing.T, body, title string) string {
+	t.Helper()
+	i := strings.Index(body, "<h3>"+title+"</h3>")
+	if i < 0 {
+		t.Fatalf("timer %q not rendered", title)
+	}
+	start := strings.LastIndex(body[:i], `<div class="timer-item`)
+	end := strings.Index(body[i:], `<div class="timer-item`)
+	if end < 0 {
+		end = len(body) - i
+	}
+	return body[start : i+end]
+}
+
+func TestTimerList_MultipleBackendsSimulateConflictsPerBackend(t *testing.T) {
+	loc := time.Local
+	now := time.Now().In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	ch1 := domain.Channel{ID: "S19.2E-1-100-10", Number: 1, Name: "One"}
+	ch2 := domain.Channel{ID: "S19.2E-1-200-20", Number: 2, Name: "Two"}
+
+	// Two overlapping timers on different transponders fail with one device.
+	mainClient := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{ch1, ch2}).
+		WithTimers([]domain.Timer{
+			{ID: 1, Active: true, ChannelID: ch1.ID, Title: "Main A", Start: day.Add(20 * time.Hour), Stop: day.Add(21 * time.Hour)},
+			{ID: 2, Active: true, ChannelID: ch2.ID, Title: "Main B", Start: day.Add(20 * time.Hour), Stop: day.Add(21 * time.Hour)},
+		})
+	// The bedroom VDR records at the same time with its own tuner.
+	bedroomClient := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{{ID: ch2.ID, Number: 5, Name: "Two"}}).
+		WithTimers([]domain.Timer{
+			{ID: 1, Active: true, ChannelID: "5", Title: "Bedroom A", Start: day.Add(20 * time.Hour), Stop: day.Add(21 * time.Hour)},
+		})
+
+	mainBackend := newTestBackend("main", mainClient, 1)
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "timers.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, mainBackend.EPG, mainBackend.Timers, nil, nil)
+	h.SetTemplates(map[string]*template.Template{"timers.html": parsed})
+	h.SetConfig(&config.Config{VDR: config.VDRConfig{Name: "main", DVBCards: 1}}, "")
+	h.SetBackends(services.NewBackends(mainBackend, newTestBackend("bedroom", bedroomClient, 1)))
+
+	render := func(query string) string {
+		req := httptest.NewRequest(http.MethodGet, "/timers"+query, nil)
+		ctx := context.WithValue(req.Context(), "role", "admin")
+		rw := httptest.NewRecorder()
+		h.TimerList(rw, req.WithContext(ctx))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rw.Code)
+		}
+		return rw.Body.String()
+	}
+
+	body := render("")
+	if item := timerItem(t, body, "Main A"); !strings.Contains(item, "conflict") {
+		t.Fatalf("expected main timers to conflict: %s", item)
+	}
+	item := timerItem(t, body, "Bedroom A")
+	if strings.Contains(item, "conflict") {
+		t.Fatalf("expected the bedroom timer to have its own device: %s", item)
+	}
+	// Channel numbers are resolved with the bedroom VDR's channel list.
+	if !strings.Contains(item, `<span class="timer-channel">Two</span>`) || !strings.Contains(item, "backend=bedroom") {
+		t.Fatalf("expected bedroom channel name and backend links: %s", item)
+	}
+
+	body = render("?backend=bedroom")
+	if strings.Contains(body, "Main A") || !strings.Contains(body, "Bedroom A") {
+		t.Fatalf("expected only bedroom timers")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/timers?backend=kitchen", nil)
+	rw := httptest.NewRecorder()
+	h.TimerList(rw, req)
+	if rw.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown backend to be not found, got %d", rw.Code)
+	}
+}
+
+func TestTimerDelete_UsesSelectedBackend(t *testing.T) {
+	var mainDeleted, bedroomDeleted []int
+	mainClient := ports.NewMockVDRClient()
+	mainClient.DeleteTimerFunc = func(ctx context.Context, id int) error {
+		mainDeleted = append(mainDeleted, id)
+		return nil
+	}
+	bedroomClient := ports.NewMockVDRClient()
+	bedroomClient.DeleteTimerFunc = func(ctx context.Context, id int) error {
+		bedroomDeleted = append(bedroomDeleted, id)
+		return nil
+	}
+	mainBackend := newTestBackend("main", mainClient, 1)
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, mainBackend.EPG, mainBackend.Timers, nil, nil)
+	h.SetBackends(services.NewBackends(mainBackend, newTestBackend("bedroom", bedroomClient, 1)))
+
+	for _, target := range []string{"/timers?id=3&backend=bedroom", "/timers?id=4"} {
+		rw := httptest.NewRecorder()
+		h.TimerDelete(rw, httptest.NewRequest(http.MethodDelete, target, nil))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("DELETE %s: expected status 200, got %d", target, rw.Code)
+		}
+	}
+	if len(bedroomDeleted) != 1 || bedroomDeleted[0] != 3 || len(mainDeleted) != 1 || mainDeleted[0] != 4 {
+		t.Fatalf("expected deletes on the selected backend, got main %v, bedroom %v", mainDeleted, bedroomDeleted)
+	}
+}
+
+func TestChannels_MarksEventsScheduledOnAnyBackend(t *testing.T) {
+	loc := time.Local
+	now := time.Now().In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).Add(48 * time.Hour)
+
+	ch := domain.Channel{ID: "C-1-2-3", Number: 1, Name: "SWR BW HD"}
+	show := domain.EPGEvent{
+		EventID:       100,
+		ChannelID:     ch.ID,
+		ChannelNumber: ch.Number,
+		Title:         "Tatort",
+		Start:         day.Add(20 * time.Hour),
+		Stop:          day.Add(21*time.Hour + 30*time.Minute),
+	}
+
+	mainClient := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{ch}).
+		WithEPGEvents([]domain.EPGEvent{show})
+	// The bedroom VDR knows the channel under another number and has the timer.
+	bedroomClient := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{{ID: ch.ID, Number: 12, Name: ch.Name}}).
+		WithTimers([]domain.Timer{{ID: 1, Active: true, ChannelID: "12", Title: show.Title, Start: show.Start.Add(-2 * time.Minute), Stop: show.Stop.Add(10 * time.Minute)}})
+
+	mainBackend := newTestBackend("main", mainClient, 1)
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "channels.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, mainBackend.EPG, mainBackend.Timers, nil, nil)
+	h.SetTemplates(map[string]*template.Template{"channels.html": parsed})
+	h.SetBackends(services.NewBackends(mainBackend, newTestBackend("bedroom", bedroomClient, 1)))
+
+	render := func(query string) string {
+		req := httptest.NewRequest(http.MethodGet, "/channels?channel="+ch.ID+"&day="+day.Format("2006-01-02")+query, nil)
+		ctx := context.WithValue(req.Context(), "role", "admin")
+		rw := httptest.NewRecorder()
+		h.Channels(rw, req.WithContext(ctx))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rw.Code)
+		}
+		return rw.Body.String()
+	}
+
+	if body := render(""); !strings.Contains(body, "disabled>Scheduled</button>") {
+		t.Fatalf("expected the show to be scheduled by the bedroom timer")
+	}
+
+	body := render("&backend=main")
+	if strings.Contains(body, "disabled>Scheduled</button>") {
+		t.Fatalf("expected the show to be recordable on the main VDR")
+	}
+	if !strings.Contains(body, `<select name="backend" aria-label="Record on">`) || !strings.Contains(body, `<option value="bedroom"`) {
+		t.Fatalf("expected a choice of backends to record on")
+	}
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// maxChannelsConfSize bounds an uploaded channels.conf snapshot.
+const maxChannelsConfSize = 4 << 20
+
+// channelEditView is a channel as listed by the channel editor.
+type channelEditView struct {
+	Position int
+	domain.Channel
+}
+
+// ChannelsManage lists the channels of the primary VDR for renaming,
+// reordering and deleting them.
+func (h *Handler) ChannelsManage(w http.ResponseWriter, r *http.Request) {
+	if h.channels == nil {
+		http.Error(w, "VDR client not available", http.StatusServiceUnavailable)
+		return
+	}
+	data := map[string]any{
+		"Message": r.URL.Query().Get("msg"),
+		"Error":   r.URL.Query().Get("error"),
+	}
+	chs, err := h.channels.GetChannels(r.Context())
+	if err != nil {
+		h.logger.Error("channels fetch error", slog.Any("error", err))
+		data["HomeError"] = err.Error()
+	}
+	views := make([]channelEditView, 0, len(chs))
+	obsolete := 0
+	for i, ch := range chs {
+		if ch.Obsolete() {
+			obsolete++
+		}
+		views = append(views, channelEditView{Position: i + 1, Channel: ch})
+	}
+	data["Channels"] = views
+	data["ObsoleteCount"] = obsolete
+	if bs := h.backendSet(); bs.Multiple() {
+		data["PrimaryBackend"] = bs.Primary().Name
+	}
+	h.renderTemplate(w, r, "channels_manage.html", data)
+}
+
+// ChannelsManageSave renames, moves, deletes or adds channels ("op": rename,
+// move, delete or add) and shows the channel editor again.
+func (h *Handler) ChannelsManageSave(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.channels == nil {
+		http.Error(w, "VDR client not available", http.StatusServiceUnavailable)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	id := strings.TrimSpace(r.FormValue("id"))
+	switch op := r.FormValue("op"); op {
+	case "rename":
+		if err := h.channels.RenameChannel(ctx, id, r.FormValue("name")); err != nil {
+			h.finishChannelEdit(w, r, "", err)
+			return
+		}
+		h.finishChannelEdit(w, r, "Channel renamed.", nil)
+	case "move":
+		to, err := strconv.Atoi(strings.TrimSpace(r.FormValue("position")))
+		if err != nil {
+			err = fmt.Errorf("%w: invalid position", domain.ErrInvalidInput)
+		} else {
+			err = h.channels.MoveChannel(ctx, id, to)
+		}
+		h.finishChannelEdit(w, r, "", err)
+	case "delete":
+		deleted, err := h.channels.DeleteChannels(ctx, r.Form["ids"])
+		msg := ""
+		if deleted > 0 {
+			msg = fmt.Sprintf("%d channel(s) deleted.", deleted)
+		}
+		h.finishChannelEdit(w, r, msg, err)
+	case "add":
+		number, err := h.channels.CreateChannel(ctx, r.FormValue("settings"))
+		if err != nil {
+			h.finishChannelEdit(w, r, "", err)
+			return
+		}
+		h.finishChannelEdit(w, r, fmt.Sprintf("Channel %d added.", number), nil)
+	default:
+		http.Error(w, "Invalid operation", http.StatusBadRequest)
+	}
+}
+
+// finishChannelEdit shows the channel editor with msg and err, e.g. VDR's
+// refusal to delete some of the channels.
+func (h *Handler) finishChannelEdit(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	if err == nil {
+		h.redirectToChannelEditor(w, r, msg, "")
+		return
+	}
+	h.logger.Warn("channel edit failed", slog.Any("error", err))
+	errMsg := err.Error()
+	switch {
+	case errors.Is(err, domain.ErrChannelInUse):
+		errMsg += " (channels a timer records from cannot be deleted)"
+	case errors.Is(err, domain.ErrChannelsLocked):
+		errMsg += " (close the channel list on the VDR and try again)"
+	}
+	h.redirectToChannelEditor(w, r, msg, errMsg)
+}
+
+func (h *Handler) redirectToChannelEditor(w http.ResponseWriter, r *http.Request, msg, errMsg string) {
+	params := url.Values{}
+	if msg != "" {
+		params.Set("msg", msg)
+	}
+	if errMsg != "" {
+		params.Set("error", errMsg)
+	}
+	target := "/channels/manage"
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+	if r.Header.Get("HX-Request") != "" {
+		w.Header().Set("HX-Redirect", target)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// ChannelsExport downloads the channels of the primary VDR as channels.conf.
+func (h *Handler) ChannelsExport(w http.ResponseWriter, r *http.Request) {
+	if h.channels == nil {
+		http.Error(w, "VDR client not available", http.StatusServiceUnavailable)
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.channels.ExportChannels(r.Context(), &buf); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	name := "channels-" + h.now().Format("20060102-150405") + ".conf"
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// ChannelsImport restores an uploaded channels.conf snapshot on the primary VDR.
+func (h *Handler) ChannelsImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.channels == nil {
+		http.Error(w, "VDR client not available", http.StatusServiceUnavailable)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxChannelsConfSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "channels.conf file required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	res, err := h.channels.ImportChannels(r.Context(), file)
+	if err != nil && res.Created+res.Updated+res.Deleted+res.Moved+len(res.Failed) == 0 {
+		h.finishChannelEdit(w, r, "", err)
+		return
+	}
+	msg := fmt.Sprintf("Snapshot restored: %d created, %d updated, %d deleted, %d moved.", res.Created, res.Updated, res.Deleted, res.Moved)
+	if res.Groups > 0 {
+		msg += fmt.Sprintf(" %d group separator(s) skipped; SVDRP cannot create them.", res.Groups)
+	}
+	h.logger.Info("channels snapshot restored",
+		slog.Int("created", res.Created), slog.Int("updated", res.Updated),
+		slog.Int("deleted", res.Deleted), slog.Int("moved", res.Moved), slog.Int("failed", len(res.Failed)))
+	h.finishChannelEdit(w, r, msg, errors.Join(append(res.Failed, err)...))
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+const (
+	channelsManageErste = "Das Erste HD;ARD:11494:HC23M5O35P0S1:S19.2E:22000:5101=27:5102=deu@3:5104:0:10301:1:1019:0"
+	channelsManageOld   = "Kinowelt OBSOLETE;Sky:11758:HC23M5O35P0S1:S19.2E:27500:255=2:256=deu@3:32:1702:29:133:6:0"
+)
+
+func newChannelsManageHandler(t *testing.T, mock *ports.MockVDRClient) *Handler {
+	t.Helper()
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "channels_manage.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, nil, nil, nil, nil)
+	h.SetTemplates(map[string]*template.Template{"channels_manage.html": parsed})
+	h.SetConfig(&config.Config{VDR: config.VDRConfig{Host: "localhost"}}, "")
+	h.SetVDRClient(mock)
+	return h
+}
+
+func channelsManageMock() *ports.MockVDRClient {
+	return ports.NewMockVDRClient().WithChannels([]domain.Channel{
+		{ID: "S19.2E-1-1019-10301", Number: 1, Name: "Das Erste HD", Provider: "ARD", Settings: channelsManageErste},
+		{ID: "S19.2E-133-6-29", Number: 2, Name: "Kinowelt OBSOLETE", Provider: "Sky", Settings: channelsManageOld},
+	})
+}
+
+func TestChannelsManage_ListsChannelsAndObsoleteOnes(t *testing.T) {
+	h := newChannelsManageHandler(t, channelsManageMock())
+
+	rw := httptest.NewRecorder()
+	h.ChannelsManage(rw, httptest.NewRequest(http.MethodGet, "/channels/manage", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	body := rw.Body.String()
+	for _, want := range []string{
+		`data-id="S19.2E-1-1019-10301"`,
+		"Select obsolete (1)",
+		`value="S19.2E-133-6-29" form="channels-delete" aria-label="Select Kinowelt OBSOLETE" data-obsolete="1"`,
+		`href="/channels/manage/export"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+}
+
+func TestChannelsManageSave_ShowsVDRRefusals(t *testing.T) {
+	mock := channelsManageMock().WithTimers([]domain.Timer{{ID: 1, ChannelID: "S19.2E-1-1019-10301"}})
+	h := newChannelsManageHandler(t, mock)
+
+	form := url.Values{"op": {"delete"}, "ids": {"S19.2E-1-1019-10301", "S19.2E-133-6-29"}}
+	req := httptest.NewRequest(http.MethodPost, "/channels/manage", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw := httptest.NewRecorder()
+	h.ChannelsManageSave(rw, req)
+
+	if rw.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect, got %d: %s", rw.Code, rw.Body.String())
+	}
+	loc, err := url.Parse(rw.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loc.Query().Get("msg"); got != "1 channel(s) deleted." {
+		t.Errorf("msg = %q", got)
+	}
+	if got := loc.Query().Get("error"); !strings.Contains(got, domain.ErrChannelInUse.Error()) {
+		t.Errorf("error = %q, want the channel in use", got)
+	}
+	chs, _ := mock.GetChannels(req.Context())
+	if len(chs) != 1 || chs[0].Name != "Das Erste HD" {
+		t.Fatalf("channels = %+v", chs)
+	}
+}
+
+func TestChannelsExportAndImport(t *testing.T) {
+	mock := channelsManageMock()
+	h := newChannelsManageHandler(t, mock)
+
+	rw := httptest.NewRecorder()
+	h.ChannelsExport(rw, httptest.NewRequest(http.MethodGet, "/channels/manage/export", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("export: expected 200, got %d", rw.Code)
+	}
+	if cd := rw.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, `attachment; filename="channels-`) {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	if !strings.Contains(rw.Body.String(), "\n"+channelsManageErste+"\n"+channelsManageOld+"\n") {
+		t.Fatalf("export lacks the channels:\n%s", rw.Body.String())
+	}
+
+	// Rename a channel, then roll back to the export.
+	snapshot := rw.Body.Bytes()
+	if err := mock.UpdateChannel(t.Context(), 1, strings.Replace(channelsManageErste, "Das Erste HD", "Erste", 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "channels.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write(snapshot)
+	_ = mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/channels/manage/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	rw = httptest.NewRecorder()
+	h.ChannelsImport(rw, req)
+
+	if rw.Code != http.StatusSeeOther {
+		t.Fatalf("import: expected a redirect, got %d: %s", rw.Code, rw.Body.String())
+	}
+	loc, _ := url.Parse(rw.Header().Get("Location"))
+	if got := loc.Query().Get("msg"); got != "Snapshot restored: 0 created, 1 updated, 0 deleted, 0 moved." {
+		t.Errorf("msg = %q", got)
+	}
+	chs, _ := mock.GetChannels(req.Context())
+	if chs[0].Settings != channelsManageErste {
+		t.Fatalf("settings not restored: %q", chs[0].Settings)
+	}
+}
@@ -188,6 +188,21 @@ func (m *channelsEPGAtSpyVDRMock) GetRecordingDir(ctx context.Context, recording
 	return "", nil
 }
 func (m *channelsEPGAtSpyVDRMock) DeleteRecording(ctx context.Context, path string) error { return nil }
+func (m *channelsEPGAtSpyVDRMock) MoveRecording(ctx context.Context, path, newName string) error {
+	return nil
+}
+func (m *channelsEPGAtSpyVDRMock) CutRecording(ctx context.Context, path string) error { return nil }
+func (m *channelsEPGAtSpyVDRMock) UpdateChannel(ctx context.Context, number int, settings string) error {
+	return nil
+}
+func (m *channelsEPGAtSpyVDRMock) MoveChannel(ctx context.Context, number, to int) error { return nil }
+func (m *channelsEPGAtSpyVDRMock) CreateChannel(ctx context.Context, settings string) (int, error) {
+	return 0, nil
+}
+func (m *channelsEPGAtSpyVDRMock) DeleteChannel(ctx context.Context, number int) error { return nil }
+func (m *channelsEPGAtSpyVDRMock) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error {
+	return nil
+}
 func (m *channelsEPGAtSpyVDRMock) GetCurrentChannel(ctx context.Context) (string, error) {
 	return "", nil
 }
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+type connectionStateClient struct {
+	*ports.MockVDRClient
+	state ports.ConnectionState
+}
+
+func (c *connectionStateClient) ConnectionState() ports.ConnectionState { return c.state }
+
+func TestConfigurations_ShowsVDRConnectionState(t *testing.T) {
+	tmpl := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "configurations.html"),
+	))
+
+	disconnected := time.Date(2026, 3, 1, 20, 15, 0, 0, time.Local)
+	client := &connectionStateClient{
+		MockVDRClient: ports.NewMockVDRClient(),
+		state: ports.ConnectionState{
+			Address:              "vdr:6419",
+			IdleTimeout:          10 * time.Second,
+			LastDisconnect:       disconnected,
+			LastDisconnectReason: "idle",
+			BusyCount:            2,
+			LastBusy:             disconnected.Add(-time.Minute),
+		},
+	}
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), tmpl, nil, nil, nil, nil)
+	cfg := &config.Config{}
+	cfg.VDR.IdleTimeout = 10 * time.Second
+	h.SetConfig(cfg, "")
+	h.SetVDRClient(client)
+
+	data := h.configurationsBaseData(httptest.NewRequest("GET", "/configurations", nil))
+	data["Role"] = "admin"
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "configurations.html", data); err != nil {
+		t.Fatalf("execute template: %v", err)
+	}
+	html := buf.String()
+	mustContain(t, html, `name="vdr_idle_timeout" type="text" value="10s"`)
+	mustContain(t, html, "Not connected</strong> to vdr:6419")
+	mustContain(t, html, "Last disconnect: 2026-03-01 20:15:00 (idle)")
+	mustContain(t, html, "busy with another SVDRP client 2 time(s)")
+}
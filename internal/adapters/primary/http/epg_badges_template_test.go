@@ -0,0 +1,73 @@
+package http
+
+import (
+	"bytes"
+	"html/template"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+func TestTemplate_EPGBadges(t *testing.T) {
+	start := time.Date(2026, 1, 4, 20, 15, 0, 0, time.Local)
+	vps := start
+	hd := domain.EPGEvent{
+		EventID:        1,
+		ChannelID:      "C-1-2-3",
+		ChannelName:    "Das Erste HD",
+		Title:          "Tatort",
+		Start:          start,
+		Stop:           start.Add(90 * time.Minute),
+		VPS:            &vps,
+		Video:          domain.VideoInfo{Format: "16:9", HD: true},
+		Audio:          []domain.AudioInfo{{Language: "deu", Channels: 2}, {Language: "deu", Channels: 6}, {Language: "eng", Channels: 2}},
+		Genres:         []string{"Movie/Drama", "Detective/Thriller"},
+		ParentalRating: 12,
+	}
+	plain := domain.EPGEvent{EventID: 2, ChannelID: "C-1-2-3", Title: "Plain", Start: start, Stop: start.Add(time.Hour)}
+
+	t.Run("event", func(t *testing.T) {
+		tmpl := template.Must(template.ParseFiles(
+			filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+			filepath.Join(repoRoot(t), "web", "templates", "event.html"),
+		))
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "event.html", map[string]any{"Event": &hd, "ThemeMode": "system"}); err != nil {
+			t.Fatalf("execute template: %v", err)
+		}
+		html := buf.String()
+		mustContain(t, html, `<span class="badge badge-epg">HD</span>`)
+		mustContain(t, html, `<span class="badge badge-epg">16:9</span>`)
+		mustContain(t, html, `<span class="badge badge-epg">5.1</span>`)
+		mustContain(t, html, `<span class="badge badge-epg badge-lang">eng</span>`)
+		mustContain(t, html, "Movie/Drama, Detective/Thriller")
+		mustContain(t, html, "Age 12+")
+		mustContain(t, html, "VPS 20:15")
+		if n := strings.Count(html, `badge-lang">deu<`); n != 1 {
+			t.Fatalf("expected language badge once, got %d", n)
+		}
+	})
+
+	t.Run("search results", func(t *testing.T) {
+		tmpl := template.Must(template.ParseFiles(filepath.Join(repoRoot(t), "web", "templates", "search_results.html")))
+		data := map[string]any{
+			"Query": "x",
+			"DayGroups": []struct {
+				DayLabel string
+				Events   []domain.EPGEvent
+			}{{DayLabel: "Sun 2026-01-04", Events: []domain.EPGEvent{hd, plain}}},
+		}
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, "search_results.html", data); err != nil {
+			t.Fatalf("execute template: %v", err)
+		}
+		html := buf.String()
+		if n := strings.Count(html, `class="epg-badges"`); n != 1 {
+			t.Fatalf("expected badges only for the HD event, got %d", n)
+		}
+		mustContain(t, html, `<span class="badge badge-epg">HD</span>`)
+	})
+}
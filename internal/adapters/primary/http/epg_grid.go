@@ -0,0 +1,308 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+const (
+	// gridChannelBlock is the number of channels shown per grid page.
+	gridChannelBlock = 15
+	gridDefaultHours = 4
+	gridMaxHours     = 12
+	// gridSlotMinutes is the width of a time column; windows start on a slot.
+	gridSlotMinutes = 30
+	// gridHourWidth is the width of an hour in pixels; wider windows scroll.
+	gridHourWidth = 300
+)
+
+// gridSlot is a time column header of the grid.
+type gridSlot struct {
+	Label   string
+	LeftPct float64
+}
+
+// gridBlock is an event of the grid, positioned within the time window.
+type gridBlock struct {
+	domain.EPGEvent
+	LeftPct  float64
+	WidthPct float64
+	// State is "critical" or "collision" for events whose timer is in a
+	// conflict, "scheduled" for other events with a timer and "" otherwise.
+	State   string
+	Running bool
+	// ClippedStart and ClippedStop mark events reaching beyond the window.
+	ClippedStart bool
+	ClippedStop  bool
+}
+
+// gridRow is a channel of the grid.
+type gridRow struct {
+	Channel domain.Channel
+	Blocks  []gridBlock
+}
+
+// EPGGrid renders the TV-guide grid: channels as rows, time as columns and
+// events as blocks sized by their duration. It pages through time windows
+// ("start", "hours") and blocks of channels ("offset").
+func (h *Handler) EPGGrid(w http.ResponseWriter, r *http.Request) {
+	loc := time.Local
+	now := h.now().In(loc)
+	data := map[string]any{}
+	r = h.withFavourites(r, data)
+
+	from, hours, offset, err := parseGridParams(r, now, loc)
+	if err != nil {
+		data["HomeError"] = err.Error()
+	}
+	to := from.Add(time.Duration(hours) * time.Hour)
+	fav := favouriteName(r)
+
+	data["Start"] = from.Format("2006-01-02T15:04")
+	data["Hours"] = hours
+	data["HourOptions"] = []int{2, 3, 4, 6, 8, 12}
+	data["Offset"] = offset
+	data["WindowLabel"] = from.Format("Mon 2006-01-02 15:04") + " - " + to.Format("15:04")
+	data["GridWidth"] = hours * gridHourWidth
+	data["EarlierURL"] = gridURL(from.Add(-time.Duration(hours)*time.Hour), hours, offset, fav)
+	data["LaterURL"] = gridURL(to, hours, offset, fav)
+	data["NowURL"] = gridURL(time.Time{}, hours, offset, fav)
+	if !now.Before(from) && now.Before(to) {
+		data["NowPct"] = gridPct(now.Sub(from), to.Sub(from))
+	}
+
+	slots := make([]gridSlot, 0, hours*60/gridSlotMinutes)
+	for t := from; t.Before(to); t = t.Add(gridSlotMinutes * time.Minute) {
+		slots = append(slots, gridSlot{Label: t.Format("15:04"), LeftPct: gridPct(t.Sub(from), to.Sub(from))})
+	}
+	data["Slots"] = slots
+
+	channels, err := h.epgService.GetChannels(r.Context())
+	if err != nil {
+		h.logger.Error("channels fetch error on grid", slog.Any("error", err))
+		data["HomeError"] = err.Error()
+		h.renderTemplate(w, r, "grid.html", data)
+		return
+	}
+	if offset >= len(channels) {
+		offset = max(0, (len(channels)-1)/gridChannelBlock*gridChannelBlock)
+		data["Offset"] = offset
+	}
+	block := channels[offset:min(offset+gridChannelBlock, len(channels))]
+	if offset > 0 {
+		data["PrevBlockURL"] = gridURL(from, hours, max(0, offset-gridChannelBlock), fav)
+	}
+	if offset+gridChannelBlock < len(channels) {
+		data["NextBlockURL"] = gridURL(from, hours, offset+gridChannelBlock, fav)
+	}
+	if len(block) > 0 {
+		data["BlockLabel"] = fmt.Sprintf("Channels %d-%d of %d", offset+1, offset+len(block), len(channels))
+	}
+
+	ids := make([]string, 0, len(block))
+	for _, ch := range block {
+		ids = append(ids, ch.ID)
+	}
+	window, err := h.epgService.GetEPGWindow(r.Context(), ids, from, to)
+	if err != nil {
+		h.logger.Error("EPG fetch error on grid", slog.Any("error", err))
+		data["HomeError"] = err.Error()
+		h.renderTemplate(w, r, "grid.html", data)
+		return
+	}
+
+	timerState := h.gridTimerStates(r.Context(), channels, from, to, loc)
+	rows := make([]gridRow, 0, len(block))
+	for _, ch := range block {
+		row := gridRow{Channel: ch}
+		for _, ev := range window[ch.ID] {
+			if ev.ChannelNumber <= 0 {
+				ev.ChannelNumber = ch.Number
+			}
+			if strings.TrimSpace(ev.ChannelName) == "" {
+				ev.ChannelName = ch.Name
+			}
+			start, stop := ev.Start, ev.Stop
+			if start.Before(from) {
+				start = from
+			}
+			if stop.After(to) {
+				stop = to
+			}
+			row.Blocks = append(row.Blocks, gridBlock{
+				EPGEvent:     ev,
+				LeftPct:      gridPct(start.Sub(from), to.Sub(from)),
+				WidthPct:     gridPct(stop.Sub(start), to.Sub(from)),
+				State:        timerState(ev),
+				Running:      !now.Before(ev.Start) && now.Before(ev.Stop),
+				ClippedStart: ev.Start.Before(from),
+				ClippedStop:  ev.Stop.After(to),
+			})
+		}
+		rows = append(rows, row)
+	}
+	data["Rows"] = rows
+	h.renderTemplate(w, r, "grid.html", data)
+}
+
+// parseGridParams returns the grid window start (rounded down to a slot),
+// its length in hours and the channel offset.
+func parseGridParams(r *http.Request, now time.Time, loc *time.Location) (from time.Time, hours, offset int, err error) {
+	q := r.URL.Query()
+	from = now
+	if v := strings.TrimSpace(q.Get("start")); v != "" {
+		t, perr := time.ParseInLocation("2006-01-02T15:04", v, loc)
+		if perr != nil {
+			err = fmt.Errorf("invalid start (expected YYYY-MM-DDTHH:MM)")
+		} else {
+			from = t
+		}
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), from.Hour(), from.Minute()/gridSlotMinutes*gridSlotMinutes, 0, 0, loc)
+
+	hours = gridDefaultHours
+	if v := strings.TrimSpace(q.Get("hours")); v != "" {
+		n, perr := strconv.Atoi(v)
+		if perr != nil || n < 1 || n > gridMaxHours {
+			if err == nil {
+				err = fmt.Errorf("invalid hours (expected 1-%d)", gridMaxHours)
+			}
+		} else {
+			hours = n
+		}
+	}
+
+	if v := strings.TrimSpace(q.Get("offset")); v != "" {
+		if n, perr := strconv.Atoi(v); perr == nil && n > 0 {
+			offset = n
+		}
+	}
+	return from, hours, offset, err
+}
+
+// gridURL links to a grid page; a zero start means now.
+func gridURL(start time.Time, hours, offset int, fav string) string {
+	q := url.Values{}
+	if !start.IsZero() {
+		q.Set("start", start.Format("2006-01-02T15:04"))
+	}
+	if hours != gridDefaultHours {
+		q.Set("hours", strconv.Itoa(hours))
+	}
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+	if fav != "" {
+		q.Set("fav", fav)
+	}
+	if len(q) == 0 {
+		return "/grid"
+	}
+	return "/grid?" + q.Encode()
+}
+
+func gridPct(d, window time.Duration) float64 {
+	if window <= 0 {
+		return 0
+	}
+	return float64(d) / float64(window) * 100
+}
+
+// gridTimerStates indexes the timers around the window and returns a function
+// telling the timer state of an event (see gridBlock.State). Conflicts are
+// those the device simulation finds within the window.
+func (h *Handler) gridTimerStates(ctx context.Context, channels []domain.Channel, from, to time.Time, loc *time.Location) func(domain.EPGEvent) string {
+	none := func(domain.EPGEvent) string { return "" }
+	if h.timerService == nil {
+		return none
+	}
+	timers, err := h.timerService.GetAllTimers(ctx)
+	if err != nil {
+		h.logger.Warn("timers fetch error for grid", slog.Any("error", err))
+		return none
+	}
+
+	numberByID := make(map[string]int, len(channels))
+	idByNumber := make(map[int]string, len(channels))
+	for _, ch := range channels {
+		if ch.ID != "" {
+			numberByID[ch.ID] = ch.Number
+		}
+		if ch.Number > 0 && ch.ID != "" {
+			idByNumber[ch.Number] = ch.ID
+		}
+	}
+
+	timersByID := map[int]domain.Timer{}
+	occByChannelID := map[string][]timerOccurrence{}
+	occByChannelNumber := map[int][]timerOccurrence{}
+	for _, t := range timers {
+		if strings.TrimSpace(t.ChannelID) == "" {
+			continue
+		}
+		timersByID[t.ID] = t
+		occs := timerOccurrences(t, from.Add(-24*time.Hour), to.Add(24*time.Hour))
+		if len(occs) == 0 {
+			continue
+		}
+		occByChannelID[t.ChannelID] = append(occByChannelID[t.ChannelID], occs...)
+		n := numberByID[t.ChannelID]
+		if v, err := strconv.Atoi(strings.TrimSpace(t.ChannelID)); err == nil {
+			n = v
+		}
+		if n > 0 {
+			occByChannelNumber[n] = append(occByChannelNumber[n], occs...)
+		}
+	}
+	for _, occs := range occByChannelID {
+		sortTimerOccurrences(occs)
+	}
+	for _, occs := range occByChannelNumber {
+		sortTimerOccurrences(occs)
+	}
+
+	// The allocation covers the same widened window as the occurrences above,
+	// so recurring timers recording in the grid are not missed.
+	report := services.SimulateTimerAllocation(timers, services.AllocationOptions{
+		DVBCards: h.dvbCardsFor(h.backendSet().Primary()),
+		From:     from.Add(-24 * time.Hour),
+		To:       to.Add(24 * time.Hour),
+		Now:      h.now(),
+		TransponderKey: func(t domain.Timer) string {
+			return transponderKeyForTimer(t, channels)
+		},
+	})
+
+	return func(ev domain.EPGEvent) string {
+		t, ok := scheduledTimerForEvent(ev, loc, occByChannelNumber, occByChannelID, numberByID, idByNumber, timersByID)
+		switch {
+		case !ok:
+			return ""
+		case report.CriticalIDs[t.ID]:
+			return "critical"
+		case report.CollisionIDs[t.ID]:
+			return "collision"
+		default:
+			return "scheduled"
+		}
+	}
+}
+
+func sortTimerOccurrences(occs []timerOccurrence) {
+	sort.SliceStable(occs, func(i, j int) bool {
+		if occs[i].Start.Equal(occs[j].Start) {
+			return occs[i].TimerID < occs[j].TimerID
+		}
+		return occs[i].Start.Before(occs[j].Start)
+	})
+}
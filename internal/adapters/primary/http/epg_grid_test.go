@@ -0,0 +1,167 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestEPGGrid_BlocksTimersAndPaging(t *testing.T) {
+	loc := time.Local
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, loc)
+
+	channels := make([]domain.Channel, 0, 20)
+	for i := 1; i <= 20; i++ {
+		channels = append(channels, domain.Channel{ID: fmt.Sprintf("S19.2E-1-%d-%d", 1000+i, i), Number: i, Name: fmt.Sprintf("Channel %d", i)})
+	}
+	events := []domain.EPGEvent{
+		{EventID: 1, ChannelID: channels[0].ID, Title: "Evening News", Start: base.Add(-15 * time.Minute), Stop: base.Add(45 * time.Minute)},
+		{EventID: 2, ChannelID: channels[0].ID, Title: "Feature Film", Start: base.Add(45 * time.Minute), Stop: base.Add(5 * time.Hour)},
+		{EventID: 3, ChannelID: channels[1].ID, Title: "Clashing Show", Start: base, Stop: base.Add(time.Hour)},
+		{EventID: 4, ChannelID: channels[2].ID, Title: "Late Talk", Start: base.Add(2 * time.Hour), Stop: base.Add(3 * time.Hour)},
+		{EventID: 5, ChannelID: channels[0].ID, Title: "Tomorrow", Start: base.Add(24 * time.Hour), Stop: base.Add(25 * time.Hour)},
+		{EventID: 6, ChannelID: channels[19].ID, Title: "Second Block Show", Start: base, Stop: base.Add(time.Hour)},
+	}
+	timers := []domain.Timer{
+		// Overlapping timers on different transponders with a single DVB card.
+		{ID: 1, Active: true, ChannelID: channels[0].ID, Title: "Evening News", Start: base.Add(-17 * time.Minute), Stop: base.Add(55 * time.Minute), Priority: 50},
+		{ID: 2, Active: true, ChannelID: channels[1].ID, Title: "Clashing Show", Start: base.Add(-2 * time.Minute), Stop: base.Add(70 * time.Minute), Priority: 50},
+		{ID: 3, Active: true, ChannelID: channels[2].ID, Title: "Late Talk", Start: base.Add(118 * time.Minute), Stop: base.Add(190 * time.Minute), Priority: 50},
+	}
+	mock := ports.NewMockVDRClient().WithChannels(channels).WithEPGEvents(events).WithTimers(timers)
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "grid.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, services.NewEPGService(mock, time.Minute), services.NewTimerService(mock), nil, nil)
+	h.SetTemplates(map[string]*template.Template{"grid.html": parsed})
+	h.SetConfig(&config.Config{VDR: config.VDRConfig{DVBCards: 1}}, "")
+	h.nowFunc = func() time.Time { return base.Add(10 * time.Minute) }
+
+	render := func(query string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/grid"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), "role", "admin"))
+		rw := httptest.NewRecorder()
+		h.EPGGrid(rw, req)
+		if rw.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+		}
+		return rw.Body.String()
+	}
+	block := func(body, title string) string {
+		t.Helper()
+		i := strings.Index(body, ">"+title+"</span>")
+		if i < 0 {
+			t.Fatalf("grid lacks %q", title)
+		}
+		start := strings.LastIndex(body[:i], `<div class="epg-grid-block`)
+		return body[start:i]
+	}
+
+	// Without a start the window begins at the current half hour.
+	body := render("")
+	if !strings.Contains(body, "Sun 2026-03-01 20:00 - 00:00") {
+		t.Fatalf("expected the 4 hour window from 20:00")
+	}
+	if strings.Contains(body, "Tomorrow") || strings.Contains(body, "Second Block Show") {
+		t.Fatalf("grid shows events outside the window or channel block")
+	}
+	news := block(body, "Evening News")
+	if !strings.Contains(news, "running") || !strings.Contains(news, "clipped-start") || !strings.Contains(news, "left: 0.0000%; width: 18.7500%;") {
+		t.Fatalf("news block = %s", news)
+	}
+	if !strings.Contains(news, " critical") && !strings.Contains(news, " collision") {
+		t.Fatalf("expected the overlapping timers to be highlighted: %s", news)
+	}
+	if talk := block(body, "Late Talk"); !strings.Contains(talk, "epg-grid-block scheduled") {
+		t.Fatalf("late talk block = %s", talk)
+	}
+	film := block(body, "Feature Film")
+	if !strings.Contains(film, "clipped-stop") || strings.Contains(film, "scheduled") {
+		t.Fatalf("film block = %s", film)
+	}
+	filmEnd := body[strings.Index(body, ">Feature Film</span>"):]
+	if !strings.Contains(filmEnd[:strings.Index(filmEnd, "</div>")], `action="/timers/create"`) {
+		t.Fatalf("expected a record button for the unscheduled film")
+	}
+	for _, want := range []string{
+		`href="/grid?start=2026-03-01T16%3A00"`,
+		`href="/grid?start=2026-03-02T00%3A00"`,
+		`href="/grid?offset=15&amp;start=2026-03-01T20%3A00"`,
+		"Channels 1-15 of 20",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("grid lacks %q", want)
+		}
+	}
+
+	// The second channel block shows the remaining channels.
+	body = render("?start=2026-03-01T20:10&offset=15&hours=2")
+	if !strings.Contains(body, "Second Block Show") || strings.Contains(body, "Evening News") {
+		t.Fatalf("second block shows the wrong channels")
+	}
+	if !strings.Contains(body, "Channels 16-20 of 20") || !strings.Contains(body, `href="/grid?hours=2&amp;start=2026-03-01T20%3A00"`) {
+		t.Fatalf("second block lacks its paging")
+	}
+}
+
+func TestEPGGrid_RecurringTimerConflicts(t *testing.T) {
+	loc := time.Local
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, loc)
+	channels := []domain.Channel{
+		{ID: "S19.2E-1-1001-1", Number: 1, Name: "Channel 1"},
+		{ID: "S19.2E-1-1002-2", Number: 2, Name: "Channel 2"},
+	}
+	mock := ports.NewMockVDRClient().WithChannels(channels).
+		WithEPGEvents([]domain.EPGEvent{
+			{EventID: 1, ChannelID: channels[0].ID, Title: "Daily News", Start: base, Stop: base.Add(30 * time.Minute)},
+			{EventID: 2, ChannelID: channels[1].ID, Title: "Clashing Show", Start: base, Stop: base.Add(time.Hour)},
+		}).
+		WithTimers([]domain.Timer{
+			{ID: 1, Active: true, ChannelID: channels[0].ID, Title: "Daily News", DaySpec: "MTWTFSS", StartMinutes: 20*60 - 2, StopMinutes: 20*60 + 40, Priority: 50},
+			{ID: 2, Active: true, ChannelID: channels[1].ID, Title: "Clashing Show", Start: base.Add(-2 * time.Minute), Stop: base.Add(70 * time.Minute), Priority: 50},
+		})
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "grid.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, services.NewEPGService(mock, time.Minute), services.NewTimerService(mock), nil, nil)
+	h.SetTemplates(map[string]*template.Template{"grid.html": parsed})
+	h.SetConfig(&config.Config{VDR: config.VDRConfig{DVBCards: 1}}, "")
+	h.nowFunc = func() time.Time { return base.Add(-time.Hour) }
+
+	// The window ends on the day the recurring timer records, so the
+	// allocation has to look beyond it to see the collision.
+	req := httptest.NewRequest(http.MethodGet, "/grid?start=2026-03-01T20:00&hours=2", nil)
+	req = req.WithContext(context.WithValue(req.Context(), "role", "admin"))
+	rw := httptest.NewRecorder()
+	h.EPGGrid(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	body := rw.Body.String()
+	i := strings.Index(body, ">Clashing Show</span>")
+	if i < 0 {
+		t.Fatalf("grid lacks the clashing show")
+	}
+	show := body[strings.LastIndex(body[:i], `<div class="epg-grid-block`):i]
+	if !strings.Contains(show, " critical") && !strings.Contains(show, " collision") {
+		t.Fatalf("expected the collision with the recurring timer: %s", show)
+	}
+}
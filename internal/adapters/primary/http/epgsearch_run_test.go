@@ -52,7 +52,22 @@ func (m *epgsearchRunVDRMock) GetRecordingDir(ctx context.Context, recordingID s
 	return "", nil
 }
 func (m *epgsearchRunVDRMock) DeleteRecording(ctx context.Context, path string) error { return nil }
-func (m *epgsearchRunVDRMock) GetCurrentChannel(ctx context.Context) (string, error)  { return "", nil }
+func (m *epgsearchRunVDRMock) MoveRecording(ctx context.Context, path, newName string) error {
+	return nil
+}
+func (m *epgsearchRunVDRMock) CutRecording(ctx context.Context, path string) error { return nil }
+func (m *epgsearchRunVDRMock) UpdateChannel(ctx context.Context, number int, settings string) error {
+	return nil
+}
+func (m *epgsearchRunVDRMock) MoveChannel(ctx context.Context, number, to int) error { return nil }
+func (m *epgsearchRunVDRMock) CreateChannel(ctx context.Context, settings string) (int, error) {
+	return 0, nil
+}
+func (m *epgsearchRunVDRMock) DeleteChannel(ctx context.Context, number int) error { return nil }
+func (m *epgsearchRunVDRMock) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error {
+	return nil
+}
+func (m *epgsearchRunVDRMock) GetCurrentChannel(ctx context.Context) (string, error) { return "", nil }
 func (m *epgsearchRunVDRMock) SetCurrentChannel(ctx context.Context, channelID string) error {
 	return nil
 }
@@ -285,3 +300,69 @@ func TestEPGSearchEdit_RunRendersMatches(t *testing.T) {
 	mustContain(t, body, ev.Title)
 	mustContain(t, body, "value=\"Heinz\"")
 }
+
+func TestEPGSearchNew_RunAppliesFilters(t *testing.T) {
+	loc := time.Local
+	ch := domain.Channel{ID: "C-1-2-3", Number: 1, Name: "SWR BW HD"}
+	event := func(id int, title string, start time.Time) domain.EPGEvent {
+		return domain.EPGEvent{EventID: id, ChannelID: ch.ID, ChannelNumber: ch.Number, ChannelName: ch.Name, Title: title, Start: start, Stop: start.Add(45 * time.Minute)}
+	}
+	mock := &epgsearchRunVDRMock{
+		channels: []domain.Channel{ch},
+		epq: []domain.EPGEvent{
+			// 2026-01-07 is a Wednesday.
+			event(1, "Heinz Becker am Abend", time.Date(2026, 1, 7, 23, 30, 0, 0, loc)),
+			event(2, "Heinz Becker (Wiederholung)", time.Date(2026, 1, 8, 0, 30, 0, 0, loc)),
+			event(3, "Heinz Becker am Nachmittag", time.Date(2026, 1, 7, 15, 0, 0, 0, loc)),
+			event(4, "Heinz Becker am Donnerstag", time.Date(2026, 1, 8, 23, 15, 0, 0, loc)),
+		},
+	}
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "epgsearch_edit.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, services.NewEPGService(mock, 0), services.NewTimerService(mock), nil, nil)
+	h.SetTemplates(map[string]*template.Template{"epgsearch_edit.html": parsed})
+	h.nowFunc = func() time.Time { return time.Date(2026, 1, 7, 12, 0, 0, 0, loc) }
+
+	form := url.Values{}
+	form.Set("action", "run")
+	form.Set("pattern", "Heinz")
+	form.Set("mode", "phrase")
+	form.Set("use_channel", "no")
+	form.Set("exclude", "wiederholung")
+	form.Set("start_from", "23:00")
+	form.Set("start_to", "01:00")
+	form["weekdays"] = []string{"wed"}
+	form.Set("horizon_days", "7")
+
+	req := httptest.NewRequest(http.MethodPost, "/epgsearch/new", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(context.WithValue(req.Context(), "role", "admin"))
+	rw := httptest.NewRecorder()
+	h.EPGSearchCreate(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rw.Code)
+	}
+
+	body := rw.Body.String()
+	mustContain(t, body, "Heinz Becker am Abend")
+	for _, excluded := range []string{"Wiederholung", "Nachmittag", "Donnerstag"} {
+		if strings.Contains(body, "<h4>Heinz Becker "+excluded) || strings.Contains(body, "<h4>Heinz Becker ("+excluded) {
+			t.Fatalf("filtered event %q is listed", excluded)
+		}
+	}
+	mustContain(t, body, `name="weekdays" value="wed" checked`)
+	mustContain(t, body, `value="wiederholung"`)
+
+	// Invalid filters are reported instead of running the search.
+	form.Set("min_duration", "90")
+	form.Set("max_duration", "30")
+	req = httptest.NewRequest(http.MethodPost, "/epgsearch/new", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req = req.WithContext(context.WithValue(req.Context(), "role", "admin"))
+	rw = httptest.NewRecorder()
+	h.EPGSearchCreate(rw, req)
+	mustContain(t, rw.Body.String(), "Invalid search: min_duration 90 exceeds max_duration 30")
+}
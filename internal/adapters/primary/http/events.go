@@ -0,0 +1,106 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/events"
+)
+
+// sseHeartbeat is the interval of keep-alive comments on idle event streams.
+const sseHeartbeat = 25 * time.Second
+
+// Events streams state changes as Server-Sent Events.
+// The optional ?types= parameter is a comma-separated list of event types
+// (e.g. "timer.created") or groups (e.g. "archive") to receive.
+func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil {
+		http.Error(w, "Event stream not available", http.StatusServiceUnavailable)
+		return
+	}
+	match := sseTypeFilter(r.URL.Query().Get("types"))
+
+	rc := http.NewResponseController(w)
+	// Event streams outlive the server write timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub := h.events.Subscribe(0)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if err := rc.Flush(); err != nil {
+		h.logger.Debug("event stream not flushable", slog.Any("error", err))
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.streamsDone:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			if !match(ev.Type) {
+				continue
+			}
+			if err := writeSSEEvent(w, ev); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, ev events.Event) error {
+	data, err := json.Marshal(map[string]any{
+		"type": ev.Type,
+		"time": ev.Time,
+		"data": ev.Data,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+	return err
+}
+
+// sseTypeFilter returns a matcher for the comma-separated list of event types or
+// groups (the part before the dot). An empty list matches all events.
+func sseTypeFilter(raw string) func(events.Type) bool {
+	wanted := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			wanted[part] = true
+		}
+	}
+	if len(wanted) == 0 {
+		return func(events.Type) bool { return true }
+	}
+	return func(t events.Type) bool {
+		if wanted[string(t)] {
+			return true
+		}
+		group, _, _ := strings.Cut(string(t), ".")
+		return wanted[group]
+	}
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"html/template"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/events"
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestEvents_StreamsFilteredEvents(t *testing.T) {
+	mock := ports.NewMockVDRClient()
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), template.New("empty"), services.NewEPGService(mock, 0), services.NewTimerService(mock), nil, nil)
+	bus := events.NewBus()
+	h.SetEventBus(bus)
+
+	srv := httptest.NewServer(SetupRoutes(h, &config.AuthConfig{}, slog.New(slog.NewTextHandler(io.Discard, nil))))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events?types=timer,channel.changed", nil)
+	// Compression must not buffer the stream.
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	for deadline := time.Now().Add(time.Second); bus.SubscriberCount() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("handler did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	bus.Publish(events.ArchiveLog, events.ArchiveLogPayload{JobID: "1", Line: "ignored"})
+	bus.Publish(events.TimerDeleted, events.TimerPayload{ID: 4})
+
+	reader := bufio.NewReader(resp.Body)
+	var block []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			if len(block) > 0 && strings.HasPrefix(block[0], "id:") {
+				break
+			}
+			block = nil
+			continue
+		}
+		block = append(block, line)
+	}
+	if len(block) != 3 || block[0] != "id: 2" || block[1] != "event: timer.deleted" {
+		t.Fatalf("unexpected event block %q", block)
+	}
+	mustContain(t, block[2], `"data":{"id":4,"active":false}`)
+
+	cancel()
+	for deadline := time.Now().Add(time.Second); bus.SubscriberCount() != 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("subscription not released after disconnect")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEvents_EndOnServerShutdown(t *testing.T) {
+	mock := ports.NewMockVDRClient()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewHandler(logger, template.New("empty"), services.NewEPGService(mock, 0), services.NewTimerService(mock), nil, nil)
+	bus := events.NewBus()
+	h.SetEventBus(bus)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := NewServer(&config.ServerConfig{}, logger, h, SetupRoutes(h, &config.AuthConfig{}, logger))
+	go func() { _ = s.server.Serve(ln) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/events")
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+	for deadline := time.Now().Add(time.Second); bus.SubscriberCount() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("handler did not subscribe")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatalf("expected the stream to end, got %v", err)
+	}
+	if n := bus.SubscriberCount(); n != 0 {
+		t.Fatalf("expected the subscription to be released, %d left", n)
+	}
+}
+
+func TestSSETypeFilter(t *testing.T) {
+	match := sseTypeFilter(" archive , timer.created")
+	for typ, want := range map[events.Type]bool{
+		events.ArchiveLog:     true,
+		events.TimerCreated:   true,
+		events.TimerDeleted:   false,
+		events.ChannelChanged: false,
+	} {
+		if got := match(typ); got != want {
+			t.Errorf("%s: got %v, want %v", typ, got, want)
+		}
+	}
+	if !sseTypeFilter("")(events.VDRConnected) {
+		t.Errorf("empty filter must match everything")
+	}
+}
@@ -0,0 +1,350 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+)
+
+// favouriteName returns the favourites list named by the "fav" form or query value.
+func favouriteName(r *http.Request) string {
+	return strings.TrimSpace(r.FormValue("fav"))
+}
+
+// favouritesFilter returns the filter of the selected favourites list, or nil
+// to show all channels, also for unknown lists.
+func (h *Handler) favouritesFilter(r *http.Request) *services.ChannelFilter {
+	if h.cfg == nil {
+		return nil
+	}
+	fav, ok := h.cfg.VDR.Favourite(favouriteName(r))
+	if !ok {
+		return nil
+	}
+	return services.NewChannelFilter(fav.Name, fav.Channels)
+}
+
+// addFavouritesData adds the favourites selector data used by the
+// channel-based pages. It adds nothing without lists, which hides the selector.
+func (h *Handler) addFavouritesData(data map[string]any, r *http.Request) {
+	if h.cfg == nil || len(h.cfg.VDR.Favourites) == 0 {
+		return
+	}
+	names := make([]string, 0, len(h.cfg.VDR.Favourites))
+	for _, f := range h.cfg.VDR.Favourites {
+		names = append(names, f.Name)
+	}
+	data["Favourites"] = names
+	if f := h.favouritesFilter(r); f != nil {
+		data["SelectedFavourite"] = f.Name()
+	}
+}
+
+// withFavourites adds the favourites selector data and narrows the channels
+// of r to the selected list.
+func (h *Handler) withFavourites(r *http.Request, data map[string]any) *http.Request {
+	h.addFavouritesData(data, r)
+	f := h.favouritesFilter(r)
+	if f == nil {
+		return r
+	}
+	return r.WithContext(services.WithChannelFilter(r.Context(), f))
+}
+
+// containsChannel reports whether chs holds the channel with the given ID.
+func containsChannel(chs []domain.Channel, channelID string) bool {
+	for _, ch := range chs {
+		if ch.ID == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// favouriteChannelView is a channel of the favourites list being edited.
+type favouriteChannelView struct {
+	Position int
+	ID       string
+	Name     string
+	// Missing marks channels VDR no longer lists (or that are not wanted).
+	Missing bool
+}
+
+// Favourites shows the favourites lists and the channels of the selected one.
+func (h *Handler) Favourites(w http.ResponseWriter, r *http.Request) {
+	if h.cfg == nil {
+		http.Error(w, "Configuration not available", http.StatusInternalServerError)
+		return
+	}
+	data := map[string]any{
+		"Message": r.URL.Query().Get("msg"),
+		"Error":   r.URL.Query().Get("error"),
+	}
+	lists := h.cfg.VDR.Favourites
+	data["Lists"] = lists
+
+	var channels []domain.Channel
+	if h.epgService != nil {
+		var err error
+		channels, err = h.epgService.GetChannels(r.Context())
+		if err != nil {
+			h.logger.Error("channels fetch error", slog.Any("error", err))
+			data["HomeError"] = err.Error()
+		}
+		groups, err := h.epgService.GetChannelGroups(r.Context())
+		if err != nil {
+			h.logger.Warn("channel groups fetch error", slog.Any("error", err))
+		}
+		data["Groups"] = groups
+	}
+
+	fav, ok := h.cfg.VDR.Favourite(r.URL.Query().Get("list"))
+	if !ok && len(lists) > 0 {
+		fav, ok = lists[0], true
+	}
+	if ok {
+		byID := make(map[string]domain.Channel, len(channels))
+		for _, ch := range channels {
+			byID[ch.ID] = ch
+		}
+		inList := make(map[string]bool, len(fav.Channels))
+		views := make([]favouriteChannelView, 0, len(fav.Channels))
+		for i, id := range fav.Channels {
+			inList[id] = true
+			ch, found := byID[id]
+			name := ch.Name
+			if !found {
+				name = id
+			}
+			views = append(views, favouriteChannelView{Position: i + 1, ID: id, Name: name, Missing: !found && len(channels) > 0})
+		}
+		addable := make([]domain.Channel, 0, len(channels))
+		for _, ch := range channels {
+			if !inList[ch.ID] {
+				addable = append(addable, ch)
+			}
+		}
+		data["Selected"] = fav.Name
+		data["SelectedChannels"] = views
+		data["Addable"] = addable
+	}
+	h.renderTemplate(w, r, "favourites.html", data)
+}
+
+// FavouritesSave changes the favourites lists ("op": create, rename, delete,
+// add, remove or move) and shows the editor again.
+func (h *Handler) FavouritesSave(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.cfg == nil {
+		http.Error(w, "Configuration not available", http.StatusInternalServerError)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+
+	list := strings.TrimSpace(r.FormValue("list"))
+	channelID := strings.TrimSpace(r.FormValue("channel"))
+	var (
+		change func([]config.FavouritesList) ([]config.FavouritesList, error)
+		msg    string
+	)
+	switch op := r.FormValue("op"); op {
+	case "create":
+		name := strings.TrimSpace(r.FormValue("name"))
+		var channels []string
+		if group := strings.TrimSpace(r.FormValue("group")); group != "" {
+			ids, err := h.channelGroupIDs(r, group)
+			if err != nil {
+				h.finishFavouritesEdit(w, r, list, "", err)
+				return
+			}
+			if name == "" {
+				name = group
+			}
+			channels = ids
+		}
+		list, msg = name, "List created."
+		change = func(lists []config.FavouritesList) ([]config.FavouritesList, error) {
+			return append(lists, config.FavouritesList{Name: name, Channels: channels}), nil
+		}
+	case "rename":
+		name, old := strings.TrimSpace(r.FormValue("name")), list
+		change = func(lists []config.FavouritesList) ([]config.FavouritesList, error) {
+			i, err := favouritesIndex(lists, old)
+			if err != nil {
+				return nil, err
+			}
+			lists[i].Name = name
+			return lists, nil
+		}
+		list, msg = name, "List renamed."
+	case "delete":
+		old := list
+		change = func(lists []config.FavouritesList) ([]config.FavouritesList, error) {
+			i, err := favouritesIndex(lists, old)
+			if err != nil {
+				return nil, err
+			}
+			return append(lists[:i], lists[i+1:]...), nil
+		}
+		list, msg = "", "List deleted."
+	case "add":
+		change = func(lists []config.FavouritesList) ([]config.FavouritesList, error) {
+			i, err := favouritesIndex(lists, list)
+			if err != nil {
+				return nil, err
+			}
+			if channelID == "" {
+				return nil, fmt.Errorf("%w: no channel selected", domain.ErrInvalidInput)
+			}
+			lists[i].Channels = append(lists[i].Channels, channelID)
+			return lists, nil
+		}
+	case "remove":
+		change = func(lists []config.FavouritesList) ([]config.FavouritesList, error) {
+			i, err := favouritesIndex(lists, list)
+			if err != nil {
+				return nil, err
+			}
+			kept := lists[i].Channels[:0]
+			for _, id := range lists[i].Channels {
+				if id != channelID {
+					kept = append(kept, id)
+				}
+			}
+			lists[i].Channels = kept
+			return lists, nil
+		}
+	case "move":
+		to, err := strconv.Atoi(strings.TrimSpace(r.FormValue("position")))
+		if err != nil {
+			h.finishFavouritesEdit(w, r, list, "", fmt.Errorf("%w: invalid position", domain.ErrInvalidInput))
+			return
+		}
+		change = func(lists []config.FavouritesList) ([]config.FavouritesList, error) {
+			i, err := favouritesIndex(lists, list)
+			if err != nil {
+				return nil, err
+			}
+			channels, err := moveFavourite(lists[i].Channels, channelID, to)
+			if err != nil {
+				return nil, err
+			}
+			lists[i].Channels = channels
+			return lists, nil
+		}
+	default:
+		http.Error(w, "Invalid operation", http.StatusBadRequest)
+		return
+	}
+
+	err := h.updateConfig(func(c *config.Config) error {
+		lists, err := change(cloneFavourites(c.VDR.Favourites))
+		if err != nil {
+			return err
+		}
+		c.VDR.Favourites = lists
+		return nil
+	})
+	if err != nil {
+		h.finishFavouritesEdit(w, r, strings.TrimSpace(r.FormValue("list")), "", err)
+		return
+	}
+	h.finishFavouritesEdit(w, r, list, msg, nil)
+}
+
+// channelGroupIDs returns the channel IDs of the channels.conf group.
+func (h *Handler) channelGroupIDs(r *http.Request, group string) ([]string, error) {
+	if h.epgService == nil {
+		return nil, fmt.Errorf("%w: EPG service not available", domain.ErrInvalidInput)
+	}
+	groups, err := h.epgService.GetChannelGroups(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if g.Name != group {
+			continue
+		}
+		ids := make([]string, 0, len(g.Channels))
+		for _, ch := range g.Channels {
+			ids = append(ids, ch.ID)
+		}
+		return ids, nil
+	}
+	return nil, fmt.Errorf("%w: channel group %q", domain.ErrNotFound, group)
+}
+
+// cloneFavourites copies the lists so changes do not touch the running configuration.
+func cloneFavourites(lists []config.FavouritesList) []config.FavouritesList {
+	out := make([]config.FavouritesList, len(lists))
+	for i, f := range lists {
+		out[i] = config.FavouritesList{Name: f.Name, Channels: append([]string(nil), f.Channels...)}
+	}
+	return out
+}
+
+func favouritesIndex(lists []config.FavouritesList, name string) (int, error) {
+	for i, f := range lists {
+		if strings.EqualFold(f.Name, name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: favourites list %q", domain.ErrNotFound, name)
+}
+
+// moveFavourite moves the channel to the 1-based position, shifting the ones in between.
+func moveFavourite(channels []string, channelID string, to int) ([]string, error) {
+	from := -1
+	for i, id := range channels {
+		if id == channelID {
+			from = i
+			break
+		}
+	}
+	if from < 0 {
+		return nil, fmt.Errorf("%w: channel %q is not in the list", domain.ErrNotFound, channelID)
+	}
+	if to < 1 || to > len(channels) {
+		return nil, fmt.Errorf("%w: position %d out of range 1-%d", domain.ErrInvalidInput, to, len(channels))
+	}
+	rest := append(append([]string(nil), channels[:from]...), channels[from+1:]...)
+	out := append(append(append([]string(nil), rest[:to-1]...), channelID), rest[to-1:]...)
+	return out, nil
+}
+
+// finishFavouritesEdit shows the favourites editor for list with msg and err.
+func (h *Handler) finishFavouritesEdit(w http.ResponseWriter, r *http.Request, list, msg string, err error) {
+	params := url.Values{}
+	if list != "" {
+		params.Set("list", list)
+	}
+	if err != nil {
+		h.logger.Warn("favourites edit failed", slog.Any("error", err))
+		params.Set("error", err.Error())
+	} else if msg != "" {
+		params.Set("msg", msg)
+	}
+	target := "/configurations/favourites"
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+	if r.Header.Get("HX-Request") != "" {
+		w.Header().Set("HX-Redirect", target)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func newFavouritesHandler(t *testing.T, page string, mock ports.VDRClient, lists ...config.FavouritesList) *Handler {
+	t.Helper()
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", page),
+	))
+	epg := services.NewEPGService(mock, time.Minute)
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, epg, nil, nil, nil)
+	h.SetTemplates(map[string]*template.Template{page: parsed})
+	cfg := &config.Config{VDR: config.VDRConfig{Host: "localhost", Port: 6419, DVBCards: 1, Favourites: lists}}
+	cfg.Server.Port = 8080
+	cfg.UI.Theme = "system"
+	h.SetConfig(cfg, filepath.Join(t.TempDir(), "config.yaml"))
+	h.SetVDRClient(mock)
+	return h
+}
+
+func TestWhatsOnNow_FavouritesListNarrowsAndOrders(t *testing.T) {
+	now := time.Now()
+	mock := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{
+			{ID: "C-1-1-1", Number: 1, Name: "Das Erste"},
+			{ID: "C-2-2-2", Number: 2, Name: "KiKA"},
+			{ID: "C-3-3-3", Number: 3, Name: "Super RTL"},
+		}).
+		WithEPGEvents([]domain.EPGEvent{
+			{EventID: 1, ChannelID: "C-1-1-1", ChannelNumber: 1, Title: "Tagesschau", Start: now.Add(-time.Minute), Stop: now.Add(time.Hour)},
+			{EventID: 2, ChannelID: "C-2-2-2", ChannelNumber: 2, Title: "Sandmann", Start: now.Add(-time.Minute), Stop: now.Add(time.Hour)},
+			{EventID: 3, ChannelID: "C-3-3-3", ChannelNumber: 3, Title: "Cartoons", Start: now.Add(-time.Minute), Stop: now.Add(time.Hour)},
+		})
+	h := newFavouritesHandler(t, "index.html", mock, config.FavouritesList{Name: "Kids", Channels: []string{"C-3-3-3", "C-2-2-2"}})
+
+	rw := httptest.NewRecorder()
+	h.WhatsOnNow(rw, httptest.NewRequest(http.MethodGet, "/now?fav=kids", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	body := rw.Body.String()
+	if strings.Contains(body, "Tagesschau") {
+		t.Fatalf("page shows a channel outside the list")
+	}
+	cartoons, sandmann := strings.Index(body, "Cartoons"), strings.Index(body, "Sandmann")
+	if cartoons < 0 || sandmann < 0 || cartoons > sandmann {
+		t.Fatalf("expected the list's order (Cartoons before Sandmann)")
+	}
+	if !strings.Contains(body, `<option value="Kids" selected>Kids</option>`) {
+		t.Fatalf("selector lacks the selected list")
+	}
+
+	rw = httptest.NewRecorder()
+	h.WhatsOnNow(rw, httptest.NewRequest(http.MethodGet, "/now", nil))
+	if !strings.Contains(rw.Body.String(), "Tagesschau") {
+		t.Fatalf("without a list all channels should be shown")
+	}
+}
+
+func postFavourites(t *testing.T, h *Handler, form url.Values) url.Values {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/configurations/favourites", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw := httptest.NewRecorder()
+	h.FavouritesSave(rw, req)
+	if rw.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect, got %d: %s", rw.Code, rw.Body.String())
+	}
+	loc, err := url.Parse(rw.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc.Query()
+}
+
+// groupedVDR reports the channels.conf groups of its channels.
+type groupedVDR struct {
+	*ports.MockVDRClient
+}
+
+func (v groupedVDR) GetChannelGroups(ctx context.Context) ([]domain.Channel, error) {
+	chs, err := v.GetChannels(ctx)
+	for i := range chs {
+		chs[i].Group = "Kids"
+	}
+	return chs, err
+}
+
+func TestFavouritesSave_EditsAndPersistsLists(t *testing.T) {
+	mock := groupedVDR{ports.NewMockVDRClient().WithChannels([]domain.Channel{
+		{ID: "C-2-2-2", Number: 2, Name: "KiKA"},
+		{ID: "C-3-3-3", Number: 3, Name: "Super RTL"},
+	})}
+	h := newFavouritesHandler(t, "favourites.html", mock)
+
+	if q := postFavourites(t, h, url.Values{"op": {"create"}, "group": {"Kids"}}); q.Get("msg") != "List created." || q.Get("list") != "Kids" {
+		t.Fatalf("create from group: %v", q)
+	}
+	if q := postFavourites(t, h, url.Values{"op": {"move"}, "list": {"Kids"}, "channel": {"C-3-3-3"}, "position": {"1"}}); q.Get("error") != "" {
+		t.Fatalf("move: %v", q)
+	}
+	if q := postFavourites(t, h, url.Values{"op": {"create"}, "name": {"kids"}}); !strings.Contains(q.Get("error"), "duplicate list name") {
+		t.Fatalf("duplicate name: %v", q)
+	}
+	if q := postFavourites(t, h, url.Values{"op": {"rename"}, "list": {"Kids"}, "name": {"Children"}}); q.Get("list") != "Children" {
+		t.Fatalf("rename: %v", q)
+	}
+
+	saved, err := config.Load(h.configPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(saved.VDR.Favourites) != 1 {
+		t.Fatalf("saved favourites = %+v", saved.VDR.Favourites)
+	}
+	if f := saved.VDR.Favourites[0]; f.Name != "Children" || strings.Join(f.Channels, ",") != "C-3-3-3,C-2-2-2" {
+		t.Fatalf("saved list = %+v", f)
+	}
+
+	rw := httptest.NewRecorder()
+	h.Favourites(rw, httptest.NewRequest(http.MethodGet, "/configurations/favourites?list=Children", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	body := rw.Body.String()
+	for _, want := range []string{`data-id="C-3-3-3" data-position="1"`, `data-id="C-2-2-2" data-position="2"`, "Import group"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+}
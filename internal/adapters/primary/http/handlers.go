@@ -3,6 +3,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -19,10 +21,16 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/githubixx/vdradmin-go/internal/application/archive"
+	"github.com/githubixx/vdradmin-go/internal/application/cutting"
+	"github.com/githubixx/vdradmin-go/internal/application/events"
 	"github.com/githubixx/vdradmin-go/internal/application/services"
 	"github.com/githubixx/vdradmin-go/internal/domain"
 	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/logos"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/metrics"
 	"github.com/githubixx/vdradmin-go/internal/infrastructure/theme"
 	"github.com/githubixx/vdradmin-go/internal/ports"
 )
@@ -36,7 +44,10 @@ type Handler struct {
 	configPath       string
 	vdrClient        ports.VDRClient
 	archiveJobs      *archive.JobManager
+	cutJobs          *cutting.Tracker
+	channels         *services.ChannelService
 	themeManager     *theme.Manager
+	logos            *logos.Store
 	instanceID       string
 	pid              int
 	nowFunc          func() time.Time
@@ -44,9 +55,21 @@ type Handler struct {
 	timerService     *services.TimerService
 	recordingService *services.RecordingService
 	autoTimerService *services.AutoTimerService
+	searchTimers     *services.SearchTimerService
+	dedup            *services.DedupService
+	timerWatchdog    *services.TimerWatchdog
+	recordingHistory *services.RecordingHistoryService
+	backends         *services.Backends
+	alternatives     *services.AlternativeAiringService
+	events           *events.Bus
+	stateMonitor     *services.StateMonitor
+	metrics          *metrics.Metrics
 	uiThemeDefault   string
 	hlsProxy         *HLSProxy
 	watchTVChannelMu sync.Mutex
+	// streamsDone is closed by CloseStreams to end long-lived responses.
+	streamsDone      chan struct{}
+	closeStreamsOnce sync.Once
 }
 
 func (h *Handler) now() time.Time {
@@ -69,7 +92,7 @@ func NewHandler(
 	recordingService *services.RecordingService,
 	autoTimerService *services.AutoTimerService,
 ) *Handler {
-	return &Handler{
+	h := &Handler{
 		logger:           logger,
 		templates:        templates,
 		templateMap:      make(map[string]*template.Template),
@@ -77,6 +100,7 @@ func NewHandler(
 		configPath:       "",
 		vdrClient:        nil,
 		archiveJobs:      archive.NewJobManager(),
+		cutJobs:          cutting.NewTracker(),
 		instanceID:       fmt.Sprintf("%d", time.Now().UnixNano()),
 		pid:              os.Getpid(),
 		nowFunc:          time.Now,
@@ -84,8 +108,21 @@ func NewHandler(
 		timerService:     timerService,
 		recordingService: recordingService,
 		autoTimerService: autoTimerService,
+		alternatives:     services.NewAlternativeAiringService(epgService, timerService),
 		uiThemeDefault:   "system",
+		streamsDone:      make(chan struct{}),
+	}
+	if recordingService != nil {
+		// Recordings must not be moved while an archive job reads them.
+		recordingService.SetArchiveJobs(h.archiveJobs)
 	}
+	return h
+}
+
+// CloseStreams ends the open event streams. http.Server.Shutdown does not
+// cancel the requests it waits for, so the server calls it when shutting down.
+func (h *Handler) CloseStreams() {
+	h.closeStreamsOnce.Do(func() { close(h.streamsDone) })
 }
 
 // SetConfig wires the runtime configuration pointer and file path.
@@ -112,6 +149,40 @@ func (h *Handler) SetConfig(cfg *config.Config, configPath string) {
 // SetVDRClient provides the VDR client so we can apply VDR connection changes immediately.
 func (h *Handler) SetVDRClient(client ports.VDRClient) {
 	h.vdrClient = client
+	h.channels = services.NewChannelService(client, h.epgService)
+}
+
+// SetEventBus sets the bus served on /events and wires the archive jobs to it.
+func (h *Handler) SetEventBus(bus *events.Bus) {
+	h.events = bus
+	h.archiveJobs.SetEventBus(bus)
+}
+
+// SetStateMonitor sets the monitor that is asked to poll VDR after state changes
+// triggered from the UI (e.g. channel switches).
+func (h *Handler) SetStateMonitor(m *services.StateMonitor) {
+	h.stateMonitor = m
+}
+
+// SetSearchTimerService sets the service creating timers for saved searches with auto-record.
+func (h *Handler) SetSearchTimerService(s *services.SearchTimerService) {
+	h.searchTimers = s
+}
+
+// SetDedupService sets the service keeping the done list of auto-record.
+func (h *Handler) SetDedupService(s *services.DedupService) {
+	h.dedup = s
+}
+
+// SetRecordingHistory sets the service keeping the history of finished timers.
+func (h *Handler) SetRecordingHistory(s *services.RecordingHistoryService) {
+	h.recordingHistory = s
+}
+
+// SetTimerWatchdog sets the service moving timers along with their EPG event.
+// It watches the primary backend unless the backend brings its own watchdog.
+func (h *Handler) SetTimerWatchdog(w *services.TimerWatchdog) {
+	h.timerWatchdog = w
 }
 
 // SetUIThemeDefault configures the default theme mode (system/light/dark).
@@ -175,8 +246,12 @@ func (h *Handler) validateRecordingPath(recordingPath string) error {
 	if h.cfg == nil {
 		return fmt.Errorf("configuration not available")
 	}
+	return validateRecordingPathIn(h.cfg.VDR.VideoDir, recordingPath)
+}
 
-	videoDir := strings.TrimSpace(h.cfg.VDR.VideoDir)
+// validateRecordingPathIn validates a recording path against the given video directory.
+func validateRecordingPathIn(videoDir, recordingPath string) error {
+	videoDir = strings.TrimSpace(videoDir)
 	if videoDir == "" {
 		return fmt.Errorf("video directory not configured")
 	}
@@ -278,6 +353,7 @@ func (h *Handler) Home(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) WhatsOnNow(w http.ResponseWriter, r *http.Request) {
 	loc := time.Local
 	data := map[string]any{}
+	r = h.withFavourites(r, data)
 
 	selectedPreset, atValue, atTime, atErr := parseWhatsOnAtParam(r, loc)
 	data["SelectedHour"] = selectedPreset
@@ -305,10 +381,26 @@ func (h *Handler) WhatsOnNow(w http.ResponseWriter, r *http.Request) {
 	} else {
 		data["Events"] = events
 	}
+	if h.recordingHistory != nil {
+		if sum := h.recordingHistory.Summary(h.now().Add(-homeFailureDays * 24 * time.Hour)); sum.Failed() > 0 {
+			if len(sum.Failures) > homeFailuresShown {
+				sum.Failures = sum.Failures[:homeFailuresShown]
+			}
+			data["RecordingFailures"] = sum
+			data["RecordingFailureDays"] = homeFailureDays
+		}
+	}
 
 	h.renderTemplate(w, r, "index.html", data)
 }
 
+// The home page summarizes the recordings that failed in the last homeFailureDays
+// and lists the newest homeFailuresShown of them.
+const (
+	homeFailureDays   = 7
+	homeFailuresShown = 5
+)
+
 func parseWhatsOnAtParam(r *http.Request, loc *time.Location) (selectedPreset string, atValue string, atTime time.Time, err error) {
 	if loc == nil {
 		loc = time.Local
@@ -388,10 +480,14 @@ func pageNameForPath(path string) string {
 		return "Channels"
 	case strings.HasPrefix(path, "/playing"):
 		return "Playing Today"
+	case strings.HasPrefix(path, "/grid"):
+		return "TV Guide"
 	case strings.HasPrefix(path, "/watch"):
 		return "Watch TV"
 	case strings.HasPrefix(path, "/timers"):
 		return "Timers"
+	case strings.HasPrefix(path, "/autotimers"):
+		return "AutoTimers"
 	case strings.HasPrefix(path, "/recordings"):
 		return "Recordings"
 	case strings.HasPrefix(path, "/search"):
@@ -448,6 +544,7 @@ func watchTVCurrentChannelIDFromSVDRPChan(cur string, channels []domain.Channel)
 // WatchTV renders the snapshot-based TV page (SVDRP GRAB + remote control).
 func (h *Handler) WatchTV(w http.ResponseWriter, r *http.Request) {
 	data := map[string]any{}
+	r = h.withFavourites(r, data)
 
 	interval := strings.TrimSpace(r.URL.Query().Get("interval"))
 	if interval == "" {
@@ -536,10 +633,77 @@ func (h *Handler) WatchTVKey(w http.ResponseWriter, r *http.Request) {
 		h.handleError(w, r, err)
 		return
 	}
+	if strings.EqualFold(key, "Stop") && h.recordingService != nil {
+		h.recordingService.StopReplay()
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// watchTVReplayResponse describes the replay started from the recordings page.
+type watchTVReplayResponse struct {
+	Path     string    `json:"path"`
+	Title    string    `json:"title"`
+	Subtitle string    `json:"subtitle,omitempty"`
+	Started  time.Time `json:"started"`
+	// ResumePoint and Length are in seconds; ResumePoint is omitted when VDR
+	// did not save one. It is not the live position: VDR saves it when the
+	// replay stops or pauses.
+	ResumePoint      *int   `json:"resume_point,omitempty"`
+	ResumePointLabel string `json:"resume_point_label,omitempty"`
+	Length           int    `json:"length"`
+	LengthLabel      string `json:"length_label,omitempty"`
+}
+
+// WatchTVReplay returns the recording replaying on the VDR output and its
+// resume point, or 204 if no replay was started from vdradmin-go.
+func (h *Handler) WatchTVReplay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.recordingService == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	status, ok, err := h.recordingService.ReplayStatus(r.Context())
+	if err != nil {
+		// The resume point is optional; still report what is replaying.
+		h.logger.Warn("replay resume point unavailable", slog.Any("error", err))
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	resp := watchTVReplayResponse{
+		Path:     status.Recording.Path,
+		Title:    status.Recording.Title,
+		Subtitle: status.Recording.Subtitle,
+		Started:  status.Started,
+		Length:   int(status.Recording.Length / time.Second),
+	}
+	if status.Recording.Length > 0 {
+		resp.LengthLabel = formatReplayClock(status.Recording.Length)
+	}
+	if status.HasResumePoint {
+		secs := int(status.ResumePoint / time.Second)
+		resp.ResumePoint = &secs
+		resp.ResumePointLabel = formatReplayClock(status.ResumePoint)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// formatReplayClock formats a replay position as h:mm:ss.
+func formatReplayClock(d time.Duration) string {
+	secs := int(d / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+}
+
 // WatchTVChannel switches to a channel via SVDRP CHAN.
 func (h *Handler) WatchTVChannel(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -645,6 +809,9 @@ func (h *Handler) WatchTVChannel(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, msg, status)
 		return
 	}
+	if h.stateMonitor != nil {
+		h.stateMonitor.Trigger()
+	}
 
 	// Start the HLS proxy for the newly tuned channel so stale playlist requests from the
 	// previous channel can't restart the old ffmpeg process.
@@ -856,8 +1023,19 @@ func parseDayParam(r *http.Request, loc *time.Location) (time.Time, error) {
 
 // Channels renders the channel view with a channel selector and per-channel EPG list.
 func (h *Handler) Channels(w http.ResponseWriter, r *http.Request) {
-	channels, err := h.epgService.GetChannels(r.Context())
+	viewBackends, err := h.backendsForView(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
 	data := map[string]any{}
+	h.addBackendData(data, r)
+	r = h.withFavourites(r, data)
+	channels, err := h.backendSet().Channels(r.Context(), viewBackends)
+	if isPartialBackendError(err) {
+		h.logger.Warn("channels fetch error", slog.Any("error", err))
+		err = nil
+	}
 	if err != nil {
 		h.logger.Error("channels fetch error", slog.Any("error", err))
 		data["HomeError"] = err.Error()
@@ -868,10 +1046,20 @@ func (h *Handler) Channels(w http.ResponseWriter, r *http.Request) {
 	data["Channels"] = channels
 
 	selected := r.URL.Query().Get("channel")
+	if _, ok := data["SelectedFavourite"]; ok && !containsChannel(channels, selected) {
+		// The channel was picked before switching to a list without it.
+		selected = ""
+	}
 	if selected == "" && len(channels) > 0 {
 		selected = channels[0].ID
 	}
 	data["SelectedChannel"] = selected
+	for _, ch := range channels {
+		if ch.ID == selected {
+			data["SelectedChannelInfo"] = ch
+			break
+		}
+	}
 
 	loc := time.Local
 	dayStart, err := parseDayParam(r, loc)
@@ -883,14 +1071,22 @@ func (h *Handler) Channels(w http.ResponseWriter, r *http.Request) {
 	dayEnd := dayStart.Add(24 * time.Hour)
 	data["SelectedDay"] = dayStart.Format("2006-01-02")
 
-	numberByID := make(map[string]int, len(channels))
-	idByNumber := make(map[int]string, len(channels))
-	for _, ch := range channels {
-		if ch.ID != "" {
-			numberByID[ch.ID] = ch.Number
+	// The EPG comes from the first shown backend receiving the channel.
+	epgBackend := viewBackends[0]
+	if selected != "" && len(viewBackends) > 1 {
+		for _, b := range viewBackends {
+			if backendHasChannel(r.Context(), b, selected) {
+				epgBackend = b
+				break
+			}
 		}
-		if ch.Number > 0 && ch.ID != "" {
-			idByNumber[ch.Number] = ch.ID
+	}
+
+	selectedChannelNumber := 0
+	for _, ch := range channels {
+		if ch.ID == selected {
+			selectedChannelNumber = ch.Number
+			break
 		}
 	}
 
@@ -901,7 +1097,7 @@ func (h *Handler) Channels(w http.ResponseWriter, r *http.Request) {
 	if selected != "" {
 		// Anchor the EPG request to the selected day. Using time.Now() here causes
 		// inconsistent behavior across day selections (and cache keys).
-		ev, err := h.epgService.GetEPG(r.Context(), selected, dayStart)
+		ev, err := epgBackend.EPG.GetEPG(r.Context(), selected, dayStart)
 		if err != nil {
 			h.logger.Error("EPG fetch error on channels", slog.Any("error", err), slog.String("channel", selected))
 			data["HomeError"] = err.Error()
@@ -926,49 +1122,39 @@ func (h *Handler) Channels(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Build timer occurrence index for the selected channel/day and mark events as scheduled
-	// only when a timer overlaps *and* matches the event title.
-	timersByID := map[int]domain.Timer{}
-	occByChannelNumber := map[int][]timerOccurrence{}
-	occByChannelID := map[string][]timerOccurrence{}
-	selectedChannelNumber := 0
-	for _, ch := range channels {
-		if ch.ID == selected {
-			selectedChannelNumber = ch.Number
-			break
-		}
-	}
-	if h.timerService != nil && selected != "" {
-		timers, tErr := h.timerService.GetAllTimers(r.Context())
-		if tErr != nil {
-			h.logger.Warn("timers fetch error for channels", slog.Any("error", tErr))
-		} else {
-			from := dayStart.Add(-24 * time.Hour)
-			to := dayEnd.Add(24 * time.Hour)
-			for _, t := range timers {
-				if strings.TrimSpace(t.ChannelID) == "" {
+	// An event is scheduled when a timer on any shown backend covers it.
+	var scheduledOn []func(domain.EPGEvent) bool
+	if selected != "" {
+		for _, b := range viewBackends {
+			bChannels := channels
+			if len(viewBackends) > 1 {
+				bChannels, err = b.EPG.GetChannels(r.Context())
+				if err != nil {
 					continue
 				}
-				timersByID[t.ID] = t
-				occs := timerOccurrences(t, from, to)
-				occByChannelID[t.ChannelID] = append(occByChannelID[t.ChannelID], occs...)
-				if selectedChannelNumber > 0 {
-					if n, err := strconv.Atoi(strings.TrimSpace(t.ChannelID)); err == nil && n == selectedChannelNumber {
-						occByChannelNumber[n] = append(occByChannelNumber[n], occs...)
-					}
-				}
-				if selected != "" && strings.TrimSpace(t.ChannelID) == strings.TrimSpace(selected) {
-					if selectedChannelNumber > 0 {
-						occByChannelNumber[selectedChannelNumber] = append(occByChannelNumber[selectedChannelNumber], occs...)
-					}
-				}
 			}
+			scheduledOn = append(scheduledOn, h.channelTimerIndex(r.Context(), b, bChannels, selected, dayStart, dayEnd, loc, b != epgBackend))
 		}
 	}
-
 	eventScheduled := func(ev domain.EPGEvent) bool {
-		_, ok := scheduledTimerForEvent(ev, loc, occByChannelNumber, occByChannelID, numberByID, idByNumber, timersByID)
-		return ok
+		for _, scheduled := range scheduledOn {
+			if scheduled(ev) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// With several backends, let the user choose where to record.
+	if h.backendSet().Multiple() && selected != "" {
+		var recordOn []string
+		for _, b := range h.backendSet().All() {
+			if backendHasChannel(r.Context(), b, selected) {
+				recordOn = append(recordOn, b.Name)
+			}
+		}
+		data["RecordBackends"] = recordOn
+		data["RecordBackend"] = epgBackend.Name
 	}
 
 	dayOptions := make([]channelsDayOption, 0, len(daysByValue))
@@ -1005,6 +1191,79 @@ func (h *Handler) Channels(w http.ResponseWriter, r *http.Request) {
 	h.renderTemplate(w, r, "channels.html", data)
 }
 
+// backendHasChannel reports whether b receives the channel with the given ID.
+func backendHasChannel(ctx context.Context, b *services.Backend, channelID string) bool {
+	chs, err := b.EPG.GetChannels(ctx)
+	if err != nil {
+		return false
+	}
+	for _, ch := range chs {
+		if ch.ID == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// channelTimerIndex indexes the timers of b for the selected channel and day and
+// returns a function reporting whether an event is covered by one of them.
+// foreign marks a backend other than the one the events come from; channel
+// numbers differ between VDRs, so events get b's number for the channel.
+func (h *Handler) channelTimerIndex(ctx context.Context, b *services.Backend, channels []domain.Channel, selected string, dayStart, dayEnd time.Time, loc *time.Location, foreign bool) func(domain.EPGEvent) bool {
+	numberByID := make(map[string]int, len(channels))
+	idByNumber := make(map[int]string, len(channels))
+	for _, ch := range channels {
+		if ch.ID != "" {
+			numberByID[ch.ID] = ch.Number
+		}
+		if ch.Number > 0 && ch.ID != "" {
+			idByNumber[ch.Number] = ch.ID
+		}
+	}
+
+	// Build timer occurrence index for the selected channel/day and mark events as scheduled
+	// only when a timer overlaps *and* matches the event title.
+	timersByID := map[int]domain.Timer{}
+	occByChannelNumber := map[int][]timerOccurrence{}
+	occByChannelID := map[string][]timerOccurrence{}
+	selectedChannelNumber := numberByID[selected]
+	if b.Timers != nil && selected != "" {
+		timers, tErr := b.Timers.GetAllTimers(ctx)
+		if tErr != nil {
+			h.logger.Warn("timers fetch error for channels", slog.String("backend", b.Name), slog.Any("error", tErr))
+		} else {
+			from := dayStart.Add(-24 * time.Hour)
+			to := dayEnd.Add(24 * time.Hour)
+			for _, t := range timers {
+				if strings.TrimSpace(t.ChannelID) == "" {
+					continue
+				}
+				timersByID[t.ID] = t
+				occs := timerOccurrences(t, from, to)
+				occByChannelID[t.ChannelID] = append(occByChannelID[t.ChannelID], occs...)
+				if selectedChannelNumber > 0 {
+					if n, err := strconv.Atoi(strings.TrimSpace(t.ChannelID)); err == nil && n == selectedChannelNumber {
+						occByChannelNumber[n] = append(occByChannelNumber[n], occs...)
+					}
+				}
+				if selected != "" && strings.TrimSpace(t.ChannelID) == strings.TrimSpace(selected) {
+					if selectedChannelNumber > 0 {
+						occByChannelNumber[selectedChannelNumber] = append(occByChannelNumber[selectedChannelNumber], occs...)
+					}
+				}
+			}
+		}
+	}
+
+	return func(ev domain.EPGEvent) bool {
+		if foreign {
+			ev.ChannelNumber = selectedChannelNumber
+		}
+		_, ok := scheduledTimerForEvent(ev, loc, occByChannelNumber, occByChannelID, numberByID, idByNumber, timersByID)
+		return ok
+	}
+}
+
 // Configurations renders a simple configuration page.
 // For now it only allows switching between system/light/dark theme.
 func (h *Handler) Configurations(w http.ResponseWriter, r *http.Request) {
@@ -1048,6 +1307,11 @@ func (h *Handler) configurationsBaseData(r *http.Request) map[string]any {
 			data["AllChannels"] = []domain.Channel{}
 		}
 	}
+	// Reported after the channel fetch so it reflects the connection that used.
+	if rep, ok := h.vdrClient.(ports.ConnectionStateReporter); ok {
+		st := rep.ConnectionState()
+		data["VDRConnection"] = &st
+	}
 	// Add available themes for dropdown
 	if h.themeManager != nil {
 		options := []themeOption{{ID: "system", Label: "System (auto)"}}
@@ -1399,6 +1663,8 @@ func (h *Handler) buildConfigFromForm(form url.Values) (*config.Config, error) {
 	if v := strings.TrimSpace(form.Get("ui_login_page")); v != "" {
 		updated.UI.LoginPage = v
 	}
+	updated.UI.LogoDir = strings.TrimSpace(form.Get("ui_logo_dir"))
+	updated.UI.LogoMap = strings.TrimSpace(form.Get("ui_logo_map"))
 
 	// VDR connection
 	if v := strings.TrimSpace(form.Get("vdr_dvb_cards")); v != "" {
@@ -1439,6 +1705,13 @@ func (h *Handler) buildConfigFromForm(form url.Values) (*config.Config, error) {
 		}
 		updated.VDR.ReconnectDelay = d
 	}
+	if v := strings.TrimSpace(form.Get("vdr_idle_timeout")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vdr idle timeout")
+		}
+		updated.VDR.IdleTimeout = d
+	}
 	// Stream URL template (optional, can be empty)
 	updated.VDR.StreamURLTemplate = strings.TrimSpace(form.Get("vdr_stream_url_template"))
 	// Streamdev backend URL for HLS proxy (optional, can be empty)
@@ -1510,6 +1783,40 @@ func (h *Handler) buildConfigFromForm(form url.Values) (*config.Config, error) {
 		}
 		updated.Timer.DefaultMarginEnd = n
 	}
+	if v := strings.TrimSpace(form.Get("timer_conflict_check")); v != "" {
+		updated.Timer.ConflictCheck = v
+	}
+	if v := strings.TrimSpace(form.Get("timer_epg_watch")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timer EPG watch interval")
+		}
+		updated.Timer.EPGWatch = d
+	}
+
+	// AutoTimer
+	if v := strings.TrimSpace(form.Get("autotimer_interval")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid autotimer interval")
+		}
+		updated.AutoTimer.Interval = d
+	}
+
+	// History
+	if v := strings.TrimSpace(form.Get("history_keep_days")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid history keep days")
+		}
+		updated.History.KeepDays = n
+	}
+
+	// Dedup
+	if v := strings.TrimSpace(form.Get("dedup_match")); v != "" {
+		updated.Dedup.Match = v
+	}
+	updated.Dedup.Description = form.Get("dedup_description") == "on"
 
 	// Validate theme with theme manager
 	if h.themeManager != nil && updated.UI.Theme != "" {
@@ -1522,6 +1829,9 @@ func (h *Handler) buildConfigFromForm(form url.Values) (*config.Config, error) {
 	if err := updated.Validate(); err != nil {
 		return nil, err
 	}
+	if err := logos.NewStore(updated.UI.LogoDir, updated.UI.LogoMap).Load(); err != nil {
+		return nil, fmt.Errorf("invalid logo settings: %w", err)
+	}
 
 	return &updated, nil
 }
@@ -1546,6 +1856,12 @@ func (h *Handler) applyRuntimeConfig(updated *config.Config) error {
 
 	// Apply pieces that can take effect immediately.
 	h.SetUIThemeDefault(h.cfg.UI.Theme)
+	if h.logos != nil {
+		h.logos.SetPaths(h.cfg.UI.LogoDir, h.cfg.UI.LogoMap)
+		if err := h.logos.Load(); err != nil {
+			h.logger.Warn("channel logos not loaded", slog.Any("error", err))
+		}
+	}
 	if h.epgService != nil {
 		h.epgService.SetCacheExpiry(h.cfg.Cache.EPGExpiry)
 		h.epgService.SetWantedChannels(h.cfg.VDR.WantedChannels)
@@ -1553,6 +1869,40 @@ func (h *Handler) applyRuntimeConfig(updated *config.Config) error {
 	}
 	if h.recordingService != nil {
 		h.recordingService.SetCacheExpiry(h.cfg.Cache.RecordingExpiry)
+		h.recordingService.SetVideoDir(h.cfg.VDR.VideoDir)
+	}
+	if h.timerService != nil {
+		h.timerService.SetDVBCards(h.cfg.VDR.DVBCards)
+		h.timerService.SetConflictPolicy(h.cfg.Timer.ConflictCheck)
+	}
+	if h.autoTimerService != nil {
+		h.autoTimerService.SetInterval(h.cfg.AutoTimer.Interval)
+	}
+	if h.dedup != nil {
+		h.dedup.SetRules(h.cfg.Dedup.Match, h.cfg.Dedup.Description)
+	}
+	if h.recordingHistory != nil {
+		h.recordingHistory.SetKeepDays(h.cfg.History.KeepDays)
+	}
+	for _, b := range h.backendSet().All() {
+		if w := h.watchdogFor(b); w != nil {
+			w.SetDefaultMargins(h.cfg.Timer.DefaultMarginStart, h.cfg.Timer.DefaultMarginEnd)
+			w.SetInterval(h.cfg.Timer.EPGWatch)
+		}
+	}
+	if h.stateMonitor != nil {
+		h.stateMonitor.SetInterval(h.cfg.Events.PollInterval)
+	}
+	// Further backends share the cache and timer policies.
+	if h.backends != nil {
+		for _, b := range h.backends.All()[1:] {
+			b.EPG.SetCacheExpiry(h.cfg.Cache.EPGExpiry)
+			b.Recordings.SetCacheExpiry(h.cfg.Cache.RecordingExpiry)
+			b.Timers.SetConflictPolicy(h.cfg.Timer.ConflictCheck)
+			if u, ok := b.Client.(interface{ SetIdleTimeout(d time.Duration) }); ok {
+				u.SetIdleTimeout(h.cfg.VDR.IdleTimeout)
+			}
+		}
 	}
 
 	// Update SVDRP connection settings (best-effort).
@@ -1562,6 +1912,9 @@ func (h *Handler) applyRuntimeConfig(updated *config.Config) error {
 		}); ok {
 			u.UpdateConnection(h.cfg.VDR.Host, h.cfg.VDR.Port, h.cfg.VDR.Timeout)
 		}
+		if u, ok := h.vdrClient.(interface{ SetIdleTimeout(d time.Duration) }); ok {
+			u.SetIdleTimeout(h.cfg.VDR.IdleTimeout)
+		}
 	}
 
 	// Server host/port/timeouts can't be changed without restarting the process.
@@ -1585,6 +1938,7 @@ func (h *Handler) PlayingToday(w http.ResponseWriter, r *http.Request) {
 	startTimeStr, endTimeStr, startTime, endTime, timeErr := parsePlayingTimeParams(r, dayStart, localNow)
 
 	data := map[string]any{}
+	r = h.withFavourites(r, data)
 	data["Day"] = dayStart
 	data["PrevDay"] = dayStart.Add(-24 * time.Hour).Format("2006-01-02")
 	data["NextDay"] = dayStart.Add(24 * time.Hour).Format("2006-01-02")
@@ -1879,13 +2233,18 @@ func (h *Handler) EventInfo(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) EPGSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	isHTMX := r.Header.Get("HX-Request") == "true"
+	data := map[string]any{"Sort": "time"}
+	if r.URL.Query().Get("sort") == "relevance" {
+		data["Sort"] = "relevance"
+	}
+	r = h.withFavourites(r, data)
 	if query == "" {
 		if isHTMX {
 			// HTMX updates the #search-results container; return results-only markup.
 			h.renderTemplate(w, r, "search_results.html", nil)
 			return
 		}
-		h.renderTemplate(w, r, "search.html", nil)
+		h.renderTemplate(w, r, "search.html", data)
 		return
 	}
 
@@ -2020,40 +2379,42 @@ func (h *Handler) EPGSearch(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Group results by local day, like the /epgsearch results view.
-	loc := time.Local
-	sort.SliceStable(views, func(i, j int) bool {
-		ai := views[i]
-		aj := views[j]
-		if !ai.Start.Equal(aj.Start) {
-			return ai.Start.Before(aj.Start)
-		}
-		// Tie-breakers for stable rendering.
-		if ai.ChannelName != aj.ChannelName {
-			return ai.ChannelName < aj.ChannelName
-		}
-		if ai.Title != aj.Title {
-			return ai.Title < aj.Title
-		}
-		return ai.EventID < aj.EventID
-	})
-	dayGroups := make([]searchDayGroup, 0)
-	var currentDay time.Time
-	currentIdx := -1
-	for _, v := range views {
-		s := v.Start.In(loc)
-		day := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, loc)
-		if currentIdx == -1 || !day.Equal(currentDay) {
-			dayGroups = append(dayGroups, searchDayGroup{DayLabel: day.Format("Mon 2006-01-02")})
-			currentDay = day
-			currentIdx = len(dayGroups) - 1
+	data["Query"] = query
+	if data["Sort"] == "relevance" {
+		// SearchEPG returns the best matches first.
+		data["DayGroups"] = []searchDayGroup{{DayLabel: "Best matches", Events: views}}
+	} else {
+		// Group results by local day, like the /epgsearch results view.
+		loc := time.Local
+		sort.SliceStable(views, func(i, j int) bool {
+			ai := views[i]
+			aj := views[j]
+			if !ai.Start.Equal(aj.Start) {
+				return ai.Start.Before(aj.Start)
+			}
+			// Tie-breakers for stable rendering.
+			if ai.ChannelName != aj.ChannelName {
+				return ai.ChannelName < aj.ChannelName
+			}
+			if ai.Title != aj.Title {
+				return ai.Title < aj.Title
+			}
+			return ai.EventID < aj.EventID
+		})
+		dayGroups := make([]searchDayGroup, 0)
+		var currentDay time.Time
+		currentIdx := -1
+		for _, v := range views {
+			s := v.Start.In(loc)
+			day := time.Date(s.Year(), s.Month(), s.Day(), 0, 0, 0, 0, loc)
+			if currentIdx == -1 || !day.Equal(currentDay) {
+				dayGroups = append(dayGroups, searchDayGroup{DayLabel: day.Format("Mon 2006-01-02")})
+				currentDay = day
+				currentIdx = len(dayGroups) - 1
+			}
+			dayGroups[currentIdx].Events = append(dayGroups[currentIdx].Events, v)
 		}
-		dayGroups[currentIdx].Events = append(dayGroups[currentIdx].Events, v)
-	}
-
-	data := map[string]any{
-		"Query":     query,
-		"DayGroups": dayGroups,
+		data["DayGroups"] = dayGroups
 	}
 
 	if isHTMX {
@@ -2106,17 +2467,25 @@ func (h *Handler) EPGSearchList(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		views = append(views, epgSearchView{
+		view := epgSearchView{
 			EPGSearch:    s,
 			ChannelLabel: label,
 			FromLabel:    "--:--",
 			ToLabel:      "--:--",
-		})
+		}
+		if s.StartFrom != "" {
+			view.FromLabel = s.StartFrom
+		}
+		if s.StartTo != "" {
+			view.ToLabel = s.StartTo
+		}
+		views = append(views, view)
 	}
 
 	data := map[string]any{
 		"Searches": views,
 	}
+	h.addFavouritesData(data, r)
 	if chErr != nil {
 		h.logger.Warn("channels fetch error for epgsearch", slog.Any("error", chErr))
 		data["HomeError"] = chErr.Error()
@@ -2306,11 +2675,9 @@ func (h *Handler) EPGSearchExecute(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	allEvents, err := h.epgService.GetEPG(r.Context(), "", time.Time{})
-	if err != nil {
-		h.handleError(w, r, err)
-		return
-	}
+	// Saved searches keep their channel ranges; a favourites list only narrows the events.
+	searchCtx := services.WithChannelFilter(r.Context(), h.favouritesFilter(r))
+	env := h.savedSearchEnv(r.Context(), selected...)
 
 	// Lookup existing active timers by channel + time overlap.
 	// NOTE: SVDRP LSTT output doesn't include an EPG EventID, so matching by (ChannelID, EventID)
@@ -2337,11 +2704,15 @@ func (h *Handler) EPGSearchExecute(w http.ResponseWriter, r *http.Request) {
 	seen := map[string]bool{}
 	combined := make([]domain.EPGEvent, 0, 128)
 	for _, s := range selected {
-		matches, err := services.ExecuteSavedEPGSearch(allEvents, s, order)
-		if err != nil {
+		matches, err := h.epgService.ExecuteSavedSearch(searchCtx, s, order, env)
+		if errors.Is(err, domain.ErrInvalidInput) {
 			http.Redirect(w, r, "/epgsearch?err="+url.QueryEscape("Invalid search: "+err.Error()), http.StatusSeeOther)
 			return
 		}
+		if err != nil {
+			h.handleError(w, r, err)
+			return
+		}
 		for _, ev := range matches {
 			key := fmt.Sprintf("%s:%d", ev.ChannelID, ev.EventID)
 			if ev.EventID <= 0 {
@@ -2487,6 +2858,7 @@ func (h *Handler) EPGSearchExecute(w http.ResponseWriter, r *http.Request) {
 	data := map[string]any{
 		"DayGroups": dayGroups,
 	}
+	h.addFavouritesData(data, r)
 	h.renderTemplate(w, r, "epgsearch_results.html", data)
 }
 
@@ -2495,25 +2867,69 @@ func (h *Handler) epgSearchFormData(r *http.Request, search config.EPGSearch) ma
 	if err != nil {
 		channels = []domain.Channel{}
 	}
+	weekdays := make([]epgSearchWeekdayOption, 0, 7)
+	for i := 1; i <= 7; i++ {
+		d := time.Weekday(i % 7)
+		name := config.WeekdayName(d)
+		weekdays = append(weekdays, epgSearchWeekdayOption{
+			Value:   name,
+			Label:   d.String()[:3],
+			Checked: slices.Contains(search.Weekdays, name),
+		})
+	}
 	data := map[string]any{
 		"Search":   search,
 		"Channels": channels,
+		"Weekdays": weekdays,
 	}
 	return data
 }
 
-func (h *Handler) EPGSearchNew(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+type epgSearchWeekdayOption struct {
+	Value   string
+	Label   string
+	Checked bool
+}
+
+// savedSearchEnv returns what the filters of saved searches compare events
+// with. Recordings are only fetched when one of searches skips recorded events.
+func (h *Handler) savedSearchEnv(ctx context.Context, searches ...config.EPGSearch) services.SavedSearchEnv {
+	env := services.SavedSearchEnv{Now: h.now()}
+	if h.recordingService == nil {
+		return env
+	}
+	for _, s := range searches {
+		if !s.NotRecorded {
+			continue
+		}
+		recs, err := h.recordingService.GetAllRecordings(ctx)
+		if err != nil {
+			h.logger.Warn("recordings fetch error for epgsearch", slog.Any("error", err))
+			return env
+		}
+		env.Recorded = services.RecordedMatcher(recs)
+		return env
+	}
+	return env
+}
+
+func (h *Handler) EPGSearchNew(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	priority, lifetime, marginStart, marginEnd := h.timerDefaults()
 	search := config.EPGSearch{
-		Active:     true,
-		Mode:       "phrase",
-		InTitle:    true,
-		InSubtitle: true,
-		InDesc:     true,
-		UseChannel: "no",
+		Active:      true,
+		Mode:        "phrase",
+		InTitle:     true,
+		InSubtitle:  true,
+		InDesc:      true,
+		UseChannel:  "no",
+		Priority:    priority,
+		Lifetime:    lifetime,
+		MarginStart: marginStart,
+		MarginEnd:   marginEnd,
 	}
 	data := h.epgSearchFormData(r, search)
 	data["PageTitle"] = "Add New Search - VDRAdmin-go"
@@ -2553,21 +2969,13 @@ func (h *Handler) renderEPGSearchRun(w http.ResponseWriter, r *http.Request, sea
 		}
 	}
 
-	allEvents, err := h.epgService.GetEPG(r.Context(), "", time.Time{})
+	matches, err := h.epgService.ExecuteSavedSearch(r.Context(), search, order, h.savedSearchEnv(r.Context(), search))
 	if err != nil {
 		data := h.epgSearchFormData(r, search)
 		data["Error"] = err.Error()
-		data["PageTitle"] = pageTitle
-		data["Heading"] = heading
-		data["FormAction"] = formAction
-		h.renderTemplate(w, r, "epgsearch_edit.html", data)
-		return
-	}
-
-	matches, err := services.ExecuteSavedEPGSearch(allEvents, search, order)
-	if err != nil {
-		data := h.epgSearchFormData(r, search)
-		data["Error"] = "Invalid search: " + err.Error()
+		if errors.Is(err, domain.ErrInvalidInput) {
+			data["Error"] = "Invalid search: " + err.Error()
+		}
 		data["PageTitle"] = pageTitle
 		data["Heading"] = heading
 		data["FormAction"] = formAction
@@ -2737,6 +3145,7 @@ func (h *Handler) EPGSearchCreate(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	h.triggerSearchTimers(search)
 
 	http.Redirect(w, r, "/epgsearch?msg="+url.QueryEscape("Saved search."), http.StatusSeeOther)
 }
@@ -2835,6 +3244,7 @@ func (h *Handler) EPGSearchUpdate(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	h.triggerSearchTimers(search)
 
 	http.Redirect(w, r, "/epgsearch?msg="+url.QueryEscape("Saved search."), http.StatusSeeOther)
 }
@@ -2904,9 +3314,39 @@ func parseEPGSearchFromForm(form url.Values) config.EPGSearch {
 	s.ChannelID = strings.TrimSpace(form.Get("channel_id"))
 	s.ChannelFrom = strings.TrimSpace(form.Get("channel_from"))
 	s.ChannelTo = strings.TrimSpace(form.Get("channel_to"))
+	s.StartFrom = strings.TrimSpace(form.Get("start_from"))
+	s.StartTo = strings.TrimSpace(form.Get("start_to"))
+	s.MinDuration = formInt(form, "min_duration")
+	s.MaxDuration = formInt(form, "max_duration")
+	s.Weekdays = form["weekdays"]
+	s.Exclude = strings.TrimSpace(form.Get("exclude"))
+	s.HDOnly = form.Get("hd_only") == "on"
+	s.HorizonDays = formInt(form, "horizon_days")
+	s.NotRecorded = form.Get("not_recorded") == "on"
+	s.AutoRecord = form.Get("auto_record") == "on"
+	s.Priority = formInt(form, "priority")
+	s.Lifetime = formInt(form, "lifetime")
+	s.MarginStart = formInt(form, "margin_start")
+	s.MarginEnd = formInt(form, "margin_end")
+	s.Folder = strings.TrimSpace(form.Get("folder"))
+	s.Dedup = strings.TrimSpace(form.Get("dedup"))
 	return s
 }
 
+// formInt returns the integer form value of key; empty values are 0 and
+// invalid ones -1, which validation rejects.
+func formInt(form url.Values, key string) int {
+	v := strings.TrimSpace(form.Get(key))
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
 type timerTimelineDayOption struct {
 	Value string
 	Label string
@@ -2923,7 +3363,9 @@ type timerTimelineBlock struct {
 
 type timerTimelineRow struct {
 	ChannelName string
-	Blocks      []timerTimelineBlock
+	// Channel is the row's channel with its plain name, for the logo.
+	Channel domain.Channel
+	Blocks  []timerTimelineBlock
 }
 
 // TimerList shows all timers
@@ -2955,28 +3397,12 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 		optionsTo = addCalendarDaysHTTP(selectedDayStart, 30)
 	}
 
-	timers, err := h.timerService.GetAllTimers(r.Context())
+	viewBackends, err := h.backendsForView(r)
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
-
-	channels, chErr := h.epgService.GetChannels(r.Context())
-	nameByID := map[string]string{}
-	orderByID := map[string]int{}
-	channelByNumber := map[int]domain.Channel{}
-	if chErr == nil {
-		for i, ch := range channels {
-			if ch.ID != "" {
-				nameByID[ch.ID] = ch.Name
-				orderByID[ch.ID] = i + 1
-			}
-			if ch.Number != 0 {
-				nameByID[strconv.Itoa(ch.Number)] = ch.Name
-				channelByNumber[ch.Number] = ch
-			}
-		}
-	}
+	multiple := h.backendSet().Multiple()
 
 	type timerView struct {
 		domain.Timer
@@ -2985,53 +3411,116 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 		IsCritical      bool
 		IsCollision     bool
 		NextOccurrences []string
-	}
-
-	views := make([]timerView, 0, len(timers))
-	for _, t := range timers {
-		name := nameByID[t.ChannelID]
-		if name == "" {
-			name = t.ChannelID
+		// Device is the DVB device the next recording is expected to use (1-based; 0 if unknown).
+		Device int
+		// WillFail is set when the next recording cannot be recorded completely.
+		WillFail bool
+		// EventVanished is set when the EPG event the timer records is no longer in the EPG.
+		EventVanished bool
+	}
+
+	// Timers, channels and devices belong to a backend, so names are resolved and
+	// conflicts simulated per backend.
+	var views []timerView
+	var backendErrors []string
+	for _, b := range viewBackends {
+		var vanished map[int]services.TimerWatchChange
+		if w := h.watchdogFor(b); w != nil {
+			vanished = w.Vanished()
+		}
+		timers, err := b.Timers.GetAllTimers(r.Context())
+		if err != nil {
+			if !multiple {
+				h.handleError(w, r, err)
+				return
+			}
+			h.logger.Warn("timers fetch error", slog.String("backend", b.Name), slog.Any("error", err))
+			backendErrors = append(backendErrors, b.Name+": "+err.Error())
+			continue
 		}
-		isRec := false
-		if !t.Start.IsZero() && !t.Stop.IsZero() {
-			isRec = (t.Start.Before(localNow) || t.Start.Equal(localNow)) && t.Stop.After(localNow)
+		if multiple {
+			for i := range timers {
+				timers[i].Backend = b.Name
+			}
 		}
 
-		// For recurring timers, show all upcoming occurrences within the next-week horizon.
-		// This matches user expectations for weekday masks (e.g. Thu+Fri at midnight).
-		var nextOcc []string
-		if t.Active && isWeekdayMaskHTTP(t.DaySpec) {
-			occFrom := todayStart
-			occTo := addCalendarDaysHTTP(todayStart, 8)
-			for _, occ := range timerOccurrences(t, occFrom, occTo) {
-				// Keep occurrences that haven't fully ended yet.
-				if !occ.Stop.After(localNow) {
-					continue
+		channels, chErr := b.EPG.GetChannels(r.Context())
+		nameByID := map[string]string{}
+		if chErr == nil {
+			for _, ch := range channels {
+				if ch.ID != "" {
+					nameByID[ch.ID] = ch.Name
+				}
+				if ch.Number != 0 {
+					nameByID[strconv.Itoa(ch.Number)] = ch.Name
 				}
-				s := occ.Start.In(loc).Format("2006-01-02 15:04") + " - " + occ.Stop.In(loc).Format("15:04")
-				nextOcc = append(nextOcc, s)
 			}
 		}
 
-		views = append(views, timerView{Timer: t, ChannelName: name, IsRecording: isRec, NextOccurrences: nextOcc})
-	}
+		first := len(views)
+		for _, t := range timers {
+			name := nameByID[t.ChannelID]
+			if name == "" {
+				name = nameByID[strings.TrimSpace(t.ChannelID)]
+			}
+			if strings.TrimSpace(name) == "" {
+				name = t.ChannelID
+			}
+			isRec := false
+			if !t.Start.IsZero() && !t.Stop.IsZero() {
+				isRec = (t.Start.Before(localNow) || t.Start.Equal(localNow)) && t.Stop.After(localNow)
+			}
 
-	// Mark overlapping timers (yellow) and critical timers (red) based on configured DVB cards.
-	dvbCards := 1
-	if h.cfg != nil && h.cfg.VDR.DVBCards > 0 {
-		dvbCards = h.cfg.VDR.DVBCards
-	}
+			// For recurring timers, show all upcoming occurrences within the next-week horizon.
+			// This matches user expectations for weekday masks (e.g. Thu+Fri at midnight).
+			var nextOcc []string
+			if t.Active && isWeekdayMaskHTTP(t.DaySpec) {
+				occFrom := todayStart
+				occTo := addCalendarDaysHTTP(todayStart, 8)
+				for _, occ := range timerOccurrences(t, occFrom, occTo) {
+					// Keep occurrences that haven't fully ended yet.
+					if !occ.Stop.After(localNow) {
+						continue
+					}
+					s := occ.Start.In(loc).Format("2006-01-02 15:04") + " - " + occ.Stop.In(loc).Format("15:04")
+					nextOcc = append(nextOcc, s)
+				}
+			}
 
-	collisionIDs, criticalIDs := timerOverlapStatesAt(timers, dvbCards, collisionWindowFrom, collisionWindowTo, localNow, func(t domain.Timer) string {
-		return transponderKeyForTimer(t, channels)
-	})
-	for i := range views {
-		if collisionIDs[views[i].ID] {
-			views[i].IsCollision = true
+			view := timerView{Timer: t, ChannelName: name, IsRecording: isRec, NextOccurrences: nextOcc}
+			if c, ok := vanished[t.ID]; ok && c.EventID == t.EventID {
+				view.EventVanished = true
+			}
+			views = append(views, view)
 		}
-		if criticalIDs[views[i].ID] {
-			views[i].IsCritical = true
+
+		// Mark overlapping timers (yellow) and critical timers (red) based on the backend's DVB cards.
+		report := services.SimulateTimerAllocation(timers, services.AllocationOptions{
+			DVBCards: h.dvbCardsFor(b),
+			From:     collisionWindowFrom,
+			To:       collisionWindowTo,
+			Now:      localNow,
+			TransponderKey: func(t domain.Timer) string {
+				return transponderKeyForTimer(t, channels)
+			},
+		})
+		collisionIDs, criticalIDs := report.CollisionIDs, report.CriticalIDs
+		for i := first; i < len(views); i++ {
+			if collisionIDs[views[i].ID] {
+				views[i].IsCollision = true
+			}
+			if criticalIDs[views[i].ID] {
+				views[i].IsCritical = true
+			}
+			// Report the allocation of the next recording that hasn't ended yet.
+			for _, a := range report.AllocationsForTimer(views[i].ID) {
+				if !a.Stop.After(localNow) {
+					continue
+				}
+				views[i].Device = a.Device
+				views[i].WillFail = a.Failed()
+				break
+			}
 		}
 	}
 
@@ -3080,7 +3569,8 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 	// Build timeline day options from active timer occurrences in the stable horizon.
 	daysByValue := map[string]time.Time{}
 	recurringHorizonTo := addCalendarDaysHTTP(todayStart, 8)
-	for _, t := range timers {
+	for _, v := range views {
+		t := v.Timer
 		if !t.Active {
 			continue
 		}
@@ -3151,28 +3641,15 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	resolveChannel := func(timer domain.Timer) string {
-		name := nameByID[timer.ChannelID]
-		if chErr == nil {
-			if n, err := strconv.Atoi(strings.TrimSpace(timer.ChannelID)); err == nil {
-				if ch, ok := channelByNumber[n]; ok {
-					name = ch.Name
-				}
-			}
-		}
-		if strings.TrimSpace(name) == "" {
-			name = timer.ChannelID
-		}
-		return name
-	}
-
 	blocksByChannel := map[string][]timerTimelineBlock{}
+	channelByName := map[string]domain.Channel{}
 	if len(availableDays) > 0 {
 		// Only generate occurrences around the selected day; this keeps rendering fast
 		// while still handling timers that cross midnight.
 		blockWindowFrom := addCalendarDaysHTTP(selectedDayStart, -1)
 		blockWindowTo := addCalendarDaysHTTP(selectedDayEnd, 1)
-		for _, t := range timers {
+		for _, v := range views {
+			t := v.Timer
 			if !t.Active {
 				continue
 			}
@@ -3194,7 +3671,10 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 					continue
 				}
 
-				channelName := resolveChannel(t)
+				channelName := v.ChannelName
+				if multiple {
+					channelName += " (" + t.Backend + ")"
+				}
 
 				start := occ.Start.In(loc)
 				stop := occ.Stop.In(loc)
@@ -3220,12 +3700,13 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 				}
 
 				cls := "ok"
-				if criticalIDs[t.ID] {
+				if v.IsCritical {
 					cls = "critical"
-				} else if collisionIDs[t.ID] {
+				} else if v.IsCollision {
 					cls = "collision"
 				}
 
+				channelByName[channelName] = domain.Channel{ID: t.ChannelID, Name: v.ChannelName}
 				blocksByChannel[channelName] = append(blocksByChannel[channelName], timerTimelineBlock{
 					Title:      t.Title,
 					StartLabel: start.Format("15:04"),
@@ -3246,7 +3727,7 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 			}
 			return blocks[i].Title < blocks[j].Title
 		})
-		rows = append(rows, timerTimelineRow{ChannelName: channelName, Blocks: blocks})
+		rows = append(rows, timerTimelineRow{ChannelName: channelName, Channel: channelByName[channelName], Blocks: blocks})
 	}
 	sort.SliceStable(rows, func(i, j int) bool {
 		return strings.ToLower(rows[i].ChannelName) < strings.ToLower(rows[j].ChannelName)
@@ -3263,184 +3744,56 @@ func (h *Handler) TimerList(w http.ResponseWriter, r *http.Request) {
 		"TimelineSelectedDay": selectedDayStart.Format("2006-01-02"),
 		"TimelineHours":       hours,
 		"TimelineRows":        rows,
+		"Message":             strings.TrimSpace(r.URL.Query().Get("msg")),
+		"Error":               strings.TrimSpace(r.URL.Query().Get("err")),
 	}
+	if len(backendErrors) > 0 {
+		data["BackendErrors"] = backendErrors
+	}
+	if changes := h.watchChanges(); len(changes) > 0 {
+		if len(changes) > timerWatchChangesShown {
+			changes = changes[:timerWatchChangesShown]
+		}
+		data["WatchChanges"] = changes
+	}
+	h.addBackendData(data, r)
 
 	h.renderTemplate(w, r, "timers.html", data)
 }
 
+// timerWatchChangesShown is the number of timer watchdog changes the timers page lists.
+const timerWatchChangesShown = 10
+
 func transponderKeyForTimer(t domain.Timer, channels []domain.Channel) string {
-	chID := strings.TrimSpace(t.ChannelID)
-	if looksLikeVDRChannelID(chID) {
-		return transponderKeyFromChannelID(chID)
-	}
-	if n, err := strconv.Atoi(chID); err == nil {
-		for i := range channels {
-			if channels[i].Number == n {
-				if looksLikeVDRChannelID(channels[i].ID) {
-					return transponderKeyFromChannelID(channels[i].ID)
-				}
-				break
-			}
-		}
-	}
-	// Unknown channel; treat as unique so it consumes a tuner.
-	return chID
+	return services.TransponderKeyForTimer(t, channels)
 }
 
 func transponderKeyFromChannelID(channelID string) string {
-	parts := strings.Split(channelID, "-")
-	if len(parts) >= 3 {
-		return strings.Join(parts[:3], "-")
-	}
-	return channelID
-}
-
-func looksLikeVDRChannelID(s string) bool {
-	s = strings.TrimSpace(s)
-	return strings.Contains(s, "-") && (strings.HasPrefix(s, "S") || strings.HasPrefix(s, "C") || strings.HasPrefix(s, "T") || strings.HasPrefix(s, "A"))
+	return services.TransponderKey(channelID)
 }
 
-type timerOccurrence struct {
-	TimerID int
-	Start   time.Time
-	Stop    time.Time
-	Key     string
-}
+type timerOccurrence = services.TimerOccurrence
 
 func timerOverlapStates(timers []domain.Timer, dvbCards int, from, to time.Time, transponderKey func(domain.Timer) string) (collisionIDs, criticalIDs map[int]bool) {
 	return timerOverlapStatesAt(timers, dvbCards, from, to, time.Now(), transponderKey)
 }
 
+// timerOverlapStatesAt classifies timers for highlighting: collision (yellow) when an
+// overlap needs more than one device, critical (red) when the simulated device
+// allocation cannot record every overlapping timer.
 func timerOverlapStatesAt(timers []domain.Timer, dvbCards int, from, to, now time.Time, transponderKey func(domain.Timer) string) (collisionIDs, criticalIDs map[int]bool) {
-	if dvbCards < 1 {
-		dvbCards = 1
-	}
-	collisionIDs = map[int]bool{}
-	criticalIDs = map[int]bool{}
-
-	occs := make([]timerOccurrence, 0, len(timers))
-	for _, t := range timers {
-		if !t.Active && !timerIsCurrentlyRecording(t, now) {
-			continue
-		}
-		key := transponderKey(t)
-		if key == "" {
-			key = strconv.Itoa(t.ID)
-		}
-		for _, occ := range timerOccurrences(t, from, to) {
-			occ.Key = key
-			occs = append(occs, occ)
-		}
-	}
-
-	type endpoint struct {
-		at    time.Time
-		start bool
-		idx   int
-	}
-	endpoints := make([]endpoint, 0, len(occs)*2)
-	for i := range occs {
-		endpoints = append(endpoints, endpoint{at: occs[i].Start, start: true, idx: i})
-		endpoints = append(endpoints, endpoint{at: occs[i].Stop, start: false, idx: i})
-	}
-
-	sort.SliceStable(endpoints, func(i, j int) bool {
-		if endpoints[i].at.Equal(endpoints[j].at) {
-			if endpoints[i].start != endpoints[j].start {
-				return !endpoints[i].start && endpoints[j].start
-			}
-			return endpoints[i].idx < endpoints[j].idx
-		}
-		return endpoints[i].at.Before(endpoints[j].at)
+	report := services.SimulateTimerAllocation(timers, services.AllocationOptions{
+		DVBCards:       dvbCards,
+		From:           from,
+		To:             to,
+		Now:            now,
+		TransponderKey: transponderKey,
 	})
-
-	activeOcc := map[int]struct{}{}
-	activeByKey := map[string]int{}
-
-	for _, e := range endpoints {
-		occ := occs[e.idx]
-		if e.start {
-			activeOcc[e.idx] = struct{}{}
-			activeByKey[occ.Key]++
-		} else {
-			delete(activeOcc, e.idx)
-			if activeByKey[occ.Key] > 1 {
-				activeByKey[occ.Key]--
-			} else {
-				delete(activeByKey, occ.Key)
-			}
-		}
-
-		// Yellow: any overlap that requires more than one transponder/tuner.
-		if len(activeByKey) > 1 {
-			for idx := range activeOcc {
-				collisionIDs[occs[idx].TimerID] = true
-			}
-		}
-		// Red: exceeds available DVB cards.
-		if len(activeByKey) > dvbCards {
-			for idx := range activeOcc {
-				criticalIDs[occs[idx].TimerID] = true
-			}
-		}
-	}
-
-	return collisionIDs, criticalIDs
-}
-
-func timerIsCurrentlyRecording(t domain.Timer, now time.Time) bool {
-	if now.IsZero() || t.Start.IsZero() || t.Stop.IsZero() {
-		return false
-	}
-	return (t.Start.Before(now) || t.Start.Equal(now)) && t.Stop.After(now)
+	return report.CollisionIDs, report.CriticalIDs
 }
 
 func timerOccurrences(t domain.Timer, from, to time.Time) []timerOccurrence {
-	if from.After(to) {
-		return nil
-	}
-	if !t.Stop.After(t.Start) || t.Start.IsZero() || t.Stop.IsZero() {
-		// For recurring timers we may not have computed Start/Stop; rely on raw specs.
-		// We'll handle them below if we detect a weekday mask.
-	}
-
-	// One-time timer with concrete timestamps.
-	if !t.Start.IsZero() && !t.Stop.IsZero() && t.Stop.After(t.Start) && !isWeekdayMaskHTTP(t.DaySpec) {
-		if t.Stop.Before(from) || !t.Start.Before(to) {
-			return nil
-		}
-		return []timerOccurrence{{TimerID: t.ID, Start: t.Start, Stop: t.Stop}}
-	}
-
-	// Recurring timer: project onto each matching weekday in the window.
-	daySpec := strings.TrimSpace(t.DaySpec)
-	if !isWeekdayMaskHTTP(daySpec) {
-		return nil
-	}
-	if t.StartMinutes < 0 || t.StopMinutes < 0 {
-		return nil
-	}
-
-	loc := time.Local
-	startDay := time.Date(from.In(loc).Year(), from.In(loc).Month(), from.In(loc).Day(), 0, 0, 0, 0, loc)
-	endDay := time.Date(to.In(loc).Year(), to.In(loc).Month(), to.In(loc).Day(), 0, 0, 0, 0, loc)
-
-	var out []timerOccurrence
-	for d := startDay; d.Before(endDay); d = addCalendarDaysHTTP(d, 1) {
-		if !weekdayMaskAllowsHTTP(daySpec, d.Weekday()) {
-			continue
-		}
-		start := d.Add(time.Duration(t.StartMinutes) * time.Minute)
-		stop := d.Add(time.Duration(t.StopMinutes) * time.Minute)
-		if stop.Before(start) {
-			stop = stop.Add(24 * time.Hour)
-		}
-		if stop.Before(from) || !start.Before(to) {
-			continue
-		}
-		out = append(out, timerOccurrence{TimerID: t.ID, Start: start, Stop: stop})
-	}
-	return out
+	return services.TimerOccurrences(t, from, to)
 }
 
 func scheduledTimerForEvent(
@@ -3460,19 +3813,7 @@ func scheduledTimerForEvent(
 }
 
 func isWeekdayMaskHTTP(daySpec string) bool {
-	daySpec = strings.TrimSpace(daySpec)
-	if len(daySpec) != 7 {
-		return false
-	}
-	for _, r := range daySpec {
-		switch r {
-		case 'M', 'T', 'W', 'F', 'S', '-', '.':
-			// ok
-		default:
-			return false
-		}
-	}
-	return true
+	return services.IsWeekdayMask(daySpec)
 }
 
 func formatClockFromMinutes(min int) string {
@@ -3507,28 +3848,7 @@ func weekdayMaskFromForm(form url.Values) (string, bool) {
 }
 
 func weekdayMaskAllowsHTTP(daySpec string, wd time.Weekday) bool {
-	idx := 0
-	switch wd {
-	case time.Monday:
-		idx = 0
-	case time.Tuesday:
-		idx = 1
-	case time.Wednesday:
-		idx = 2
-	case time.Thursday:
-		idx = 3
-	case time.Friday:
-		idx = 4
-	case time.Saturday:
-		idx = 5
-	case time.Sunday:
-		idx = 6
-	}
-	if idx < 0 || idx >= len(daySpec) {
-		return false
-	}
-	c := daySpec[idx]
-	return c != '-' && c != '.'
+	return services.WeekdayMaskAllows(daySpec, wd)
 }
 
 type timerFormModel struct {
@@ -3584,7 +3904,13 @@ func (h *Handler) TimerNew(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	channels, err := h.epgService.GetChannels(r.Context())
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	channels, err := b.EPG.GetChannels(r.Context())
 	if err != nil {
 		channels = []domain.Channel{}
 	}
@@ -3623,6 +3949,8 @@ func (h *Handler) TimerNew(w http.ResponseWriter, r *http.Request) {
 		"SelectedChannel": selectedChannel,
 		"Channels":        channels,
 	}
+	h.addBackendData(data, r)
+	data["SelectedBackend"] = b.Name
 	h.renderTemplate(w, r, "timer_edit.html", data)
 }
 
@@ -3639,7 +3967,13 @@ func (h *Handler) TimerEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	timers, err := h.timerService.GetAllTimers(r.Context())
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	timers, err := b.Timers.GetAllTimers(r.Context())
 	if err != nil {
 		h.handleError(w, r, err)
 		return
@@ -3657,7 +3991,7 @@ func (h *Handler) TimerEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	channels, chErr := h.epgService.GetChannels(r.Context())
+	channels, chErr := b.EPG.GetChannels(r.Context())
 	if chErr != nil {
 		channels = []domain.Channel{}
 	}
@@ -3696,6 +4030,8 @@ func (h *Handler) TimerEdit(w http.ResponseWriter, r *http.Request) {
 		"SelectedChannel": selectedChannel,
 		"Channels":        channels,
 	}
+	h.addBackendData(data, r)
+	data["SelectedBackend"] = b.Name
 	h.renderTemplate(w, r, "timer_edit.html", data)
 }
 
@@ -3793,23 +4129,25 @@ func (h *Handler) TimerCreateManual(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	timer, err := h.timerFromCreateForm(r)
+	b, err := h.backendFromRequest(r)
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
 
-	if err := h.timerService.CreateTimer(r.Context(), &timer); err != nil {
+	timer, err := h.timerFromCreateForm(r)
+	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
 
-	if r.Header.Get("HX-Request") != "" {
-		w.Header().Set("HX-Redirect", "/timers")
-		w.WriteHeader(http.StatusOK)
+	warning := h.timerConflictWarning(r.Context(), b.Timers, &timer)
+	if err := b.Timers.CreateTimer(r.Context(), &timer); err != nil {
+		h.handleTimerChangeError(w, r, err)
 		return
 	}
-	http.Redirect(w, r, "/timers", http.StatusSeeOther)
+
+	h.redirectToTimers(w, r, warning)
 }
 
 // TimerUpdate persists edits to an existing timer.
@@ -3824,23 +4162,76 @@ func (h *Handler) TimerUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
 	timer, err := h.timerFromForm(r)
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
 
-	if err := h.timerService.UpdateTimer(r.Context(), &timer); err != nil {
+	warning := h.timerConflictWarning(r.Context(), b.Timers, &timer)
+	if err := b.Timers.UpdateTimer(r.Context(), &timer); err != nil {
+		h.handleTimerChangeError(w, r, err)
+		return
+	}
+
+	h.redirectToTimers(w, r, warning)
+}
+
+// timerConflictWarning checks a timer that is about to be created or updated and
+// returns a warning when it would make recordings fail. It returns "" unless the
+// conflict policy is "warn"; with "reject" the timer service refuses the change itself.
+// timers is the timer service of the backend the timer belongs to.
+func (h *Handler) timerConflictWarning(ctx context.Context, timers *services.TimerService, timer *domain.Timer) string {
+	if timers.ConflictPolicy() != services.ConflictPolicyWarn {
+		return ""
+	}
+	conflict, err := timers.CheckTimerConflicts(ctx, timer)
+	if err != nil {
+		h.logger.Warn("timer conflict check failed", slog.Any("error", err))
+		return ""
+	}
+	if conflict == nil {
+		return ""
+	}
+	return "Warning: " + conflict.Error()
+}
+
+// handleTimerChangeError sends conflicts back to the timer list so the user sees why
+// the change was refused; all other errors go through handleError.
+func (h *Handler) handleTimerChangeError(w http.ResponseWriter, r *http.Request, err error) {
+	var conflict *services.TimerConflictError
+	if !errors.As(err, &conflict) {
 		h.handleError(w, r, err)
 		return
 	}
+	target := "/timers?err=" + url.QueryEscape(conflict.Error())
+	if r.Header.Get("HX-Request") != "" {
+		w.Header().Set("HX-Redirect", target)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
 
+// redirectToTimers finishes a successful timer change, carrying an optional message.
+func (h *Handler) redirectToTimers(w http.ResponseWriter, r *http.Request, msg string) {
+	target := "/timers"
+	if msg != "" {
+		target += "?msg=" + url.QueryEscape(msg)
+	}
+	// Support both HTMX requests and normal browser form posts.
 	if r.Header.Get("HX-Request") != "" {
-		w.Header().Set("HX-Redirect", "/timers")
+		w.Header().Set("HX-Redirect", target)
 		w.WriteHeader(http.StatusOK)
 		return
 	}
-	http.Redirect(w, r, "/timers", http.StatusSeeOther)
+	http.Redirect(w, r, target, http.StatusSeeOther)
 }
 
 func (h *Handler) timerToFormModel(t domain.Timer) timerFormModel {
@@ -4018,6 +4409,12 @@ func (h *Handler) TimerCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
 	eventID, _ := strconv.Atoi(r.FormValue("event_id"))
 	channelID := r.FormValue("channel")
 	if eventID > 0 {
@@ -4025,9 +4422,9 @@ func (h *Handler) TimerCreate(w http.ResponseWriter, r *http.Request) {
 		var events []domain.EPGEvent
 		var err error
 		if channelID != "" {
-			events, err = h.epgService.GetEPG(r.Context(), channelID, time.Time{})
+			events, err = b.EPG.GetEPG(r.Context(), channelID, time.Time{})
 		} else {
-			events, err = h.epgService.GetEPG(r.Context(), "", time.Time{})
+			events, err = b.EPG.GetEPG(r.Context(), "", time.Time{})
 		}
 		if err != nil {
 			h.handleError(w, r, err)
@@ -4043,19 +4440,15 @@ func (h *Handler) TimerCreate(w http.ResponseWriter, r *http.Request) {
 			}
 
 			priority, lifetime, marginStart, marginEnd := h.timerDefaults()
-			err := h.timerService.CreateTimerFromEPG(r.Context(), event, priority, lifetime, marginStart, marginEnd)
+			candidate := services.NewTimerFromEPG(event, priority, lifetime, marginStart, marginEnd)
+			warning := h.timerConflictWarning(r.Context(), b.Timers, &candidate)
+			err := b.Timers.CreateTimerFromEPG(r.Context(), event, priority, lifetime, marginStart, marginEnd)
 			if err != nil {
-				h.handleError(w, r, err)
+				h.handleTimerChangeError(w, r, err)
 				return
 			}
 
-			// Support both HTMX requests and normal browser form posts.
-			if r.Header.Get("HX-Request") != "" {
-				w.Header().Set("HX-Redirect", "/timers")
-				w.WriteHeader(http.StatusOK)
-			} else {
-				http.Redirect(w, r, "/timers", http.StatusSeeOther)
-			}
+			h.redirectToTimers(w, r, warning)
 			return
 		}
 	}
@@ -4103,7 +4496,13 @@ func (h *Handler) TimerDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.timerService.DeleteTimer(r.Context(), timerID); err != nil {
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := b.Timers.DeleteTimer(r.Context(), timerID); err != nil {
 		h.handleError(w, r, err)
 		return
 	}
@@ -4129,11 +4528,17 @@ func (h *Handler) TimerToggle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.timerService.ToggleTimer(r.Context(), timerID); err != nil {
+	b, err := h.backendFromRequest(r)
+	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
 
+	if err := b.Timers.ToggleTimer(r.Context(), timerID); err != nil {
+		h.handleTimerChangeError(w, r, err)
+		return
+	}
+
 	if r.Header.Get("HX-Request") != "" {
 		w.Header().Set("HX-Redirect", "/timers")
 		w.WriteHeader(http.StatusOK)
@@ -4144,37 +4549,79 @@ func (h *Handler) TimerToggle(w http.ResponseWriter, r *http.Request) {
 
 // RecordingList shows all recordings
 func (h *Handler) RecordingList(w http.ResponseWriter, r *http.Request) {
-	recordings, err := h.recordingService.GetAllRecordings(r.Context())
+	viewBackends, err := h.backendsForView(r)
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
+	recordings, err := h.backendSet().Recordings(r.Context(), viewBackends)
+	if err != nil && !isPartialBackendError(err) {
+		h.handleError(w, r, err)
+		return
+	}
 
 	q := strings.TrimSpace(r.URL.Query().Get("q"))
 	includeSubtitle := isTruthy(r.URL.Query().Get("in_subtitle"))
 	includePath := isTruthy(r.URL.Query().Get("in_path"))
-	recordings = filterRecordings(recordings, q, includeSubtitle, includePath)
 
 	sortBy := r.URL.Query().Get("sort")
 	if sortBy == "" {
 		sortBy = "date"
 	}
-	recordings = h.recordingService.SortRecordings(recordings, sortBy)
 
 	data := map[string]any{
-		"Recordings": recordings,
 		"Sort":       sortBy,
 		"Query":      q,
 		"InSubtitle": includeSubtitle,
 		"InPath":     includePath,
 	}
-	if role, _ := r.Context().Value("role").(string); role == "admin" {
-		data["ActiveArchiveJobs"] = h.archiveJobs.ActiveJobIDsByRecording()
+	if err != nil {
+		data["BackendError"] = err.Error()
+	}
+	folder := strings.TrimSpace(r.URL.Query().Get("folder"))
+	if err := h.addRecordingListData(data, r, recordings, q, includeSubtitle, includePath, sortBy, folder); err != nil {
+		h.handleError(w, r, err)
+		return
 	}
+	h.addBackendData(data, r)
 
 	h.renderTemplate(w, r, "recordings.html", data)
 }
 
+// addRecordingListData adds the recordings to show. A search of at least three
+// characters lists the matching recordings of all folders; otherwise the page
+// shows the given folder as a tree.
+func (h *Handler) addRecordingListData(data map[string]any, r *http.Request, recordings []domain.Recording, q string, includeSubtitle, includePath bool, sortBy, folder string) error {
+	views := h.newRecordingViews(r, sortBy)
+
+	root := services.BuildRecordingTree(recordings)
+	if views.bulk {
+		data["BulkMove"] = true
+		paths := folderPaths(root)
+		sort.Strings(paths)
+		data["FolderPaths"] = paths
+	}
+
+	if utf8.RuneCountInString(q) >= 3 {
+		recordings = filterRecordings(recordings, q, includeSubtitle, includePath)
+		recordings = h.recordingService.SortRecordings(recordings, sortBy)
+		data["Recordings"] = views.items(recordings)
+		return nil
+	}
+
+	node, trail, err := services.FindRecordingFolder(root, folder)
+	if err != nil {
+		return fmt.Errorf("recording folder %q: %w", folder, err)
+	}
+	services.SortRecordingTree(node, sortBy)
+	data["Tree"] = views.folder(node)
+	data["Folder"] = node.Path
+	if len(trail) > 1 {
+		data["Breadcrumbs"] = views.breadcrumbs(trail)
+	}
+	return nil
+}
+
 // RecordingRefresh invalidates the recordings cache and returns a fresh list.
 // It is intended for cases where recordings are changed out-of-band (e.g. deleted on disk).
 func (h *Handler) RecordingRefresh(w http.ResponseWriter, r *http.Request) {
@@ -4210,26 +4657,40 @@ func (h *Handler) RecordingRefresh(w http.ResponseWriter, r *http.Request) {
 		includePath = isTruthy(r.URL.Query().Get("in_path"))
 	}
 
-	h.recordingService.InvalidateCache()
-
-	recordings, err := h.recordingService.GetAllRecordings(r.Context())
+	viewBackends, err := h.backendsForView(r)
 	if err != nil {
 		h.handleError(w, r, err)
 		return
 	}
-	recordings = filterRecordings(recordings, q, includeSubtitle, includePath)
-	recordings = h.recordingService.SortRecordings(recordings, sortBy)
+	for _, b := range viewBackends {
+		b.Recordings.InvalidateCache()
+	}
+
+	recordings, err := h.backendSet().Recordings(r.Context(), viewBackends)
+	if err != nil && !isPartialBackendError(err) {
+		h.handleError(w, r, err)
+		return
+	}
 
 	data := map[string]any{
-		"Recordings": recordings,
 		"Sort":       sortBy,
 		"Query":      q,
 		"InSubtitle": includeSubtitle,
 		"InPath":     includePath,
 	}
-	if role, _ := r.Context().Value("role").(string); role == "admin" {
-		data["ActiveArchiveJobs"] = h.archiveJobs.ActiveJobIDsByRecording()
+	if err != nil {
+		data["BackendError"] = err.Error()
+	}
+	folder := strings.TrimSpace(r.FormValue("folder"))
+	if err := h.addRecordingListData(data, r, recordings, q, includeSubtitle, includePath, sortBy, folder); err != nil {
+		// The folder may have disappeared with the refresh; show the top level.
+		folder = ""
+		if err := h.addRecordingListData(data, r, recordings, q, includeSubtitle, includePath, sortBy, folder); err != nil {
+			h.handleError(w, r, err)
+			return
+		}
 	}
+	h.addBackendData(data, r)
 
 	// For non-HTMX browsers, behave like a standard action.
 	if r.Header.Get("HX-Request") == "" {
@@ -4244,6 +4705,12 @@ func (h *Handler) RecordingRefresh(w http.ResponseWriter, r *http.Request) {
 		if includePath {
 			params.Set("in_path", "1")
 		}
+		if folder != "" {
+			params.Set("folder", folder)
+		}
+		if name := backendName(r); name != "" {
+			params.Set("backend", name)
+		}
 		http.Redirect(w, r, "/recordings?"+params.Encode(), http.StatusSeeOther)
 		return
 	}
@@ -4301,14 +4768,20 @@ func (h *Handler) RecordingDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
 	// Validate the recording path to prevent directory traversal
-	if err := h.validateRecordingPath(path); err != nil {
+	if err := validateRecordingPathIn(h.videoDirFor(b), path); err != nil {
 		h.logger.Warn("invalid recording path rejected", slog.String("path", path), slog.Any("error", err))
 		http.Error(w, "Invalid path", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.recordingService.DeleteRecording(r.Context(), path); err != nil {
+	if err := b.Recordings.DeleteRecording(r.Context(), path); err != nil {
 		h.handleError(w, r, err)
 		return
 	}
@@ -4316,6 +4789,153 @@ func (h *Handler) RecordingDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// RecordingMove moves the selected recordings (form values "path") into the
+// folder given by "folder" and shows that folder.
+func (h *Handler) RecordingMove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	folder := r.FormValue("folder")
+	if _, err := b.Recordings.MoveRecordings(r.Context(), r.Form["path"], folder); err != nil {
+		h.handleRecordingEditError(w, r, err)
+		return
+	}
+	h.redirectToRecordingFolder(w, r, folder)
+}
+
+// RecordingRename gives a recording a new name within its folder.
+func (h *Handler) RecordingRename(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	b, err := h.backendFromRequest(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := b.Recordings.RenameRecording(r.Context(), r.FormValue("path"), r.FormValue("name")); err != nil {
+		h.handleRecordingEditError(w, r, err)
+		return
+	}
+	h.redirectToRecordingFolder(w, r, r.FormValue("folder"))
+}
+
+// handleRecordingEditError explains refused changes to recordings, e.g. moves of
+// recordings that are being archived, instead of answering with a bare status.
+func (h *Handler) handleRecordingEditError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, domain.ErrInvalidInput):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.handleError(w, r, err)
+	}
+}
+
+func (h *Handler) redirectToRecordingFolder(w http.ResponseWriter, r *http.Request, folder string) {
+	params := url.Values{}
+	if folder = strings.Trim(strings.ReplaceAll(strings.TrimSpace(folder), "/", services.FolderSeparator), services.FolderSeparator); folder != "" {
+		params.Set("folder", folder)
+	}
+	if name := backendName(r); name != "" {
+		params.Set("backend", name)
+	}
+	target := "/recordings"
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+	if r.Header.Get("HX-Request") != "" {
+		w.Header().Set("HX-Redirect", target)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// RecordingPlay starts the replay of a recording on the VDR output ("from":
+// resume, begin or offset with "offset" as [hh:]mm:ss or minutes) and opens the
+// Watch TV remote.
+func (h *Handler) RecordingPlay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.recordingService == nil {
+		http.Error(w, "Recording service not available", http.StatusInternalServerError)
+		return
+	}
+
+	var start domain.ReplayStart
+	switch strings.TrimSpace(r.FormValue("from")) {
+	case "", "resume":
+	case "begin":
+		start.FromBeginning = true
+	case "offset":
+		offset, err := parseReplayOffset(r.FormValue("offset"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		start.Offset = offset
+	default:
+		http.Error(w, "Invalid start position", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.recordingService.PlayRecording(r.Context(), r.FormValue("path"), start); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if r.Header.Get("HX-Request") != "" {
+		w.Header().Set("HX-Redirect", "/watch")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, "/watch", http.StatusSeeOther)
+}
+
+// parseReplayOffset parses a replay position given as hh:mm:ss, mm:ss or minutes.
+func parseReplayOffset(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, fmt.Errorf("%w: missing offset", domain.ErrInvalidInput)
+	}
+	parts := strings.Split(v, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("%w: invalid offset %q", domain.ErrInvalidInput, v)
+	}
+	if len(parts) == 1 {
+		// Plain minutes.
+		parts = []string{parts[0], "0"}
+	}
+	var secs int
+	for i, p := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("%w: invalid offset %q", domain.ErrInvalidInput, v)
+		}
+		secs = secs*60 + n
+	}
+	return time.Duration(secs) * time.Second, nil
+}
+
 // RecordingArchivePrepare shows a preview form for archiving a recording.
 // MVP: preview-only (directory naming + target paths), no ffmpeg execution yet.
 func (h *Handler) RecordingArchivePrepare(w http.ResponseWriter, r *http.Request) {
@@ -4450,6 +5070,7 @@ func (h *Handler) RecordingArchivePrepare(w http.ResponseWriter, r *http.Request
 		"SelectedProfileID": selectedID,
 		"Format":            format,
 		"ArchiveWarning":    warn,
+		"MarkCount":         recordingMarkCount(recDir),
 	}
 	if perr != nil {
 		data["Error"] = perr.Error()
@@ -4468,6 +5089,20 @@ func (h *Handler) RecordingArchivePrepare(w http.ResponseWriter, r *http.Request
 	h.renderTemplate(w, r, "recording_archive.html", data)
 }
 
+// recordingMarkCount returns the number of cutting marks of a recording; it
+// is 0 if the marks cannot be read.
+func recordingMarkCount(recDir string) int {
+	rec, err := cutting.Open(recDir)
+	if err != nil {
+		return 0
+	}
+	marks, err := rec.Marks()
+	if err != nil {
+		return 0
+	}
+	return len(marks)
+}
+
 // RecordingArchiveStart starts a background archive job and redirects to the job page.
 func (h *Handler) RecordingArchiveStart(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -4590,6 +5225,12 @@ func (h *Handler) RecordingArchiveStart(w http.ResponseWriter, r *http.Request)
 		}
 		plan, planErr = archive.BuildPlan(recID, recDir, infoPath, selected, title, episode, format, ffArgs)
 	}
+	markedOnly := r.FormValue("marked_only") != ""
+	if planErr == nil && markedOnly {
+		if err := plan.LimitToMarks(); err != nil {
+			planErr = fmt.Errorf("cannot limit the archive to the marked parts: %w", err)
+		}
+	}
 	if planErr != nil {
 		h.renderTemplate(w, r, "recording_archive.html", map[string]any{
 			"Error":             planErr.Error(),
@@ -4601,6 +5242,8 @@ func (h *Handler) RecordingArchiveStart(w http.ResponseWriter, r *http.Request)
 			"Profiles":          profiles,
 			"SelectedProfileID": profileID,
 			"Format":            format,
+			"MarkCount":         recordingMarkCount(recDir),
+			"MarkedOnly":        markedOnly,
 			"Preview": &archive.Preview{
 				TargetDir:   oTargetDir,
 				VideoPath:   oVideoPath,
@@ -4640,6 +5283,8 @@ func (h *Handler) RecordingArchiveStart(w http.ResponseWriter, r *http.Request)
 				"Profiles":          profiles,
 				"SelectedProfileID": profileID,
 				"Preview":           plan.Preview,
+				"MarkCount":         recordingMarkCount(recDir),
+				"MarkedOnly":        markedOnly,
 				"OutputExists":      true,
 				"OutputExistsPath":  plan.Preview.VideoPath,
 			})
@@ -4647,7 +5292,8 @@ func (h *Handler) RecordingArchiveStart(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	jobID, err := h.archiveJobs.Start(context.Background(), plan, h.instanceID)
+	// The job outlives the request; keep only its values (e.g. the trace to link to).
+	jobID, err := h.archiveJobs.Start(context.WithoutCancel(r.Context()), plan, h.instanceID)
 	if err != nil {
 		h.handleError(w, r, err)
 		return
@@ -5099,7 +5745,10 @@ func (h *Handler) renderTemplate(w http.ResponseWriter, r *http.Request, name st
 	}
 
 	// Execute the template
+	_, span := startSpan(r.Context(), "render "+name, attribute.String("template", name))
+	defer span.End()
 	if err := tmpl.ExecuteTemplate(w, name, data); err != nil {
+		span.RecordError(err)
 		h.logger.Error("template error", slog.Any("error", err), slog.String("template", name))
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
@@ -5133,6 +5782,26 @@ func (h *Handler) handleError(w http.ResponseWriter, r *http.Request, err error)
 	case domain.ErrForbidden:
 		http.Error(w, "Forbidden", http.StatusForbidden)
 	default:
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidInput) {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrChannelInUse) || errors.Is(err, domain.ErrChannelExists) {
+			http.Error(w, "Conflict", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, domain.ErrChannelsLocked) {
+			http.Error(w, "VDR's channels are being edited, try again", http.StatusServiceUnavailable)
+			return
+		}
+		if errors.Is(err, domain.ErrBusy) {
+			http.Error(w, "VDR is busy serving another SVDRP client", http.StatusServiceUnavailable)
+			return
+		}
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -426,6 +426,16 @@ func (p *HLSProxy) cleanupLoop() {
 	}
 }
 
+// ActiveStreams returns the number of running streams, each backed by one ffmpeg process.
+func (p *HLSProxy) ActiveStreams() int {
+	n := 0
+	p.streams.Range(func(key, value any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
 // Shutdown stops all active streams.
 func (p *HLSProxy) Shutdown() {
 	p.streams.Range(func(key, value any) bool {
@@ -0,0 +1,137 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/logos"
+)
+
+// Logos and monograms are cached by the browser; a logo replaced on disk shows
+// up after a day, a channel that just got a logo after an hour.
+const (
+	logoCacheControl     = "private, max-age=86400"
+	monogramCacheControl = "private, max-age=3600"
+)
+
+// SetLogos sets the store the channel logos are served from.
+func (h *Handler) SetLogos(store *logos.Store) {
+	h.logos = store
+}
+
+// ChannelLogo serves the logo of the channel given by the "id" and "name"
+// query values, or a monogram of the name when there is no logo.
+func (h *Handler) ChannelLogo(w http.ResponseWriter, r *http.Request) {
+	id, name := r.URL.Query().Get("id"), r.URL.Query().Get("name")
+	if h.logos != nil {
+		if m, ok := h.logos.Lookup(id, name); ok {
+			if h.serveLogoFile(w, r, m.File) {
+				return
+			}
+		}
+	}
+
+	svg := logos.MonogramSVG(name)
+	sum := fnv.New64a()
+	_, _ = sum.Write(svg)
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", monogramCacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`"m%x"`, sum.Sum64()))
+	http.ServeContent(w, r, "monogram.svg", time.Time{}, bytes.NewReader(svg))
+}
+
+// serveLogoFile serves a logo file and reports whether it could be read.
+func (h *Handler) serveLogoFile(w http.ResponseWriter, r *http.Request, file string) bool {
+	path, err := h.logos.Path(file)
+	if err != nil {
+		return false
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		h.logger.Warn("channel logo not readable", slog.String("file", file), slog.Any("error", err))
+		return false
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil || st.IsDir() {
+		return false
+	}
+	w.Header().Set("Cache-Control", logoCacheControl)
+	// ServeContent sniffs the type of unknown extensions such as .webp.
+	http.ServeContent(w, r, filepath.Base(path), st.ModTime(), f)
+	return true
+}
+
+// channelLogoView is a row of the logo report.
+type channelLogoView struct {
+	domain.Channel
+	File string
+	Rule string
+}
+
+// ChannelLogos lists all channels with the logo found for them, channels
+// without a logo first.
+func (h *Handler) ChannelLogos(w http.ResponseWriter, r *http.Request) {
+	if h.channels == nil {
+		http.Error(w, "VDR client not available", http.StatusServiceUnavailable)
+		return
+	}
+	data := map[string]any{
+		"Message": r.URL.Query().Get("msg"),
+		"Error":   r.URL.Query().Get("error"),
+	}
+	if h.cfg != nil {
+		data["LogoDir"] = h.cfg.UI.LogoDir
+		data["LogoMap"] = h.cfg.UI.LogoMap
+	}
+	chs, err := h.channels.GetChannels(r.Context())
+	if err != nil {
+		h.logger.Error("channels fetch error", slog.Any("error", err))
+		data["HomeError"] = err.Error()
+	}
+	var missing, found []channelLogoView
+	for _, ch := range chs {
+		if ch.Obsolete() {
+			continue
+		}
+		v := channelLogoView{Channel: ch}
+		if h.logos != nil {
+			if m, ok := h.logos.Lookup(ch.ID, ch.Name); ok {
+				v.File, v.Rule = m.File, m.Rule
+			}
+		}
+		if v.File == "" {
+			missing = append(missing, v)
+		} else {
+			found = append(found, v)
+		}
+	}
+	data["Channels"] = append(missing, found...)
+	data["MissingCount"] = len(missing)
+	data["FoundCount"] = len(found)
+	h.renderTemplate(w, r, "channel_logos.html", data)
+}
+
+// ChannelLogosReload scans the logo directory and mapping file again.
+func (h *Handler) ChannelLogosReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	target := "/channels/logos?msg=Logos+reloaded."
+	if h.logos == nil {
+		target = "/channels/logos"
+	} else if err := h.logos.Load(); err != nil {
+		h.logger.Warn("channel logos reload failed", slog.Any("error", err))
+		target = "/channels/logos?error=" + url.QueryEscape(err.Error())
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
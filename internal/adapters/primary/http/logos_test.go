@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/logos"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func newLogoStore(t *testing.T) *logos.Store {
+	t.Helper()
+	dir := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n")
+	if err := os.WriteFile(filepath.Join(dir, "das erste hd.png"), png, 0644); err != nil {
+		t.Fatal(err)
+	}
+	store := logos.NewStore(dir, "")
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return store
+}
+
+func TestChannelLogo_ServesLogoOrMonogram(t *testing.T) {
+	h := newFavouritesHandler(t, "index.html", ports.NewMockVDRClient())
+	h.SetLogos(newLogoStore(t))
+
+	rw := httptest.NewRecorder()
+	h.ChannelLogo(rw, httptest.NewRequest(http.MethodGet, "/logos/channel?id=C-1-1-1&name=Das+Erste+HD", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rw.Code)
+	}
+	if ct := rw.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if cc := rw.Header().Get("Cache-Control"); cc != logoCacheControl {
+		t.Fatalf("Cache-Control = %q", cc)
+	}
+	lastModified := rw.Header().Get("Last-Modified")
+	if lastModified == "" {
+		t.Fatal("expected a Last-Modified header")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/logos/channel?id=C-1-1-1&name=Das+Erste+HD", nil)
+	req.Header.Set("If-Modified-Since", lastModified)
+	rw = httptest.NewRecorder()
+	h.ChannelLogo(rw, req)
+	if rw.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for a cached logo, got %d", rw.Code)
+	}
+
+	rw = httptest.NewRecorder()
+	h.ChannelLogo(rw, httptest.NewRequest(http.MethodGet, "/logos/channel?id=C-2-2-2&name=KiKA", nil))
+	if ct := rw.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Fatalf("monogram Content-Type = %q", ct)
+	}
+	if !strings.Contains(rw.Body.String(), ">KI</text>") {
+		t.Fatalf("monogram = %s", rw.Body.String())
+	}
+	etag := rw.Header().Get("ETag")
+	req = httptest.NewRequest(http.MethodGet, "/logos/channel?id=C-2-2-2&name=KiKA", nil)
+	req.Header.Set("If-None-Match", etag)
+	rw = httptest.NewRecorder()
+	h.ChannelLogo(rw, req)
+	if rw.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for a cached monogram, got %d", rw.Code)
+	}
+}
+
+func TestChannelLogos_ListsMissingLogosFirst(t *testing.T) {
+	mock := ports.NewMockVDRClient().WithChannels([]domain.Channel{
+		{ID: "C-1-1-1", Number: 1, Name: "Das Erste HD"},
+		{ID: "C-2-2-2", Number: 2, Name: "KiKA"},
+	})
+	h := newFavouritesHandler(t, "channel_logos.html", mock)
+	h.SetLogos(newLogoStore(t))
+	h.cfg.UI.LogoDir = "/logos"
+
+	rw := httptest.NewRecorder()
+	h.ChannelLogos(rw, httptest.NewRequest(http.MethodGet, "/channels/logos", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	body := rw.Body.String()
+	if !strings.Contains(body, "1 channels have a logo, 1 show a monogram.") {
+		t.Fatalf("report lacks the counts: %s", body)
+	}
+	kika, erste := strings.Index(body, "<strong>KiKA</strong>"), strings.Index(body, "<strong>Das Erste HD</strong>")
+	if kika < 0 || erste < 0 || kika > erste {
+		t.Fatalf("expected channels without a logo first")
+	}
+	if !strings.Contains(body, `src="/logos/channel?id=C-1-1-1&name=Das%20Erste%20HD"`) {
+		t.Fatalf("report lacks the logo image: %s", body)
+	}
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/archive"
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/metrics"
+)
+
+// metricsConflictWindow is how far ahead timer conflicts are simulated for /metrics.
+const metricsConflictWindow = 8 * 24 * time.Hour
+
+// SetMetrics sets the metrics served on /metrics and registers the gauges
+// computed from the handler's services at scrape time.
+func (h *Handler) SetMetrics(m *metrics.Metrics) {
+	h.metrics = m
+	if m == nil {
+		return
+	}
+	r := m.Registry
+
+	r.NewCounterFunc("vdradmin_cache_lookups_total", "Cache lookups by cache and result (hit or miss).", []string{"cache", "result"},
+		func(ctx context.Context, emit func(float64, ...string)) {
+			for name, stats := range h.cacheStats() {
+				emit(float64(stats.Hits), name, "hit")
+				emit(float64(stats.Misses), name, "miss")
+			}
+		})
+	r.NewGaugeFunc("vdradmin_cache_hit_ratio", "Share of cache lookups served from the cache since start.", []string{"cache"},
+		func(ctx context.Context, emit func(float64, ...string)) {
+			for name, stats := range h.cacheStats() {
+				emit(stats.HitRatio(), name)
+			}
+		})
+	r.NewGaugeFunc("vdradmin_hls_streams", "Active Watch TV HLS streams.", nil,
+		func(ctx context.Context, emit func(float64, ...string)) {
+			emit(float64(h.hlsStreams()))
+		})
+	r.NewGaugeFunc("vdradmin_ffmpeg_processes", "Running ffmpeg processes by purpose.", []string{"purpose"},
+		func(ctx context.Context, emit func(float64, ...string)) {
+			emit(float64(h.hlsStreams()), "hls")
+			emit(float64(h.archiveJobCounts()[archive.JobRunning]), "archive")
+		})
+	r.NewGaugeFunc("vdradmin_archive_jobs", "Archive jobs by status.", []string{"status"},
+		func(ctx context.Context, emit func(float64, ...string)) {
+			counts := h.archiveJobCounts()
+			for _, status := range []archive.JobStatus{archive.JobQueued, archive.JobRunning, archive.JobSuccess, archive.JobFailed} {
+				emit(float64(counts[status]), string(status))
+			}
+		})
+	r.NewGaugeFunc("vdradmin_timers", "VDR timers by state (active or inactive). Missing while VDR is unreachable.", []string{"state"},
+		func(ctx context.Context, emit func(float64, ...string)) {
+			if h.timerService == nil {
+				return
+			}
+			timers, err := h.timerService.GetAllTimers(ctx)
+			if err != nil {
+				return
+			}
+			active := 0
+			for _, t := range timers {
+				if t.Active {
+					active++
+				}
+			}
+			emit(float64(active), "active")
+			emit(float64(len(timers)-active), "inactive")
+		})
+	r.NewGaugeFunc("vdradmin_timer_conflicts", "Timers that will not record completely within the next 8 days. Missing while VDR is unreachable.", nil,
+		func(ctx context.Context, emit func(float64, ...string)) {
+			if h.timerService == nil {
+				return
+			}
+			from := h.now()
+			report, err := h.timerService.SimulateConflicts(ctx, from, from.Add(metricsConflictWindow))
+			if err != nil {
+				return
+			}
+			emit(float64(len(report.FailedIDs)))
+		})
+	r.NewGaugeFunc("vdradmin_video_dir_free_bytes", "Free disk space available in vdr.video_dir.", nil,
+		func(ctx context.Context, emit func(float64, ...string)) {
+			if free, _, ok := h.videoDirSpace(); ok {
+				emit(float64(free))
+			}
+		})
+	r.NewGaugeFunc("vdradmin_video_dir_size_bytes", "Total disk space of the file system holding vdr.video_dir.", nil,
+		func(ctx context.Context, emit func(float64, ...string)) {
+			if _, size, ok := h.videoDirSpace(); ok {
+				emit(float64(size))
+			}
+		})
+}
+
+// Metrics serves the metrics in the Prometheus text format.
+func (h *Handler) Metrics(w http.ResponseWriter, r *http.Request) {
+	if h.metrics == nil || h.cfg == nil || !h.cfg.Metrics.Enabled {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	if err := h.metrics.Registry.WriteText(r.Context(), w); err != nil {
+		h.logger.Debug("metrics write failed", slog.Any("error", err))
+	}
+}
+
+// MetricsMiddleware records request counts and latency per matched route.
+func MetricsMiddleware(m *metrics.Metrics) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if m == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			rw := &responseWriter{ResponseWriter: w}
+			next.ServeHTTP(rw, r)
+
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			m.ObserveHTTPRequest(metricsRoute(r), r.Method, status, time.Since(start))
+		})
+	}
+}
+
+// metricsRoute returns the route pattern that matched r without its method,
+// so path parameters don't multiply the label values.
+func metricsRoute(r *http.Request) string {
+	pattern := r.Pattern
+	if pattern == "" {
+		return "unmatched"
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// metricsAuthMiddleware protects /metrics with metrics.token when one is configured
+// and falls back to the regular authentication otherwise.
+func (h *Handler) metricsAuthMiddleware(authCfg *config.AuthConfig) func(http.Handler) http.Handler {
+	auth := AuthMiddleware(authCfg)
+	return func(next http.Handler) http.Handler {
+		authenticated := auth(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := ""
+			if h.cfg != nil {
+				token = h.cfg.Metrics.Token
+			}
+			if token == "" {
+				authenticated.ServeHTTP(w, r)
+				return
+			}
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || !secureCompare(strings.TrimSpace(bearer), token) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="VDRAdmin metrics"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (h *Handler) cacheStats() map[string]services.CacheStats {
+	out := map[string]services.CacheStats{}
+	if h.epgService != nil {
+		out["epg"] = h.epgService.CacheStats()
+	}
+	if h.recordingService != nil {
+		out["recordings"] = h.recordingService.CacheStats()
+	}
+	return out
+}
+
+func (h *Handler) hlsStreams() int {
+	if h.hlsProxy == nil {
+		return 0
+	}
+	return h.hlsProxy.ActiveStreams()
+}
+
+func (h *Handler) archiveJobCounts() map[archive.JobStatus]int {
+	counts := map[archive.JobStatus]int{}
+	if h.archiveJobs == nil {
+		return counts
+	}
+	for _, job := range h.archiveJobs.List() {
+		counts[job.Status]++
+	}
+	return counts
+}
+
+// videoDirSpace returns the free and total bytes of the file system holding vdr.video_dir.
+func (h *Handler) videoDirSpace() (free, size uint64, ok bool) {
+	if h.cfg == nil || strings.TrimSpace(h.cfg.VDR.VideoDir) == "" {
+		return 0, 0, false
+	}
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(h.cfg.VDR.VideoDir, &st); err != nil {
+		return 0, 0, false
+	}
+	return st.Bavail * uint64(st.Bsize), st.Blocks * uint64(st.Bsize), true
+}
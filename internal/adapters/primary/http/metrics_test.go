@@ -0,0 +1,116 @@
+package http
+
+import (
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/metrics"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func newMetricsTestServer(t *testing.T, cfg *config.Config) *httptest.Server {
+	t.Helper()
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	mock := ports.NewMockVDRClient().WithTimers([]domain.Timer{
+		{ID: 1, Active: true, ChannelID: "C-1-1-1", Title: "A", Start: start, Stop: start.Add(time.Hour)},
+		{ID: 2, Active: false, ChannelID: "C-1-1-1", Title: "B", Start: start, Stop: start.Add(time.Hour)},
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	epgSvc := services.NewEPGService(mock, time.Minute)
+	timerSvc := services.NewTimerService(mock)
+	timerSvc.SetDVBCards(1)
+	h := NewHandler(logger, template.New("empty"), epgSvc, timerSvc, services.NewRecordingService(mock, time.Minute), nil)
+	h.SetConfig(cfg, "")
+	h.SetMetrics(metrics.New())
+
+	srv := httptest.NewServer(SetupRoutes(h, &cfg.Auth, logger))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func scrapeMetrics(t *testing.T, srv *httptest.Server, token string) (*http.Response, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/metrics", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestMetrics_ExposesPrometheusText(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.Enabled = true
+	cfg.VDR.VideoDir = t.TempDir()
+	srv := newMetricsTestServer(t, cfg)
+
+	resp, err := http.Get(srv.URL + "/api/v1/timers/1")
+	if err != nil {
+		t.Fatalf("GET timer: %v", err)
+	}
+	resp.Body.Close()
+
+	resp, body := scrapeMetrics(t, srv, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != metrics.ContentType {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	for _, s := range []string{
+		`vdradmin_http_requests_total{route="/api/v1/timers/{id}",method="GET",code="200"} 1`,
+		`vdradmin_http_request_duration_seconds_count{route="/api/v1/timers/{id}",method="GET"} 1`,
+		`vdradmin_timers{state="active"} 1`,
+		`vdradmin_timers{state="inactive"} 1`,
+		`vdradmin_timer_conflicts 0`,
+		`vdradmin_archive_jobs{status="running"} 0`,
+		`vdradmin_ffmpeg_processes{purpose="hls"} 0`,
+		`vdradmin_hls_streams 0`,
+		`vdradmin_cache_lookups_total{cache="epg",result="miss"} 0`,
+		`# TYPE vdradmin_cache_hit_ratio gauge`,
+		`vdradmin_video_dir_free_bytes `,
+	} {
+		mustContain(t, body, s)
+	}
+}
+
+func TestMetrics_DisabledIsNotFound(t *testing.T) {
+	srv := newMetricsTestServer(t, &config.Config{})
+	if resp, _ := scrapeMetrics(t, srv, ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestMetrics_TokenReplacesRegularAuth(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.Token = "s3cret"
+	cfg.Auth.Enabled = true
+	cfg.Auth.AdminUser = "admin"
+	cfg.Auth.AdminPass = "secret"
+	srv := newMetricsTestServer(t, cfg)
+
+	// Loopback is trusted by the regular authentication, but not for a token-protected endpoint.
+	if resp, _ := scrapeMetrics(t, srv, ""); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", resp.StatusCode)
+	}
+	if resp, _ := scrapeMetrics(t, srv, "wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with a wrong token, got %d", resp.StatusCode)
+	}
+	if resp, body := scrapeMetrics(t, srv, "s3cret"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 with token, got %d: %s", resp.StatusCode, body)
+	}
+}
@@ -35,6 +35,11 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer (e.g. to flush event streams).
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // LoggingMiddleware logs HTTP requests
 func LoggingMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -156,6 +161,20 @@ func RequireAdminMiddleware() func(http.Handler) http.Handler {
 	}
 }
 
+// RequireAdminAPIMiddleware is RequireAdminMiddleware with a JSON error body.
+func RequireAdminAPIMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := r.Context().Value("role").(string)
+			if !ok || role != "admin" {
+				writeAPIErrorStatus(w, http.StatusForbidden, "forbidden", "admin role required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // CompressionMiddleware handles gzip compression
 func CompressionMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
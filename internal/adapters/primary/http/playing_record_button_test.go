@@ -52,8 +52,21 @@ func (m *playingVDRMock) GetRecordings(ctx context.Context) ([]domain.Recording,
 func (m *playingVDRMock) GetRecordingDir(ctx context.Context, recordingID string) (string, error) {
 	return "", nil
 }
-func (m *playingVDRMock) DeleteRecording(ctx context.Context, path string) error { return nil }
-func (m *playingVDRMock) GetCurrentChannel(ctx context.Context) (string, error)  { return "", nil }
+func (m *playingVDRMock) DeleteRecording(ctx context.Context, path string) error        { return nil }
+func (m *playingVDRMock) MoveRecording(ctx context.Context, path, newName string) error { return nil }
+func (m *playingVDRMock) CutRecording(ctx context.Context, path string) error           { return nil }
+func (m *playingVDRMock) UpdateChannel(ctx context.Context, number int, settings string) error {
+	return nil
+}
+func (m *playingVDRMock) MoveChannel(ctx context.Context, number, to int) error { return nil }
+func (m *playingVDRMock) CreateChannel(ctx context.Context, settings string) (int, error) {
+	return 0, nil
+}
+func (m *playingVDRMock) DeleteChannel(ctx context.Context, number int) error { return nil }
+func (m *playingVDRMock) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error {
+	return nil
+}
+func (m *playingVDRMock) GetCurrentChannel(ctx context.Context) (string, error) { return "", nil }
 func (m *playingVDRMock) SetCurrentChannel(ctx context.Context, channelID string) error {
 	return nil
 }
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// historyChannelOption is a channel the history page can be filtered by.
+type historyChannelOption struct {
+	ID   string
+	Name string
+}
+
+// RecordingHistory lists the timers that fired and whether they left a
+// complete recording, filtered by result, channel, title and period.
+func (h *Handler) RecordingHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	result := strings.TrimSpace(q.Get("result"))
+	channel := strings.TrimSpace(q.Get("channel"))
+	query := strings.TrimSpace(q.Get("q"))
+	days, _ := strconv.Atoi(strings.TrimSpace(q.Get("days")))
+	if days < 0 {
+		days = 0
+	}
+
+	var entries []domain.RecordingHistoryEntry
+	if h.recordingHistory != nil {
+		entries = h.recordingHistory.Entries()
+	}
+
+	var since time.Time
+	if days > 0 {
+		since = h.now().Add(-time.Duration(days) * 24 * time.Hour)
+	}
+	needle := strings.ToLower(query)
+	channels := map[string]string{}
+	var shown []domain.RecordingHistoryEntry
+	counts := map[domain.RecordingResult]int{}
+	for _, e := range entries {
+		if e.ChannelID != "" {
+			name := e.ChannelName
+			if name == "" {
+				name = e.ChannelID
+			}
+			channels[e.ChannelID] = name
+		}
+		if !since.IsZero() && e.Start.Before(since) {
+			continue
+		}
+		if channel != "" && e.ChannelID != channel {
+			continue
+		}
+		if needle != "" && !strings.Contains(strings.ToLower(e.Title), needle) && !strings.Contains(strings.ToLower(e.Recording), needle) {
+			continue
+		}
+		// The counts cover the other filters, so they tell how the selection went.
+		counts[e.Result]++
+		if !historyResultMatches(e.Result, result) {
+			continue
+		}
+		shown = append(shown, e)
+	}
+
+	options := make([]historyChannelOption, 0, len(channels))
+	for id, name := range channels {
+		options = append(options, historyChannelOption{ID: id, Name: name})
+	}
+	sort.Slice(options, func(i, j int) bool {
+		return strings.ToLower(options[i].Name) < strings.ToLower(options[j].Name)
+	})
+
+	data := map[string]any{
+		"Entries":   shown,
+		"Channels":  options,
+		"Result":    result,
+		"Channel":   channel,
+		"Query":     query,
+		"Days":      days,
+		"Complete":  counts[domain.RecordingComplete],
+		"Short":     counts[domain.RecordingShort],
+		"Missing":   counts[domain.RecordingMissing],
+		"Errored":   counts[domain.RecordingErrored],
+		"Pending":   counts[domain.RecordingPending],
+		"Available": h.recordingHistory != nil,
+	}
+	h.renderTemplate(w, r, "recording_history.html", data)
+}
+
+// historyResultMatches reports whether result passes the result filter:
+// empty for all, "failed" for short, missing and errored, or a single result.
+func historyResultMatches(result domain.RecordingResult, filter string) bool {
+	switch filter {
+	case "":
+		return true
+	case "failed":
+		return result.Failed()
+	default:
+		return string(result) == filter
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+type fixedHistoryStore []domain.RecordingHistoryEntry
+
+func (s fixedHistoryStore) LoadHistory() ([]domain.RecordingHistoryEntry, error) {
+	return append([]domain.RecordingHistoryEntry(nil), s...), nil
+}
+
+func (s fixedHistoryStore) SaveHistory([]domain.RecordingHistoryEntry) error { return nil }
+
+func newRecordingHistory(t *testing.T, mock *ports.MockVDRClient) *services.RecordingHistoryService {
+	t.Helper()
+	day := time.Now().Add(-24 * time.Hour).Truncate(time.Hour)
+	entry := func(title, channel, name string, result domain.RecordingResult, start time.Time) domain.RecordingHistoryEntry {
+		return domain.RecordingHistoryEntry{Title: title, ChannelID: channel, ChannelName: name, Start: start, Stop: start.Add(time.Hour), Result: result}
+	}
+	history := services.NewRecordingHistoryService(services.NewTimerService(mock), services.NewRecordingService(mock, 0))
+	if err := history.SetStore(fixedHistoryStore{
+		entry("Tatort", "C-1", "Das Erste HD", domain.RecordingComplete, day),
+		entry("Sportschau", "C-1", "Das Erste HD", domain.RecordingMissing, day.Add(2*time.Hour)),
+		entry("heute journal", "C-2", "ZDF HD", domain.RecordingErrored, day.Add(3*time.Hour)),
+		entry("Terra X", "C-2", "ZDF HD", domain.RecordingShort, day.AddDate(0, 0, -20)),
+	}); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	return history
+}
+
+func TestRecordingHistory_Filters(t *testing.T) {
+	mock := ports.NewMockVDRClient()
+	h := newFavouritesHandler(t, "recording_history.html", mock)
+	h.SetRecordingHistory(newRecordingHistory(t, mock))
+
+	get := func(query string) string {
+		t.Helper()
+		rw := httptest.NewRecorder()
+		h.RecordingHistory(rw, httptest.NewRequest(http.MethodGet, "/timers/history"+query, nil))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", query, rw.Code)
+		}
+		return rw.Body.String()
+	}
+
+	body := get("")
+	for _, title := range []string{"Tatort", "Sportschau", "heute journal", "Terra X"} {
+		if !strings.Contains(body, title) {
+			t.Fatalf("expected %q in the full history", title)
+		}
+	}
+	if !strings.Contains(body, "Complete 1, short 1, missing 1, errored 1") {
+		t.Fatalf("expected the result counts, body=%s", body)
+	}
+
+	body = get("?result=failed&days=7")
+	if strings.Contains(body, "Tatort") || strings.Contains(body, "Terra X") || !strings.Contains(body, "Sportschau") || !strings.Contains(body, "heute journal") {
+		t.Fatalf("expected the failures of the last 7 days, body=%s", body)
+	}
+
+	body = get("?channel=C-2&q=TERRA")
+	if !strings.Contains(body, "Terra X") || strings.Contains(body, "heute journal") {
+		t.Fatalf("expected channel and title filters to apply, body=%s", body)
+	}
+}
+
+func TestWhatsOnNow_ShowsRecordingFailures(t *testing.T) {
+	mock := ports.NewMockVDRClient()
+	h := newFavouritesHandler(t, "index.html", mock)
+
+	rw := httptest.NewRecorder()
+	h.WhatsOnNow(rw, httptest.NewRequest(http.MethodGet, "/now", nil))
+	if strings.Contains(rw.Body.String(), "failed in the last") {
+		t.Fatalf("did not expect a failure summary without history")
+	}
+
+	h.SetRecordingHistory(newRecordingHistory(t, mock))
+	rw = httptest.NewRecorder()
+	h.WhatsOnNow(rw, httptest.NewRequest(http.MethodGet, "/now", nil))
+	body := rw.Body.String()
+	if !strings.Contains(body, "2 recordings failed in the last 7 days:") || !strings.Contains(body, "1 missing, 1 with errors") {
+		t.Fatalf("expected the failure summary, body=%s", body)
+	}
+	if !strings.Contains(body, "Sportschau (Das Erste HD): missing") || strings.Contains(body, "Terra X") {
+		t.Fatalf("expected the recent failures only, body=%s", body)
+	}
+}
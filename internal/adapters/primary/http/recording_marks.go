@@ -0,0 +1,296 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/cutting"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// markView is a cutting mark as rendered on the marks editor.
+type markView struct {
+	Index    int
+	Position string
+	Comment  string
+	// Starts is set for marks that start a part the cutter keeps.
+	Starts   bool
+	ThumbURL string
+}
+
+// cutStatusResponse is the JSON answer of RecordingCutStatus.
+type cutStatusResponse struct {
+	Status  cutting.CutStatus `json:"status"`
+	Percent float64           `json:"percent"`
+	Started time.Time         `json:"started"`
+	Ended   *time.Time        `json:"ended,omitempty"`
+	Error   string            `json:"error,omitempty"`
+}
+
+// recordingCutting opens the directory of a recording of the primary VDR for
+// its marks and frame index.
+func (h *Handler) recordingCutting(r *http.Request, recID string) (cutting.Recording, error) {
+	if h.vdrClient == nil {
+		return cutting.Recording{}, errors.New("VDR client not available")
+	}
+	if recID == "" {
+		return cutting.Recording{}, fmt.Errorf("%w: path required", domain.ErrInvalidInput)
+	}
+	if err := h.validateRecordingPath(recID); err != nil {
+		return cutting.Recording{}, fmt.Errorf("%w: invalid path", domain.ErrInvalidInput)
+	}
+	recDir, err := h.vdrClient.GetRecordingDir(r.Context(), recID)
+	if err != nil {
+		return cutting.Recording{}, err
+	}
+	if strings.TrimSpace(recDir) == "" {
+		return cutting.Recording{}, fmt.Errorf("%w: could not resolve recording directory", domain.ErrNotFound)
+	}
+	if err := h.validateRecordingDir(recDir); err != nil {
+		h.logger.Warn("invalid recording directory rejected for marks", slog.String("dir", recDir), slog.Any("error", err))
+		return cutting.Recording{}, fmt.Errorf("%w: invalid recording directory", domain.ErrInvalidInput)
+	}
+	return cutting.Open(recDir)
+}
+
+// recordingTitle returns the title of a recording, or its path if the list of
+// recordings is not available.
+func (h *Handler) recordingTitle(r *http.Request, recID string) string {
+	if h.recordingService == nil {
+		return recID
+	}
+	recs, err := h.recordingService.GetAllRecordings(r.Context())
+	if err != nil {
+		return recID
+	}
+	for _, rec := range recs {
+		if rec.Path == recID {
+			return rec.Title
+		}
+	}
+	return recID
+}
+
+// RecordingMarks shows the cutting marks of a recording with a thumbnail at
+// each mark.
+func (h *Handler) RecordingMarks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	recID := strings.TrimSpace(r.URL.Query().Get("path"))
+	rec, err := h.recordingCutting(r, recID)
+	if err != nil {
+		h.handleRecordingEditError(w, r, err)
+		return
+	}
+	marks, err := rec.Marks()
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	views := make([]markView, 0, len(marks))
+	for i, m := range marks {
+		views = append(views, markView{
+			Index:    i,
+			Position: cutting.FormatHMSF(m.Frame, rec.FPS),
+			Comment:  m.Comment,
+			Starts:   i%2 == 0,
+			ThumbURL: "/recordings/marks/thumbnail?path=" + url.QueryEscape(recID) + "&frame=" + strconv.Itoa(m.Frame),
+		})
+	}
+
+	data := map[string]any{
+		"RecordingID": recID,
+		"Title":       h.recordingTitle(r, recID),
+		"Marks":       views,
+		"Error":       r.URL.Query().Get("error"),
+	}
+	if idx, err := rec.Index(); err == nil {
+		data["Length"] = formatReplayClock(idx.Length(rec.FPS))
+		if kept := cutting.Segments(marks); len(kept) > 0 {
+			data["KeptLength"] = formatReplayClock(cutting.FrameToDuration(idx.SegmentFrames(kept), rec.FPS))
+		}
+	}
+	if jobID, ok := h.archiveJobs.ActiveJobIDForRecording(recID); ok {
+		data["ArchiveJobID"] = jobID
+	}
+	if cut, ok := h.cutJobs.Status(recID); ok {
+		data["Cut"] = cut
+		data["CutRunning"] = cut.Status == cutting.CutRunning
+	}
+
+	h.renderTemplate(w, r, "recording_marks.html", data)
+}
+
+// RecordingMarksSave adds, moves or deletes a cutting mark ("op": add, move or
+// delete) and shows the marks editor again.
+func (h *Handler) RecordingMarksSave(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	recID := strings.TrimSpace(r.FormValue("path"))
+	if h.cutJobs.Active(recID) {
+		h.handleRecordingEditError(w, r, fmt.Errorf("%w: VDR is cutting this recording", domain.ErrConflict))
+		return
+	}
+	rec, err := h.recordingCutting(r, recID)
+	if err != nil {
+		h.handleRecordingEditError(w, r, err)
+		return
+	}
+	marks, err := rec.Marks()
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	index := -1
+	op := strings.TrimSpace(r.FormValue("op"))
+	if op != "add" {
+		index, err = strconv.Atoi(r.FormValue("index"))
+		if err != nil || index < 0 || index >= len(marks) {
+			h.handleRecordingEditError(w, r, fmt.Errorf("%w: unknown mark", domain.ErrInvalidInput))
+			return
+		}
+	}
+	switch op {
+	case "add", "move":
+		frame, err := cutting.ParseHMSF(r.FormValue("position"), rec.FPS)
+		if err != nil {
+			h.handleRecordingEditError(w, r, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err))
+			return
+		}
+		mark := cutting.Mark{Frame: frame, Comment: strings.TrimSpace(r.FormValue("comment"))}
+		if op == "add" {
+			marks = append(marks, mark)
+		} else {
+			marks[index] = mark
+		}
+	case "delete":
+		marks = append(marks[:index], marks[index+1:]...)
+	default:
+		http.Error(w, "Invalid operation", http.StatusBadRequest)
+		return
+	}
+
+	if err := rec.SaveMarks(marks); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	h.redirectToMarks(w, r, recID, "")
+}
+
+func (h *Handler) redirectToMarks(w http.ResponseWriter, r *http.Request, recID, msg string) {
+	params := url.Values{"path": {recID}}
+	if msg != "" {
+		params.Set("error", msg)
+	}
+	target := "/recordings/marks?" + params.Encode()
+	if r.Header.Get("HX-Request") != "" {
+		w.Header().Set("HX-Redirect", target)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+// RecordingMarkThumbnail returns a JPEG of the recording at a frame.
+func (h *Handler) RecordingMarkThumbnail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rec, err := h.recordingCutting(r, strings.TrimSpace(r.URL.Query().Get("path")))
+	if err != nil {
+		h.handleRecordingEditError(w, r, err)
+		return
+	}
+	frame, err := strconv.Atoi(r.URL.Query().Get("frame"))
+	if err != nil || frame < 0 {
+		http.Error(w, "Invalid frame", http.StatusBadRequest)
+		return
+	}
+
+	img, err := rec.Thumbnail(r.Context(), frame, 320)
+	if err != nil {
+		h.logger.Warn("mark thumbnail failed", slog.String("dir", rec.Dir), slog.Int("frame", frame), slog.Any("error", err))
+		http.Error(w, "Thumbnail unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	_, _ = w.Write(img)
+}
+
+// RecordingCut starts VDR's cutter on a recording with marks.
+func (h *Handler) RecordingCut(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	recID := strings.TrimSpace(r.FormValue("path"))
+	if h.cutJobs.Active(recID) {
+		h.handleRecordingEditError(w, r, fmt.Errorf("%w: VDR is already cutting this recording", domain.ErrConflict))
+		return
+	}
+	rec, err := h.recordingCutting(r, recID)
+	if err != nil {
+		h.handleRecordingEditError(w, r, err)
+		return
+	}
+	marks, err := rec.Marks()
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	if len(cutting.Segments(marks)) == 0 {
+		h.handleRecordingEditError(w, r, fmt.Errorf("%w: set cutting marks first", domain.ErrInvalidInput))
+		return
+	}
+
+	if err := h.vdrClient.CutRecording(r.Context(), recID); err != nil {
+		h.logger.Warn("VDR refused to cut", slog.String("path", recID), slog.Any("error", err))
+		h.redirectToMarks(w, r, recID, "VDR did not start cutting: "+err.Error())
+		return
+	}
+	h.cutJobs.Track(recID, h.recordingTitle(r, recID), rec, marks)
+	if h.recordingService != nil {
+		// VDR lists the edited recording as soon as the cutter starts.
+		h.recordingService.InvalidateCache()
+	}
+	h.logger.Info("cutting started", slog.String("path", recID), slog.String("target_dir", cutting.EditedDir(rec.Dir)))
+	h.redirectToMarks(w, r, recID, "")
+}
+
+// RecordingCutStatus returns the progress of the last cut of a recording, or
+// 204 if none was started from vdradmin-go.
+func (h *Handler) RecordingCutStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	cut, ok := h.cutJobs.Status(strings.TrimSpace(r.URL.Query().Get("path")))
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	resp := cutStatusResponse{
+		Status:  cut.Status,
+		Percent: cut.Percent,
+		Started: cut.Started,
+		Error:   cut.Error,
+	}
+	if !cut.Ended.IsZero() {
+		resp.Ended = &cut.Ended
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/githubixx/vdradmin-go/internal/application/cutting"
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestRecordingMarks_EditAndCut(t *testing.T) {
+	videoDir := t.TempDir()
+	recDir := filepath.Join(videoDir, "Film", "2026-02-01.20.15.1-0.rec")
+	if err := os.MkdirAll(recDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Ten minutes at 25 fps in one segment file.
+	var index []byte
+	for i := 0; i < 10*60*25; i++ {
+		index = binary.LittleEndian.AppendUint64(index, uint64(1)<<48|uint64(i))
+	}
+	if err := os.WriteFile(filepath.Join(recDir, "index"), index, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cuts []string
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{{Path: "3", Title: "Film"}})
+	mock.GetRecordingDirFunc = func(ctx context.Context, id string) (string, error) {
+		if id != "3" {
+			return "", domain.ErrNotFound
+		}
+		return recDir, nil
+	}
+	mock.CutRecordingFunc = func(ctx context.Context, path string) error {
+		cuts = append(cuts, path)
+		return nil
+	}
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "recording_marks.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, nil, nil, services.NewRecordingService(mock, 0), nil)
+	h.SetTemplates(map[string]*template.Template{"recording_marks.html": parsed})
+	h.SetConfig(&config.Config{VDR: config.VDRConfig{VideoDir: videoDir}}, "")
+	h.SetVDRClient(mock)
+
+	post := func(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/recordings/marks", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rw := httptest.NewRecorder()
+		handler(rw, req)
+		return rw
+	}
+
+	for _, form := range []url.Values{
+		{"path": {"3"}, "op": {"add"}, "position": {"0:08:00"}},
+		{"path": {"3"}, "op": {"add"}, "position": {"0:01:00"}, "comment": {"after the intro"}},
+		{"path": {"3"}, "op": {"add"}, "position": {"0:09:00"}},
+		{"path": {"3"}, "op": {"move"}, "index": {"1"}, "position": {"0:07:00"}},
+		{"path": {"3"}, "op": {"delete"}, "index": {"2"}},
+	} {
+		if rw := post(h.RecordingMarksSave, form); rw.Code != http.StatusSeeOther {
+			t.Fatalf("%v: expected a redirect, got %d: %s", form, rw.Code, rw.Body.String())
+		}
+	}
+	b, _ := os.ReadFile(filepath.Join(recDir, "marks"))
+	if string(b) != "0:01:00.01 after the intro\n0:07:00.01\n" {
+		t.Fatalf("unexpected marks file %q", b)
+	}
+
+	if rw := post(h.RecordingMarksSave, url.Values{"path": {"3"}, "op": {"add"}, "position": {"0:61:00"}}); rw.Code != http.StatusBadRequest {
+		t.Fatalf("expected an invalid position to be rejected, got %d", rw.Code)
+	}
+	if rw := post(h.RecordingMarksSave, url.Values{"path": {"4"}, "op": {"add"}, "position": {"0:01:00"}}); rw.Code != http.StatusNotFound {
+		t.Fatalf("expected an unknown recording to be not found, got %d", rw.Code)
+	}
+
+	rw := httptest.NewRecorder()
+	h.RecordingMarks(rw, httptest.NewRequest(http.MethodGet, "/recordings/marks?path=3", nil))
+	body := rw.Body.String()
+	if rw.Code != http.StatusOK || !strings.Contains(body, "Keep from 0:01:00.01") || !strings.Contains(body, "Cut from 0:07:00.01") {
+		t.Fatalf("expected the marks to render, got %d", rw.Code)
+	}
+	if !strings.Contains(body, "Length 0:10:00") || !strings.Contains(body, "after cutting 0:06:00") {
+		t.Fatalf("expected the lengths to render")
+	}
+	if !strings.Contains(body, `/recordings/marks/thumbnail?path=3&amp;frame=1500`) {
+		t.Fatalf("expected thumbnail links")
+	}
+
+	if rw := post(h.RecordingCut, url.Values{"path": {"3"}}); rw.Code != http.StatusSeeOther || len(cuts) != 1 {
+		t.Fatalf("expected the cut to start, got %d, cuts %v", rw.Code, cuts)
+	}
+	rw = httptest.NewRecorder()
+	h.RecordingCutStatus(rw, httptest.NewRequest(http.MethodGet, "/recordings/marks/cut/status?path=3", nil))
+	var status cutStatusResponse
+	if err := json.NewDecoder(rw.Body).Decode(&status); err != nil || status.Status != cutting.CutRunning {
+		t.Fatalf("expected a running cut, got %+v, %v", status, err)
+	}
+	if rw := post(h.RecordingMarksSave, url.Values{"path": {"3"}, "op": {"delete"}, "index": {"0"}}); rw.Code != http.StatusConflict {
+		t.Fatalf("expected marks to be locked while cutting, got %d", rw.Code)
+	}
+	if rw := post(h.RecordingCut, url.Values{"path": {"3"}}); rw.Code != http.StatusConflict || len(cuts) != 1 {
+		t.Fatalf("expected a second cut to be refused, got %d", rw.Code)
+	}
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// recordingItemView is a recording as rendered on the recordings page.
+type recordingItemView struct {
+	domain.Recording
+	Admin        bool
+	ArchiveJobID string
+	// CanArchive is set for recordings of the primary backend, the only one
+	// the archive jobs can reach.
+	CanArchive bool
+	// CanPlay is set for recordings the Watch TV remote can control, i.e.
+	// those of the primary backend.
+	CanPlay bool
+	// Selectable is set when the recording can be selected for moving it
+	// together with others.
+	Selectable bool
+	// BaseName is the recording's name without its folders.
+	BaseName  string
+	SizeLabel string
+}
+
+// recordingFolderView is a folder of the recording tree as rendered on the
+// recordings page.
+type recordingFolderView struct {
+	Path        string
+	Title       string
+	Link        string
+	Count       int
+	NewCount    int
+	SizeLabel   string
+	LengthLabel string
+	Folders     []recordingFolderView
+	Items       []recordingItemView
+}
+
+// recordingBreadcrumb links to a folder on the path to the shown folder.
+type recordingBreadcrumb struct {
+	Title string
+	Link  string
+}
+
+// recordingViews prepares the page data shared by recordings and folders.
+type recordingViews struct {
+	admin      bool
+	bulk       bool
+	activeJobs map[string]string
+	primary    string
+	sortBy     string
+	backend    string
+}
+
+func (h *Handler) newRecordingViews(r *http.Request, sortBy string) recordingViews {
+	v := recordingViews{sortBy: sortBy, backend: backendName(r)}
+	if role, _ := r.Context().Value("role").(string); role == "admin" {
+		v.admin = true
+		v.activeJobs = h.archiveJobs.ActiveJobIDsByRecording()
+	}
+	if set := h.backendSet(); set.Multiple() {
+		v.primary = set.Primary().Name
+	}
+	// Bulk moves go to a single VDR.
+	v.bulk = v.admin && (v.primary == "" || v.backend != "")
+	return v
+}
+
+func (v recordingViews) item(rec domain.Recording) recordingItemView {
+	primary := rec.Backend == "" || rec.Backend == v.primary
+	item := recordingItemView{
+		Recording:  rec,
+		Admin:      v.admin,
+		CanArchive: primary,
+		CanPlay:    primary,
+		SizeLabel:  formatSize(rec.Size),
+	}
+	_, item.BaseName = services.SplitRecordingName(rec)
+	if primary && v.activeJobs != nil {
+		item.ArchiveJobID = v.activeJobs[rec.Path]
+	}
+	item.Selectable = v.bulk && item.ArchiveJobID == ""
+	return item
+}
+
+func (v recordingViews) items(recs []domain.Recording) []recordingItemView {
+	out := make([]recordingItemView, 0, len(recs))
+	for _, rec := range recs {
+		out = append(out, v.item(rec))
+	}
+	return out
+}
+
+// folderLink returns the recordings page URL showing the given folder.
+func (v recordingViews) folderLink(path string) string {
+	params := url.Values{}
+	if path != "" {
+		params.Set("folder", path)
+	}
+	params.Set("sort", v.sortBy)
+	if v.backend != "" {
+		params.Set("backend", v.backend)
+	}
+	return "/recordings?" + params.Encode()
+}
+
+func (v recordingViews) folder(node *domain.Recording) recordingFolderView {
+	out := recordingFolderView{
+		Path:        node.Path,
+		Title:       node.Title,
+		Link:        v.folderLink(node.Path),
+		Count:       node.Count,
+		NewCount:    node.NewCount,
+		SizeLabel:   formatSize(node.Size),
+		LengthLabel: formatLength(node.Length),
+	}
+	for _, child := range node.Children {
+		if child.IsFolder {
+			out.Folders = append(out.Folders, v.folder(child))
+			continue
+		}
+		out.Items = append(out.Items, v.item(*child))
+	}
+	return out
+}
+
+func (v recordingViews) breadcrumbs(trail []*domain.Recording) []recordingBreadcrumb {
+	out := make([]recordingBreadcrumb, 0, len(trail))
+	for _, node := range trail {
+		out = append(out, recordingBreadcrumb{Title: node.Title, Link: v.folderLink(node.Path)})
+	}
+	return out
+}
+
+// folderPaths returns the paths of all folders below node, in tree order.
+func folderPaths(node *domain.Recording) []string {
+	var out []string
+	for _, child := range node.Children {
+		if child.IsFolder {
+			out = append(out, child.Path)
+			out = append(out, folderPaths(child)...)
+		}
+	}
+	return out
+}
+
+// formatSize formats a size in bytes for display; it returns "" for unknown sizes.
+func formatSize(n int64) string {
+	if n <= 0 {
+		return ""
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTP"[exp])
+}
+
+// formatLength formats a total length as hours and minutes.
+func formatLength(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	m := int(d.Round(time.Minute) / time.Minute)
+	if m < 60 {
+		return fmt.Sprintf("%d min", m)
+	}
+	return fmt.Sprintf("%d h %02d min", m/60, m%60)
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+type archivingRecordings map[string]string
+
+func (a archivingRecordings) ActiveJobIDForRecording(recordingID string) (string, bool) {
+	id, ok := a[recordingID]
+	return id, ok
+}
+
+func TestRecordingMoveAndRename(t *testing.T) {
+	moves := map[string]string{}
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{
+		{Path: "1", Title: "Folge 1", Name: "Folge 1"},
+		{Path: "2", Title: "Folge 2", Name: "Serie~Folge 2"},
+		{Path: "3", Title: "Film", Name: "Film"},
+	})
+	mock.MoveRecordingFunc = func(ctx context.Context, path, newName string) error {
+		moves[path] = newName
+		return nil
+	}
+	recSvc := services.NewRecordingService(mock, 0)
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil, recSvc, nil)
+	recSvc.SetArchiveJobs(archivingRecordings{"3": "job-7"})
+
+	post := func(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/recordings/move", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rw := httptest.NewRecorder()
+		handler(rw, req)
+		return rw
+	}
+
+	rw := post(h.RecordingMove, url.Values{"path": {"1", "2"}, "folder": {"Serie/Staffel 1"}})
+	if rw.Code != http.StatusSeeOther || rw.Header().Get("Location") != "/recordings?folder=Serie~Staffel+1" {
+		t.Fatalf("expected a redirect to the target folder, got %d %q", rw.Code, rw.Header().Get("Location"))
+	}
+	if moves["1"] != "Serie~Staffel 1~Folge 1" || moves["2"] != "Serie~Staffel 1~Folge 2" {
+		t.Fatalf("unexpected moves %v", moves)
+	}
+
+	rw = post(h.RecordingRename, url.Values{"path": {"2"}, "name": {"Pilot"}, "folder": {"Serie"}})
+	if rw.Code != http.StatusSeeOther || rw.Header().Get("Location") != "/recordings?folder=Serie" || moves["2"] != "Serie~Pilot" {
+		t.Fatalf("unexpected rename result %d %q, moves %v", rw.Code, rw.Header().Get("Location"), moves)
+	}
+
+	rw = post(h.RecordingMove, url.Values{"path": {"3"}, "folder": {"Archiv"}})
+	if rw.Code != http.StatusConflict || !strings.Contains(rw.Body.String(), "job-7") {
+		t.Fatalf("expected a recording being archived to be refused, got %d: %s", rw.Code, rw.Body.String())
+	}
+
+	rw = post(h.RecordingMove, url.Values{"folder": {"Archiv"}})
+	if rw.Code != http.StatusBadRequest {
+		t.Fatalf("expected a move without recordings to be rejected, got %d", rw.Code)
+	}
+}
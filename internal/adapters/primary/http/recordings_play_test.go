@@ -0,0 +1,115 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestRecordingPlay_StartsReplayAndReportsIt(t *testing.T) {
+	var starts []domain.ReplayStart
+	var keys []string
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{{Path: "3", Title: "Tatort", Length: time.Hour}})
+	mock.PlayRecordingFunc = func(ctx context.Context, path string, start domain.ReplayStart) error {
+		starts = append(starts, start)
+		return nil
+	}
+	mock.SendKeyFunc = func(ctx context.Context, key string) error {
+		keys = append(keys, key)
+		return nil
+	}
+	recSvc := services.NewRecordingService(mock, 0)
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), nil, nil, nil, recSvc, nil)
+	h.SetVDRClient(mock)
+
+	post := func(handler http.HandlerFunc, target string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rw := httptest.NewRecorder()
+		handler(rw, req)
+		return rw
+	}
+	replay := func() *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		h.WatchTVReplay(rw, httptest.NewRequest(http.MethodGet, "/watch/replay", nil))
+		return rw
+	}
+
+	if rw := replay(); rw.Code != http.StatusNoContent {
+		t.Fatalf("expected no replay yet, got %d", rw.Code)
+	}
+
+	rw := post(h.RecordingPlay, "/recordings/play", url.Values{"path": {"3"}, "from": {"offset"}, "offset": {"1:30:00"}})
+	if rw.Code != http.StatusSeeOther || rw.Header().Get("Location") != "/watch" {
+		t.Fatalf("expected a redirect to the remote, got %d %q", rw.Code, rw.Header().Get("Location"))
+	}
+	rw = post(h.RecordingPlay, "/recordings/play", url.Values{"path": {"3"}, "from": {"begin"}})
+	if rw.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect, got %d", rw.Code)
+	}
+	if len(starts) != 2 || starts[0].Offset != 90*time.Minute || !starts[1].FromBeginning {
+		t.Fatalf("unexpected replay starts %+v", starts)
+	}
+
+	rw = replay()
+	var resp watchTVReplayResponse
+	if rw.Code != http.StatusOK || json.NewDecoder(rw.Body).Decode(&resp) != nil {
+		t.Fatalf("expected the replay as JSON, got %d", rw.Code)
+	}
+	if resp.Path != "3" || resp.Title != "Tatort" || resp.Length != 3600 || resp.LengthLabel != "1:00:00" || resp.ResumePoint != nil {
+		t.Fatalf("unexpected replay %+v", resp)
+	}
+
+	if rw := post(h.WatchTVKey, "/watch/key", url.Values{"key": {"stop"}}); rw.Code != http.StatusNoContent {
+		t.Fatalf("expected the key to be sent, got %d", rw.Code)
+	}
+	if len(keys) != 1 || keys[0] != "stop" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+	if rw := replay(); rw.Code != http.StatusNoContent {
+		t.Fatalf("expected Stop to end the replay, got %d", rw.Code)
+	}
+
+	for _, tc := range []struct {
+		form url.Values
+		want int
+	}{
+		{url.Values{"path": {"3"}, "from": {"offset"}, "offset": {"1:75"}}, http.StatusBadRequest},
+		{url.Values{"path": {"3"}, "from": {"somewhere"}}, http.StatusBadRequest},
+		{url.Values{"path": {"4"}, "from": {"begin"}}, http.StatusNotFound},
+	} {
+		if rw := post(h.RecordingPlay, "/recordings/play", tc.form); rw.Code != tc.want {
+			t.Fatalf("expected %v to fail with %d, got %d", tc.form, tc.want, rw.Code)
+		}
+	}
+}
+
+func TestParseReplayOffset(t *testing.T) {
+	cases := map[string]time.Duration{
+		"90":      90 * time.Minute,
+		"12:30":   12*time.Minute + 30*time.Second,
+		"1:02:03": time.Hour + 2*time.Minute + 3*time.Second,
+	}
+	for in, want := range cases {
+		got, err := parseReplayOffset(in)
+		if err != nil || got != want {
+			t.Fatalf("parseReplayOffset(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "x", "1:2:3:4", "-5", "0:60"} {
+		if _, err := parseReplayOffset(in); err == nil {
+			t.Fatalf("expected %q to be rejected", in)
+		}
+	}
+}
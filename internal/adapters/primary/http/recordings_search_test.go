@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"html/template"
 	"io"
 	"log/slog"
@@ -118,3 +119,77 @@ func TestRecordings_SearchThresholdAndFiltering(t *testing.T) {
 		}
 	}
 }
+
+func TestRecordings_FolderTree(t *testing.T) {
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{
+		{Path: "1", Title: "Film", Name: "Film", Channel: "ARD"},
+		{Path: "2", Title: "Folge 1", Name: "Serie~Staffel 1~Folge 1", New: true, Size: 3 << 30},
+		{Path: "3", Title: "Folge 2", Name: "Serie~Staffel 1~Folge 2"},
+	})
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "recordings.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, nil, nil, services.NewRecordingService(mock, 0), nil)
+	h.SetTemplates(map[string]*template.Template{"recordings.html": parsed})
+
+	render := func(query string) (int, string) {
+		req := httptest.NewRequest(http.MethodGet, "/recordings"+query, nil)
+		rw := httptest.NewRecorder()
+		h.RecordingList(rw, req)
+		return rw.Code, rw.Body.String()
+	}
+
+	code, body := render("")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if !strings.Contains(body, `<details class="recording-folder">`) || !strings.Contains(body, `<span class="recording-folder-title">Serie</span>`) {
+		t.Fatalf("expected the Serie folder to render")
+	}
+	if !strings.Contains(body, "2 recordings, 1 new · 3.0 GiB") {
+		t.Fatalf("expected folder totals to render")
+	}
+	if strings.Contains(body, "breadcrumbs") {
+		t.Fatalf("did not expect breadcrumbs at the top level")
+	}
+
+	code, body = render("?folder=Serie~Staffel+1&sort=name")
+	if code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if strings.Contains(body, "<h3>Film</h3>") || !strings.Contains(body, "Folge 2</h3>") {
+		t.Fatalf("expected only the folder's recordings")
+	}
+	if !strings.Contains(body, `href="/recordings?folder=Serie&amp;sort=name">Serie</a>`) {
+		t.Fatalf("expected a breadcrumb link to the parent folder")
+	}
+	if !strings.Contains(body, `name="folder" value="Serie~Staffel 1"`) {
+		t.Fatalf("expected the folder to be kept by the filters")
+	}
+	if !strings.Contains(body, `action="/recordings/play"`) || strings.Contains(body, "Move / rename") {
+		t.Fatalf("expected viewers to get Play on TV but no admin actions")
+	}
+
+	// A search lists matching recordings of all folders.
+	if _, body = render("?q=folge"); strings.Contains(body, "recording-folder-title") || !strings.Contains(body, "Folge 1</h3>") {
+		t.Fatalf("expected a flat list of search results")
+	}
+
+	if code, _ = render("?folder=Nope"); code != http.StatusNotFound {
+		t.Fatalf("expected an unknown folder to be not found, got %d", code)
+	}
+
+	// Admins can select recordings and move them into a folder.
+	req := httptest.NewRequest(http.MethodGet, "/recordings?folder=Serie~Staffel+1", nil)
+	rw := httptest.NewRecorder()
+	h.RecordingList(rw, req.WithContext(context.WithValue(req.Context(), "role", "admin")))
+	body = rw.Body.String()
+	if !strings.Contains(body, `<form id="recordings-move"`) || !strings.Contains(body, `name="path" value="2" form="recordings-move"`) {
+		t.Fatalf("expected the bulk move form and selectable recordings")
+	}
+	if !strings.Contains(body, `<option value="Serie~Staffel 1">`) || !strings.Contains(body, `<input name="name" value="Folge 1"`) {
+		t.Fatalf("expected known folders and the rename form")
+	}
+}
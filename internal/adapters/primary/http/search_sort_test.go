@@ -87,3 +87,40 @@ func TestSearch_SortsDayGroupsChronologically(t *testing.T) {
 		t.Fatalf("expected earlier day group to render first")
 	}
 }
+
+func TestSearch_RelevanceSortListsBestMatchesFirst(t *testing.T) {
+	loc := time.Local
+	mock := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{{ID: "C-1-2-3", Number: 1, Name: "3sat HD"}}).
+		WithEPGEvents([]domain.EPGEvent{
+			{EventID: 1, ChannelID: "C-1-2-3", Title: "Kabarett am Abend", Description: "Zu Gast: Hannes Ringlstetter.", Start: time.Date(2026, 1, 22, 22, 0, 0, 0, loc), Stop: time.Date(2026, 1, 22, 23, 0, 0, 0, loc)},
+			{EventID: 2, ChannelID: "C-1-2-3", Title: "Ringlstetter", Start: time.Date(2026, 1, 27, 3, 0, 0, 0, loc), Stop: time.Date(2026, 1, 27, 3, 45, 0, 0, loc)},
+		})
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "search_results.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, services.NewEPGService(mock, 0), services.NewTimerService(mock), nil, nil)
+	h.SetTemplates(map[string]*template.Template{"search_results.html": parsed})
+
+	req := httptest.NewRequest(http.MethodGet, "/search?q=Ringlstetter&sort=relevance", nil)
+	req.Header.Set("HX-Request", "true")
+	req = req.WithContext(context.WithValue(req.Context(), "role", "admin"))
+	rw := httptest.NewRecorder()
+	h.EPGSearch(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rw.Code)
+	}
+
+	body := rw.Body.String()
+	if !strings.Contains(body, "Best matches") || strings.Contains(body, "Thu 2026-01-22") {
+		t.Fatalf("expected one list of best matches: %s", body)
+	}
+	title, desc := strings.Index(body, ">Ringlstetter</div>"), strings.Index(body, ">Kabarett am Abend</div>")
+	if title < 0 || desc < 0 || title > desc {
+		t.Fatalf("expected the title match before the description match")
+	}
+	if !strings.Contains(body, "Tue 01-27") {
+		t.Fatalf("expected the date of each match")
+	}
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+)
+
+// searchTimerEntryView is the template model of a search timer log entry.
+type searchTimerEntryView struct {
+	services.SearchTimerLogEntry
+	ChannelName string
+}
+
+// searchTimerRunView is the template model of a search timer run.
+type searchTimerRunView struct {
+	services.SearchTimerRun
+	Entries []searchTimerEntryView
+}
+
+// doneEntryView is the template model of a done list entry.
+type doneEntryView struct {
+	domain.DoneEntry
+	Index       int
+	ChannelName string
+}
+
+// triggerSearchTimers starts a search timer run after saving a search with auto-record.
+func (h *Handler) triggerSearchTimers(search config.EPGSearch) {
+	if search.Active && search.AutoRecord && h.searchTimers != nil {
+		h.searchTimers.Trigger()
+	}
+}
+
+// EPGSearchLog shows what the recent search timer runs created and skipped.
+func (h *Handler) EPGSearchLog(w http.ResponseWriter, r *http.Request) {
+	nameByID := map[string]string{}
+	if channels, err := h.epgService.GetChannels(r.Context()); err == nil {
+		for _, ch := range channels {
+			nameByID[ch.ID] = ch.Name
+		}
+	}
+
+	var runs []searchTimerRunView
+	if h.searchTimers != nil {
+		for _, run := range h.searchTimers.Runs() {
+			view := searchTimerRunView{SearchTimerRun: run}
+			for _, e := range run.Entries {
+				name := e.Event.ChannelName
+				if name == "" {
+					name = nameByID[e.Event.ChannelID]
+				}
+				if name == "" {
+					name = e.Event.ChannelID
+				}
+				view.Entries = append(view.Entries, searchTimerEntryView{SearchTimerLogEntry: e, ChannelName: name})
+			}
+			runs = append(runs, view)
+		}
+	}
+
+	data := map[string]any{
+		"Runs":    runs,
+		"Message": strings.TrimSpace(r.URL.Query().Get("msg")),
+		"Error":   strings.TrimSpace(r.URL.Query().Get("err")),
+	}
+	h.renderTemplate(w, r, "epgsearch_log.html", data)
+}
+
+// EPGSearchAutoRecordRun runs the search timers now.
+func (h *Handler) EPGSearchAutoRecordRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.searchTimers == nil {
+		http.Error(w, "Search timers not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	run, err := h.searchTimers.Process(r.Context())
+	if err != nil {
+		http.Redirect(w, r, "/epgsearch/log?err="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	msg := fmt.Sprintf("Search timers processed: created %d, skipped %d.", run.Created, run.Skipped)
+	http.Redirect(w, r, "/epgsearch/log?msg="+url.QueryEscape(msg), http.StatusSeeOther)
+}
+
+// EPGSearchDone shows the done list: the episodes search timers and AutoTimers
+// scheduled, which they do not schedule again. Newest entries come first.
+func (h *Handler) EPGSearchDone(w http.ResponseWriter, r *http.Request) {
+	nameByID := map[string]string{}
+	if channels, err := h.epgService.GetChannels(r.Context()); err == nil {
+		for _, ch := range channels {
+			nameByID[ch.ID] = ch.Name
+		}
+	}
+
+	var entries []doneEntryView
+	if h.dedup != nil {
+		done := h.dedup.Done()
+		for i := len(done) - 1; i >= 0; i-- {
+			name := nameByID[done[i].ChannelID]
+			if name == "" {
+				name = done[i].ChannelID
+			}
+			entries = append(entries, doneEntryView{DoneEntry: done[i], Index: i, ChannelName: name})
+		}
+	}
+
+	data := map[string]any{
+		"Entries": entries,
+		"Message": strings.TrimSpace(r.URL.Query().Get("msg")),
+		"Error":   strings.TrimSpace(r.URL.Query().Get("err")),
+	}
+	h.renderTemplate(w, r, "epgsearch_done.html", data)
+}
+
+// EPGSearchDoneDelete removes an entry from the done list, so the episode may
+// be recorded again.
+func (h *Handler) EPGSearchDoneDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.dedup == nil {
+		http.Error(w, "Done list not available", http.StatusServiceUnavailable)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Invalid form", http.StatusBadRequest)
+		return
+	}
+	index, err := strconv.Atoi(r.PostForm.Get("index"))
+	if err != nil {
+		http.Error(w, "Invalid index", http.StatusBadRequest)
+		return
+	}
+	if err := h.dedup.RemoveDone(index); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			http.Error(w, "Entry not found", http.StatusNotFound)
+			return
+		}
+		http.Redirect(w, r, "/epgsearch/done?err="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/epgsearch/done?msg="+url.QueryEscape("Removed the episode from the done list."), http.StatusSeeOther)
+}
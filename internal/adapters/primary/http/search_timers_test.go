@@ -0,0 +1,120 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestSearchTimers_SaveRunAndLog(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	mock := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{{ID: "C-1-1-1", Number: 1, Name: "Das Erste"}}).
+		WithEPGEvents([]domain.EPGEvent{{EventID: 1, ChannelID: "C-1-1-1", Title: "Tatort", Subtitle: "Borowski", Start: base, Stop: base.Add(90 * time.Minute)}})
+	h := newFavouritesHandler(t, "epgsearch_log.html", mock)
+	h.SetSearchTimerService(services.NewSearchTimerService(services.NewTimerService(mock), h.epgService, func() []config.EPGSearch {
+		return h.cfg.EPG.Searches
+	}))
+
+	form := url.Values{
+		"action": {"save"}, "active": {"on"}, "pattern": {"Tatort"}, "mode": {"phrase"}, "in_title": {"on"}, "use_channel": {"no"},
+		"auto_record": {"on"}, "priority": {"70"}, "lifetime": {"14"}, "margin_start": {"3"}, "margin_end": {"12"}, "folder": {"Krimi/Tatort"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/epgsearch/new", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw := httptest.NewRecorder()
+	h.EPGSearchCreate(rw, req)
+	if rw.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect, got %d: %s", rw.Code, rw.Body.String())
+	}
+	saved, err := config.Load(h.configPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(saved.EPG.Searches) != 1 {
+		t.Fatalf("saved searches = %+v", saved.EPG.Searches)
+	}
+	if s := saved.EPG.Searches[0]; !s.AutoRecord || s.Priority != 70 || s.Lifetime != 14 || s.MarginStart != 3 || s.MarginEnd != 12 || s.Folder != "Krimi~Tatort" {
+		t.Fatalf("saved search = %+v", s)
+	}
+
+	rw = httptest.NewRecorder()
+	h.EPGSearchAutoRecordRun(rw, httptest.NewRequest(http.MethodPost, "/epgsearch/autorecord", nil))
+	if rw.Code != http.StatusSeeOther || !strings.Contains(rw.Header().Get("Location"), url.QueryEscape("created 1, skipped 0")) {
+		t.Fatalf("run redirect = %d %q", rw.Code, rw.Header().Get("Location"))
+	}
+	timers, _ := mock.GetTimers(context.Background())
+	if len(timers) != 1 || timers[0].Title != "Krimi~Tatort~Tatort" || timers[0].Priority != 70 {
+		t.Fatalf("timers = %+v", timers)
+	}
+
+	rw = httptest.NewRecorder()
+	h.EPGSearchLog(rw, httptest.NewRequest(http.MethodGet, "/epgsearch/log", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	body := rw.Body.String()
+	for _, want := range []string{"created 1, skipped 0", "Das Erste", "Tatort - Borowski", `href="/epgsearch/edit?id=1"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("log lacks %q", want)
+		}
+	}
+}
+
+func TestSearchTimers_DoneList(t *testing.T) {
+	mock := ports.NewMockVDRClient().WithChannels([]domain.Channel{{ID: "C-1-1-1", Number: 1, Name: "Das Erste"}})
+	h := newFavouritesHandler(t, "epgsearch_done.html", mock)
+	dedup := services.NewDedupService()
+	h.SetDedupService(dedup)
+	start := time.Now().Add(-24 * time.Hour)
+	if err := dedup.MarkDone(services.SearchDoneSource(4),
+		domain.EPGEvent{EventID: 1, ChannelID: "C-1-1-1", Title: "Tatort", Subtitle: "Borowski", Start: start},
+		domain.EPGEvent{EventID: 2, ChannelID: "C-1-1-1", Title: "Polizeiruf 110", Start: start.Add(time.Hour)},
+	); err != nil {
+		t.Fatalf("MarkDone: %v", err)
+	}
+
+	rw := httptest.NewRecorder()
+	h.EPGSearchDone(rw, httptest.NewRequest(http.MethodGet, "/epgsearch/done", nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rw.Code, rw.Body.String())
+	}
+	body := rw.Body.String()
+	for _, want := range []string{"Tatort - Borowski", "Polizeiruf 110", "Das Erste", "search 4"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("done list lacks %q", want)
+		}
+	}
+	if strings.Index(body, "Polizeiruf 110") > strings.Index(body, "Tatort - Borowski") {
+		t.Errorf("expected the newest entry first")
+	}
+
+	form := url.Values{"index": {"0"}}
+	req := httptest.NewRequest(http.MethodPost, "/epgsearch/done/delete", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw = httptest.NewRecorder()
+	h.EPGSearchDoneDelete(rw, req)
+	if rw.Code != http.StatusSeeOther {
+		t.Fatalf("expected a redirect, got %d: %s", rw.Code, rw.Body.String())
+	}
+	if done := dedup.Done(); len(done) != 1 || done[0].Title != "Polizeiruf 110" {
+		t.Fatalf("done list = %+v", done)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/epgsearch/done/delete", strings.NewReader(url.Values{"index": {"5"}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw = httptest.NewRecorder()
+	h.EPGSearchDoneDelete(rw, req)
+	if rw.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing entry, got %d", rw.Code)
+	}
+}
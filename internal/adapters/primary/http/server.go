@@ -20,7 +20,7 @@ type Server struct {
 
 // NewServer creates a new HTTP server
 func NewServer(cfg *config.ServerConfig, logger *slog.Logger, handler *Handler, mux http.Handler) *Server {
-	return &Server{
+	s := &Server{
 		config:  cfg,
 		logger:  logger,
 		handler: handler,
@@ -32,6 +32,11 @@ func NewServer(cfg *config.ServerConfig, logger *slog.Logger, handler *Handler,
 			MaxHeaderBytes: cfg.MaxHeaderBytes,
 		},
 	}
+	if handler != nil {
+		// Shutdown waits for active requests; event streams would otherwise hold it up.
+		s.server.RegisterOnShutdown(handler.CloseStreams)
+	}
+	return s
 }
 
 // Start starts the HTTP server
@@ -79,6 +84,8 @@ func SetupRoutes(handler *Handler, authCfg *config.AuthConfig, logger *slog.Logg
 	commonMiddleware := []func(http.Handler) http.Handler{
 		RecoveryMiddleware(logger),
 		LoggingMiddleware(logger),
+		MetricsMiddleware(handler.metrics),
+		TracingMiddleware(),
 		SecurityHeadersMiddleware(),
 		CompressionMiddleware(),
 		AuthMiddleware(authCfg),
@@ -91,16 +98,21 @@ func SetupRoutes(handler *Handler, authCfg *config.AuthConfig, logger *slog.Logg
 	mux.Handle("GET /", chain(handler.Home, commonMiddleware...))
 	mux.Handle("GET /now", chain(handler.WhatsOnNow, commonMiddleware...))
 	mux.Handle("GET /channels", chain(handler.Channels, commonMiddleware...))
+	mux.Handle("GET /grid", chain(handler.EPGGrid, commonMiddleware...))
+	mux.Handle("GET /logos/channel", chain(handler.ChannelLogo, commonMiddleware...))
 	mux.Handle("GET /configurations", chain(handler.Configurations, commonMiddleware...))
 
 	// Config management sub-pages (admin-only)
 	mux.Handle("GET /configurations/archive-profiles", chain(handler.ConfigurationsArchiveProfiles, adminMiddleware...))
 	mux.Handle("POST /configurations/archive-profiles/save", chain(handler.ConfigurationsArchiveProfilesSave, adminMiddleware...))
+	mux.Handle("GET /configurations/favourites", chain(handler.Favourites, adminMiddleware...))
+	mux.Handle("POST /configurations/favourites", chain(handler.FavouritesSave, adminMiddleware...))
 	mux.Handle("GET /playing", chain(handler.PlayingToday, commonMiddleware...))
 	mux.Handle("GET /watch", chain(handler.WatchTV, commonMiddleware...))
 	mux.Handle("POST /watch/key", chain(handler.WatchTVKey, commonMiddleware...))
 	mux.Handle("POST /watch/channel", chain(handler.WatchTVChannel, commonMiddleware...))
 	mux.Handle("GET /watch/now", chain(handler.WatchTVNow, commonMiddleware...))
+	mux.Handle("GET /watch/replay", chain(handler.WatchTVReplay, commonMiddleware...))
 	mux.Handle("GET /watch/snapshot", chain(handler.WatchTVSnapshot, commonMiddleware...))
 	mux.Handle("GET /watch/stream/{channel}/index.m3u8", chain(handler.WatchTVStreamPlaylist, commonMiddleware...))
 	mux.Handle("GET /watch/stream/{channel}/{segment}", chain(handler.WatchTVStreamSegment, commonMiddleware...))
@@ -109,11 +121,22 @@ func SetupRoutes(handler *Handler, authCfg *config.AuthConfig, logger *slog.Logg
 	mux.Handle("GET /search", chain(handler.EPGSearch, commonMiddleware...))
 	mux.Handle("GET /epgsearch", chain(handler.EPGSearchList, commonMiddleware...))
 	mux.Handle("POST /epgsearch/execute", chain(handler.EPGSearchExecute, commonMiddleware...))
+	mux.Handle("GET /epgsearch/log", chain(handler.EPGSearchLog, commonMiddleware...))
+	mux.Handle("GET /epgsearch/done", chain(handler.EPGSearchDone, commonMiddleware...))
 	mux.Handle("GET /timers", chain(handler.TimerList, commonMiddleware...))
+	mux.Handle("GET /timers/alternatives", chain(handler.TimerAlternatives, commonMiddleware...))
+	mux.Handle("GET /timers/history", chain(handler.RecordingHistory, commonMiddleware...))
+	mux.Handle("GET /autotimers", chain(handler.AutoTimerList, commonMiddleware...))
 	mux.Handle("GET /recordings", chain(handler.RecordingList, commonMiddleware...))
 	mux.Handle("POST /recordings/refresh", chain(handler.RecordingRefresh, commonMiddleware...))
+	mux.Handle("POST /recordings/play", chain(handler.RecordingPlay, commonMiddleware...))
 
 	// Archive (admin-only for now)
+	mux.Handle("GET /recordings/marks", chain(handler.RecordingMarks, adminMiddleware...))
+	mux.Handle("POST /recordings/marks", chain(handler.RecordingMarksSave, adminMiddleware...))
+	mux.Handle("GET /recordings/marks/thumbnail", chain(handler.RecordingMarkThumbnail, adminMiddleware...))
+	mux.Handle("POST /recordings/marks/cut", chain(handler.RecordingCut, adminMiddleware...))
+	mux.Handle("GET /recordings/marks/cut/status", chain(handler.RecordingCutStatus, adminMiddleware...))
 	mux.Handle("GET /recordings/archive", chain(handler.RecordingArchivePrepare, adminMiddleware...))
 	mux.Handle("GET /recordings/archive/preview", chain(handler.RecordingArchivePreview, adminMiddleware...))
 	mux.Handle("POST /recordings/archive/start", chain(handler.RecordingArchiveStart, adminMiddleware...))
@@ -132,16 +155,85 @@ func SetupRoutes(handler *Handler, authCfg *config.AuthConfig, logger *slog.Logg
 	mux.Handle("GET /epgsearch/edit", chain(handler.EPGSearchEdit, adminMiddleware...))
 	mux.Handle("POST /epgsearch/edit", chain(handler.EPGSearchUpdate, adminMiddleware...))
 	mux.Handle("POST /epgsearch/delete", chain(handler.EPGSearchDelete, adminMiddleware...))
+	mux.Handle("POST /epgsearch/autorecord", chain(handler.EPGSearchAutoRecordRun, adminMiddleware...))
+	mux.Handle("POST /epgsearch/done/delete", chain(handler.EPGSearchDoneDelete, adminMiddleware...))
+	mux.Handle("GET /autotimers/new", chain(handler.AutoTimerNew, adminMiddleware...))
+	mux.Handle("POST /autotimers/new", chain(handler.AutoTimerCreate, adminMiddleware...))
+	mux.Handle("GET /autotimers/edit", chain(handler.AutoTimerEdit, adminMiddleware...))
+	mux.Handle("POST /autotimers/edit", chain(handler.AutoTimerUpdate, adminMiddleware...))
+	mux.Handle("POST /autotimers/toggle", chain(handler.AutoTimerToggle, adminMiddleware...))
+	mux.Handle("POST /autotimers/delete", chain(handler.AutoTimerDelete, adminMiddleware...))
+	mux.Handle("POST /autotimers/run", chain(handler.AutoTimerRun, adminMiddleware...))
 	mux.Handle("GET /timers/new", chain(handler.TimerNew, adminMiddleware...))
 	mux.Handle("POST /timers/new", chain(handler.TimerCreateManual, adminMiddleware...))
 	mux.Handle("GET /timers/edit", chain(handler.TimerEdit, adminMiddleware...))
 	mux.Handle("POST /timers/create", chain(handler.TimerCreate, adminMiddleware...))
 	mux.Handle("POST /timers/update", chain(handler.TimerUpdate, adminMiddleware...))
 	mux.Handle("POST /timers/toggle", chain(handler.TimerToggle, adminMiddleware...))
+	mux.Handle("POST /timers/move", chain(handler.TimerMove, adminMiddleware...))
 	mux.Handle("DELETE /timers", chain(handler.TimerDelete, adminMiddleware...))
 	mux.Handle("POST /timers/delete", chain(handler.TimerDelete, adminMiddleware...)) // For browsers without DELETE
 	mux.Handle("DELETE /recordings", chain(handler.RecordingDelete, adminMiddleware...))
 	mux.Handle("POST /recordings/delete", chain(handler.RecordingDelete, adminMiddleware...)) // For browsers without DELETE
+	mux.Handle("POST /recordings/move", chain(handler.RecordingMove, adminMiddleware...))
+	mux.Handle("POST /recordings/rename", chain(handler.RecordingRename, adminMiddleware...))
+	mux.Handle("GET /channels/manage", chain(handler.ChannelsManage, adminMiddleware...))
+	mux.Handle("POST /channels/manage", chain(handler.ChannelsManageSave, adminMiddleware...))
+	mux.Handle("GET /channels/manage/export", chain(handler.ChannelsExport, adminMiddleware...))
+	mux.Handle("POST /channels/manage/import", chain(handler.ChannelsImport, adminMiddleware...))
+	mux.Handle("GET /channels/logos", chain(handler.ChannelLogos, adminMiddleware...))
+	mux.Handle("POST /channels/logos/reload", chain(handler.ChannelLogosReload, adminMiddleware...))
+
+	// JSON API (reads use the common middleware, writes require admin with a JSON error body)
+	apiAdminMiddleware := append(append([]func(http.Handler) http.Handler(nil), commonMiddleware...), RequireAdminAPIMiddleware())
+	mux.Handle("GET /api/v1/openapi.json", chain(handler.APIOpenAPI, commonMiddleware...))
+	mux.Handle("GET /api/v1/channels", chain(handler.APIChannels, commonMiddleware...))
+	mux.Handle("GET /api/v1/channels/{id}/epg", chain(handler.APIChannelEPG, commonMiddleware...))
+	mux.Handle("GET /api/v1/epg/now", chain(handler.APIEPGNow, commonMiddleware...))
+	mux.Handle("GET /api/v1/epg/next", chain(handler.APIEPGNext, commonMiddleware...))
+	mux.Handle("GET /api/v1/epg/at", chain(handler.APIEPGAt, commonMiddleware...))
+	mux.Handle("GET /api/v1/epg/search", chain(handler.APIEPGSearch, commonMiddleware...))
+	mux.Handle("GET /api/v1/timers", chain(handler.APITimers, commonMiddleware...))
+	mux.Handle("GET /api/v1/timers/conflicts", chain(handler.APITimerConflicts, commonMiddleware...))
+	mux.Handle("GET /api/v1/timers/{id}", chain(handler.APITimer, commonMiddleware...))
+	mux.Handle("POST /api/v1/timers", chain(handler.APITimerCreate, apiAdminMiddleware...))
+	mux.Handle("PUT /api/v1/timers/{id}", chain(handler.APITimerUpdate, apiAdminMiddleware...))
+	mux.Handle("DELETE /api/v1/timers/{id}", chain(handler.APITimerDelete, apiAdminMiddleware...))
+	mux.Handle("GET /api/v1/recordings", chain(handler.APIRecordings, commonMiddleware...))
+	mux.Handle("GET /api/v1/recordings/tree", chain(handler.APIRecordingTree, commonMiddleware...))
+	mux.Handle("DELETE /api/v1/recordings", chain(handler.APIRecordingDelete, apiAdminMiddleware...))
+	mux.Handle("GET /api/v1/searches", chain(handler.APISavedSearches, commonMiddleware...))
+	mux.Handle("GET /api/v1/searches/{id}", chain(handler.APISavedSearch, commonMiddleware...))
+	mux.Handle("GET /api/v1/searches/{id}/results", chain(handler.APISavedSearchResults, commonMiddleware...))
+	mux.Handle("POST /api/v1/searches", chain(handler.APISavedSearchCreate, apiAdminMiddleware...))
+	mux.Handle("PUT /api/v1/searches/{id}", chain(handler.APISavedSearchUpdate, apiAdminMiddleware...))
+	mux.Handle("DELETE /api/v1/searches/{id}", chain(handler.APISavedSearchDelete, apiAdminMiddleware...))
+	mux.Handle("GET /api/v1/archive/profiles", chain(handler.APIArchiveProfiles, apiAdminMiddleware...))
+	mux.Handle("PUT /api/v1/archive/profiles", chain(handler.APIArchiveProfilesReplace, apiAdminMiddleware...))
+	mux.Handle("GET /api/v1/archive/jobs", chain(handler.APIArchiveJobs, apiAdminMiddleware...))
+	mux.Handle("POST /api/v1/archive/jobs", chain(handler.APIArchiveJobStart, apiAdminMiddleware...))
+	mux.Handle("GET /api/v1/archive/jobs/{id}", chain(handler.APIArchiveJob, apiAdminMiddleware...))
+	mux.Handle("POST /api/v1/archive/jobs/{id}/cancel", chain(handler.APIArchiveJobCancel, apiAdminMiddleware...))
+
+	// Server-Sent Events (no compression so events are flushed immediately)
+	eventMiddleware := []func(http.Handler) http.Handler{
+		RecoveryMiddleware(logger),
+		LoggingMiddleware(logger),
+		MetricsMiddleware(handler.metrics),
+		TracingMiddleware(),
+		SecurityHeadersMiddleware(),
+		AuthMiddleware(authCfg),
+	}
+	mux.Handle("GET /events", chain(handler.Events, eventMiddleware...))
+
+	// Prometheus metrics (own bearer token if configured, regular authentication otherwise)
+	mux.Handle("GET /metrics", chain(handler.Metrics,
+		RecoveryMiddleware(logger),
+		LoggingMiddleware(logger),
+		SecurityHeadersMiddleware(),
+		CompressionMiddleware(),
+		handler.metricsAuthMiddleware(authCfg),
+	))
 
 	// Static files
 	fs := http.FileServer(http.Dir("web/static"))
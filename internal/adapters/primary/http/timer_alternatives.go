@@ -0,0 +1,74 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// TimerAlternatives lists other broadcasts of the program a timer records, so a
+// conflicting timer can be moved to a repeat.
+func (h *Handler) TimerAlternatives(w http.ResponseWriter, r *http.Request) {
+	timerID, _ := strconv.Atoi(r.URL.Query().Get("id"))
+	if timerID <= 0 {
+		http.Error(w, "Invalid timer ID", http.StatusBadRequest)
+		return
+	}
+
+	_, _, marginStart, marginEnd := h.timerDefaults()
+	timer, original, alternatives, err := h.alternatives.FindAlternatives(r.Context(), timerID, marginStart, marginEnd)
+	if err != nil {
+		if timer.ID != 0 && timer.ID == timerID {
+			// Known timer that has no alternatives (e.g. recurring); explain on the page.
+			h.renderTemplate(w, r, "timer_alternatives.html", map[string]any{
+				"Timer": timer,
+				"Error": err.Error(),
+			})
+			return
+		}
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.renderTemplate(w, r, "timer_alternatives.html", map[string]any{
+		"Timer":        timer,
+		"Event":        original,
+		"Alternatives": alternatives,
+		"Error":        strings.TrimSpace(r.URL.Query().Get("err")),
+	})
+}
+
+// TimerMove moves a timer to another airing of the same program.
+func (h *Handler) TimerMove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	timerID, _ := strconv.Atoi(strings.TrimSpace(r.FormValue("id")))
+	eventID, _ := strconv.Atoi(strings.TrimSpace(r.FormValue("event_id")))
+	channelID := strings.TrimSpace(r.FormValue("channel"))
+	if timerID <= 0 || eventID <= 0 {
+		h.handleError(w, r, domain.ErrInvalidInput)
+		return
+	}
+
+	_, _, marginStart, marginEnd := h.timerDefaults()
+	moved, err := h.alternatives.MoveTimer(r.Context(), timerID, channelID, eventID, marginStart, marginEnd)
+	if err != nil {
+		h.handleTimerChangeError(w, r, err)
+		return
+	}
+
+	msg := "Timer moved to " + moved.Start.Format("2006-01-02 15:04")
+	if conflict, err := h.timerService.CheckTimerConflicts(r.Context(), &moved); err == nil && conflict != nil {
+		msg += ". Warning: " + conflict.Error()
+	}
+	h.redirectToTimers(w, r, msg)
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func newTimerAlternativesTestHandler(t *testing.T, mock *ports.MockVDRClient) *Handler {
+	t.Helper()
+	epgSvc := services.NewEPGService(mock, 0)
+	timerSvc := services.NewTimerService(mock)
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "timer_alternatives.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, epgSvc, timerSvc, nil, nil)
+	h.SetTemplates(map[string]*template.Template{"timer_alternatives.html": parsed})
+	return h
+}
+
+func TestTimerAlternatives_ListsRepeatsAndMovesTimer(t *testing.T) {
+	base := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	original := domain.EPGEvent{EventID: 10, ChannelID: "S19.2E-1-1011-11110", ChannelName: "Das Erste HD", Title: "Tatort", Subtitle: "Der Fall", Start: base, Stop: base.Add(90 * time.Minute)}
+	repeat := domain.EPGEvent{EventID: 11, ChannelID: "S19.2E-1-1011-11110", ChannelName: "Das Erste HD", Title: "Tatort", Subtitle: "Der Fall", Start: base.Add(24 * time.Hour), Stop: base.Add(25*time.Hour + 30*time.Minute)}
+
+	var updated []domain.Timer
+	mock := ports.NewMockVDRClient().
+		WithEPGEvents([]domain.EPGEvent{original, repeat}).
+		WithTimers([]domain.Timer{
+			{ID: 1, Active: true, ChannelID: "S19.2E-1-1019-10301", Start: base, Stop: base.Add(time.Hour), Title: "News"},
+			{ID: 2, Active: true, ChannelID: original.ChannelID, EventID: 10, Start: original.Start, Stop: original.Stop, Title: "Tatort"},
+		})
+	mock.UpdateTimerFunc = func(ctx context.Context, timer *domain.Timer) error {
+		updated = append(updated, *timer)
+		return nil
+	}
+	h := newTimerAlternativesTestHandler(t, mock)
+
+	req := httptest.NewRequest(http.MethodGet, "/timers/alternatives?id=2", nil)
+	ctx := context.WithValue(req.Context(), "user", "admin")
+	ctx = context.WithValue(ctx, "role", "admin")
+	rw := httptest.NewRecorder()
+	h.TimerAlternatives(rw, req.WithContext(ctx))
+
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rw.Code)
+	}
+	body := rw.Body.String()
+	mustContain(t, body, repeat.Start.Format("Mon 2006-01-02 15:04"))
+	mustContain(t, body, "No conflict")
+	mustContain(t, body, `action="/timers/move"`)
+	mustContain(t, body, `name="event_id" value="11"`)
+
+	form := url.Values{}
+	form.Set("id", "2")
+	form.Set("event_id", "11")
+	form.Set("channel", repeat.ChannelID)
+	req = httptest.NewRequest(http.MethodPost, "/timers/move", bytes.NewBufferString(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rw = httptest.NewRecorder()
+	h.TimerMove(rw, req)
+
+	if rw.Code != http.StatusSeeOther {
+		t.Fatalf("expected status 303, got %d", rw.Code)
+	}
+	if loc := rw.Header().Get("Location"); !strings.HasPrefix(loc, "/timers?msg=") {
+		t.Fatalf("unexpected redirect %q", loc)
+	}
+	if len(updated) != 1 || updated[0].ID != 2 || updated[0].EventID != 11 || !updated[0].Start.Equal(repeat.Start) {
+		t.Fatalf("unexpected update: %+v", updated)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -88,3 +89,77 @@ func TestTimerCreate_UsesConfiguredDefaultMargins(t *testing.T) {
 		t.Fatalf("expected Stop %s, got %s", expectedStop.Format(time.RFC3339), timer.Stop.Format(time.RFC3339))
 	}
 }
+
+func TestTimerCreate_ConflictPolicyWarnAndReject(t *testing.T) {
+	start := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	stop := start.Add(time.Hour)
+
+	var mu sync.Mutex
+	created := 0
+
+	mock := ports.NewMockVDRClient().
+		WithEPGEvents([]domain.EPGEvent{{
+			EventID:   123,
+			ChannelID: "S19.2E-1-1011-11110",
+			Title:     "Show",
+			Start:     start,
+			Stop:      stop,
+		}}).
+		WithTimers([]domain.Timer{{
+			ID:        1,
+			Active:    true,
+			ChannelID: "S19.2E-1-1019-10301",
+			Start:     start.Add(-30 * time.Minute),
+			Stop:      stop,
+			Priority:  50,
+			Title:     "Other",
+		}})
+	mock.CreateTimerFunc = func(ctx context.Context, timer *domain.Timer) error {
+		mu.Lock()
+		defer mu.Unlock()
+		created++
+		return nil
+	}
+
+	epgSvc := services.NewEPGService(mock, 0)
+	timerSvc := services.NewTimerService(mock)
+	recSvc := services.NewRecordingService(mock, 0)
+	autoSvc := services.NewAutoTimerService(mock, timerSvc, epgSvc)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+	h := NewHandler(logger, template.New("test"), epgSvc, timerSvc, recSvc, autoSvc)
+
+	post := func() *http.Response {
+		form := url.Values{}
+		form.Set("event_id", "123")
+		form.Set("channel", "S19.2E-1-1011-11110")
+		req := httptest.NewRequest(http.MethodPost, "/timers/create", bytes.NewBufferString(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		h.TimerCreate(w, req)
+		return w.Result()
+	}
+
+	resp := post()
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("warn: expected %d, got %d", http.StatusSeeOther, resp.StatusCode)
+	}
+	if loc := resp.Header.Get("Location"); !strings.HasPrefix(loc, "/timers?msg=") || !strings.Contains(loc, "Other") {
+		t.Fatalf("warn: expected warning redirect mentioning the other timer, got %q", loc)
+	}
+
+	timerSvc.SetConflictPolicy(services.ConflictPolicyReject)
+	resp = post()
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("reject: expected %d, got %d", http.StatusSeeOther, resp.StatusCode)
+	}
+	if loc := resp.Header.Get("Location"); !strings.HasPrefix(loc, "/timers?err=") {
+		t.Fatalf("reject: expected error redirect, got %q", loc)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if created != 1 {
+		t.Fatalf("expected only the warned timer to be created, got %d", created)
+	}
+}
@@ -54,6 +54,21 @@ func (m *timersTimelineVDRMock) GetRecordingDir(ctx context.Context, recordingID
 	return "", nil
 }
 func (m *timersTimelineVDRMock) DeleteRecording(ctx context.Context, path string) error { return nil }
+func (m *timersTimelineVDRMock) MoveRecording(ctx context.Context, path, newName string) error {
+	return nil
+}
+func (m *timersTimelineVDRMock) CutRecording(ctx context.Context, path string) error { return nil }
+func (m *timersTimelineVDRMock) UpdateChannel(ctx context.Context, number int, settings string) error {
+	return nil
+}
+func (m *timersTimelineVDRMock) MoveChannel(ctx context.Context, number, to int) error { return nil }
+func (m *timersTimelineVDRMock) CreateChannel(ctx context.Context, settings string) (int, error) {
+	return 0, nil
+}
+func (m *timersTimelineVDRMock) DeleteChannel(ctx context.Context, number int) error { return nil }
+func (m *timersTimelineVDRMock) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error {
+	return nil
+}
 func (m *timersTimelineVDRMock) GetCurrentChannel(ctx context.Context) (string, error) {
 	return "", nil
 }
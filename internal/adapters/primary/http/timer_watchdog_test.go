@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestTimerList_ShowsTimerWatchdogChanges(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	ch := domain.Channel{ID: "C-1", Number: 1, Name: "Das Erste HD"}
+	planned := []domain.EPGEvent{
+		{EventID: 1, ChannelID: ch.ID, ChannelNumber: 1, Title: "Tatort", Start: base, Stop: base.Add(90 * time.Minute)},
+		{EventID: 2, ChannelID: ch.ID, ChannelNumber: 1, Title: "Brennpunkt", Start: base.Add(2 * time.Hour), Stop: base.Add(135 * time.Minute)},
+	}
+	moved := services.NewTimerFromEPG(planned[0], 50, 99, 2, 10)
+	moved.ID = 1
+	vanished := services.NewTimerFromEPG(planned[1], 50, 99, 2, 10)
+	vanished.ID = 2
+
+	mock := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{ch}).
+		WithEPGEvents([]domain.EPGEvent{
+			{EventID: 1, ChannelID: ch.ID, ChannelNumber: 1, Title: "Tatort", Start: base.Add(15 * time.Minute), Stop: base.Add(105 * time.Minute)},
+			{EventID: 3, ChannelID: ch.ID, ChannelNumber: 1, Title: "Tagesthemen", Start: base.Add(2 * time.Hour), Stop: base.Add(150 * time.Minute)},
+		}).
+		WithTimers([]domain.Timer{moved, vanished})
+
+	epgService := services.NewEPGService(mock, 0)
+	timerService := services.NewTimerService(mock)
+	watchdog := services.NewTimerWatchdog(timerService, epgService)
+	if _, err := watchdog.Check(context.Background()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "timers.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, epgService, timerService, nil, nil)
+	h.SetUIThemeDefault("light")
+	h.SetTemplates(map[string]*template.Template{"timers.html": parsed})
+	h.SetConfig(&config.Config{VDR: config.VDRConfig{DVBCards: 2}}, "")
+	h.SetTimerWatchdog(watchdog)
+
+	req := httptest.NewRequest(http.MethodGet, "/timers?day="+base.Format("2006-01-02"), nil)
+	rw := httptest.NewRecorder()
+	h.TimerList(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rw.Code)
+	}
+
+	body := rw.Body.String()
+	if !strings.Contains(body, "EPG schedule changes (2)") {
+		t.Fatalf("expected the list of watchdog changes, body=%s", body)
+	}
+	if !strings.Contains(body, "to "+base.Add(13*time.Minute).Format("01-02 15:04")) {
+		t.Fatalf("expected the moved start time in the change list")
+	}
+	if strings.Count(body, "Event no longer in the EPG") != 1 {
+		t.Fatalf("expected exactly one timer flagged as vanished")
+	}
+}
+
+func TestTimerList_WatchesTimersOfEveryBackend(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	ch := domain.Channel{ID: "C-1", Number: 1, Name: "Das Erste HD"}
+	planned := domain.EPGEvent{EventID: 1, ChannelID: ch.ID, ChannelNumber: 1, Title: "Tatort", Start: base, Stop: base.Add(90 * time.Minute)}
+	timer := services.NewTimerFromEPG(planned, 50, 99, 2, 10)
+	timer.ID = 1
+	// Both VDRs have a timer with ID 1; only the bedroom's event vanished.
+	epg := func(title string) []domain.EPGEvent {
+		return []domain.EPGEvent{{EventID: 2, ChannelID: ch.ID, ChannelNumber: 1, Title: title, Start: base, Stop: base.Add(90 * time.Minute)}}
+	}
+	mainClient := ports.NewMockVDRClient().WithChannels([]domain.Channel{ch}).
+		WithEPGEvents(append(epg("Brennpunkt"), planned)).WithTimers([]domain.Timer{timer})
+	bedroomClient := ports.NewMockVDRClient().WithChannels([]domain.Channel{ch}).
+		WithEPGEvents(epg("Brennpunkt")).WithTimers([]domain.Timer{timer})
+
+	mainBackend := newTestBackend("main", mainClient, 1)
+	bedroom := newTestBackend("bedroom", bedroomClient, 1)
+	for _, b := range []*services.Backend{mainBackend, bedroom} {
+		b.Watchdog = services.NewTimerWatchdog(b.Timers, b.EPG)
+		if _, err := b.Watchdog.Check(context.Background()); err != nil {
+			t.Fatalf("Check %s: %v", b.Name, err)
+		}
+	}
+
+	parsed := template.Must(template.ParseFiles(
+		filepath.Join(repoRoot(t), "web", "templates", "_nav.html"),
+		filepath.Join(repoRoot(t), "web", "templates", "timers.html"),
+	))
+	h := NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil)), parsed, mainBackend.EPG, mainBackend.Timers, nil, nil)
+	h.SetTemplates(map[string]*template.Template{"timers.html": parsed})
+	h.SetConfig(&config.Config{VDR: config.VDRConfig{Name: "main", DVBCards: 1}}, "")
+	h.SetBackends(services.NewBackends(mainBackend, bedroom))
+
+	rw := httptest.NewRecorder()
+	h.TimerList(rw, httptest.NewRequest(http.MethodGet, "/timers?day="+base.Format("2006-01-02"), nil))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rw.Code)
+	}
+	body := rw.Body.String()
+	if strings.Count(body, "Event no longer in the EPG") != 1 {
+		t.Fatalf("expected only the bedroom timer to be flagged, body=%s", body)
+	}
+	if !strings.Contains(body, `<span class="badge timer-backend">bedroom</span> Tatort`) {
+		t.Fatalf("expected the change to name its backend, body=%s", body)
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/githubixx/vdradmin-go/internal/application/services"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestTracingMiddleware_SpansFollowRequest(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	mock := ports.NewMockVDRClient()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := NewHandler(logger, template.New("empty"), services.NewEPGService(mock, 0), services.NewTimerService(mock), nil, nil)
+	srv := httptest.NewServer(SetupRoutes(h, &config.AuthConfig{}, logger))
+	defer srv.Close()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/timers", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET timers: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		spans[s.Name()] = s
+	}
+	server, ok := spans["GET /api/v1/timers"]
+	if !ok {
+		t.Fatalf("expected a server span named after the route, got %v", spans)
+	}
+	if got := server.SpanContext().TraceID().String(); got != traceID {
+		t.Fatalf("expected the incoming trace to be continued, got trace %s", got)
+	}
+	svc, ok := spans["TimerService.GetAllTimers"]
+	if !ok {
+		t.Fatalf("expected a service span, got %v", spans)
+	}
+	if svc.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Fatalf("expected the service span to be a child of the server span")
+	}
+}
@@ -0,0 +1,311 @@
+// Package svdrpproxy provides an SVDRP-compatible TCP port that relays commands
+// of other SVDRP clients (svdrpsend, epgsearch scripts, ...) through the single
+// connection vdradmin-go holds to VDR. VDR serves one SVDRP client at a time, so
+// without the proxy these tools and vdradmin-go lock each other out.
+package svdrpproxy
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/adapters/secondary/svdrp"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+)
+
+// Executor executes a raw SVDRP command on VDR and returns the reply lines
+// verbatim, including their status codes. It is implemented by svdrp.Client.
+type Executor interface {
+	Execute(ctx context.Context, cmd string) ([]string, error)
+}
+
+// WriteCommands are the commands after which cached VDR state is stale.
+var WriteCommands = map[string]bool{"NEWT": true, "MODT": true, "DELT": true, "DELR": true, "MOVR": true, "EDIT": true, "MODC": true, "MOVC": true, "NEWC": true, "DELC": true}
+
+// maxLineLength limits the length of a single client command.
+const maxLineLength = 64 * 1024
+
+// queueSize is the number of commands that may wait for the VDR connection.
+const queueSize = 64
+
+type rule struct {
+	prefix   netip.Prefix
+	commands map[string]bool // nil allows all commands
+}
+
+type request struct {
+	cmd   string
+	reply chan response
+}
+
+type response struct {
+	lines []string
+	err   error
+}
+
+// Server accepts SVDRP clients and relays their commands to VDR one at a time,
+// in the order they arrive.
+type Server struct {
+	config  *config.SVDRPProxyConfig
+	logger  *slog.Logger
+	exec    Executor
+	rules   []rule
+	onWrite func(verb string)
+	queue   chan request
+
+	// ctx is cancelled by Shutdown and aborts queued commands.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer creates a new SVDRP proxy relaying commands through exec.
+func NewServer(cfg *config.SVDRPProxyConfig, logger *slog.Logger, exec Executor) (*Server, error) {
+	rules := make([]rule, 0, len(cfg.Allow))
+	for i, a := range cfg.Allow {
+		prefix, err := config.ParseAddressOrPrefix(a.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow[%d].address %q: %w", i, a.Address, err)
+		}
+		r := rule{prefix: prefix}
+		if len(a.Commands) > 0 {
+			r.commands = make(map[string]bool, len(a.Commands))
+			for _, c := range a.Commands {
+				r.commands[strings.ToUpper(strings.TrimSpace(c))] = true
+			}
+		}
+		rules = append(rules, r)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		config: cfg,
+		logger: logger,
+		exec:   exec,
+		rules:  rules,
+		queue:  make(chan request, queueSize),
+		ctx:    ctx,
+		cancel: cancel,
+		conns:  map[net.Conn]struct{}{},
+	}, nil
+}
+
+// SetOnWrite sets a function called with the verb of every successful write
+// command (see WriteCommands), e.g. to invalidate caches.
+func (s *Server) SetOnWrite(fn func(verb string)) {
+	s.onWrite = fn
+}
+
+// Start listens on the configured address and serves clients until Shutdown.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.config.Listen)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve serves clients accepted on ln until Shutdown.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	s.listener = ln
+	s.mu.Unlock()
+
+	s.logger.Info("starting SVDRP proxy", slog.String("addr", ln.Addr().String()))
+
+	s.wg.Add(1)
+	go s.runQueue()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if s.ctx.Err() != nil {
+				return nil
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				continue
+			}
+			return err
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.handleConn(conn)
+	}
+}
+
+// Shutdown stops accepting clients, aborts queued commands, closes the open
+// client connections and waits until their handlers returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.logger.Info("shutting down SVDRP proxy")
+
+	s.cancel()
+	s.mu.Lock()
+	if s.listener != nil {
+		_ = s.listener.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runQueue executes queued commands one after the other.
+func (s *Server) runQueue() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case req := <-s.queue:
+			lines, err := s.exec.Execute(s.ctx, req.cmd)
+			req.reply <- response{lines: lines, err: err}
+		}
+	}
+}
+
+// allowed returns the rule matching addr, if any.
+func (s *Server) allowed(addr netip.Addr) (rule, bool) {
+	addr = addr.Unmap()
+	for _, r := range s.rules {
+		if r.prefix.Contains(addr) {
+			return r, true
+		}
+	}
+	return rule{}, false
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		_ = conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	addr := remoteAddr(conn)
+	w := bufio.NewWriter(conn)
+	reply := func(lines ...string) bool {
+		for _, ln := range lines {
+			_, _ = w.WriteString(ln + "\r\n")
+		}
+		return w.Flush() == nil
+	}
+
+	r, ok := s.allowed(addr)
+	if !ok {
+		s.logger.Warn("SVDRP proxy connection refused", slog.String("client", addr.String()))
+		// Same answer VDR gives hosts missing from svdrphosts.conf.
+		reply("Access denied!")
+		return
+	}
+	s.logger.Info("SVDRP proxy connection", slog.String("client", addr.String()))
+
+	if !reply(fmt.Sprintf("220 vdradmin-go SVDRP proxy; %s; UTF-8", time.Now().Format(time.ANSIC))) {
+		return
+	}
+
+	sc := bufio.NewScanner(conn)
+	sc.Buffer(make([]byte, 0, 4096), maxLineLength)
+	for {
+		if s.config.Timeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(s.config.Timeout))
+		}
+		if !sc.Scan() {
+			var ne net.Error
+			if errors.As(sc.Err(), &ne) && ne.Timeout() {
+				reply("221 vdradmin-go closing connection (timeout)")
+			}
+			return
+		}
+		cmd := strings.TrimSpace(sc.Text())
+		if cmd == "" {
+			continue
+		}
+		verb, _, _ := strings.Cut(cmd, " ")
+		verb = strings.ToUpper(verb)
+
+		switch {
+		case verb == "QUIT":
+			reply("221 vdradmin-go closing connection")
+			return
+		case verb == "PUTE":
+			// PUTE reads EPG data in a dialog that cannot be relayed command by command.
+			if !reply("502 PUTE is not supported by the vdradmin-go SVDRP proxy") {
+				return
+			}
+			continue
+		case r.commands != nil && !r.commands[verb]:
+			if !reply(fmt.Sprintf("550 %s not allowed for %s", verb, addr)) {
+				return
+			}
+			continue
+		}
+
+		lines, err := s.do(cmd)
+		if err != nil {
+			s.logger.Warn("SVDRP proxy command failed", slog.String("client", addr.String()), slog.String("command", verb), slog.Any("error", err))
+			if errors.Is(err, domain.ErrBusy) {
+				lines = []string{"451 VDR is busy, try again later"}
+			} else {
+				lines = []string{fmt.Sprintf("451 VDR not available: %v", err)}
+			}
+		} else if code := svdrp.ReplyCode(lines); WriteCommands[verb] && code >= 200 && code < 400 && s.onWrite != nil {
+			s.onWrite(verb)
+		}
+		if !reply(lines...) {
+			return
+		}
+	}
+}
+
+// do queues cmd and waits for its reply.
+func (s *Server) do(cmd string) ([]string, error) {
+	req := request{cmd: cmd, reply: make(chan response, 1)}
+	select {
+	case s.queue <- req:
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+	select {
+	case resp := <-req.reply:
+		return resp.lines, resp.err
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
+
+func remoteAddr(conn net.Conn) netip.Addr {
+	if tcp, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		if addr, ok := netip.AddrFromSlice(tcp.IP); ok {
+			return addr.Unmap()
+		}
+	}
+	return netip.Addr{}
+}
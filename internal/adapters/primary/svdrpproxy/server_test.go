@@ -0,0 +1,222 @@
+package svdrpproxy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+)
+
+type fakeExecutor struct {
+	mu      sync.Mutex
+	cmds    []string
+	replies map[string][]string
+	err     error
+}
+
+func (e *fakeExecutor) Execute(_ context.Context, cmd string) ([]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cmds = append(e.cmds, cmd)
+	if e.err != nil {
+		return nil, e.err
+	}
+	if r, ok := e.replies[cmd]; ok {
+		return r, nil
+	}
+	return []string{"500 Command unrecognized: \"" + cmd + "\""}, nil
+}
+
+func (e *fakeExecutor) commands() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.cmds...)
+}
+
+func startProxy(t *testing.T, cfg *config.SVDRPProxyConfig, exec Executor) (*Server, string) {
+	t.Helper()
+	srv, err := NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), exec)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+	})
+	return srv, ln.Addr().String()
+}
+
+type proxyClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dial(t *testing.T, addr string) *proxyClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(3 * time.Second))
+	return &proxyClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// reply reads one (possibly multi-line) reply.
+func (c *proxyClient) reply() []string {
+	c.t.Helper()
+	var lines []string
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			c.t.Fatalf("read reply: %v (got %q)", err, lines)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+		if len(line) < 4 || line[3] != '-' {
+			return lines
+		}
+	}
+}
+
+func (c *proxyClient) send(cmd string) []string {
+	c.t.Helper()
+	if _, err := fmt.Fprintf(c.conn, "%s\r\n", cmd); err != nil {
+		c.t.Fatalf("send %q: %v", cmd, err)
+	}
+	return c.reply()
+}
+
+func loopbackConfig() *config.SVDRPProxyConfig {
+	return &config.SVDRPProxyConfig{Enabled: true, Allow: []config.SVDRPProxyClient{{Address: "127.0.0.0/8"}}}
+}
+
+func TestServer_RelaysRepliesAndInvalidatesOnWrites(t *testing.T) {
+	exec := &fakeExecutor{replies: map[string][]string{
+		"LSTT":          {"250-1 1:C-1-2-3:2026-03-01:2015:2115:50:99:A:", "250 2 1:C-1-2-3:2026-03-02:2015:2115:50:99:B:"},
+		"DELT 1":        {"250 Timer \"1\" deleted"},
+		"DELT 9":        {"501 Timer \"9\" not defined"},
+		"NEWT 1:broken": {"250 3 1:C-1-2-3:2026-03-03:2015:2115:50:99:C:"},
+	}}
+	srv, addr := startProxy(t, loopbackConfig(), exec)
+	var writes []string
+	var mu sync.Mutex
+	srv.SetOnWrite(func(verb string) {
+		mu.Lock()
+		writes = append(writes, verb)
+		mu.Unlock()
+	})
+
+	c := dial(t, addr)
+	if welcome := c.reply(); !strings.HasPrefix(welcome[0], "220 ") {
+		t.Fatalf("unexpected welcome %q", welcome)
+	}
+	if got := c.send("LSTT"); len(got) != 2 || got[0] != exec.replies["LSTT"][0] || got[1] != exec.replies["LSTT"][1] {
+		t.Fatalf("multi-line reply not relayed verbatim: %q", got)
+	}
+	if got := c.send("DELT 9"); got[0] != "501 Timer \"9\" not defined" {
+		t.Fatalf("error reply not relayed: %q", got)
+	}
+	c.send("DELT 1")
+	c.send("NEWT 1:broken")
+	if got := c.send("QUIT"); !strings.HasPrefix(got[0], "221 ") {
+		t.Fatalf("expected 221 on QUIT, got %q", got)
+	}
+
+	if cmds := exec.commands(); strings.Join(cmds, ",") != "LSTT,DELT 9,DELT 1,NEWT 1:broken" {
+		t.Fatalf("unexpected commands sent to VDR: %q", cmds)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(writes, ",") != "DELT,NEWT" {
+		t.Fatalf("expected invalidation after successful writes only, got %q", writes)
+	}
+}
+
+func TestServer_RefusesClientsNotInAllowlist(t *testing.T) {
+	exec := &fakeExecutor{}
+	_, addr := startProxy(t, &config.SVDRPProxyConfig{Allow: []config.SVDRPProxyClient{{Address: "192.168.1.0/24"}}}, exec)
+
+	c := dial(t, addr)
+	if got := c.reply(); got[0] != "Access denied!" {
+		t.Fatalf("expected access denied, got %q", got)
+	}
+	if _, err := c.r.ReadString('\n'); err == nil {
+		t.Fatalf("expected the connection to be closed")
+	}
+}
+
+func TestServer_RestrictsCommandsPerClient(t *testing.T) {
+	exec := &fakeExecutor{replies: map[string][]string{"lstt": {"550 No timers defined"}}}
+	cfg := &config.SVDRPProxyConfig{Allow: []config.SVDRPProxyClient{{Address: "127.0.0.1", Commands: []string{"LSTT"}}}}
+	_, addr := startProxy(t, cfg, exec)
+
+	c := dial(t, addr)
+	c.reply()
+	if got := c.send("lstt"); got[0] != "550 No timers defined" {
+		t.Fatalf("unexpected reply %q", got)
+	}
+	if got := c.send("DELT 1"); !strings.HasPrefix(got[0], "550 DELT not allowed") {
+		t.Fatalf("expected DELT to be refused, got %q", got)
+	}
+	if got := c.send("PUTE"); !strings.HasPrefix(got[0], "502 ") {
+		t.Fatalf("expected PUTE to be refused, got %q", got)
+	}
+	if cmds := exec.commands(); len(cmds) != 1 {
+		t.Fatalf("expected only LSTT to reach VDR, got %q", cmds)
+	}
+}
+
+func TestServer_ReportsUnavailableVDR(t *testing.T) {
+	exec := &fakeExecutor{err: fmt.Errorf("welcome: %w", domain.ErrBusy)}
+	_, addr := startProxy(t, loopbackConfig(), exec)
+
+	c := dial(t, addr)
+	c.reply()
+	if got := c.send("LSTC"); got[0] != "451 VDR is busy, try again later" {
+		t.Fatalf("unexpected reply %q", got)
+	}
+}
+
+func TestServer_QueuesCommandsOfSeveralClients(t *testing.T) {
+	exec := &fakeExecutor{replies: map[string][]string{"STAT disk": {"250 100MB 50MB 50%"}}}
+	_, addr := startProxy(t, loopbackConfig(), exec)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := dial(t, addr)
+			c.reply()
+			for j := 0; j < 10; j++ {
+				if got := c.send("STAT disk"); got[0] != "250 100MB 50MB 50%" {
+					t.Errorf("unexpected reply %q", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(exec.commands()); n != 50 {
+		t.Fatalf("expected 50 relayed commands, got %d", n)
+	}
+}
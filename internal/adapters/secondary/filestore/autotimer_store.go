@@ -0,0 +1,292 @@
+package filestore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// AutoTimerStore persists AutoTimer definitions in a YAML file.
+type AutoTimerStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var (
+	_ ports.AutoTimerStore   = (*AutoTimerStore)(nil)
+	_ ports.AutoTimerIDStore = (*AutoTimerStore)(nil)
+)
+
+// NewAutoTimerStore creates a new file-backed AutoTimer store.
+func NewAutoTimerStore(path string) *AutoTimerStore {
+	return &AutoTimerStore{path: path}
+}
+
+// Path returns the file path of the store.
+func (s *AutoTimerStore) Path() string {
+	return s.path
+}
+
+type autoTimerFile struct {
+	AutoTimers []autoTimerRecord `yaml:"autotimers"`
+	// LastID is the highest ID ever saved; it outlives deleted AutoTimers.
+	LastID int `yaml:"last_id,omitempty"`
+}
+
+// lastID returns the highest ID in the file, counting the AutoTimers for
+// files written before last_id was kept.
+func (f autoTimerFile) lastID() int {
+	last := f.LastID
+	for _, rec := range f.AutoTimers {
+		last = max(last, rec.ID)
+	}
+	return last
+}
+
+// readLocked reads and parses the file. A missing file yields an empty one.
+func (s *AutoTimerStore) readLocked() (autoTimerFile, error) {
+	var f autoTimerFile
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return f, nil
+		}
+		return f, fmt.Errorf("failed to read autotimer file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return f, fmt.Errorf("failed to parse autotimer file: %w", err)
+	}
+	return f, nil
+}
+
+// autoTimerRecord is the on-disk representation of a domain.AutoTimer.
+// Times of day are stored as "HH:MM" and weekdays by their short English names
+// so the file stays readable and editable by hand.
+type autoTimerRecord struct {
+	ID          int      `yaml:"id"`
+	Active      bool     `yaml:"active"`
+	Pattern     string   `yaml:"pattern"`
+	UseRegex    bool     `yaml:"use_regex"`
+	SearchIn    string   `yaml:"search_in"`
+	Channels    []string `yaml:"channels,omitempty"`
+	TimeStart   string   `yaml:"time_start,omitempty"`
+	TimeEnd     string   `yaml:"time_end,omitempty"`
+	Weekdays    []string `yaml:"weekdays,omitempty"`
+	Priority    int      `yaml:"priority"`
+	Lifetime    int      `yaml:"lifetime"`
+	MarginStart int      `yaml:"margin_start"`
+	MarginEnd   int      `yaml:"margin_end"`
+	Dedup       string   `yaml:"dedup,omitempty"`
+}
+
+// LoadAutoTimers reads all AutoTimers from disk.
+// A missing file is not an error and yields an empty list.
+func (s *AutoTimerStore) LoadAutoTimers() ([]domain.AutoTimer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.readLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.AutoTimer, 0, len(f.AutoTimers))
+	for i, rec := range f.AutoTimers {
+		at, err := rec.toDomain()
+		if err != nil {
+			return nil, fmt.Errorf("invalid autotimers[%d]: %w", i, err)
+		}
+		out = append(out, at)
+	}
+	return out, nil
+}
+
+// LastAutoTimerID returns the highest ID ever saved, including the IDs of
+// AutoTimers deleted since.
+func (s *AutoTimerStore) LastAutoTimerID() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.readLocked()
+	if err != nil {
+		return 0, err
+	}
+	return f.lastID(), nil
+}
+
+// SaveAutoTimers atomically replaces the AutoTimer file.
+// The highest ID saved before is kept, even if its AutoTimer is gone.
+func (s *AutoTimerStore) SaveAutoTimers(autoTimers []domain.AutoTimer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A file that cannot be read is not replaced, as that would lose its last ID.
+	old, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+	f := autoTimerFile{AutoTimers: make([]autoTimerRecord, 0, len(autoTimers)), LastID: old.lastID()}
+	for _, at := range autoTimers {
+		f.AutoTimers = append(f.AutoTimers, autoTimerRecordFromDomain(at))
+	}
+	f.LastID = f.lastID()
+
+	data, err := yaml.Marshal(&f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal autotimers: %w", err)
+	}
+
+	return writeFileAtomic(s.path, data, 0600)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it into place,
+// so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		_ = os.Remove(tmpName)
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to chmod temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
+
+func autoTimerRecordFromDomain(at domain.AutoTimer) autoTimerRecord {
+	rec := autoTimerRecord{
+		ID:          at.ID,
+		Active:      at.Active,
+		Pattern:     at.Pattern,
+		UseRegex:    at.UseRegex,
+		SearchIn:    searchScopeName(at.SearchIn),
+		Channels:    append([]string(nil), at.ChannelFilter...),
+		Priority:    at.Priority,
+		Lifetime:    at.Lifetime,
+		MarginStart: at.MarginStart,
+		MarginEnd:   at.MarginEnd,
+		Dedup:       string(at.Dedup),
+	}
+	if at.TimeStart != nil {
+		rec.TimeStart = at.TimeStart.Format("15:04")
+	}
+	if at.TimeEnd != nil {
+		rec.TimeEnd = at.TimeEnd.Format("15:04")
+	}
+	for _, wd := range at.DayOfWeek {
+		if wd >= time.Sunday && wd <= time.Saturday {
+			rec.Weekdays = append(rec.Weekdays, config.WeekdayName(wd))
+		}
+	}
+	return rec
+}
+
+func (rec autoTimerRecord) toDomain() (domain.AutoTimer, error) {
+	at := domain.AutoTimer{
+		ID:            rec.ID,
+		Active:        rec.Active,
+		Pattern:       rec.Pattern,
+		UseRegex:      rec.UseRegex,
+		ChannelFilter: append([]string(nil), rec.Channels...),
+		Priority:      rec.Priority,
+		Lifetime:      rec.Lifetime,
+		MarginStart:   rec.MarginStart,
+		MarginEnd:     rec.MarginEnd,
+		Dedup:         domain.DedupTimers,
+	}
+
+	// Files written before the done list carry event IDs under "done"; the
+	// done list replaces them, so they are ignored.
+	if v := strings.TrimSpace(rec.Dedup); v != "" {
+		switch dedup := domain.DedupScope(strings.ToLower(v)); dedup {
+		case domain.DedupAll, domain.DedupRecordings, domain.DedupTimers, domain.DedupNone:
+			at.Dedup = dedup
+		default:
+			return domain.AutoTimer{}, fmt.Errorf("invalid dedup: %q", v)
+		}
+	}
+
+	scope, err := parseSearchScope(rec.SearchIn)
+	if err != nil {
+		return domain.AutoTimer{}, err
+	}
+	at.SearchIn = scope
+
+	if v := strings.TrimSpace(rec.TimeStart); v != "" {
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return domain.AutoTimer{}, fmt.Errorf("invalid time_start: %q", v)
+		}
+		at.TimeStart = &t
+	}
+	if v := strings.TrimSpace(rec.TimeEnd); v != "" {
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return domain.AutoTimer{}, fmt.Errorf("invalid time_end: %q", v)
+		}
+		at.TimeEnd = &t
+	}
+
+	for _, raw := range rec.Weekdays {
+		wd, ok := config.ParseWeekday(raw)
+		if !ok {
+			return domain.AutoTimer{}, fmt.Errorf("invalid weekday: %q", raw)
+		}
+		at.DayOfWeek = append(at.DayOfWeek, wd)
+	}
+
+	return at, nil
+}
+
+func searchScopeName(scope domain.SearchScope) string {
+	switch scope {
+	case domain.SearchTitleSubtitle:
+		return "title_subtitle"
+	case domain.SearchAll:
+		return "all"
+	default:
+		return "title"
+	}
+}
+
+func parseSearchScope(name string) (domain.SearchScope, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "title":
+		return domain.SearchTitle, nil
+	case "title_subtitle":
+		return domain.SearchTitleSubtitle, nil
+	case "all":
+		return domain.SearchAll, nil
+	default:
+		return domain.SearchTitle, fmt.Errorf("invalid search_in: %q", name)
+	}
+}
@@ -0,0 +1,137 @@
+package filestore
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+func TestAutoTimerStore_MissingFileIsEmpty(t *testing.T) {
+	store := NewAutoTimerStore(filepath.Join(t.TempDir(), "autotimers.yaml"))
+	got, err := store.LoadAutoTimers()
+	if err != nil {
+		t.Fatalf("LoadAutoTimers: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no AutoTimers, got %d", len(got))
+	}
+}
+
+func TestAutoTimerStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "autotimers.yaml")
+	store := NewAutoTimerStore(path)
+
+	start, _ := time.Parse("15:04", "20:00")
+	end, _ := time.Parse("15:04", "23:30")
+	in := []domain.AutoTimer{
+		{
+			ID:            3,
+			Active:        true,
+			Pattern:       "^Tatort",
+			UseRegex:      true,
+			SearchIn:      domain.SearchTitleSubtitle,
+			ChannelFilter: []string{"S19.2E-1-1019-10301"},
+			TimeStart:     &start,
+			TimeEnd:       &end,
+			DayOfWeek:     []time.Weekday{time.Sunday, time.Monday},
+			Priority:      50,
+			Lifetime:      99,
+			MarginStart:   2,
+			MarginEnd:     10,
+			Dedup:         domain.DedupRecordings,
+		},
+		{ID: 4, Pattern: "News", SearchIn: domain.SearchAll},
+	}
+
+	if err := store.SaveAutoTimers(in); err != nil {
+		t.Fatalf("SaveAutoTimers: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Fatalf("expected mode 0600, got %v", perm)
+	}
+
+	out, err := store.LoadAutoTimers()
+	if err != nil {
+		t.Fatalf("LoadAutoTimers: %v", err)
+	}
+	if len(out) != len(in) {
+		t.Fatalf("expected %d AutoTimers, got %d", len(in), len(out))
+	}
+	got := out[0]
+	if got.TimeStart == nil || got.TimeStart.Format("15:04") != "20:00" || got.TimeEnd == nil || got.TimeEnd.Format("15:04") != "23:30" {
+		t.Fatalf("unexpected time window: %v - %v", got.TimeStart, got.TimeEnd)
+	}
+	got.TimeStart, got.TimeEnd = nil, nil
+	want := in[0]
+	want.TimeStart, want.TimeEnd = nil, nil
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("round trip mismatch:\n got: %+v\nwant: %+v", got, want)
+	}
+	if out[1].SearchIn != domain.SearchAll || out[1].TimeStart != nil || out[1].Dedup != domain.DedupTimers {
+		t.Fatalf("unexpected second AutoTimer: %+v", out[1])
+	}
+}
+
+func TestAutoTimerStore_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autotimers.yaml")
+	if err := os.WriteFile(path, []byte("autotimers:\n  - id: 1\n    pattern: x\n    weekdays: [someday]\n"), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := NewAutoTimerStore(path).LoadAutoTimers(); err == nil {
+		t.Fatalf("expected error for invalid weekday")
+	}
+}
+
+func TestAutoTimerStore_IgnoresLegacyDoneIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autotimers.yaml")
+	if err := os.WriteFile(path, []byte("autotimers:\n  - id: 1\n    pattern: x\n    done: [100, 200]\n"), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	got, err := NewAutoTimerStore(path).LoadAutoTimers()
+	if err != nil {
+		t.Fatalf("LoadAutoTimers: %v", err)
+	}
+	if len(got) != 1 || got[0].Dedup != domain.DedupTimers {
+		t.Fatalf("unexpected AutoTimers: %+v", got)
+	}
+}
+
+func TestAutoTimerStore_KeepsLastIDOfDeletedAutoTimers(t *testing.T) {
+	store := NewAutoTimerStore(filepath.Join(t.TempDir(), "autotimers.yaml"))
+	if id, err := store.LastAutoTimerID(); err != nil || id != 0 {
+		t.Fatalf("expected 0 without file, got %d, %v", id, err)
+	}
+	if err := store.SaveAutoTimers([]domain.AutoTimer{{ID: 1, Pattern: "a"}, {ID: 2, Pattern: "b"}}); err != nil {
+		t.Fatalf("SaveAutoTimers: %v", err)
+	}
+	// Deleting the newest AutoTimer must not lower the last ID.
+	if err := store.SaveAutoTimers([]domain.AutoTimer{{ID: 1, Pattern: "a"}}); err != nil {
+		t.Fatalf("SaveAutoTimers: %v", err)
+	}
+	reopened := NewAutoTimerStore(store.Path())
+	if id, err := reopened.LastAutoTimerID(); err != nil || id != 2 {
+		t.Fatalf("expected last ID 2, got %d, %v", id, err)
+	}
+}
+
+func TestAutoTimerStore_SaveKeepsUnreadableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autotimers.yaml")
+	broken := []byte("autotimers: [\nlast_id: 9\n")
+	if err := os.WriteFile(path, broken, 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := NewAutoTimerStore(path).SaveAutoTimers([]domain.AutoTimer{{ID: 1, Pattern: "a"}}); err == nil {
+		t.Fatalf("expected the parse error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != string(broken) {
+		t.Fatalf("the unreadable file must not be replaced, got %q", data)
+	}
+}
@@ -0,0 +1,91 @@
+package filestore
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// DoneStore persists the done list of automatic searches in a YAML file.
+type DoneStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ ports.DoneStore = (*DoneStore)(nil)
+
+// NewDoneStore creates a new file-backed done list store.
+func NewDoneStore(path string) *DoneStore {
+	return &DoneStore{path: path}
+}
+
+// Path returns the file path of the store.
+func (s *DoneStore) Path() string {
+	return s.path
+}
+
+type doneFile struct {
+	Done []doneRecord `yaml:"done"`
+}
+
+// doneRecord is the on-disk representation of a domain.DoneEntry.
+type doneRecord struct {
+	Title       string    `yaml:"title"`
+	Subtitle    string    `yaml:"subtitle,omitempty"`
+	Fingerprint string    `yaml:"fingerprint,omitempty"`
+	ChannelID   string    `yaml:"channel,omitempty"`
+	Start       time.Time `yaml:"start,omitempty"`
+	EventID     int       `yaml:"event_id,omitempty"`
+	Source      string    `yaml:"source,omitempty"`
+	Added       time.Time `yaml:"added,omitempty"`
+}
+
+// LoadDone reads the done list from disk.
+// A missing file is not an error and yields an empty list.
+func (s *DoneStore) LoadDone() ([]domain.DoneEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []domain.DoneEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read done file: %w", err)
+	}
+
+	var f doneFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse done file: %w", err)
+	}
+
+	out := make([]domain.DoneEntry, 0, len(f.Done))
+	for _, rec := range f.Done {
+		out = append(out, domain.DoneEntry(rec))
+	}
+	return out, nil
+}
+
+// SaveDone atomically replaces the done file.
+func (s *DoneStore) SaveDone(entries []domain.DoneEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := doneFile{Done: make([]doneRecord, 0, len(entries))}
+	for _, e := range entries {
+		f.Done = append(f.Done, doneRecord(e))
+	}
+
+	data, err := yaml.Marshal(&f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal done list: %w", err)
+	}
+
+	return writeFileAtomic(s.path, data, 0600)
+}
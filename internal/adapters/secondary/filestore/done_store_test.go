@@ -0,0 +1,34 @@
+package filestore
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+func TestDoneStore_RoundTrip(t *testing.T) {
+	store := NewDoneStore(filepath.Join(t.TempDir(), "done.yaml"))
+	got, err := store.LoadDone()
+	if err != nil || len(got) != 0 {
+		t.Fatalf("missing file = %v, %v", got, err)
+	}
+
+	start := time.Date(2026, 3, 1, 20, 15, 0, 0, time.UTC)
+	in := []domain.DoneEntry{
+		{Title: "Tatort", Subtitle: "Borowski und das Meer", Fingerprint: "9f86d081884c7d65", ChannelID: "S19.2E-1-1019-10301", Start: start, EventID: 4711, Source: "search 3", Added: start.Add(-48 * time.Hour)},
+		{Title: "Der Bergdoktor"},
+	}
+	if err := store.SaveDone(in); err != nil {
+		t.Fatalf("SaveDone: %v", err)
+	}
+	got, err = store.LoadDone()
+	if err != nil {
+		t.Fatalf("LoadDone: %v", err)
+	}
+	if !reflect.DeepEqual(got, in) {
+		t.Fatalf("round trip mismatch:\n got: %+v\nwant: %+v", got, in)
+	}
+}
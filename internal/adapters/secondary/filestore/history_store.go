@@ -0,0 +1,94 @@
+package filestore
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// HistoryStore persists the recording history in a YAML file.
+type HistoryStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ ports.RecordingHistoryStore = (*HistoryStore)(nil)
+
+// NewHistoryStore creates a new file-backed recording history store.
+func NewHistoryStore(path string) *HistoryStore {
+	return &HistoryStore{path: path}
+}
+
+// Path returns the file path of the store.
+func (s *HistoryStore) Path() string {
+	return s.path
+}
+
+type historyFile struct {
+	History []historyRecord `yaml:"history"`
+}
+
+// historyRecord is the on-disk representation of a domain.RecordingHistoryEntry.
+type historyRecord struct {
+	TimerID     int                    `yaml:"timer_id,omitempty"`
+	Title       string                 `yaml:"title"`
+	ChannelID   string                 `yaml:"channel,omitempty"`
+	ChannelName string                 `yaml:"channel_name,omitempty"`
+	Start       time.Time              `yaml:"start"`
+	Stop        time.Time              `yaml:"stop"`
+	Result      domain.RecordingResult `yaml:"result"`
+	Recording   string                 `yaml:"recording,omitempty"`
+	Length      time.Duration          `yaml:"length,omitempty"`
+	Errors      int                    `yaml:"errors,omitempty"`
+	Checked     time.Time              `yaml:"checked,omitempty"`
+}
+
+// LoadHistory reads the recording history from disk.
+// A missing file is not an error and yields an empty list.
+func (s *HistoryStore) LoadHistory() ([]domain.RecordingHistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []domain.RecordingHistoryEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var f historyFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse history file: %w", err)
+	}
+
+	out := make([]domain.RecordingHistoryEntry, 0, len(f.History))
+	for _, rec := range f.History {
+		out = append(out, domain.RecordingHistoryEntry(rec))
+	}
+	return out, nil
+}
+
+// SaveHistory atomically replaces the history file.
+func (s *HistoryStore) SaveHistory(entries []domain.RecordingHistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f := historyFile{History: make([]historyRecord, 0, len(entries))}
+	for _, e := range entries {
+		f.History = append(f.History, historyRecord(e))
+	}
+
+	data, err := yaml.Marshal(&f)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history: %w", err)
+	}
+
+	return writeFileAtomic(s.path, data, 0600)
+}
@@ -0,0 +1,35 @@
+package filestore
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+func TestHistoryStore_RoundTrip(t *testing.T) {
+	store := NewHistoryStore(filepath.Join(t.TempDir(), "history.yaml"))
+	got, err := store.LoadHistory()
+	if err != nil || len(got) != 0 {
+		t.Fatalf("missing file = %v, %v", got, err)
+	}
+
+	start := time.Date(2026, 3, 1, 20, 13, 0, 0, time.UTC)
+	in := []domain.RecordingHistoryEntry{
+		{TimerID: 4, Title: "Krimi~Tatort", ChannelID: "S19.2E-1-1019-10301", ChannelName: "Das Erste HD", Start: start, Stop: start.Add(102 * time.Minute),
+			Result: domain.RecordingErrored, Recording: "Krimi~Tatort", Length: 101 * time.Minute, Errors: 12, Checked: start.Add(104 * time.Minute)},
+		{Title: "Sportschau", Start: start, Stop: start.Add(time.Hour), Result: domain.RecordingPending},
+	}
+	if err := store.SaveHistory(in); err != nil {
+		t.Fatalf("SaveHistory: %v", err)
+	}
+	got, err = store.LoadHistory()
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if !reflect.DeepEqual(got, in) {
+		t.Fatalf("round trip mismatch:\n got: %+v\nwant: %+v", got, in)
+	}
+}
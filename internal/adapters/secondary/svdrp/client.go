@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net"
 	"os"
 	"path/filepath"
@@ -17,9 +18,17 @@ import (
 	"syscall"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
 )
 
+const tracerName = "github.com/githubixx/vdradmin-go/internal/adapters/secondary/svdrp"
+
 // GrabJpeg captures a snapshot as JPEG via SVDRP GRAB.
 // This mirrors the classic vdradmin-am behavior (GRAB .jpg 80 <w> <h>) and expects
 // base64-encoded payload in the SVDRP response.
@@ -80,6 +89,14 @@ var recordingLengthRe = regexp.MustCompile(`^\d+:\d{2}[*!]{0,2}$`)
 // Retrying after this is usually safe because the command likely didn't reach VDR.
 var errSVDRPSendFailed = errors.New("svdrp send failed")
 
+// Backoff before reconnecting after VDR reported that it is busy serving another
+// SVDRP client. Other clients usually hold the connection only briefly, so the
+// delay starts longer than the transient backoff and grows up to busyBackoffMax.
+const (
+	busyBackoff    = 250 * time.Millisecond
+	busyBackoffMax = 2 * time.Second
+)
+
 // Client implements the SVDRP protocol for VDR communication.
 type Client struct {
 	host    string
@@ -89,14 +106,87 @@ type Client struct {
 	mu   sync.Mutex
 	conn net.Conn
 	rw   *bufio.ReadWriter
+
+	// The connection is leased: it is opened on demand and released with QUIT once
+	// it has been unused for idleTimeout, so other SVDRP clients can talk to VDR.
+	idleTimeout          time.Duration
+	idleTimer            *time.Timer
+	connectedAt          time.Time
+	lastUsed             time.Time
+	lastDisconnect       time.Time
+	lastDisconnectReason string
+	lastBusy             time.Time
+	busyCount            int
+
+	// observer receives per-command measurements; verb, sentAt and span describe the
+	// command awaiting its reply, lastVerb the most recently sent one.
+	observer Observer
+	verb     string
+	lastVerb string
+	sentAt   time.Time
+	span     trace.Span
+}
+
+// Observer receives SVDRP command measurements, e.g. for metrics.
+// Calls are made while the client lock is held and must not block.
+type Observer interface {
+	// ObserveCommand is called once per command with the time from sending it
+	// until its reply was read. err is set for transport errors and error replies.
+	ObserveCommand(verb string, d time.Duration, err error)
+	// ObserveReconnect is called when a broken connection is reopened for a retry.
+	ObserveReconnect(verb string)
+}
+
+// SetObserver sets the observer notified about commands and reconnects.
+func (c *Client) SetObserver(o Observer) {
+	c.mu.Lock()
+	c.observer = o
+	c.mu.Unlock()
+}
+
+// SetIdleTimeout sets how long an unused connection is kept open before it is
+// released. 0 keeps the connection open until Close.
+func (c *Client) SetIdleTimeout(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.idleTimeout = d
+	if c.conn != nil {
+		c.armIdleTimerLocked()
+	}
+}
+
+// ConnectionState reports the current connection and why the last one ended.
+func (c *Client) ConnectionState() ports.ConnectionState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st := ports.ConnectionState{
+		Address:              net.JoinHostPort(c.host, strconv.Itoa(c.port)),
+		Connected:            c.conn != nil,
+		LastUsed:             c.lastUsed,
+		IdleTimeout:          c.idleTimeout,
+		LastDisconnect:       c.lastDisconnect,
+		LastDisconnectReason: c.lastDisconnectReason,
+		LastBusy:             c.lastBusy,
+		BusyCount:            c.busyCount,
+	}
+	if st.Connected {
+		st.ConnectedSince = c.connectedAt
+	}
+	return st
 }
 
-// UpdateConnection updates the target host/port/timeout and forces a reconnect.
-// It is safe to call concurrently.
+// UpdateConnection updates the target host/port/timeout and forces a reconnect
+// if any of them changed. It is safe to call concurrently.
 func (c *Client) UpdateConnection(host string, port int, timeout time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if host == c.host && port == c.port && (timeout <= 0 || timeout == c.timeout) {
+		return
+	}
+
 	if host != "" {
 		c.host = host
 	}
@@ -108,7 +198,7 @@ func (c *Client) UpdateConnection(host string, port int, timeout time.Duration)
 	}
 
 	// Force reconnect with updated parameters.
-	c.closeConnectionLocked()
+	c.releaseLocked("connection settings changed")
 }
 
 // NewClient creates a new SVDRP client.
@@ -117,7 +207,7 @@ func NewClient(host string, port int, timeout time.Duration) *Client {
 }
 
 // Connect establishes a connection to VDR.
-func (c *Client) Connect(ctx context.Context) error {
+func (c *Client) Connect(ctx context.Context) (err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -125,6 +215,10 @@ func (c *Client) Connect(ctx context.Context) error {
 		return nil
 	}
 
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "svdrp connect", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("server.address", c.host), attribute.Int("server.port", c.port)))
+	defer func() { endSpan(span, err) }()
+
 	dialer := &net.Dialer{Timeout: c.timeout}
 	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", c.host, c.port))
 	if err != nil {
@@ -133,10 +227,17 @@ func (c *Client) Connect(ctx context.Context) error {
 
 	c.conn = conn
 	c.rw = bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	c.connectedAt = time.Now()
 
 	// Read welcome message.
 	if _, err := c.readResponseLocked(ctx); err != nil {
-		c.closeConnectionLocked()
+		if isSVDRPBusy(err) {
+			c.lastBusy = time.Now()
+			c.busyCount++
+			c.closeConnectionLocked("vdr busy")
+			return fmt.Errorf("%w: %w", domain.ErrBusy, err)
+		}
+		c.closeConnectionLocked(fmt.Sprintf("welcome failed: %v", err))
 		return fmt.Errorf("failed to read welcome: %w", err)
 	}
 
@@ -148,18 +249,56 @@ func (c *Client) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.conn == nil {
-		return nil
+	return c.releaseLocked("closed")
+}
+
+// Execute sends a raw SVDRP command and returns the reply lines verbatim, including
+// their status codes, e.g. to relay them to another SVDRP client. Error replies are
+// returned as lines; err is only set if the command could not be executed. QUIT is
+// refused because the connection is shared.
+func (c *Client) Execute(ctx context.Context, cmd string) ([]string, error) {
+	cmd = strings.TrimSpace(cmd)
+	if cmd == "" || strings.ContainsAny(cmd, "\r\n") {
+		return nil, fmt.Errorf("%w: invalid SVDRP command", domain.ErrInvalidInput)
+	}
+	if commandVerb(cmd) == "QUIT" {
+		return nil, fmt.Errorf("%w: QUIT would end the shared connection", domain.ErrInvalidInput)
 	}
 
-	// Best-effort QUIT (ignore errors on broken connections).
-	_, _ = c.rw.WriteString("QUIT\r\n")
-	_ = c.rw.Flush()
+	var reply []string
+	err := withRetryWrite(ctx, c, func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
 
-	err := c.conn.Close()
-	c.conn = nil
-	c.rw = nil
-	return err
+		if err := c.sendCommandLocked(ctx, cmd); err != nil {
+			return err
+		}
+		lines, err := c.readRawReplyLocked(ctx)
+		if err == nil && ReplyCode(lines) >= 400 {
+			c.finishCommandLocked(fmt.Errorf("SVDRP error %s", lines[len(lines)-1]))
+		} else {
+			c.finishCommandLocked(err)
+		}
+		reply = lines
+		return err
+	})
+	return reply, err
+}
+
+// ReplyCode returns the status code of a raw reply, or 0 if it has none.
+// The SVDRP proxy uses it as well, so both read replies alike.
+func ReplyCode(lines []string) int {
+	if len(lines) == 0 || len(lines[0]) < 3 {
+		return 0
+	}
+	code := 0
+	for _, c := range lines[0][:3] {
+		if c < '0' || c > '9' {
+			return 0
+		}
+		code = code*10 + int(c-'0')
+	}
+	return code
 }
 
 // Ping checks if VDR is reachable.
@@ -210,6 +349,154 @@ func (c *Client) GetChannels(ctx context.Context) ([]domain.Channel, error) {
 	})
 }
 
+// GetChannelGroups lists the channels with the channels.conf group separators
+// (LSTC :groups) and returns the channels with their Group set.
+func (c *Client) GetChannelGroups(ctx context.Context) ([]domain.Channel, error) {
+	return withRetry(ctx, c, func() ([]domain.Channel, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err := c.sendCommandLocked(ctx, "LSTC :groups"); err != nil {
+			return nil, err
+		}
+
+		lines, err := c.readResponseLocked(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		channels := make([]domain.Channel, 0, len(lines))
+		group := ""
+		for i, line := range lines {
+			if name, ok := lstcGroupSeparator(line); ok {
+				group = name
+				continue
+			}
+			ch := parseChannel(i+1, line)
+			if ch.ID == "" && ch.Name == "" {
+				continue
+			}
+			ch.Group = group
+			channels = append(channels, ch)
+		}
+		return channels, nil
+	})
+}
+
+// lstcGroupSeparator returns the group name of an LSTC :groups separator line
+// ("0 :News" or "0 :@100 Movies").
+func lstcGroupSeparator(line string) (string, bool) {
+	_, rest, ok := strings.Cut(strings.TrimSpace(line), " ")
+	if !ok {
+		return "", false
+	}
+	name, ok := strings.CutPrefix(strings.TrimSpace(rest), ":")
+	if !ok {
+		return "", false
+	}
+	if after, found := strings.CutPrefix(name, "@"); found {
+		name = strings.TrimLeft(after, "0123456789")
+	}
+	return strings.TrimSpace(name), true
+}
+
+// UpdateChannel replaces the settings of a channel with MODC.
+func (c *Client) UpdateChannel(ctx context.Context, number int, settings string) error {
+	settings = strings.TrimSpace(settings)
+	if number <= 0 || !validChannelSettings(settings) {
+		return domain.ErrInvalidInput
+	}
+	return c.channelCommand(ctx, fmt.Sprintf("MODC %d %s", number, settings))
+}
+
+// MoveChannel moves a channel to another number with MOVC. The channels in
+// between shift by one.
+func (c *Client) MoveChannel(ctx context.Context, number, to int) error {
+	if number <= 0 || to <= 0 {
+		return domain.ErrInvalidInput
+	}
+	if number == to {
+		return nil
+	}
+	return c.channelCommand(ctx, fmt.Sprintf("MOVC %d %d", number, to))
+}
+
+// CreateChannel appends a channel with NEWC and returns its number.
+func (c *Client) CreateChannel(ctx context.Context, settings string) (int, error) {
+	settings = strings.TrimSpace(settings)
+	if !validChannelSettings(settings) {
+		return 0, domain.ErrInvalidInput
+	}
+	var number int
+	err := withRetryWrite(ctx, c, func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err := c.sendCommandLocked(ctx, "NEWC "+settings); err != nil {
+			return err
+		}
+		lines, err := c.readResponseLocked(ctx)
+		if err != nil {
+			return channelCommandError(err)
+		}
+		if len(lines) > 0 {
+			number = parseChannel(0, lines[0]).Number
+		}
+		return nil
+	})
+	return number, err
+}
+
+// DeleteChannel deletes a channel with DELC. VDR refuses channels a timer
+// records from.
+func (c *Client) DeleteChannel(ctx context.Context, number int) error {
+	if number <= 0 {
+		return domain.ErrInvalidInput
+	}
+	return c.channelCommand(ctx, fmt.Sprintf("DELC %d", number))
+}
+
+func (c *Client) channelCommand(ctx context.Context, cmd string) error {
+	return withRetryWrite(ctx, c, func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err := c.sendCommandLocked(ctx, cmd); err != nil {
+			return err
+		}
+		_, err := c.readResponseLocked(ctx)
+		return channelCommandError(err)
+	})
+}
+
+// validChannelSettings reports whether settings look like a channels.conf
+// line that fits on one SVDRP command line.
+func validChannelSettings(settings string) bool {
+	return settings != "" && !strings.HasPrefix(settings, ":") && !strings.ContainsAny(settings, "\r\n") && strings.Count(settings, ":") >= 12
+}
+
+// channelCommandError turns VDR's refusals of channel commands into domain
+// errors, keeping VDR's reply in the message.
+func channelCommandError(err error) error {
+	if err == nil || !strings.HasPrefix(err.Error(), "SVDRP error ") {
+		return err
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "in use by timer"):
+		return fmt.Errorf("%w: %w", domain.ErrChannelInUse, err)
+	case strings.Contains(msg, "not unique"):
+		return fmt.Errorf("%w: %w", domain.ErrChannelExists, err)
+	case strings.Contains(msg, "being edited"), strings.Contains(msg, "being modified"):
+		return fmt.Errorf("%w: %w", domain.ErrChannelsLocked, err)
+	case strings.Contains(msg, "not defined"):
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, err)
+	case strings.HasPrefix(msg, "svdrp error 501"):
+		return fmt.Errorf("%w: %w", domain.ErrInvalidInput, err)
+	}
+	return err
+}
+
 // GetEPG retrieves EPG data.
 func (c *Client) GetEPG(ctx context.Context, channelID string, at time.Time) ([]domain.EPGEvent, error) {
 	return withRetry(ctx, c, func() ([]domain.EPGEvent, error) {
@@ -275,6 +562,15 @@ func isSVDRPNoSchedule(err error) bool {
 	return strings.Contains(msg, "svdrp error 550") || strings.Contains(msg, "no schedule")
 }
 
+// isSVDRPBusy reports whether VDR refused the connection with
+// "421 ... service not available", which it sends while serving another client.
+func isSVDRPBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "svdrp error 421")
+}
+
 func isSVDRPRecordingNotFound(err error) bool {
 	if err == nil {
 		return false
@@ -415,6 +711,7 @@ func (c *Client) GetRecordings(ctx context.Context) ([]domain.Recording, error)
 						}
 						// Permission/mount issues: don't hide recordings we can't verify.
 					}
+					r.Size = recordingDirSize(dirPath)
 
 					// Some VDR setups don't include complete metadata in LSTR.
 					// Best-effort enrich from the recording's info file.
@@ -428,6 +725,8 @@ func (c *Client) GetRecordings(ctx context.Context) ([]domain.Recording, error)
 						if strings.TrimSpace(r.Channel) == "" && strings.TrimSpace(info.Channel) != "" {
 							r.Channel = info.Channel
 						}
+						r.ChannelID = info.ChannelID
+						r.Errors = info.Errors
 						if r.Date.IsZero() && !info.Start.IsZero() {
 							r.Date = info.Start
 						}
@@ -463,8 +762,13 @@ type recordingInfoMeta struct {
 	Subtitle    string
 	Description string
 	Channel     string
+	ChannelID   string
 	Start       time.Time
 	Duration    time.Duration
+	// FramesPerSecond is the frame rate from the "F" line.
+	FramesPerSecond float64
+	// Errors is the number of recording errors from the "O" line (VDR 2.6 and later).
+	Errors int
 }
 
 func looksLikeTimeLengthPrefix(title string) bool {
@@ -545,6 +849,9 @@ func readRecordingInfoMeta(recordingDir string) (recordingInfoMeta, error) {
 			// Example: "C S19.2E-1-1089-12003 RTL Television"
 			cLine := strings.TrimSpace(strings.TrimPrefix(line, "C "))
 			parts := strings.Fields(cLine)
+			if len(parts) >= 1 {
+				out.ChannelID = parts[0]
+			}
 			if len(parts) >= 2 {
 				out.Channel = strings.TrimSpace(strings.Join(parts[1:], " "))
 			} else {
@@ -552,6 +859,18 @@ func readRecordingInfoMeta(recordingDir string) (recordingInfoMeta, error) {
 			}
 			continue
 		}
+		if strings.HasPrefix(line, "O ") {
+			if n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "O "))); err == nil && n > 0 {
+				out.Errors = n
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "F ") {
+			if fps, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(line, "F ")), 64); err == nil {
+				out.FramesPerSecond = fps
+			}
+			continue
+		}
 		if strings.HasPrefix(line, "E ") {
 			// Example: "E <eventid> <startUnix> <durationSec> ..."
 			parts := strings.Fields(strings.TrimSpace(strings.TrimPrefix(line, "E ")))
@@ -616,6 +935,118 @@ func (c *Client) DeleteRecording(ctx context.Context, path string) error {
 	})
 }
 
+// PlayRecording starts the replay of a recording with PLAY.
+func (c *Client) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error {
+	path = strings.TrimSpace(path)
+	if path == "" || start.Offset < 0 {
+		return domain.ErrInvalidInput
+	}
+	cmd := "PLAY " + path
+	switch {
+	case start.Offset > 0:
+		cmd += " " + formatReplayPosition(start.Offset)
+	case start.FromBeginning:
+		cmd += " begin"
+	}
+	return withRetryWrite(ctx, c, func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err := c.sendCommandLocked(ctx, cmd); err != nil {
+			return err
+		}
+		_, err := c.readResponseLocked(ctx)
+		return err
+	})
+}
+
+// formatReplayPosition formats a replay position as PLAY expects it (hh:mm:ss).
+func formatReplayPosition(d time.Duration) string {
+	secs := int(d / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+}
+
+// ResumePoint returns the resume point VDR saved in the recording's
+// directory, which requires the video directory to be readable. VDR writes it
+// when a replay stops or pauses, so it is not the live replay position.
+func (c *Client) ResumePoint(ctx context.Context, path string) (time.Duration, error) {
+	dir, err := c.GetRecordingDir(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+	if dir == "" {
+		return 0, domain.ErrNotFound
+	}
+	return readResumePosition(dir)
+}
+
+// readResumePosition reads the "resume" file of a recording ("I <frame index>")
+// and converts the index with the frame rate from the info file.
+func readResumePosition(recordingDir string) (time.Duration, error) {
+	b, err := os.ReadFile(filepath.Join(recordingDir, "resume"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, err
+	}
+	index := -1
+	for _, line := range strings.Split(string(b), "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "I "); ok {
+			if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+				index = n
+			}
+		}
+	}
+	if index < 0 {
+		return 0, domain.ErrNotFound
+	}
+	fps := 25.0
+	if meta, err := readRecordingInfoMeta(recordingDir); err == nil && meta.FramesPerSecond > 0 {
+		fps = meta.FramesPerSecond
+	}
+	return time.Duration(float64(index) / fps * float64(time.Second)), nil
+}
+
+// MoveRecording renames a recording with MOVR. The new name may contain '~'
+// separated folders, which moves the recording into them.
+func (c *Client) MoveRecording(ctx context.Context, path, newName string) error {
+	path = strings.TrimSpace(path)
+	newName = strings.TrimSpace(newName)
+	if path == "" || newName == "" || strings.ContainsAny(newName, "\r\n") {
+		return domain.ErrInvalidInput
+	}
+	return withRetryWrite(ctx, c, func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err := c.sendCommandLocked(ctx, fmt.Sprintf("MOVR %s %s", path, newName)); err != nil {
+			return err
+		}
+		_, err := c.readResponseLocked(ctx)
+		return err
+	})
+}
+
+// CutRecording starts VDR's cutter on a recording with EDIT. VDR refuses
+// recordings without editing marks and starts only one cut at a time.
+func (c *Client) CutRecording(ctx context.Context, path string) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return domain.ErrInvalidInput
+	}
+	return withRetryWrite(ctx, c, func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if err := c.sendCommandLocked(ctx, "EDIT "+path); err != nil {
+			return err
+		}
+		_, err := c.readResponseLocked(ctx)
+		return err
+	})
+}
+
 // GetCurrentChannel returns the current channel.
 func (c *Client) GetCurrentChannel(ctx context.Context) (string, error) {
 	return withRetry(ctx, c, func() (string, error) {
@@ -678,6 +1109,7 @@ func withRetry[T any](ctx context.Context, c *Client, fn func() (T, error)) (T,
 
 	const maxAttempts = 3
 	backoff := 60 * time.Millisecond
+	busyDelay := busyBackoff
 	var lastErr error
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
@@ -685,7 +1117,13 @@ func withRetry[T any](ctx context.Context, c *Client, fn func() (T, error)) (T,
 			return zero, err
 		}
 		if err := c.ensureConnected(ctx); err != nil {
-			return zero, err
+			if !errors.Is(err, domain.ErrBusy) || attempt == maxAttempts {
+				return zero, err
+			}
+			if err := waitBusy(ctx, attempt, &busyDelay); err != nil {
+				return zero, err
+			}
+			continue
 		}
 
 		if err := ctx.Err(); err != nil {
@@ -700,18 +1138,19 @@ func withRetry[T any](ctx context.Context, c *Client, fn func() (T, error)) (T,
 		if !isTransientConnErr(err) || attempt == maxAttempts {
 			return zero, err
 		}
+		trace.SpanFromContext(ctx).AddEvent("svdrp retry", trace.WithAttributes(
+			attribute.Int("svdrp.attempt", attempt+1),
+			attribute.String("error", err.Error()),
+		))
 
 		// Force reconnection before next try.
 		c.mu.Lock()
-		c.closeConnectionLocked()
+		c.observeReconnectLocked()
+		c.closeConnectionLocked(fmt.Sprintf("%s failed: %v", c.lastVerb, err))
 		c.mu.Unlock()
 
-		t := time.NewTimer(backoff)
-		select {
-		case <-ctx.Done():
-			t.Stop()
-			return zero, ctx.Err()
-		case <-t.C:
+		if err := sleepContext(ctx, withJitter(backoff)); err != nil {
+			return zero, err
 		}
 
 		backoff *= 2
@@ -728,13 +1167,21 @@ func withRetry[T any](ctx context.Context, c *Client, fn func() (T, error)) (T,
 func withRetryWrite(ctx context.Context, c *Client, fn func() error) error {
 	const maxAttempts = 3
 	backoff := 60 * time.Millisecond
+	busyDelay := busyBackoff
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		if err := ctx.Err(); err != nil {
 			return err
 		}
 		if err := c.ensureConnected(ctx); err != nil {
-			return err
+			// Nothing was sent yet, so retrying a busy refusal is safe for writes too.
+			if !errors.Is(err, domain.ErrBusy) || attempt == maxAttempts {
+				return err
+			}
+			if err := waitBusy(ctx, attempt, &busyDelay); err != nil {
+				return err
+			}
+			continue
 		}
 
 		if err := ctx.Err(); err != nil {
@@ -749,17 +1196,18 @@ func withRetryWrite(ctx context.Context, c *Client, fn func() error) error {
 		if !isTransientConnErr(err) || !errors.Is(err, errSVDRPSendFailed) || attempt == maxAttempts {
 			return err
 		}
+		trace.SpanFromContext(ctx).AddEvent("svdrp retry", trace.WithAttributes(
+			attribute.Int("svdrp.attempt", attempt+1),
+			attribute.String("error", err.Error()),
+		))
 
 		c.mu.Lock()
-		c.closeConnectionLocked()
+		c.observeReconnectLocked()
+		c.closeConnectionLocked(fmt.Sprintf("%s failed: %v", c.lastVerb, err))
 		c.mu.Unlock()
 
-		t := time.NewTimer(backoff)
-		select {
-		case <-ctx.Done():
-			t.Stop()
-			return ctx.Err()
-		case <-t.C:
+		if err := sleepContext(ctx, withJitter(backoff)); err != nil {
+			return err
 		}
 
 		backoff *= 2
@@ -771,6 +1219,39 @@ func withRetryWrite(ctx context.Context, c *Client, fn func() error) error {
 	return domain.ErrConnection
 }
 
+// waitBusy records a busy refusal on the caller's span and sleeps before the next
+// connection attempt, doubling *delay up to busyBackoffMax.
+func waitBusy(ctx context.Context, attempt int, delay *time.Duration) error {
+	trace.SpanFromContext(ctx).AddEvent("svdrp busy", trace.WithAttributes(
+		attribute.Int("svdrp.attempt", attempt+1),
+	))
+	if err := sleepContext(ctx, withJitter(*delay)); err != nil {
+		return err
+	}
+	*delay = min(*delay*2, busyBackoffMax)
+	return nil
+}
+
+// withJitter spreads d randomly over [d/2, d) so that several clients backing off
+// at once don't retry in lockstep.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + rand.N(d/2)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 func isTransientConnErr(err error) bool {
 	if err == nil {
 		return false
@@ -804,30 +1285,155 @@ func (c *Client) sendCommandLocked(ctx context.Context, cmd string) error {
 		return domain.ErrConnection
 	}
 
-	deadline := time.Now().Add(c.timeout)
-	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
-		deadline = ctxDeadline
-	}
-	_ = c.conn.SetWriteDeadline(deadline)
+	_ = c.conn.SetWriteDeadline(c.deadline(ctx))
+
+	c.verb = commandVerb(cmd)
+	c.lastVerb = c.verb
+	c.sentAt = time.Now()
+	_, c.span = otel.Tracer(tracerName).Start(ctx, "svdrp "+c.verb, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("svdrp.verb", c.verb), attribute.String("server.address", c.host), attribute.Int("server.port", c.port)))
 
 	if _, err := c.rw.WriteString(cmd + "\r\n"); err != nil {
-		c.closeConnectionLocked()
+		c.closeConnectionLocked(fmt.Sprintf("sending %s failed: %v", c.verb, err))
 		if ctx.Err() != nil {
-			return ctx.Err()
+			err = ctx.Err()
+		} else {
+			err = fmt.Errorf("%w: %w", errSVDRPSendFailed, err)
 		}
-		return fmt.Errorf("%w: %w", errSVDRPSendFailed, err)
+		c.observeCommandLocked(err)
+		return err
 	}
 	if err := c.rw.Flush(); err != nil {
-		c.closeConnectionLocked()
+		c.closeConnectionLocked(fmt.Sprintf("sending %s failed: %v", c.verb, err))
 		if ctx.Err() != nil {
-			return ctx.Err()
+			err = ctx.Err()
+		} else {
+			err = fmt.Errorf("%w: %w", errSVDRPSendFailed, err)
 		}
-		return fmt.Errorf("%w: %w", errSVDRPSendFailed, err)
+		c.observeCommandLocked(err)
+		return err
 	}
 	return nil
 }
 
+// deadline returns the I/O deadline for the next read or write: the client
+// timeout, or the context deadline if that is earlier.
+func (c *Client) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	return deadline
+}
+
 func (c *Client) readResponseLocked(ctx context.Context) ([]string, error) {
+	lines, err := c.readReplyLocked(ctx)
+	c.finishCommandLocked(err)
+	return lines, err
+}
+
+// finishCommandLocked reports the outcome of the command awaiting its reply and
+// restarts the idle window of a connection that is still open.
+func (c *Client) finishCommandLocked(err error) {
+	c.observeCommandLocked(err)
+	if c.conn != nil {
+		c.lastUsed = time.Now()
+		c.armIdleTimerLocked()
+	}
+}
+
+// armIdleTimerLocked (re)starts the timer releasing the connection once it has
+// been unused for idleTimeout.
+func (c *Client) armIdleTimerLocked() {
+	if c.idleTimeout <= 0 {
+		if c.idleTimer != nil {
+			c.idleTimer.Stop()
+		}
+		return
+	}
+	if c.idleTimer == nil {
+		c.idleTimer = time.AfterFunc(c.idleTimeout, c.releaseIdle)
+		return
+	}
+	c.idleTimer.Reset(c.idleTimeout)
+}
+
+// releaseIdle releases the connection unless it was used again while the timer fired.
+func (c *Client) releaseIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil || c.idleTimeout <= 0 {
+		return
+	}
+	if remaining := c.idleTimeout - time.Since(c.lastUsed); remaining > 0 {
+		c.idleTimer.Reset(remaining)
+		return
+	}
+	_ = c.releaseLocked("idle")
+}
+
+// releaseLocked ends the session with a best-effort QUIT and closes the connection.
+func (c *Client) releaseLocked(reason string) error {
+	if c.conn == nil {
+		return nil
+	}
+
+	// Best-effort QUIT (ignore errors on broken connections).
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	_, _ = c.rw.WriteString("QUIT\r\n")
+	_ = c.rw.Flush()
+
+	return c.closeConnectionLocked(reason)
+}
+
+// observeCommandLocked reports the outcome of the command awaiting its reply, if any,
+// and ends its span.
+func (c *Client) observeCommandLocked(err error) {
+	if c.verb == "" {
+		return
+	}
+	if c.observer != nil {
+		c.observer.ObserveCommand(c.verb, time.Since(c.sentAt), err)
+	}
+	if c.span != nil {
+		endSpan(c.span, err)
+		c.span = nil
+	}
+	c.verb = ""
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *Client) observeReconnectLocked() {
+	if c.observer != nil {
+		c.observer.ObserveReconnect(c.lastVerb)
+	}
+}
+
+// commandVerb returns the SVDRP verb of cmd for metrics. Anything that does not
+// look like a verb is reported as "OTHER" to keep the label set small.
+func commandVerb(cmd string) string {
+	verb, _, _ := strings.Cut(strings.TrimSpace(cmd), " ")
+	verb = strings.ToUpper(verb)
+	if len(verb) < 3 || len(verb) > 4 {
+		return "OTHER"
+	}
+	for _, r := range verb {
+		if r < 'A' || r > 'Z' {
+			return "OTHER"
+		}
+	}
+	return verb
+}
+
+func (c *Client) readReplyLocked(ctx context.Context) ([]string, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -835,17 +1441,13 @@ func (c *Client) readResponseLocked(ctx context.Context) ([]string, error) {
 		return nil, domain.ErrConnection
 	}
 
-	deadline := time.Now().Add(c.timeout)
-	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
-		deadline = ctxDeadline
-	}
-	_ = c.conn.SetReadDeadline(deadline)
+	_ = c.conn.SetReadDeadline(c.deadline(ctx))
 
 	var lines []string
 	for {
 		line, err := c.rw.ReadString('\n')
 		if err != nil {
-			c.closeConnectionLocked()
+			c.closeConnectionLocked(fmt.Sprintf("reading reply failed: %v", err))
 			if ctx.Err() != nil {
 				return nil, ctx.Err()
 			}
@@ -884,12 +1486,53 @@ func (c *Client) readResponseLocked(ctx context.Context) ([]string, error) {
 	return lines, nil
 }
 
-func (c *Client) closeConnectionLocked() {
-	if c.conn != nil {
-		_ = c.conn.Close()
-		c.conn = nil
-		c.rw = nil
+// readRawReplyLocked reads a single reply and returns its lines verbatim,
+// including the status codes. Error replies are not turned into errors.
+func (c *Client) readRawReplyLocked(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if c.conn == nil {
+		return nil, domain.ErrConnection
+	}
+
+	_ = c.conn.SetReadDeadline(c.deadline(ctx))
+
+	var lines []string
+	for {
+		line, err := c.rw.ReadString('\n')
+		if err != nil {
+			c.closeConnectionLocked(fmt.Sprintf("reading reply failed: %v", err))
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+
+		// Every line but the last one has a '-' after the status code.
+		if len(line) < 4 || line[3] != '-' {
+			return lines, nil
+		}
+	}
+}
+
+// closeConnectionLocked closes the connection, if any, and records reason as the
+// cause of the disconnect.
+func (c *Client) closeConnectionLocked(reason string) error {
+	if c.conn == nil {
+		return nil
+	}
+	if c.idleTimer != nil {
+		c.idleTimer.Stop()
 	}
+	err := c.conn.Close()
+	c.conn = nil
+	c.rw = nil
+	c.lastDisconnect = time.Now()
+	c.lastDisconnectReason = reason
+	return err
 }
 
 func parseChannel(number int, line string) domain.Channel {
@@ -903,7 +1546,25 @@ func parseChannel(number int, line string) domain.Channel {
 	if chID == "" {
 		chID = strconv.Itoa(chNumber)
 	}
-	return domain.Channel{ID: chID, Number: chNumber, Name: chName, Provider: provider}
+	return domain.Channel{ID: chID, Number: chNumber, Name: chName, Provider: provider, Settings: lstcChannelSettings(strings.TrimSpace(line))}
+}
+
+// lstcChannelSettings returns the channels.conf line of an LSTC line ("<num>
+// [<channel-id>] <channels.conf line>"), or "" if it does not hold one.
+func lstcChannelSettings(text string) string {
+	rest := text
+	if num, after, ok := strings.Cut(text, " "); ok {
+		if _, err := strconv.Atoi(num); err == nil {
+			rest = strings.TrimSpace(after)
+		}
+	}
+	if id, conf, ok := strings.Cut(rest, " "); ok && looksLikeVDRChannelID(id) && strings.Count(id, "-") >= 3 && !strings.Contains(id, ":") {
+		rest = strings.TrimSpace(conf)
+	}
+	if strings.Count(rest, ":") < 12 {
+		return ""
+	}
+	return rest
 }
 
 func parseSVDRPChannelHeader(text string, numberFallback int) (channelID string, channelNumber int, channelName string, provider string) {
@@ -1023,6 +1684,19 @@ func parseEPGEvents(lines []string) []domain.EPGEvent {
 			currentEvent.Subtitle = line[2:]
 		} else if strings.HasPrefix(line, "D ") {
 			currentEvent.Description += line[2:] + "\n"
+		} else if strings.HasPrefix(line, "X ") {
+			applyEPGComponent(currentEvent, line[2:])
+		} else if strings.HasPrefix(line, "V ") {
+			if vps, err := strconv.ParseInt(strings.TrimSpace(line[2:]), 10, 64); err == nil && vps > 0 {
+				t := time.Unix(vps, 0)
+				currentEvent.VPS = &t
+			}
+		} else if strings.HasPrefix(line, "G ") {
+			currentEvent.Genres = appendEPGGenres(currentEvent.Genres, line[2:])
+		} else if strings.HasPrefix(line, "R ") {
+			if age, err := strconv.Atoi(strings.TrimSpace(line[2:])); err == nil && age > 0 {
+				currentEvent.ParentalRating = age
+			}
 		}
 	}
 
@@ -1033,6 +1707,232 @@ func parseEPGEvents(lines []string) []domain.EPGEvent {
 	return events
 }
 
+// applyEPGComponent parses an "X <stream> <type> <language> <description>" line.
+// Stream and type are the hex stream_content/component_type values of the DVB
+// component descriptor (ETSI EN 300 468). VDR encodes stream_content_ext in the
+// upper nibble of the stream value.
+func applyEPGComponent(ev *domain.EPGEvent, data string) {
+	fields := strings.Fields(data)
+	if len(fields) < 2 {
+		return
+	}
+	stream, err := strconv.ParseUint(fields[0], 16, 8)
+	if err != nil {
+		return
+	}
+	compType, err := strconv.ParseUint(fields[1], 16, 8)
+	if err != nil {
+		return
+	}
+	language := ""
+	if len(fields) > 2 {
+		language = fields[2]
+	}
+	description := ""
+	if len(fields) > 3 {
+		description = strings.Join(fields[3:], " ")
+	}
+
+	content := stream & 0x0F
+	ext := stream >> 4
+
+	switch {
+	case content == 0x01 || content == 0x05:
+		// MPEG-2 and H.264 video use the same layout for types 0x01-0x10:
+		// four aspect ratios per block; blocks 3 and 4 are HD.
+		if compType >= 0x01 && compType <= 0x10 {
+			if ev.Video.Format == "" {
+				ev.Video.Format = [...]string{"4:3", "16:9", "16:9", ">16:9"}[(compType-1)%4]
+			}
+			if compType >= 0x09 {
+				ev.Video.HD = true
+			}
+		}
+		applyVideoDescription(&ev.Video, description)
+	case content == 0x09 && ext == 0x00:
+		// HEVC video is HD or UHD only.
+		ev.Video.HD = true
+		applyVideoDescription(&ev.Video, description)
+	case content == 0x02 || content == 0x04 || content == 0x06 || content == 0x07 || (content == 0x09 && ext == 0x01):
+		ev.Audio = append(ev.Audio, domain.AudioInfo{
+			Language: language,
+			Channels: audioChannels(byte(content), byte(compType), description),
+		})
+	}
+}
+
+func applyVideoDescription(v *domain.VideoInfo, description string) {
+	d := strings.ToLower(description)
+	for _, word := range strings.Fields(d) {
+		switch strings.Trim(word, ",;()") {
+		case "hd", "hdtv", "uhd", "hd-tv":
+			v.HD = true
+		}
+	}
+	if v.Format == "" {
+		switch {
+		case strings.Contains(d, "16:9"):
+			v.Format = "16:9"
+		case strings.Contains(d, "4:3"):
+			v.Format = "4:3"
+		}
+	}
+}
+
+// audioChannels derives the channel count from the component type, falling back to the description.
+func audioChannels(content, compType byte, description string) int {
+	switch content {
+	case 0x02, 0x06:
+		// MPEG-1 Layer 2 and HE-AAC: 0x01 mono, 0x02 dual mono, 0x03 stereo, 0x04/0x05 multichannel.
+		switch compType {
+		case 0x01:
+			return 1
+		case 0x02, 0x03:
+			return 2
+		case 0x04, 0x05:
+			return 6
+		}
+	case 0x04:
+		// AC-3: the lower three bits encode the number of channels.
+		switch compType & 0x07 {
+		case 0x00:
+			return 1
+		case 0x01, 0x02, 0x03:
+			return 2
+		default:
+			return 6
+		}
+	case 0x07:
+		return 6
+	}
+
+	d := strings.ToLower(description)
+	switch {
+	case strings.Contains(d, "5.1") || strings.Contains(d, "surround"):
+		return 6
+	case strings.Contains(d, "mono"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// appendEPGGenres parses a "G <hex> <hex> ..." line into genre names, skipping duplicates.
+func appendEPGGenres(genres []string, data string) []string {
+	for _, f := range strings.Fields(data) {
+		v, err := strconv.ParseUint(f, 16, 8)
+		if err != nil {
+			continue
+		}
+		name := epgContentName(byte(v))
+		if name == "" {
+			continue
+		}
+		dup := false
+		for _, g := range genres {
+			if g == name {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			genres = append(genres, name)
+		}
+	}
+	return genres
+}
+
+// epgContentName maps a DVB content nibble pair to a readable genre.
+// Unknown sub-genres fall back to their main category.
+func epgContentName(content byte) string {
+	if name, ok := epgContentNames[content]; ok {
+		return name
+	}
+	return epgContentNames[content&0xF0]
+}
+
+var epgContentNames = map[byte]string{
+	0x10: "Movie/Drama",
+	0x11: "Detective/Thriller",
+	0x12: "Adventure/Western/War",
+	0x13: "Science Fiction/Fantasy/Horror",
+	0x14: "Comedy",
+	0x15: "Soap/Melodrama/Folklore",
+	0x16: "Romance",
+	0x17: "Serious/Classical/Religious/Historical Movie/Drama",
+	0x18: "Adult Movie/Drama",
+	0x20: "News/Current Affairs",
+	0x21: "News/Weather Report",
+	0x22: "News Magazine",
+	0x23: "Documentary",
+	0x24: "Discussion/Interview/Debate",
+	0x30: "Show/Game Show",
+	0x31: "Game Show/Quiz/Contest",
+	0x32: "Variety Show",
+	0x33: "Talk Show",
+	0x40: "Sports",
+	0x41: "Special Event",
+	0x42: "Sport Magazine",
+	0x43: "Football/Soccer",
+	0x44: "Tennis/Squash",
+	0x45: "Team Sports",
+	0x46: "Athletics",
+	0x47: "Motor Sport",
+	0x48: "Water Sport",
+	0x49: "Winter Sports",
+	0x4A: "Equestrian",
+	0x4B: "Martial Sports",
+	0x50: "Children's/Youth Programme",
+	0x51: "Pre-school Children's Programme",
+	0x52: "Entertainment Programme for 6 to 14",
+	0x53: "Entertainment Programme for 10 to 16",
+	0x54: "Informational/Educational/School Programme",
+	0x55: "Cartoons/Puppets",
+	0x60: "Music/Ballet/Dance",
+	0x61: "Rock/Pop",
+	0x62: "Serious/Classical Music",
+	0x63: "Folk/Traditional Music",
+	0x64: "Jazz",
+	0x65: "Musical/Opera",
+	0x66: "Ballet",
+	0x70: "Arts/Culture",
+	0x71: "Performing Arts",
+	0x72: "Fine Arts",
+	0x73: "Religion",
+	0x74: "Popular Culture/Traditional Arts",
+	0x75: "Literature",
+	0x76: "Film/Cinema",
+	0x77: "Experimental Film/Video",
+	0x78: "Broadcasting/Press",
+	0x79: "New Media",
+	0x7A: "Arts/Culture Magazine",
+	0x7B: "Fashion",
+	0x80: "Social/Political/Economics",
+	0x81: "Magazine/Report/Documentary",
+	0x82: "Economics/Social Advisory",
+	0x83: "Remarkable People",
+	0x90: "Education/Science/Factual",
+	0x91: "Nature/Animals/Environment",
+	0x92: "Technology/Natural Sciences",
+	0x93: "Medicine/Physiology/Psychology",
+	0x94: "Foreign Countries/Expeditions",
+	0x95: "Social/Spiritual Sciences",
+	0x96: "Further Education",
+	0x97: "Languages",
+	0xA0: "Leisure/Hobbies",
+	0xA1: "Tourism/Travel",
+	0xA2: "Handicraft",
+	0xA3: "Motoring",
+	0xA4: "Fitness & Health",
+	0xA5: "Cooking",
+	0xA6: "Advertisement/Shopping",
+	0xA7: "Gardening",
+	0xB0: "Original Language",
+	0xB1: "Black & White",
+	0xB2: "Unpublished",
+	0xB3: "Live Broadcast",
+}
+
 func parseEPGEventLine(line string) *domain.EPGEvent {
 	parts := strings.Fields(line[2:])
 	if len(parts) < 3 {
@@ -1312,8 +2212,11 @@ func parseRecording(line string) (domain.Recording, error) {
 			rec.Date = t
 		}
 		rec.Length = parseRecordingLength(fields[3])
+		// A '*' after the length marks recordings that have not been watched yet.
+		rec.New = strings.Contains(fields[3], "*")
 
 		metaText := strings.Join(fields[4:], " ")
+		rec.Name = metaText
 		applyRecordingMeta(&rec, metaText)
 		return rec, nil
 	}
@@ -1341,6 +2244,25 @@ func parseRecording(line string) (domain.Recording, error) {
 	return rec, nil
 }
 
+// recordingDirSize returns the total size of the files in a recording directory.
+// Unreadable directories count as empty.
+func recordingDirSize(dirPath string) int64 {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return 0
+	}
+	var size int64
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
 func parseRecordingLength(token string) time.Duration {
 	token = strings.TrimSpace(token)
 	// Tokens may include trailing flags like '*' and '!' (e.g. "4:22*!" or "0:22*").
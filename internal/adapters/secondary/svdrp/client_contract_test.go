@@ -2,11 +2,13 @@ package svdrp_test
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/githubixx/vdradmin-go/internal/adapters/secondary/svdrp"
+	"github.com/githubixx/vdradmin-go/internal/domain"
 )
 
 func TestClient_GetEPG_FallsBackWhenTimestampUnsupported(t *testing.T) {
@@ -171,3 +173,197 @@ func mkdirAll(path string) error {
 	// keep test helper small; avoid extra dependencies
 	return mkdirAllMode(path, 0o755)
 }
+
+func TestClient_MoveRecording_SendsMOVR(t *testing.T) {
+	srv := newSVDRPTestServer(t, []svdrpConnScript{{
+		steps: []svdrpConnStep{
+			{expect: "MOVR 4 Krimi~Tatort~Der Fall", respond: []string{`250 Recording "4" moved to "Krimi~Tatort~Der Fall"`}},
+			{expect: "MOVR 5 Film", respond: []string{"550 Recording \"5\" not found"}},
+		},
+	}})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.MoveRecording(ctx, "4", "Krimi~Tatort~Der Fall"); err != nil {
+		t.Fatalf("MoveRecording: %v", err)
+	}
+	if err := c.MoveRecording(ctx, "5", "Film"); err == nil {
+		t.Fatalf("expected VDR's refusal to be returned")
+	}
+	if err := c.MoveRecording(ctx, "6", "two\nlines"); err == nil {
+		t.Fatalf("expected a name with a line break to be rejected")
+	}
+}
+
+func TestClient_PlayRecording_SendsPLAY(t *testing.T) {
+	srv := newSVDRPTestServer(t, []svdrpConnScript{{
+		steps: []svdrpConnStep{
+			{expect: "PLAY 3", respond: []string{`250 Playing recording "3" [Tatort]`}},
+			{expect: "PLAY 3 begin", respond: []string{`250 Playing recording "3" [Tatort]`}},
+			{expect: "PLAY 3 01:02:03", respond: []string{`250 Playing recording "3" [Tatort]`}},
+			{expect: "PLAY 9", respond: []string{`550 Recording "9" not found`}},
+		},
+	}})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	starts := []domain.ReplayStart{
+		{},
+		{FromBeginning: true},
+		{Offset: time.Hour + 2*time.Minute + 3*time.Second},
+	}
+	for _, start := range starts {
+		if err := c.PlayRecording(ctx, "3", start); err != nil {
+			t.Fatalf("PlayRecording(%+v): %v", start, err)
+		}
+	}
+	if err := c.PlayRecording(ctx, "9", domain.ReplayStart{}); err == nil {
+		t.Fatalf("expected VDR's refusal to be returned")
+	}
+}
+
+func TestClient_CutRecording_SendsEDIT(t *testing.T) {
+	srv := newSVDRPTestServer(t, []svdrpConnScript{{
+		steps: []svdrpConnStep{
+			{expect: "EDIT 4", respond: []string{`250 Editing recording "4" [Tatort]`}},
+			{expect: "EDIT 5", respond: []string{"550 No editing marks defined"}},
+		},
+	}})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.CutRecording(ctx, "4"); err != nil {
+		t.Fatalf("CutRecording: %v", err)
+	}
+	if err := c.CutRecording(ctx, "5"); err == nil {
+		t.Fatalf("expected VDR's refusal to be returned")
+	}
+}
+
+func TestClient_GetChannels_KeepsSettings(t *testing.T) {
+	conf := "Das Erste HD;ARD:11494:HC23M5O35P0S1:S19.2E:22000:5101=27:5102=deu@3:5104:0:10301:1:1019:0"
+	srv := newSVDRPTestServer(t, []svdrpConnScript{{
+		steps: []svdrpConnStep{{expect: "LSTC", respond: []string{"250 1 " + conf}}},
+	}})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	chs, err := c.GetChannels(ctx)
+	if err != nil {
+		t.Fatalf("GetChannels: %v", err)
+	}
+	if len(chs) != 1 || chs[0].Settings != conf || chs[0].ID != "S19.2E-1-1019-10301" {
+		t.Fatalf("channels = %+v", chs)
+	}
+}
+
+func TestClient_GetChannelGroups(t *testing.T) {
+	erste := "Das Erste HD;ARD:11494:HC23M5O35P0S1:S19.2E:22000:5101=27:5102=deu@3:5104:0:10301:1:1019:0"
+	arte := "arte HD;ARD:10743:HC23M5O35P0S1:S19.2E:22000:6210=27:6220=deu@3:6230:0:10302:1:1010:0"
+	srv := newSVDRPTestServer(t, []svdrpConnScript{{
+		steps: []svdrpConnStep{{expect: "LSTC :groups", respond: []string{
+			"250-1 " + erste,
+			"250-0 :News",
+			"250-0 :@100 Culture",
+			"250 100 " + arte,
+		}}},
+	}})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	chs, err := c.GetChannelGroups(ctx)
+	if err != nil {
+		t.Fatalf("GetChannelGroups: %v", err)
+	}
+	if len(chs) != 2 {
+		t.Fatalf("channels = %+v", chs)
+	}
+	if chs[0].Name != "Das Erste HD" || chs[0].Group != "" {
+		t.Errorf("first channel = %+v, want no group", chs[0])
+	}
+	if chs[1].Number != 100 || chs[1].ID != "S19.2E-1-1010-10302" || chs[1].Group != "Culture" {
+		t.Errorf("second channel = %+v, want channel 100 in Culture", chs[1])
+	}
+}
+
+func TestClient_ChannelCommands(t *testing.T) {
+	conf := "arte HD;ARD:10743:HC23M5O35P0S1:S19.2E:22000:6210=27:6220=deu@3:6230:0:10302:1:1010:0"
+	srv := newSVDRPTestServer(t, []svdrpConnScript{{
+		steps: []svdrpConnStep{
+			{expect: "MODC 2 " + conf, respond: []string{"250 2 " + conf}},
+			{expect: "MOVC 7 2", respond: []string{`250 Channel "7" moved to "2"`}},
+			{expect: "NEWC " + conf, respond: []string{"250 42 " + conf}},
+			{expect: "NEWC " + conf, respond: []string{"501 Channel settings are not unique"}},
+			{expect: "DELC 3", respond: []string{`250 Channel "3" deleted`}},
+			{expect: "DELC 4", respond: []string{`550 Channel "4" is in use by timer 1 (2 A)`}},
+			{expect: "DELC 99", respond: []string{`501 Channel "99" not defined`}},
+			{expect: "DELC 5", respond: []string{"550 Channels are being modified - try again"}},
+		},
+	}})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := c.UpdateChannel(ctx, 2, conf); err != nil {
+		t.Fatalf("UpdateChannel: %v", err)
+	}
+	if err := c.MoveChannel(ctx, 7, 2); err != nil {
+		t.Fatalf("MoveChannel: %v", err)
+	}
+	if n, err := c.CreateChannel(ctx, conf); err != nil || n != 42 {
+		t.Fatalf("CreateChannel = %d, %v; want 42, nil", n, err)
+	}
+	if _, err := c.CreateChannel(ctx, conf); !errors.Is(err, domain.ErrChannelExists) {
+		t.Fatalf("CreateChannel duplicate: err = %v, want ErrChannelExists", err)
+	}
+	if err := c.DeleteChannel(ctx, 3); err != nil {
+		t.Fatalf("DeleteChannel: %v", err)
+	}
+	if err := c.DeleteChannel(ctx, 4); !errors.Is(err, domain.ErrChannelInUse) {
+		t.Fatalf("DeleteChannel in use: err = %v, want ErrChannelInUse", err)
+	}
+	if err := c.DeleteChannel(ctx, 99); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("DeleteChannel unknown: err = %v, want ErrNotFound", err)
+	}
+	if err := c.DeleteChannel(ctx, 5); !errors.Is(err, domain.ErrChannelsLocked) {
+		t.Fatalf("DeleteChannel while editing: err = %v, want ErrChannelsLocked", err)
+	}
+	if err := c.UpdateChannel(ctx, 1, "no settings"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("UpdateChannel invalid: err = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,98 @@
+package svdrp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseEPGEvents_ComponentsVPSGenreRating(t *testing.T) {
+	lines := []string{
+		"C S19.2E-1-1019-10301 Das Erste HD",
+		"E 4711 1767121200 5400 4E 10",
+		"T Tatort",
+		"S Borowski und das Haupt der Medusa",
+		"D Kommissar Borowski ermittelt.",
+		"G 10 11",
+		"R 12",
+		"X 5 0B deu HD 16:9",
+		"X 2 03 deu stereo",
+		"X 2 03 mis stereo",
+		"X 4 44 deu Dolby Digital 5.1",
+		"X 3 20 deu Untertitel",
+		"V 1767121200",
+		"e",
+		"E 4712 1767126600 1800 4E 10",
+		"T Tagesthemen",
+		"X 1 03 deu 16:9",
+		"X 2 01 eng",
+		"G 2F",
+		"e",
+		"c",
+	}
+
+	events := parseEPGEvents(lines)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+
+	ev := events[0]
+	if !ev.Video.HD || ev.Video.Format != "16:9" {
+		t.Fatalf("Video=%+v, want HD 16:9", ev.Video)
+	}
+	if len(ev.Audio) != 3 {
+		t.Fatalf("expected 3 audio streams, got %+v", ev.Audio)
+	}
+	if ev.Audio[2].Channels != 6 || !ev.HasSurround() {
+		t.Fatalf("expected AC-3 5.1 stream, got %+v", ev.Audio)
+	}
+	if got := ev.AudioLanguages(); !reflect.DeepEqual(got, []string{"deu", "mis"}) {
+		t.Fatalf("AudioLanguages=%v", got)
+	}
+	if ev.VPS == nil || !ev.VPS.Equal(time.Unix(1767121200, 0)) {
+		t.Fatalf("VPS=%v", ev.VPS)
+	}
+	if want := []string{"Movie/Drama", "Detective/Thriller"}; !reflect.DeepEqual(ev.Genres, want) {
+		t.Fatalf("Genres=%v, want %v", ev.Genres, want)
+	}
+	if ev.ParentalRating != 12 {
+		t.Fatalf("ParentalRating=%d, want 12", ev.ParentalRating)
+	}
+
+	ev = events[1]
+	if ev.Video.HD || ev.Video.Format != "16:9" {
+		t.Fatalf("Video=%+v, want SD 16:9", ev.Video)
+	}
+	if len(ev.Audio) != 1 || ev.Audio[0].Language != "eng" || ev.Audio[0].Channels != 1 || ev.HasSurround() {
+		t.Fatalf("Audio=%+v, want single mono eng", ev.Audio)
+	}
+	// Unknown sub-genres fall back to the main category.
+	if want := []string{"News/Current Affairs"}; !reflect.DeepEqual(ev.Genres, want) {
+		t.Fatalf("Genres=%v, want %v", ev.Genres, want)
+	}
+	if ev.VPS != nil || ev.ParentalRating != 0 {
+		t.Fatalf("expected no VPS/rating, got %v/%d", ev.VPS, ev.ParentalRating)
+	}
+}
+
+func TestParseEPGEvents_IgnoresMalformedExtendedLines(t *testing.T) {
+	lines := []string{
+		"E 1 1767121200 600",
+		"T X",
+		"X",
+		"X zz 03 deu",
+		"X 2",
+		"V soon",
+		"G xyz 00",
+		"R -3",
+		"e",
+	}
+	events := parseEPGEvents(lines)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.VPS != nil || len(ev.Audio) != 0 || len(ev.Genres) != 0 || ev.ParentalRating != 0 || ev.Video.HD {
+		t.Fatalf("expected malformed lines to be ignored, got %+v", ev)
+	}
+}
@@ -0,0 +1,58 @@
+package svdrp_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/adapters/secondary/svdrp"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+func TestClient_ExecuteReturnsRawReply(t *testing.T) {
+	srv := newSVDRPTestServer(t, []svdrpConnScript{
+		{steps: []svdrpConnStep{
+			{expect: "LSTT", respond: []string{"250-1 1:C-1-2-3:2026-03-01:2015:2115:50:99:A:", "250 2 1:C-1-2-3:2026-03-02:2015:2115:50:99:B:"}},
+			{expect: "DELT 9", respond: []string{"501 Timer \"9\" not defined"}},
+		}},
+	})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+	obs := &recordingObserver{}
+	c.SetObserver(obs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	lines, err := c.Execute(ctx, "LSTT")
+	if err != nil {
+		t.Fatalf("Execute LSTT: %v", err)
+	}
+	want := "250-1 1:C-1-2-3:2026-03-01:2015:2115:50:99:A:|250 2 1:C-1-2-3:2026-03-02:2015:2115:50:99:B:"
+	if got := strings.Join(lines, "|"); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	lines, err = c.Execute(ctx, "DELT 9")
+	if err != nil {
+		t.Fatalf("error replies must be returned as lines, got %v", err)
+	}
+	if len(lines) != 1 || lines[0] != "501 Timer \"9\" not defined" {
+		t.Fatalf("unexpected reply %q", lines)
+	}
+
+	if _, err := c.Execute(ctx, "quit"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected QUIT to be refused, got %v", err)
+	}
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if len(obs.errors) != 1 || obs.errors[0] != "DELT" {
+		t.Fatalf("expected the error reply to be observed as error, got %v", obs.errors)
+	}
+}
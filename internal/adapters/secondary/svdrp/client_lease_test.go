@@ -0,0 +1,111 @@
+package svdrp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/adapters/secondary/svdrp"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+func TestClient_ReleasesIdleConnection(t *testing.T) {
+	srv := newSVDRPTestServer(t, []svdrpConnScript{
+		{steps: []svdrpConnStep{{expect: "LSTC", respond: []string{"250 1 C-1-2-3 SomeChannel:provider"}}}},
+		{steps: []svdrpConnStep{{expect: "LSTC", respond: []string{"250 1 C-1-2-3 SomeChannel:provider"}}}},
+	})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+	c.SetIdleTimeout(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if _, err := c.GetChannels(ctx); err != nil {
+		t.Fatalf("GetChannels: %v", err)
+	}
+	if st := c.ConnectionState(); !st.Connected || st.ConnectedSince.IsZero() || st.LastUsed.IsZero() {
+		t.Fatalf("expected an open connection, got %+v", st)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for c.ConnectionState().Connected {
+		if time.Now().After(deadline) {
+			t.Fatalf("connection was not released after the idle timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	st := c.ConnectionState()
+	if st.LastDisconnectReason != "idle" || st.LastDisconnect.IsZero() {
+		t.Fatalf("expected idle disconnect, got %+v", st)
+	}
+	for {
+		if _, quits := srv.Counts(); quits == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected QUIT to be sent on release")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The next command opens a new connection on demand.
+	if _, err := c.GetChannels(ctx); err != nil {
+		t.Fatalf("GetChannels after release: %v", err)
+	}
+	if accepted, _ := srv.Counts(); accepted != 2 {
+		t.Fatalf("expected a second connection, got %d", accepted)
+	}
+}
+
+func TestClient_BusyVDRIsRetried(t *testing.T) {
+	srv := newSVDRPTestServer(t, []svdrpConnScript{
+		{welcome: "421 vdr service not available, closing transmission channel"},
+		{steps: []svdrpConnStep{{expect: "LSTC", respond: []string{"250 1 C-1-2-3 SomeChannel:provider"}}}},
+	})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if _, err := c.GetChannels(ctx); err != nil {
+		t.Fatalf("GetChannels: %v", err)
+	}
+	st := c.ConnectionState()
+	if st.BusyCount != 1 || st.LastBusy.IsZero() {
+		t.Fatalf("expected one busy refusal, got %+v", st)
+	}
+}
+
+func TestClient_BusyVDRReportsErrBusy(t *testing.T) {
+	busy := svdrpConnScript{welcome: "421 vdr service not available, closing transmission channel"}
+	srv := newSVDRPTestServer(t, []svdrpConnScript{busy, busy, busy})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := c.CreateTimer(ctx, &domain.Timer{Active: true, ChannelID: "C-1-2-3", Day: time.Now(), Start: time.Now(), Stop: time.Now().Add(time.Hour), Title: "X"})
+	if !errors.Is(err, domain.ErrBusy) {
+		t.Fatalf("expected ErrBusy, got %v", err)
+	}
+	if errors.Is(err, domain.ErrConnection) {
+		t.Fatalf("busy must be distinct from connection errors")
+	}
+	st := c.ConnectionState()
+	if st.BusyCount != 3 || st.LastDisconnectReason != "vdr busy" {
+		t.Fatalf("unexpected state %+v", st)
+	}
+}
@@ -0,0 +1,123 @@
+package svdrp_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/githubixx/vdradmin-go/internal/adapters/secondary/svdrp"
+)
+
+type recordingObserver struct {
+	mu         sync.Mutex
+	commands   []string
+	errors     []string
+	reconnects []string
+}
+
+func (o *recordingObserver) ObserveCommand(verb string, d time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.commands = append(o.commands, verb)
+	if err != nil {
+		o.errors = append(o.errors, verb)
+	}
+}
+
+func (o *recordingObserver) ObserveReconnect(verb string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.reconnects = append(o.reconnects, verb)
+}
+
+func TestClient_ObserverSeesCommandsErrorsAndReconnects(t *testing.T) {
+	srv := newSVDRPTestServer(t, []svdrpConnScript{
+		{steps: []svdrpConnStep{{expect: "LSTC", closeAfterRead: true}}},
+		{steps: []svdrpConnStep{
+			{expect: "LSTC", respond: []string{"250 1 C-1-2-3 SomeChannel:provider"}},
+			{expect: "CHAN", respond: []string{"550 Unknown channel"}},
+		}},
+	})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+	obs := &recordingObserver{}
+	c.SetObserver(obs)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if _, err := c.GetChannels(ctx); err != nil {
+		t.Fatalf("GetChannels: %v", err)
+	}
+	_, _ = c.GetCurrentChannel(ctx)
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	if len(obs.commands) != 3 || obs.commands[0] != "LSTC" || obs.commands[1] != "LSTC" || obs.commands[2] != "CHAN" {
+		t.Fatalf("unexpected commands %v", obs.commands)
+	}
+	if len(obs.errors) != 2 || obs.errors[0] != "LSTC" || obs.errors[1] != "CHAN" {
+		t.Fatalf("unexpected errors %v", obs.errors)
+	}
+	if len(obs.reconnects) != 1 || obs.reconnects[0] != "LSTC" {
+		t.Fatalf("unexpected reconnects %v", obs.reconnects)
+	}
+}
+
+func TestClient_TracesCommandsAndRetries(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	defer otel.SetTracerProvider(prev)
+
+	srv := newSVDRPTestServer(t, []svdrpConnScript{
+		{steps: []svdrpConnStep{{expect: "LSTC", closeAfterRead: true}}},
+		{steps: []svdrpConnStep{{expect: "LSTC", respond: []string{"250 1 C-1-2-3 SomeChannel:provider"}}}},
+	})
+	defer srv.Close()
+
+	host, port := srv.Addr()
+	c := svdrp.NewClient(host, port, 2*time.Second)
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	ctx, parent := otel.Tracer("test").Start(ctx, "parent")
+	if _, err := c.GetChannels(ctx); err != nil {
+		t.Fatalf("GetChannels: %v", err)
+	}
+	parent.End()
+
+	var names []string
+	var parentSpan sdktrace.ReadOnlySpan
+	for _, s := range rec.Ended() {
+		if s.Name() == "parent" {
+			parentSpan = s
+			continue
+		}
+		names = append(names, s.Name())
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Fatalf("expected %s to be a child of the caller's span", s.Name())
+		}
+	}
+	want := []string{"svdrp connect", "svdrp LSTC", "svdrp connect", "svdrp LSTC"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected spans %v, got %v", want, names)
+	}
+	if got := rec.Ended()[1].Status().Code; got != codes.Error {
+		t.Fatalf("expected the dropped command to be marked as error, got %v", got)
+	}
+	if evs := parentSpan.Events(); len(evs) != 1 || evs[0].Name != "svdrp retry" {
+		t.Fatalf("expected one retry event on the caller's span, got %+v", evs)
+	}
+}
@@ -39,3 +39,24 @@ func TestInferRecordingTitleFromDir_UsesShowFolder(t *testing.T) {
 		t.Fatalf("inferRecordingTitleFromDir=%q, want %q", got, "Tuff Stuff")
 	}
 }
+
+func TestParseRecording_VDR27_LSTR_NewFlagAndName(t *testing.T) {
+	rec, err := parseRecording("12 01.02.26 20:15 1:30* Krimi~Tatort~Der Fall")
+	if err != nil {
+		t.Fatalf("parseRecording: %v", err)
+	}
+	if !rec.New {
+		t.Fatalf("expected '*' to mark the recording as new")
+	}
+	if rec.Name != "Krimi~Tatort~Der Fall" {
+		t.Fatalf("Name=%q, want the full recording name", rec.Name)
+	}
+
+	rec, err = parseRecording("13 01.02.26 21:45 0:45 Nachrichten")
+	if err != nil {
+		t.Fatalf("parseRecording: %v", err)
+	}
+	if rec.New {
+		t.Fatalf("expected a watched recording without '*'")
+	}
+}
@@ -113,3 +113,31 @@ func FuzzParseTimestamp(f *testing.F) {
 		}
 	})
 }
+
+// FuzzParseEPGEvents tests LSTE parsing including component, VPS, genre and rating lines
+func FuzzParseEPGEvents(f *testing.F) {
+	f.Add("C S19.2E-1-1019-10301 Das Erste HD\nE 4711 1767121200 5400 4E 10\nT Tatort\nS Sub\nD Desc\nG 10 11\nR 12\nX 5 0B deu HD 16:9\nX 2 03 deu stereo\nX 4 44 deu Dolby Digital 5.1\nV 1767121200\ne\nc")
+	f.Add("E 1 0 0\nX 1 03 deu 16:9\nX 9 00 deu UHD\nX 19 00 deu AC-4\nG 2F B3\nR 0\nV 0\ne")
+	f.Add("E 1 1 1\nX\nX 2\nX ff ff\nG\nG zz\nR\nR x\nV\nV -1\ne")
+	f.Add("X 2 03 deu\nG 10\nR 16\nV 1")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("panic on EPG data %q: %v", input, r)
+			}
+		}()
+
+		events := parseEPGEvents(strings.Split(input, "\n"))
+		for _, ev := range events {
+			for _, a := range ev.Audio {
+				if a.Channels <= 0 {
+					t.Errorf("invalid audio channel count %d in %q", a.Channels, input)
+				}
+			}
+			if ev.ParentalRating < 0 {
+				t.Errorf("negative parental rating in %q", input)
+			}
+		}
+	})
+}
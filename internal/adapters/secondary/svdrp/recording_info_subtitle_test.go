@@ -36,6 +36,32 @@ func TestReadRecordingInfoSubtitle_EmptyWhenMissing(t *testing.T) {
 	}
 }
 
+func TestReadRecordingInfoMeta_ChannelAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	info := "C S19.2E-1-1019-10301 Das Erste HD\nE 4711 1772392380 5400 4E 12\nT Tatort\nF 25\nO 17\n"
+	if err := writeFile(dir+"/info", info); err != nil {
+		t.Fatalf("write info: %v", err)
+	}
+	meta, err := readRecordingInfoMeta(dir)
+	if err != nil {
+		t.Fatalf("readRecordingInfoMeta: %v", err)
+	}
+	if meta.ChannelID != "S19.2E-1-1019-10301" || meta.Channel != "Das Erste HD" {
+		t.Fatalf("channel = %q (%q)", meta.ChannelID, meta.Channel)
+	}
+	if meta.Errors != 17 {
+		t.Fatalf("errors = %d, want 17", meta.Errors)
+	}
+
+	// VDR before 2.6 writes no "O" line.
+	if err := writeFile(dir+"/info", "C S19.2E-1-1019-10301 Das Erste HD\nT Tatort\n"); err != nil {
+		t.Fatalf("write info: %v", err)
+	}
+	if meta, err := readRecordingInfoMeta(dir); err != nil || meta.Errors != 0 {
+		t.Fatalf("errors without O line = %d, %v", meta.Errors, err)
+	}
+}
+
 func writeFile(path string, content string) error {
 	// small helper to keep tests compact
 	return os.WriteFile(path, []byte(content), 0644)
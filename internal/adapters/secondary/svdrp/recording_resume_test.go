@@ -0,0 +1,46 @@
+package svdrp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+func TestReadResumePosition_UsesFrameRate(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeFile(dir+"/resume", "I 4500\n"); err != nil {
+		t.Fatalf("write resume: %v", err)
+	}
+	if err := writeFile(dir+"/info", "T Title\nF 50\n"); err != nil {
+		t.Fatalf("write info: %v", err)
+	}
+	got, err := readResumePosition(dir)
+	if err != nil {
+		t.Fatalf("readResumePosition: %v", err)
+	}
+	if got != 90*time.Second {
+		t.Fatalf("position=%v, want 1m30s", got)
+	}
+}
+
+func TestReadResumePosition_DefaultFrameRate(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeFile(dir+"/resume", "I 1500\n"); err != nil {
+		t.Fatalf("write resume: %v", err)
+	}
+	got, err := readResumePosition(dir)
+	if err != nil {
+		t.Fatalf("readResumePosition: %v", err)
+	}
+	if got != time.Minute {
+		t.Fatalf("position=%v, want 1m0s", got)
+	}
+}
+
+func TestReadResumePosition_NotFoundWithoutResumeFile(t *testing.T) {
+	if _, err := readResumePosition(t.TempDir()); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
@@ -33,6 +33,7 @@ type svdrpTestServer struct {
 
 	mu        sync.Mutex
 	accepted  int
+	quits     int
 	closed    bool
 	closeOnce sync.Once
 }
@@ -56,6 +57,13 @@ func newSVDRPTestServer(t *testing.T, scripts []svdrpConnScript) *svdrpTestServe
 
 func (s *svdrpTestServer) Addr() (string, int) { return s.host, s.port }
 
+// Counts returns the number of accepted connections and QUIT commands received.
+func (s *svdrpTestServer) Counts() (accepted, quits int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accepted, s.quits
+}
+
 func (s *svdrpTestServer) Close() {
 	s.closeOnce.Do(func() {
 		s.mu.Lock()
@@ -138,6 +146,9 @@ func (s *svdrpTestServer) handleConn(conn net.Conn, idx int, script svdrpConnScr
 		}
 		cmd := strings.TrimSpace(line)
 		if cmd == "QUIT" {
+			s.mu.Lock()
+			s.quits++
+			s.mu.Unlock()
 			return
 		}
 	}
@@ -14,8 +14,26 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/githubixx/vdradmin-go/internal/application/cutting"
+	"github.com/githubixx/vdradmin-go/internal/application/events"
 )
 
+const tracerName = "github.com/githubixx/vdradmin-go/internal/application/archive"
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // validatePath checks that a path doesn't contain directory traversal sequences.
 // This is a defensive check for paths that should have been validated by the caller.
 func validatePath(path string) error {
@@ -254,7 +272,10 @@ type Plan struct {
 	RecordingDir string
 	InfoPath     string
 	Segments     []string
-	ConcatList   string
+	// Parts, when set, limits the archive to these sections of the segments,
+	// e.g. the parts between the recording's cutting marks.
+	Parts      []cutting.Part
+	ConcatList string
 
 	Profile ArchiveProfile
 	Preview Preview
@@ -337,6 +358,64 @@ func WriteConcatList(dir string, segments []string) (string, error) {
 	return f.Name(), nil
 }
 
+// WriteConcatParts writes a concat demuxer list file for sections of segment
+// files and returns its path. The caller is responsible for deleting the file.
+// The dir path must be validated by the caller before calling this function.
+func WriteConcatParts(dir string, parts []cutting.Part) (string, error) {
+	if len(parts) == 0 {
+		return "", errors.New("parts required")
+	}
+	if err := validatePath(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("mkdir concat dir: %w", err)
+	}
+	f, err := os.CreateTemp(dir, "vdradmin-archive-*.concat")
+	if err != nil {
+		return "", fmt.Errorf("create concat list: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	var b strings.Builder
+	for _, p := range parts {
+		fmt.Fprintf(&b, "file '%s'\n", escapeConcatPath(p.Path))
+		if p.In > 0 {
+			fmt.Fprintf(&b, "inpoint %.3f\n", p.In.Seconds())
+		}
+		if p.Out > 0 {
+			fmt.Fprintf(&b, "outpoint %.3f\n", p.Out.Seconds())
+		}
+	}
+	if _, err := f.WriteString(b.String()); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("write concat list: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("sync concat list: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// LimitToMarks restricts the plan to the parts between the recording's cutting
+// marks. It returns cutting.ErrNoMarks if the recording has none.
+func (p *Plan) LimitToMarks() error {
+	rec, err := cutting.Open(p.RecordingDir)
+	if err != nil {
+		return err
+	}
+	marks, err := rec.Marks()
+	if err != nil {
+		return err
+	}
+	parts, err := rec.Parts(marks)
+	if err != nil {
+		return err
+	}
+	p.Parts = parts
+	return nil
+}
+
 func SplitArgs(s string) []string {
 	// Minimal shell-like splitting: space-separated, no quoting.
 	// Good enough for our config default; can be improved later.
@@ -443,6 +522,7 @@ type Job struct {
 	progress    Progress
 	logLines    []string
 	cancel      context.CancelFunc
+	bus         *events.Bus
 }
 
 func (j *Job) snapshot() JobSnapshot {
@@ -569,12 +649,40 @@ func (j *Job) addLog(line string) {
 	}
 	j.mu.Lock()
 	j.logLines = append(j.logLines, line)
+	index := len(j.logLines) - 1
 	j.mu.Unlock()
+	j.bus.Publish(events.ArchiveLog, events.ArchiveLogPayload{JobID: j.id, Index: index, Line: line})
+}
+
+// publish sends the current job state to the event bus.
+func (j *Job) publish(typ events.Type) {
+	if j.bus == nil {
+		return
+	}
+	j.mu.RLock()
+	payload := events.ArchivePayload{
+		JobID:       j.id,
+		RecordingID: j.recordingID,
+		Status:      string(j.status),
+		Percent:     j.progress.Percent,
+		Speed:       j.progress.Speed,
+		Error:       j.errMsg,
+	}
+	j.mu.RUnlock()
+	j.bus.Publish(typ, payload)
 }
 
 type JobManager struct {
 	mu   sync.RWMutex
 	jobs map[string]*Job
+	bus  *events.Bus
+}
+
+// SetEventBus sets the bus job status, progress and log lines are published to.
+func (m *JobManager) SetEventBus(bus *events.Bus) {
+	m.mu.Lock()
+	m.bus = bus
+	m.mu.Unlock()
 }
 
 func NewJobManager() *JobManager {
@@ -666,18 +774,29 @@ func (m *JobManager) Start(ctx context.Context, plan Plan, instanceID string) (s
 	ctxRun, cancel := context.WithCancel(ctx)
 	j := &Job{id: jobID, instanceID: inst, recordingID: strings.TrimSpace(plan.RecordingID), status: JobQueued, created: time.Now(), preview: plan.Preview, progress: Progress{Raw: map[string]string{}}, cancel: cancel}
 	m.mu.Lock()
+	j.bus = m.bus
 	m.jobs[jobID] = j
 	m.mu.Unlock()
+	j.publish(events.ArchiveStatus)
 
 	go func() {
+		// Jobs outlive the request that started them: trace them as their own root,
+		// linked to the request's span.
+		spanCtx, span := otel.Tracer(tracerName).Start(ctxRun, "archive job",
+			trace.WithNewRoot(),
+			trace.WithLinks(trace.LinkFromContext(ctx)),
+			trace.WithAttributes(attribute.String("archive.job_id", jobID), attribute.String("archive.recording_id", j.recordingID)),
+		)
+
 		j.mu.Lock()
 		j.status = JobRunning
 		j.started = time.Now()
 		j.mu.Unlock()
+		j.publish(events.ArchiveStatus)
 
-		err := runArchive(ctxRun, j, plan)
+		err := runArchive(spanCtx, j, plan)
+		endSpan(span, err)
 		j.mu.Lock()
-		defer j.mu.Unlock()
 		j.ended = time.Now()
 		if err != nil {
 			j.status = JobFailed
@@ -689,12 +808,17 @@ func (m *JobManager) Start(ctx context.Context, plan Plan, instanceID string) (s
 		} else {
 			j.status = JobSuccess
 		}
+		j.mu.Unlock()
+		j.publish(events.ArchiveStatus)
 	}()
 
 	return jobID, nil
 }
 
-func ffprobeDurationSeconds(ctx context.Context, concatList string) (float64, error) {
+func ffprobeDurationSeconds(ctx context.Context, concatList string) (_ float64, err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ffprobe")
+	defer func() { endSpan(span, err) }()
+
 	// Try to compute duration via ffprobe; may not be available.
 	cmd := exec.CommandContext(ctx, "ffprobe",
 		"-v", "error",
@@ -734,7 +858,7 @@ func parseProgressLine(kv string) (key string, val string, ok bool) {
 	return kv[:idx], kv[idx+1:], true
 }
 
-func runArchive(ctx context.Context, job *Job, plan Plan) error {
+func runArchive(ctx context.Context, job *Job, plan Plan) (err error) {
 	// If the job was canceled before the runner starts, avoid touching the filesystem.
 	if err := ctx.Err(); err != nil {
 		return err
@@ -781,7 +905,13 @@ func runArchive(ctx context.Context, job *Job, plan Plan) error {
 	}
 
 	// Create concat list file in the target dir so paths are easy to diagnose.
-	concatList, err := WriteConcatList(plan.Preview.TargetDir, plan.Segments)
+	var concatList string
+	if len(plan.Parts) > 0 {
+		job.addLog(fmt.Sprintf("encoding %d marked part(s)", len(plan.Parts)))
+		concatList, err = WriteConcatParts(plan.Preview.TargetDir, plan.Parts)
+	} else {
+		concatList, err = WriteConcatList(plan.Preview.TargetDir, plan.Segments)
+	}
 	if err != nil {
 		return err
 	}
@@ -811,6 +941,9 @@ func runArchive(ctx context.Context, job *Job, plan Plan) error {
 
 	job.addLog("ffmpeg " + strings.Join(args, " "))
 
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ffmpeg")
+	defer func() { endSpan(span, err) }()
+
 	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -862,6 +995,10 @@ func runArchive(ctx context.Context, job *Job, plan Plan) error {
 				job.progress.Speed = v
 			}
 			job.mu.Unlock()
+			// ffmpeg terminates each progress block with a "progress" key.
+			if k == "progress" {
+				job.publish(events.ArchiveProgress)
+			}
 		}
 		close(progressDone)
 	}()
@@ -1,11 +1,15 @@
 package archive
 
 import (
+	"encoding/binary"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/cutting"
 )
 
 func TestParseVDRInfo_Movie(t *testing.T) {
@@ -124,6 +128,52 @@ func TestWriteConcatList_EscapesSpecialChars(t *testing.T) {
 	}
 }
 
+func TestWriteConcatParts_AddsInAndOutPoints(t *testing.T) {
+	dir := t.TempDir()
+	listPath, err := WriteConcatParts(dir, []cutting.Part{
+		{Path: "/video/Film/1.rec/00001.ts", In: 1500 * time.Millisecond},
+		{Path: "/video/Film/1.rec/00002.ts", Out: 90 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("WriteConcatParts: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Remove(listPath) })
+	b, err := os.ReadFile(listPath)
+	if err != nil {
+		t.Fatalf("read list: %v", err)
+	}
+	want := "file '/video/Film/1.rec/00001.ts'\ninpoint 1.500\nfile '/video/Film/1.rec/00002.ts'\noutpoint 90.000\n"
+	if string(b) != want {
+		t.Fatalf("concat list = %q, want %q", b, want)
+	}
+}
+
+func TestPlan_LimitToMarks(t *testing.T) {
+	dir := t.TempDir()
+	// 100 frames in 00001.ts at the default 25 fps.
+	index := make([]byte, 0, 800)
+	for i := 0; i < 100; i++ {
+		index = binary.LittleEndian.AppendUint64(index, uint64(1)<<48|uint64(i))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index"), index, 0644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+	plan := Plan{RecordingDir: dir}
+	if err := plan.LimitToMarks(); !errors.Is(err, cutting.ErrNoMarks) {
+		t.Fatalf("expected no marks, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "marks"), []byte("0:00:01.01\n0:00:02.01\n"), 0644); err != nil {
+		t.Fatalf("write marks: %v", err)
+	}
+	if err := plan.LimitToMarks(); err != nil {
+		t.Fatalf("LimitToMarks: %v", err)
+	}
+	if len(plan.Parts) != 1 || plan.Parts[0].In != time.Second || plan.Parts[0].Out != 2*time.Second {
+		t.Fatalf("unexpected parts %+v", plan.Parts)
+	}
+}
+
 func TestParseProgressLine(t *testing.T) {
 	k, v, ok := parseProgressLine("out_time_ms=123")
 	if !ok || k != "out_time_ms" || v != "123" {
@@ -0,0 +1,144 @@
+package cutting
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// indexEntrySize is the size of a frame entry in the index file of a TS
+// recording: a 40 bit file offset, 7 reserved bits, the independent frame flag
+// and the 16 bit number of the segment file, little-endian.
+const indexEntrySize = 8
+
+// Index maps the frames of a recording to its segment files (00001.ts, ...).
+type Index struct {
+	// Frames is the number of frames in the recording.
+	Frames int
+	files  []indexFile
+}
+
+type indexFile struct {
+	number int
+	first  int
+}
+
+// Part is a section of a segment file. Zero In and Out mean the start and the
+// end of the file.
+type Part struct {
+	Path string
+	In   time.Duration
+	Out  time.Duration
+}
+
+// Index reads the recording's index file.
+func (r Recording) Index() (Index, error) {
+	b, err := os.ReadFile(filepath.Join(r.Dir, "index"))
+	if err != nil {
+		return Index{}, fmt.Errorf("read index: %w", err)
+	}
+	return parseIndex(b)
+}
+
+func parseIndex(b []byte) (Index, error) {
+	n := len(b) / indexEntrySize
+	if n == 0 {
+		return Index{}, errors.New("empty index")
+	}
+	idx := Index{Frames: n}
+	last := -1
+	for i := 0; i < n; i++ {
+		v := binary.LittleEndian.Uint64(b[i*indexEntrySize:])
+		number := int(v >> 48)
+		if number != last {
+			idx.files = append(idx.files, indexFile{number: number, first: i})
+			last = number
+		}
+	}
+	return idx, nil
+}
+
+// Length returns the length of the recording.
+func (idx Index) Length(fps float64) time.Duration {
+	return FrameToDuration(idx.Frames, fps)
+}
+
+// Locate returns the segment file holding a frame and the frame's number
+// within that file.
+func (idx Index) Locate(frame int) (file string, offset int) {
+	if len(idx.files) == 0 {
+		return segmentFileName(1), frame
+	}
+	i := sort.Search(len(idx.files), func(i int) bool { return idx.files[i].first > frame }) - 1
+	if i < 0 {
+		i = 0
+	}
+	f := idx.files[i]
+	return segmentFileName(f.number), frame - f.first
+}
+
+// SegmentFrames returns the number of frames the segments keep.
+func (idx Index) SegmentFrames(segments []Segment) int {
+	total := 0
+	for _, seg := range segments {
+		end := seg.End
+		if end < 0 || end > idx.Frames {
+			end = idx.Frames
+		}
+		if end > seg.Start {
+			total += end - seg.Start
+		}
+	}
+	return total
+}
+
+// Parts returns the sections of the recording's segment files that the marks
+// keep, in order.
+func (r Recording) Parts(marks []Mark) ([]Part, error) {
+	segments := Segments(marks)
+	if len(segments) == 0 {
+		return nil, ErrNoMarks
+	}
+	idx, err := r.Index()
+	if err != nil {
+		return nil, err
+	}
+	fps := r.fps()
+	var parts []Part
+	for _, seg := range segments {
+		end := seg.End
+		if end < 0 || end > idx.Frames {
+			end = idx.Frames
+		}
+		for i, f := range idx.files {
+			next := idx.Frames
+			if i+1 < len(idx.files) {
+				next = idx.files[i+1].first
+			}
+			from, to := max(seg.Start, f.first), min(end, next)
+			if from >= to {
+				continue
+			}
+			p := Part{Path: filepath.Join(r.Dir, segmentFileName(f.number))}
+			if from > f.first {
+				p.In = FrameToDuration(from-f.first, fps)
+			}
+			if to < next {
+				p.Out = FrameToDuration(to-f.first, fps)
+			}
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, ErrNoMarks
+	}
+	return parts, nil
+}
+
+func segmentFileName(number int) string {
+	return fmt.Sprintf("%05d.ts", number)
+}
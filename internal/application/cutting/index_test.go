@@ -0,0 +1,72 @@
+package cutting
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeIndex writes an index file with the given number of frames per segment
+// file.
+func writeIndex(t *testing.T, dir string, framesPerFile ...int) {
+	t.Helper()
+	var b []byte
+	for i, n := range framesPerFile {
+		for f := 0; f < n; f++ {
+			v := uint64(i+1)<<48 | uint64(f*1000)
+			b = binary.LittleEndian.AppendUint64(b, v)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index"), b, 0644); err != nil {
+		t.Fatalf("write index: %v", err)
+	}
+}
+
+func TestIndex_Locate(t *testing.T) {
+	dir := t.TempDir()
+	writeIndex(t, dir, 100, 50)
+	idx, err := Recording{Dir: dir, FPS: 25}.Index()
+	if err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if idx.Frames != 150 || idx.Length(25) != 6*time.Second {
+		t.Fatalf("unexpected index: %d frames", idx.Frames)
+	}
+	if file, off := idx.Locate(120); file != "00002.ts" || off != 20 {
+		t.Fatalf("Locate(120) = %s, %d", file, off)
+	}
+	if file, off := idx.Locate(10); file != "00001.ts" || off != 10 {
+		t.Fatalf("Locate(10) = %s, %d", file, off)
+	}
+}
+
+func TestRecording_PartsSpanSegmentFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeIndex(t, dir, 100, 100)
+	rec := Recording{Dir: dir, FPS: 25}
+
+	parts, err := rec.Parts([]Mark{{Frame: 50}, {Frame: 125}, {Frame: 175}})
+	if err != nil {
+		t.Fatalf("Parts: %v", err)
+	}
+	want := []Part{
+		{Path: filepath.Join(dir, "00001.ts"), In: 2 * time.Second},
+		{Path: filepath.Join(dir, "00002.ts"), Out: time.Second},
+		{Path: filepath.Join(dir, "00002.ts"), In: 3 * time.Second},
+	}
+	if len(parts) != len(want) {
+		t.Fatalf("unexpected parts %+v", parts)
+	}
+	for i := range want {
+		if parts[i] != want[i] {
+			t.Fatalf("part %d = %+v, want %+v", i, parts[i], want[i])
+		}
+	}
+
+	if _, err := rec.Parts(nil); !errors.Is(err, ErrNoMarks) {
+		t.Fatalf("expected no marks, got %v", err)
+	}
+}
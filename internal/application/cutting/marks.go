@@ -0,0 +1,257 @@
+// Package cutting reads and writes the cutting data of VDR recordings: the
+// marks file, the frame index and frame thumbnails, and tracks the progress of
+// VDR's cutter.
+package cutting
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFramesPerSecond is used when a recording's info file has no F line.
+const DefaultFramesPerSecond = 25.0
+
+// ErrNoMarks is returned when a recording has no cutting marks.
+var ErrNoMarks = errors.New("no cutting marks")
+
+// Mark is a cutting mark at a frame of a recording. Pairs of marks enclose the
+// parts VDR's cutter keeps; an odd last mark keeps the rest of the recording.
+type Mark struct {
+	Frame   int
+	Comment string
+}
+
+// Segment is a part of a recording between two marks, by frame. End is -1 for
+// a part that runs to the end of the recording.
+type Segment struct {
+	Start int
+	End   int
+}
+
+// Recording gives access to the cutting data in a VDR recording directory.
+type Recording struct {
+	Dir string
+	FPS float64
+}
+
+// Open returns the recording in dir with the frame rate from its info file.
+// The dir path must be validated by the caller to be within the video directory.
+func Open(dir string) (Recording, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return Recording{}, errors.New("recording dir is required")
+	}
+	if strings.Contains(dir, "..") {
+		return Recording{}, fmt.Errorf("path contains '..': %s", dir)
+	}
+	st, err := os.Stat(dir)
+	if err != nil {
+		return Recording{}, fmt.Errorf("stat recording dir: %w", err)
+	}
+	if !st.IsDir() {
+		return Recording{}, fmt.Errorf("not a directory: %s", dir)
+	}
+	return Recording{Dir: dir, FPS: readFramesPerSecond(dir)}, nil
+}
+
+// readFramesPerSecond reads the F line of a recording's info file.
+func readFramesPerSecond(dir string) float64 {
+	f, err := os.Open(filepath.Join(dir, "info"))
+	if err != nil {
+		return DefaultFramesPerSecond
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "F "); ok {
+			if fps, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && fps > 0 {
+				return fps
+			}
+		}
+	}
+	return DefaultFramesPerSecond
+}
+
+// Marks reads the recording's marks file, sorted by frame. A recording without
+// marks file has no marks.
+func (r Recording) Marks() ([]Mark, error) {
+	f, err := os.Open(filepath.Join(r.Dir, "marks"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open marks: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return ParseMarks(f, r.fps())
+}
+
+// SaveMarks replaces the recording's marks file. Without marks, the file is
+// removed.
+func (r Recording) SaveMarks(marks []Mark) error {
+	path := filepath.Join(r.Dir, "marks")
+	if len(marks) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove marks: %w", err)
+		}
+		return nil
+	}
+	tmp, err := os.CreateTemp(r.Dir, ".marks-*")
+	if err != nil {
+		return fmt.Errorf("create marks: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.WriteString(FormatMarks(marks, r.fps())); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write marks: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write marks: %w", err)
+	}
+	// VDR runs as its own user; keep the marks file readable and writable for it.
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("chmod marks: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename marks: %w", err)
+	}
+	return nil
+}
+
+// Position returns the time of a frame in the recording.
+func (r Recording) Position(frame int) time.Duration {
+	return FrameToDuration(frame, r.fps())
+}
+
+func (r Recording) fps() float64 {
+	if r.FPS > 0 {
+		return r.FPS
+	}
+	return DefaultFramesPerSecond
+}
+
+// ParseMarks parses a VDR marks file. Each line holds a position as h:mm:ss.ff,
+// where ff is the frame within the second counting from 1, and an optional
+// comment. Lines that do not parse are skipped like VDR does.
+func ParseMarks(r io.Reader, fps float64) ([]Mark, error) {
+	var marks []Mark
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		pos, comment, _ := strings.Cut(line, " ")
+		frame, err := ParseHMSF(pos, fps)
+		if err != nil {
+			continue
+		}
+		marks = append(marks, Mark{Frame: frame, Comment: strings.TrimSpace(comment)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read marks: %w", err)
+	}
+	SortMarks(marks)
+	return marks, nil
+}
+
+// FormatMarks formats marks as a VDR marks file.
+func FormatMarks(marks []Mark, fps float64) string {
+	sorted := append([]Mark(nil), marks...)
+	SortMarks(sorted)
+	var b strings.Builder
+	for _, m := range sorted {
+		b.WriteString(FormatHMSF(m.Frame, fps))
+		if c := strings.Join(strings.Fields(m.Comment), " "); c != "" {
+			b.WriteByte(' ')
+			b.WriteString(c)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// SortMarks sorts marks by frame.
+func SortMarks(marks []Mark) {
+	sort.SliceStable(marks, func(i, j int) bool { return marks[i].Frame < marks[j].Frame })
+}
+
+// Segments returns the parts of a recording the marks keep.
+func Segments(marks []Mark) []Segment {
+	sorted := append([]Mark(nil), marks...)
+	SortMarks(sorted)
+	var out []Segment
+	for i := 0; i < len(sorted); i += 2 {
+		seg := Segment{Start: sorted[i].Frame, End: -1}
+		if i+1 < len(sorted) {
+			seg.End = sorted[i+1].Frame
+		}
+		if seg.End >= 0 && seg.End <= seg.Start {
+			continue
+		}
+		out = append(out, seg)
+	}
+	return out
+}
+
+// ParseHMSF parses a position given as h:mm:ss.ff, h:mm:ss, mm:ss or ss into a
+// frame number; ff is the frame within the second counting from 1.
+func ParseHMSF(s string, fps float64) (int, error) {
+	if fps <= 0 {
+		fps = DefaultFramesPerSecond
+	}
+	s = strings.TrimSpace(s)
+	clock, frac, hasFrame := strings.Cut(s, ".")
+	parts := strings.Split(clock, ":")
+	if s == "" || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid position %q", s)
+	}
+	secs := 0
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid position %q", s)
+		}
+		secs = secs*60 + n
+	}
+	frame := 1
+	if hasFrame {
+		n, err := strconv.Atoi(frac)
+		if err != nil || n < 1 || float64(n) > math.Ceil(fps) {
+			return 0, fmt.Errorf("invalid frame in position %q", s)
+		}
+		frame = n
+	}
+	return int(math.Round(float64(secs)*fps)) + frame - 1, nil
+}
+
+// FormatHMSF formats a frame number as h:mm:ss.ff like VDR.
+func FormatHMSF(frame int, fps float64) string {
+	if fps <= 0 {
+		fps = DefaultFramesPerSecond
+	}
+	if frame < 0 {
+		frame = 0
+	}
+	secs, frac := math.Modf((float64(frame) + 0.5) / fps)
+	f := int(frac * fps)
+	s := int(secs)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", s/3600, s/60%60, s%60, f+1)
+}
+
+// FrameToDuration converts a frame number into a time in the recording.
+func FrameToDuration(frame int, fps float64) time.Duration {
+	if fps <= 0 {
+		fps = DefaultFramesPerSecond
+	}
+	return time.Duration(float64(frame) / fps * float64(time.Second))
+}
@@ -0,0 +1,104 @@
+package cutting
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMarks_SortsAndSkipsInvalidLines(t *testing.T) {
+	in := "0:30:00.01 end of film\n0:00:12.13\nbogus\n\n1:00:00.26 credits\n"
+	marks, err := ParseMarks(strings.NewReader(in), 25)
+	if err != nil {
+		t.Fatalf("ParseMarks: %v", err)
+	}
+	if len(marks) != 2 {
+		t.Fatalf("expected two valid marks, got %+v", marks)
+	}
+	if marks[0].Frame != 12*25+12 || marks[1].Frame != 30*60*25 || marks[1].Comment != "end of film" {
+		t.Fatalf("unexpected marks %+v", marks)
+	}
+}
+
+func TestFormatHMSF_RoundTrips(t *testing.T) {
+	for _, fps := range []float64{25, 50, 29.97} {
+		for _, frame := range []int{0, 1, 24, 25, 12345, 180000} {
+			s := FormatHMSF(frame, fps)
+			got, err := ParseHMSF(s, fps)
+			if err != nil || got != frame {
+				t.Fatalf("fps %v: frame %d -> %q -> %d, %v", fps, frame, s, got, err)
+			}
+		}
+	}
+	if got := FormatHMSF(25*3725+4, 25); got != "1:02:05.05" {
+		t.Fatalf("FormatHMSF = %q", got)
+	}
+}
+
+func TestParseHMSF_Forms(t *testing.T) {
+	cases := map[string]int{
+		"90":        90 * 25,
+		"1:30":      90 * 25,
+		"0:01:30":   90 * 25,
+		"0:01:30.3": 90*25 + 2,
+	}
+	for in, want := range cases {
+		if got, err := ParseHMSF(in, 25); err != nil || got != want {
+			t.Fatalf("ParseHMSF(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "x", "1:60", "0:00:01.0", "0:00:01.26", "1:2:3:4"} {
+		if _, err := ParseHMSF(in, 25); err == nil {
+			t.Fatalf("expected %q to be rejected", in)
+		}
+	}
+}
+
+func TestSegments_PairsMarks(t *testing.T) {
+	got := Segments([]Mark{{Frame: 900}, {Frame: 100}, {Frame: 500}})
+	if len(got) != 2 || got[0] != (Segment{Start: 100, End: 500}) || got[1] != (Segment{Start: 900, End: -1}) {
+		t.Fatalf("unexpected segments %+v", got)
+	}
+}
+
+func TestRecording_SaveAndReadMarks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "info"), []byte("T Film\nF 50\n"), 0644); err != nil {
+		t.Fatalf("write info: %v", err)
+	}
+	rec, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if rec.FPS != 50 {
+		t.Fatalf("expected the frame rate from info, got %v", rec.FPS)
+	}
+	if marks, err := rec.Marks(); err != nil || len(marks) != 0 {
+		t.Fatalf("expected no marks yet, got %+v, %v", marks, err)
+	}
+
+	want := []Mark{{Frame: 3000, Comment: "ads"}, {Frame: 100}}
+	if err := rec.SaveMarks(want); err != nil {
+		t.Fatalf("SaveMarks: %v", err)
+	}
+	b, _ := os.ReadFile(filepath.Join(dir, "marks"))
+	if string(b) != "0:00:02.01\n0:01:00.01 ads\n" {
+		t.Fatalf("unexpected marks file %q", b)
+	}
+	got, err := rec.Marks()
+	if err != nil || len(got) != 2 || got[0].Frame != 100 || got[1].Comment != "ads" {
+		t.Fatalf("unexpected marks %+v, %v", got, err)
+	}
+	if rec.Position(got[1].Frame) != time.Minute {
+		t.Fatalf("unexpected position %v", rec.Position(got[1].Frame))
+	}
+
+	if err := rec.SaveMarks(nil); err != nil {
+		t.Fatalf("SaveMarks(nil): %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "marks")); !os.IsNotExist(err) {
+		t.Fatalf("expected the marks file to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package cutting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/githubixx/vdradmin-go/internal/application/cutting"
+
+// thumbnailTimeout bounds a single ffmpeg frame grab.
+const thumbnailTimeout = 20 * time.Second
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Thumbnail returns a JPEG of the recording at the given frame, scaled to
+// width pixels. It runs ffmpeg on the segment file holding the frame.
+func (r Recording) Thumbnail(ctx context.Context, frame, width int) (_ []byte, err error) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "ffmpeg thumbnail", trace.WithAttributes(attribute.Int("cutting.frame", frame)))
+	defer func() { endSpan(span, err) }()
+
+	if width <= 0 {
+		width = 320
+	}
+	file, offset := segmentFileName(1), frame
+	if idx, err := r.Index(); err == nil {
+		file, offset = idx.Locate(frame)
+	}
+	at := FrameToDuration(offset, r.fps())
+
+	ctx, cancel := context.WithTimeout(ctx, thumbnailTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner",
+		"-loglevel", "error",
+		"-ss", strconv.FormatFloat(at.Seconds(), 'f', 3, 64),
+		"-i", filepath.Join(r.Dir, file),
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale=%d:-2", width),
+		"-f", "image2",
+		"-c:v", "mjpeg",
+		"pipe:1",
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("ffmpeg failed: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("ffmpeg failed: %w", err)
+	}
+	if len(out) == 0 {
+		return nil, errors.New("ffmpeg returned no image")
+	}
+	return out, nil
+}
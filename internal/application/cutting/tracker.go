@@ -0,0 +1,158 @@
+package cutting
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CutStatus is the state of a cut started with VDR's EDIT command.
+type CutStatus string
+
+const (
+	CutRunning CutStatus = "running"
+	CutDone    CutStatus = "done"
+	CutFailed  CutStatus = "failed"
+)
+
+// DefaultStallTimeout is how long the edited recording may stop growing before
+// a cut counts as finished or failed.
+const DefaultStallTimeout = time.Minute
+
+// CutProgress describes a cut. Percent is only set when the length of the
+// marked parts is known.
+type CutProgress struct {
+	Path      string
+	Title     string
+	TargetDir string
+	Status    CutStatus
+	Percent   float64
+	Started   time.Time
+	Ended     time.Time
+	Error     string
+}
+
+// Tracker follows the cuts VDR runs. VDR does not report the cutter's progress
+// over SVDRP, so the tracker watches the index file of the edited recording
+// grow towards the number of frames the marks keep.
+type Tracker struct {
+	mu           sync.Mutex
+	cuts         map[string]*cut
+	now          func() time.Time
+	stallTimeout time.Duration
+}
+
+type cut struct {
+	progress   CutProgress
+	expected   int
+	frames     int
+	seen       bool
+	lastChange time.Time
+}
+
+// NewTracker returns an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{cuts: make(map[string]*cut), now: time.Now, stallTimeout: DefaultStallTimeout}
+}
+
+// Track registers a cut of the recording with the given VDR path that was just
+// started, replacing an earlier one.
+func (t *Tracker) Track(path, title string, rec Recording, marks []Mark) {
+	expected := 0
+	if idx, err := rec.Index(); err == nil {
+		expected = idx.SegmentFrames(Segments(marks))
+	}
+	now := t.now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cuts[path] = &cut{
+		progress: CutProgress{
+			Path:      path,
+			Title:     title,
+			TargetDir: EditedDir(rec.Dir),
+			Status:    CutRunning,
+			Started:   now,
+		},
+		expected:   expected,
+		lastChange: now,
+	}
+}
+
+// Active reports whether a cut of the recording is running.
+func (t *Tracker) Active(path string) bool {
+	p, ok := t.Status(path)
+	return ok && p.Status == CutRunning
+}
+
+// Status returns the progress of the last cut of a recording.
+func (t *Tracker) Status(path string) (CutProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.cuts[path]
+	if !ok {
+		return CutProgress{}, false
+	}
+	if c.progress.Status == CutRunning {
+		t.update(c)
+	}
+	return c.progress, true
+}
+
+// update refreshes a running cut from the edited recording's index file.
+func (t *Tracker) update(c *cut) {
+	now := t.now()
+	frames := -1
+	if st, err := os.Stat(filepath.Join(c.progress.TargetDir, "index")); err == nil {
+		frames = int(st.Size() / indexEntrySize)
+	}
+
+	switch {
+	case frames < 0 && c.seen:
+		// VDR removes the edited recording when cutting fails.
+		t.finish(c, CutFailed, "VDR removed the edited recording")
+		return
+	case frames >= 0 && frames != c.frames:
+		c.seen = true
+		c.frames = frames
+		c.lastChange = now
+	}
+
+	if c.expected > 0 {
+		c.progress.Percent = min(float64(c.frames)/float64(c.expected)*100, 100)
+		if c.frames >= c.expected {
+			t.finish(c, CutDone, "")
+			return
+		}
+	}
+	if now.Sub(c.lastChange) < t.stallTimeout {
+		return
+	}
+	switch {
+	case !c.seen:
+		t.finish(c, CutFailed, "VDR did not create the edited recording")
+	case c.expected == 0 || float64(c.frames) >= 0.95*float64(c.expected):
+		// The cutter keeps whole groups of pictures, so the result may be a
+		// little shorter than the marks.
+		t.finish(c, CutDone, "")
+	default:
+		t.finish(c, CutFailed, "the edited recording stopped growing")
+	}
+}
+
+func (t *Tracker) finish(c *cut, status CutStatus, msg string) {
+	c.progress.Status = status
+	c.progress.Error = msg
+	c.progress.Ended = t.now()
+	if status == CutDone {
+		c.progress.Percent = 100
+	}
+}
+
+// EditedDir returns the directory VDR's cutter writes the edited version of a
+// recording to: the recording's name gets a '%' prefix.
+func EditedDir(dir string) string {
+	dir = filepath.Clean(dir)
+	nameDir := filepath.Dir(dir)
+	return filepath.Join(filepath.Dir(nameDir), "%"+filepath.Base(nameDir), filepath.Base(dir))
+}
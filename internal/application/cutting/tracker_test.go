@@ -0,0 +1,97 @@
+package cutting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestTracker(now *time.Time) *Tracker {
+	t := NewTracker()
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func TestEditedDir(t *testing.T) {
+	got := EditedDir("/video/Krimi/Der_Fall/2026-02-01.20.15.1-0.rec")
+	if got != "/video/Krimi/%Der_Fall/2026-02-01.20.15.1-0.rec" {
+		t.Fatalf("EditedDir = %q", got)
+	}
+}
+
+func TestTracker_FollowsEditedRecording(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "Film", "2026-02-01.20.15.1-0.rec")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeIndex(t, src, 200)
+	now := time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC)
+	tr := newTestTracker(&now)
+
+	tr.Track("1", "Film", Recording{Dir: src, FPS: 25}, []Mark{{Frame: 0}, {Frame: 100}})
+	if p, ok := tr.Status("1"); !ok || p.Status != CutRunning || p.Percent != 0 {
+		t.Fatalf("expected a running cut, got %+v", p)
+	}
+
+	dst := EditedDir(src)
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeIndex(t, dst, 40)
+	now = now.Add(5 * time.Second)
+	if p, _ := tr.Status("1"); p.Status != CutRunning || p.Percent != 40 {
+		t.Fatalf("expected 40%%, got %+v", p)
+	}
+
+	writeIndex(t, dst, 100)
+	if p, _ := tr.Status("1"); p.Status != CutDone || p.Percent != 100 {
+		t.Fatalf("expected the cut to be done, got %+v", p)
+	}
+	if tr.Active("1") {
+		t.Fatalf("expected no active cut")
+	}
+}
+
+func TestTracker_FailsWhenNothingHappens(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "Film", "2026-02-01.20.15.1-0.rec")
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC)
+	tr := newTestTracker(&now)
+	tr.Track("1", "Film", Recording{Dir: src, FPS: 25}, []Mark{{Frame: 0}, {Frame: 100}})
+
+	now = now.Add(DefaultStallTimeout)
+	if p, _ := tr.Status("1"); p.Status != CutFailed || p.Error == "" {
+		t.Fatalf("expected the cut to fail, got %+v", p)
+	}
+}
+
+func TestTracker_FailsWhenEditedRecordingIsRemoved(t *testing.T) {
+	root := t.TempDir()
+	src := filepath.Join(root, "Film", "2026-02-01.20.15.1-0.rec")
+	dst := EditedDir(src)
+	for _, dir := range []string{src, dst} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeIndex(t, src, 200)
+	writeIndex(t, dst, 10)
+	now := time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC)
+	tr := newTestTracker(&now)
+	tr.Track("1", "Film", Recording{Dir: src, FPS: 25}, []Mark{{Frame: 0}, {Frame: 100}})
+	if p, _ := tr.Status("1"); p.Status != CutRunning {
+		t.Fatalf("expected a running cut, got %+v", p)
+	}
+
+	if err := os.RemoveAll(filepath.Dir(dst)); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := tr.Status("1"); p.Status != CutFailed {
+		t.Fatalf("expected the cut to fail, got %+v", p)
+	}
+}
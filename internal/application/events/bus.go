@@ -0,0 +1,200 @@
+// Package events provides the in-process event bus used to push state changes
+// (timers, recordings, archive jobs, VDR state) to subscribers such as the
+// /events Server-Sent Events endpoint.
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Type identifies the kind of an event. It is used as the SSE event name.
+type Type string
+
+const (
+	TimerCreated Type = "timer.created"
+	TimerUpdated Type = "timer.updated"
+	TimerDeleted Type = "timer.deleted"
+
+	RecordingStarted  Type = "recording.started"
+	RecordingFinished Type = "recording.finished"
+
+	ArchiveStatus   Type = "archive.status"
+	ArchiveProgress Type = "archive.progress"
+	ArchiveLog      Type = "archive.log"
+
+	ChannelChanged Type = "channel.changed"
+
+	VDRDisconnected Type = "vdr.disconnected"
+	VDRConnected    Type = "vdr.connected"
+)
+
+// Event is a single published state change.
+type Event struct {
+	// ID increases monotonically per bus.
+	ID   uint64
+	Type Type
+	Time time.Time
+	// Data is the event payload; one of the payload types of this package.
+	Data any
+}
+
+// TimerPayload describes a created, changed or deleted timer.
+// For deleted timers only ID is set.
+type TimerPayload struct {
+	ID        int       `json:"id"`
+	Active    bool      `json:"active"`
+	ChannelID string    `json:"channel_id,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Start     time.Time `json:"start,omitzero"`
+	Stop      time.Time `json:"stop,omitzero"`
+	EventID   int       `json:"event_id,omitempty"`
+}
+
+// RecordingPayload describes a recording started or finished by a timer.
+type RecordingPayload struct {
+	TimerID   int       `json:"timer_id"`
+	ChannelID string    `json:"channel_id"`
+	Title     string    `json:"title"`
+	Start     time.Time `json:"start"`
+	Stop      time.Time `json:"stop"`
+}
+
+// ArchivePayload describes the state of an archive job.
+type ArchivePayload struct {
+	JobID       string  `json:"job_id"`
+	RecordingID string  `json:"recording_id"`
+	Status      string  `json:"status"`
+	Percent     float64 `json:"percent"`
+	Speed       string  `json:"speed,omitempty"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// ArchiveLogPayload is a single log line of an archive job.
+// Index is the position of the line in the job log.
+type ArchiveLogPayload struct {
+	JobID string `json:"job_id"`
+	Index int    `json:"index"`
+	Line  string `json:"line"`
+}
+
+// ChannelPayload describes the current channel of VDR.
+type ChannelPayload struct {
+	ChannelID string `json:"channel_id"`
+	Previous  string `json:"previous,omitempty"`
+}
+
+// ConnectivityPayload describes a change of the VDR connection state.
+type ConnectivityPayload struct {
+	Error string `json:"error,omitempty"`
+}
+
+// DefaultBuffer is the number of events buffered per subscriber.
+const DefaultBuffer = 64
+
+// Bus fans out published events to subscribers. Publishing never blocks:
+// events for subscribers whose buffer is full are dropped and counted.
+// A nil *Bus is valid and discards all events.
+type Bus struct {
+	mu     sync.RWMutex
+	nextID uint64
+	subs   map[*Subscription]struct{}
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// NewBus creates a new event bus.
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{}), now: time.Now}
+}
+
+// Subscription receives the events of a bus on C until Close is called.
+type Subscription struct {
+	C <-chan Event
+
+	bus     *Bus
+	ch      chan Event
+	types   map[Type]bool
+	dropped uint64
+	once    sync.Once
+}
+
+// Subscribe registers a subscriber for the given event types (all types if none are given).
+// buffer <= 0 uses DefaultBuffer.
+func (b *Bus) Subscribe(buffer int, types ...Type) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	ch := make(chan Event, buffer)
+	sub := &Subscription{C: ch, bus: b, ch: ch}
+	if len(types) > 0 {
+		sub.types = make(map[Type]bool, len(types))
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+	if b == nil {
+		close(ch)
+		return sub
+	}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Close unsubscribes and closes C.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		if s.bus == nil {
+			return
+		}
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		close(s.ch)
+		s.bus.mu.Unlock()
+	})
+}
+
+// Dropped returns the number of events dropped because the subscriber was too slow.
+func (s *Subscription) Dropped() uint64 {
+	if s.bus == nil {
+		return 0
+	}
+	s.bus.mu.RLock()
+	defer s.bus.mu.RUnlock()
+	return s.dropped
+}
+
+// Publish sends an event to all interested subscribers.
+func (b *Bus) Publish(typ Type, data any) {
+	if b == nil {
+		return
+	}
+	// The write lock serializes IDs and protects channel sends against Close.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	ev := Event{ID: b.nextID, Type: typ, Time: b.now(), Data: data}
+	for sub := range b.subs {
+		if sub.types != nil && !sub.types[typ] {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			sub.dropped++
+		}
+	}
+}
+
+// SubscriberCount returns the number of active subscriptions.
+func (b *Bus) SubscriberCount() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
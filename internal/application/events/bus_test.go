@@ -0,0 +1,78 @@
+package events
+
+import (
+	"testing"
+)
+
+func TestBus_PublishFansOutByType(t *testing.T) {
+	bus := NewBus()
+	all := bus.Subscribe(4)
+	timers := bus.Subscribe(4, TimerCreated)
+	defer all.Close()
+	defer timers.Close()
+
+	bus.Publish(ArchiveLog, ArchiveLogPayload{JobID: "1", Line: "x"})
+	bus.Publish(TimerCreated, TimerPayload{ID: 3})
+
+	if ev := <-all.C; ev.Type != ArchiveLog || ev.ID != 1 {
+		t.Fatalf("unexpected first event %+v", ev)
+	}
+	if ev := <-all.C; ev.Type != TimerCreated || ev.ID != 2 {
+		t.Fatalf("unexpected second event %+v", ev)
+	}
+	ev := <-timers.C
+	if ev.Type != TimerCreated || ev.Data.(TimerPayload).ID != 3 {
+		t.Fatalf("unexpected filtered event %+v", ev)
+	}
+	select {
+	case ev := <-timers.C:
+		t.Fatalf("expected no further events, got %+v", ev)
+	default:
+	}
+}
+
+func TestBus_DropsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1)
+	defer sub.Close()
+
+	bus.Publish(ChannelChanged, ChannelPayload{ChannelID: "a"})
+	bus.Publish(ChannelChanged, ChannelPayload{ChannelID: "b"})
+
+	if got := sub.Dropped(); got != 1 {
+		t.Fatalf("expected 1 dropped event, got %d", got)
+	}
+	if ev := <-sub.C; ev.Data.(ChannelPayload).ChannelID != "a" {
+		t.Fatalf("expected the first event to be kept, got %+v", ev)
+	}
+}
+
+func TestBus_CloseUnsubscribes(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1)
+	if bus.SubscriberCount() != 1 {
+		t.Fatalf("expected 1 subscriber")
+	}
+	sub.Close()
+	sub.Close()
+	if bus.SubscriberCount() != 0 {
+		t.Fatalf("expected no subscribers after Close")
+	}
+	if _, ok := <-sub.C; ok {
+		t.Fatalf("expected closed channel")
+	}
+	bus.Publish(VDRConnected, ConnectivityPayload{})
+}
+
+func TestBus_NilIsNoop(t *testing.T) {
+	var bus *Bus
+	bus.Publish(TimerDeleted, TimerPayload{ID: 1})
+	sub := bus.Subscribe(1)
+	defer sub.Close()
+	if _, ok := <-sub.C; ok {
+		t.Fatalf("expected closed channel for nil bus")
+	}
+	if bus.SubscriberCount() != 0 {
+		t.Fatalf("expected no subscribers")
+	}
+}
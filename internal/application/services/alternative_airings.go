@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// AlternativeAiring is another broadcast of the program a timer records.
+type AlternativeAiring struct {
+	Event domain.EPGEvent
+	// Timer is the original timer moved to this airing (same ID, margins applied).
+	Timer domain.Timer
+	// ResolvesConflict is set when moving the timer here makes no recording fail.
+	ResolvesConflict bool
+	// Failed are the timers that would still not be recorded completely after the move.
+	Failed []domain.Timer
+}
+
+// AlternativeAiringService finds repeats of conflicting timers in the EPG.
+type AlternativeAiringService struct {
+	epgService   *EPGService
+	timerService *TimerService
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// NewAlternativeAiringService creates a new alternative airing service
+func NewAlternativeAiringService(epgService *EPGService, timerService *TimerService) *AlternativeAiringService {
+	return &AlternativeAiringService{
+		epgService:   epgService,
+		timerService: timerService,
+		now:          time.Now,
+	}
+}
+
+// FindAlternatives returns the timer with the given ID, the EPG event it records (if known)
+// and other future broadcasts of the same title and subtitle on wanted channels.
+// Airings that resolve the conflict come first, then the ones with fewer failing timers,
+// each ordered by start time. The moved timers keep the margins of the timer when
+// its event is known; otherwise marginStart and marginEnd (minutes) are applied.
+func (s *AlternativeAiringService) FindAlternatives(ctx context.Context, timerID, marginStart, marginEnd int) (domain.Timer, *domain.EPGEvent, []AlternativeAiring, error) {
+	timers, err := s.timerService.GetAllTimers(ctx)
+	if err != nil {
+		return domain.Timer{}, nil, nil, err
+	}
+	timer, ok := findTimer(timers, timerID)
+	if !ok {
+		return domain.Timer{}, nil, nil, domain.ErrNotFound
+	}
+	if IsWeekdayMask(timer.DaySpec) {
+		return timer, nil, nil, fmt.Errorf("%w: recurring timers have no alternative airings", domain.ErrInvalidInput)
+	}
+
+	events, err := s.epgService.GetEPG(ctx, "", time.Time{})
+	if err != nil {
+		return timer, nil, nil, err
+	}
+	channels, err := s.timerService.listChannels(ctx)
+	if err != nil {
+		return timer, nil, nil, err
+	}
+
+	original := eventForTimer(timer, events)
+	title, subtitle := timer.Title, ""
+	if original != nil {
+		title, subtitle = original.Title, original.Subtitle
+	}
+	marginStart, marginEnd = timerMargins(timer, original, marginStart, marginEnd)
+	titleKey, subtitleKey := normalizeAiringText(title), normalizeAiringText(subtitle)
+	if titleKey == "" {
+		return timer, original, nil, nil
+	}
+
+	now := s.now()
+	var out []AlternativeAiring
+	for _, ev := range events {
+		if !ev.Start.After(now) {
+			continue
+		}
+		if original != nil && ev.EventID == original.EventID && ev.ChannelID == original.ChannelID {
+			continue
+		}
+		if normalizeAiringText(ev.Title) != titleKey || normalizeAiringText(ev.Subtitle) != subtitleKey {
+			continue
+		}
+
+		moved := movedTimer(timer, ev, marginStart, marginEnd)
+		// Skip the airing the timer already covers (e.g. events without EPG IDs).
+		if timerChannelMatchesEvent(timer, ev) && !moved.Start.Before(timer.Start) && !moved.Stop.After(timer.Stop) {
+			continue
+		}
+
+		alt := AlternativeAiring{Event: ev, Timer: moved, ResolvesConflict: true}
+		if conflict := s.timerService.conflictsWith(timers, channels, &moved); conflict != nil {
+			alt.ResolvesConflict = false
+			alt.Failed = conflict.Failed
+		}
+		out = append(out, alt)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].ResolvesConflict != out[j].ResolvesConflict {
+			return out[i].ResolvesConflict
+		}
+		if len(out[i].Failed) != len(out[j].Failed) {
+			return len(out[i].Failed) < len(out[j].Failed)
+		}
+		return out[i].Event.Start.Before(out[j].Event.Start)
+	})
+	return timer, original, out, nil
+}
+
+// MoveTimer changes the timer with the given ID to record the EPG event instead.
+// Priority, lifetime, margins and auxiliary data of the timer are kept.
+func (s *AlternativeAiringService) MoveTimer(ctx context.Context, timerID int, channelID string, eventID, marginStart, marginEnd int) (domain.Timer, error) {
+	timers, err := s.timerService.GetAllTimers(ctx)
+	if err != nil {
+		return domain.Timer{}, err
+	}
+	timer, ok := findTimer(timers, timerID)
+	if !ok {
+		return domain.Timer{}, domain.ErrNotFound
+	}
+	if IsWeekdayMask(timer.DaySpec) {
+		return domain.Timer{}, fmt.Errorf("%w: recurring timers cannot be moved", domain.ErrInvalidInput)
+	}
+
+	events, err := s.epgService.GetEPG(ctx, "", time.Time{})
+	if err != nil {
+		return domain.Timer{}, err
+	}
+	marginStart, marginEnd = timerMargins(timer, eventForTimer(timer, events), marginStart, marginEnd)
+	for _, ev := range events {
+		if ev.EventID != eventID || (channelID != "" && ev.ChannelID != channelID) {
+			continue
+		}
+		moved := movedTimer(timer, ev, marginStart, marginEnd)
+		if err := s.timerService.UpdateTimer(ctx, &moved); err != nil {
+			return domain.Timer{}, err
+		}
+		return moved, nil
+	}
+	return domain.Timer{}, domain.ErrNotFound
+}
+
+func movedTimer(timer domain.Timer, ev domain.EPGEvent, marginStart, marginEnd int) domain.Timer {
+	moved := NewTimerFromEPG(ev, timer.Priority, timer.Lifetime, marginStart, marginEnd)
+	moved.ID = timer.ID
+	moved.Aux = timer.Aux
+	if ev.EventID > 0 {
+		moved.Aux = withTimerEventAux(timer.Aux, ev.EventID, marginStart, marginEnd)
+	}
+	if strings.TrimSpace(timer.Title) != "" {
+		// Keep the recording name (it may contain a folder path).
+		moved.Title = timer.Title
+	}
+	return moved
+}
+
+// timerMargins derives the margins (minutes) of a timer from the event it records,
+// falling back to the given defaults.
+func timerMargins(timer domain.Timer, event *domain.EPGEvent, defStart, defEnd int) (int, int) {
+	if event == nil {
+		return defStart, defEnd
+	}
+	marginStart, marginEnd := defStart, defEnd
+	if d := event.Start.Sub(timer.Start); d >= 0 {
+		marginStart = int(d / time.Minute)
+	}
+	if d := timer.Stop.Sub(event.Stop); d >= 0 {
+		marginEnd = int(d / time.Minute)
+	}
+	return marginStart, marginEnd
+}
+
+func findTimer(timers []domain.Timer, id int) (domain.Timer, bool) {
+	for _, t := range timers {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return domain.Timer{}, false
+}
+
+// eventForTimer returns the EPG event recorded by a one-time timer: the event with the
+// timer's EventID or, failing that, the event on the timer's channel it covers the most.
+func eventForTimer(timer domain.Timer, events []domain.EPGEvent) *domain.EPGEvent {
+	var best *domain.EPGEvent
+	var bestOverlap time.Duration
+	for i := range events {
+		ev := &events[i]
+		if !timerChannelMatchesEvent(timer, *ev) {
+			continue
+		}
+		if timer.EventID > 0 && ev.EventID == timer.EventID {
+			found := *ev
+			return &found
+		}
+		start, stop := ev.Start, ev.Stop
+		if timer.Start.After(start) {
+			start = timer.Start
+		}
+		if timer.Stop.Before(stop) {
+			stop = timer.Stop
+		}
+		if overlap := stop.Sub(start); overlap > bestOverlap {
+			best, bestOverlap = ev, overlap
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	found := *best
+	return &found
+}
+
+func normalizeAiringText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
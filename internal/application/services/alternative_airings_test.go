@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestAlternativeAiringService_RanksResolvingAiringsFirst(t *testing.T) {
+	base := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+
+	original := domain.EPGEvent{EventID: 10, ChannelID: "S19.2E-1-1011-11110", Title: "Tatort", Subtitle: "Der Fall", Start: base, Stop: base.Add(90 * time.Minute)}
+	// Repeat on the transponder of the blocking timer: shares its device.
+	sameTransponder := domain.EPGEvent{EventID: 20, ChannelID: "S19.2E-1-1019-10302", Title: "Tatort", Subtitle: "Der Fall", Start: base.Add(10 * time.Minute), Stop: base.Add(100 * time.Minute)}
+	// Repeat at the same time on a third transponder: still conflicts.
+	stillClashing := domain.EPGEvent{EventID: 30, ChannelID: "S19.2E-1-1093-28006", Title: "tatort ", Subtitle: "Der  Fall", Start: base.Add(5 * time.Minute), Stop: base.Add(95 * time.Minute)}
+	// Repeat the next day: no conflict.
+	nextDay := domain.EPGEvent{EventID: 40, ChannelID: "S19.2E-1-1011-11110", Title: "Tatort", Subtitle: "Der Fall", Start: base.Add(24 * time.Hour), Stop: base.Add(25*time.Hour + 30*time.Minute)}
+	// Different episode: ignored.
+	otherEpisode := domain.EPGEvent{EventID: 50, ChannelID: "S19.2E-1-1011-11110", Title: "Tatort", Subtitle: "Anderer Fall", Start: base.Add(48 * time.Hour), Stop: base.Add(49 * time.Hour)}
+
+	blocking := domain.Timer{ID: 1, Active: true, ChannelID: "S19.2E-1-1019-10301", Start: base.Add(-30 * time.Minute), Stop: base.Add(2 * time.Hour), Priority: 50, Title: "News"}
+	conflicting := domain.Timer{ID: 2, Active: true, ChannelID: original.ChannelID, EventID: 10, Start: base.Add(-2 * time.Minute), Stop: original.Stop.Add(10 * time.Minute), Priority: 50, Lifetime: 99, Title: "Tatort"}
+
+	mock := ports.NewMockVDRClient().
+		WithEPGEvents([]domain.EPGEvent{original, sameTransponder, stillClashing, nextDay, otherEpisode}).
+		WithTimers([]domain.Timer{blocking, conflicting})
+
+	timerSvc := NewTimerService(mock)
+	svc := NewAlternativeAiringService(NewEPGService(mock, 0), timerSvc)
+
+	timer, event, alts, err := svc.FindAlternatives(context.Background(), 2, 5, 5)
+	if err != nil {
+		t.Fatalf("FindAlternatives: %v", err)
+	}
+	if timer.ID != 2 || event == nil || event.EventID != 10 {
+		t.Fatalf("unexpected timer/event: %+v %+v", timer, event)
+	}
+	if len(alts) != 3 {
+		t.Fatalf("expected 3 alternatives, got %d", len(alts))
+	}
+	if alts[0].Event.EventID != 20 || !alts[0].ResolvesConflict {
+		t.Fatalf("expected same-transponder airing first, got %+v", alts[0])
+	}
+	if alts[1].Event.EventID != 40 || !alts[1].ResolvesConflict {
+		t.Fatalf("expected next-day airing second, got %+v", alts[1])
+	}
+	if alts[2].Event.EventID != 30 || alts[2].ResolvesConflict {
+		t.Fatalf("expected clashing airing last, got %+v", alts[2])
+	}
+	// Margins of the original timer (2 and 10 minutes) are kept.
+	if got := alts[1].Timer; !got.Start.Equal(nextDay.Start.Add(-2*time.Minute)) || !got.Stop.Equal(nextDay.Stop.Add(10*time.Minute)) || got.ID != 2 {
+		t.Fatalf("unexpected moved timer: %+v", got)
+	}
+
+	moved, err := svc.MoveTimer(context.Background(), 2, nextDay.ChannelID, 40, 5, 5)
+	if err != nil {
+		t.Fatalf("MoveTimer: %v", err)
+	}
+	if moved.EventID != 40 || moved.Priority != 50 || moved.Lifetime != 99 {
+		t.Fatalf("unexpected moved timer: %+v", moved)
+	}
+	timers, _ := timerSvc.GetAllTimers(context.Background())
+	for _, tm := range timers {
+		if tm.ID == 2 && !tm.Start.Equal(moved.Start) {
+			t.Fatalf("expected timer 2 to be updated, got %+v", tm)
+		}
+	}
+}
+
+func TestAlternativeAiringService_RejectsRecurringTimers(t *testing.T) {
+	mock := ports.NewMockVDRClient().WithTimers([]domain.Timer{{ID: 1, Active: true, ChannelID: "C-1-2-3", DaySpec: "MTWTF--", StartMinutes: 60, StopMinutes: 120, Title: "Daily"}})
+	svc := NewAlternativeAiringService(NewEPGService(mock, 0), NewTimerService(mock))
+
+	if _, _, _, err := svc.FindAlternatives(context.Background(), 1, 2, 10); err == nil {
+		t.Fatalf("expected error for recurring timer")
+	}
+	if _, _, _, err := svc.FindAlternatives(context.Background(), 99, 2, 10); err != domain.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/githubixx/vdradmin-go/internal/domain"
@@ -14,7 +18,31 @@ type AutoTimerService struct {
 	vdrClient    ports.VDRClient
 	timerService *TimerService
 	epgService   *EPGService
-	autoTimers   []domain.AutoTimer
+
+	mu         sync.RWMutex
+	autoTimers []domain.AutoTimer
+	lastID     int
+	store      ports.AutoTimerStore
+	dedup      *DedupService
+
+	// processMu serializes ProcessAutoTimers runs (background and manual).
+	processMu sync.Mutex
+
+	statusMu  sync.RWMutex
+	lastRun   AutoTimerRunStatus
+	interval  time.Duration
+	trigger   chan struct{}
+	reconfig  chan struct{}
+	listenMu  sync.Mutex
+	listening bool
+}
+
+// AutoTimerRunStatus describes the outcome of the most recent AutoTimer run.
+type AutoTimerRunStatus struct {
+	StartedAt time.Time
+	EndedAt   time.Time
+	Created   int
+	Err       string
 }
 
 // NewAutoTimerService creates a new autotimer service
@@ -24,46 +52,180 @@ func NewAutoTimerService(vdrClient ports.VDRClient, timerService *TimerService,
 		timerService: timerService,
 		epgService:   epgService,
 		autoTimers:   make([]domain.AutoTimer, 0),
+		trigger:      make(chan struct{}, 1),
+		reconfig:     make(chan struct{}, 1),
 	}
 }
 
-// AddAutoTimer adds a new autotimer
-func (s *AutoTimerService) AddAutoTimer(at domain.AutoTimer) error {
-	if at.Pattern == "" {
-		return domain.ErrInvalidInput
+// SetStore configures persistent storage and loads the stored AutoTimers.
+// Subsequent changes are written back to the store.
+func (s *AutoTimerService) SetStore(store ports.AutoTimerStore) error {
+	autoTimers, err := store.LoadAutoTimers()
+	if err != nil {
+		return err
 	}
-
-	// Validate regex if enabled
-	if at.UseRegex {
-		if _, err := regexp.Compile(at.Pattern); err != nil {
+	lastID := 0
+	if ids, ok := store.(ports.AutoTimerIDStore); ok {
+		// The stored AutoTimers miss the IDs of deleted ones.
+		if lastID, err = ids.LastAutoTimerID(); err != nil {
 			return err
 		}
 	}
 
-	at.ID = len(s.autoTimers) + 1
-	s.autoTimers = append(s.autoTimers, at)
-
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	s.autoTimers = autoTimers
+	s.lastID = lastID
+	for _, at := range autoTimers {
+		if at.ID > s.lastID {
+			s.lastID = at.ID
+		}
+	}
 	return nil
 }
 
+// SetDedup sets the service recognising episodes recorded or scheduled before.
+// Without one, matches are only checked for timers recording the same event.
+func (s *AutoTimerService) SetDedup(dedup *DedupService) {
+	s.mu.Lock()
+	s.dedup = dedup
+	s.mu.Unlock()
+}
+
+// AddAutoTimer adds a new autotimer and returns its ID
+func (s *AutoTimerService) AddAutoTimer(at domain.AutoTimer) (int, error) {
+	if err := validateAutoTimer(at); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// IDs are not reused, even after deleting the most recent AutoTimer: done
+	// entries refer to their AutoTimer by ID. Across restarts this relies on
+	// the store keeping the last ID (ports.AutoTimerIDStore).
+	for _, existing := range s.autoTimers {
+		if existing.ID > s.lastID {
+			s.lastID = existing.ID
+		}
+	}
+	s.lastID++
+	at.ID = s.lastID
+
+	updated := append(append([]domain.AutoTimer(nil), s.autoTimers...), at)
+	if err := s.persistLocked(updated); err != nil {
+		s.lastID--
+		return 0, err
+	}
+	return at.ID, nil
+}
+
+// UpdateAutoTimer replaces an existing autotimer definition.
+func (s *AutoTimerService) UpdateAutoTimer(at domain.AutoTimer) error {
+	if err := validateAutoTimer(at); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := append([]domain.AutoTimer(nil), s.autoTimers...)
+	for i := range updated {
+		if updated[i].ID == at.ID {
+			updated[i] = at
+			return s.persistLocked(updated)
+		}
+	}
+	return domain.ErrNotFound
+}
+
+// SetAutoTimerActive enables or disables an autotimer
+func (s *AutoTimerService) SetAutoTimerActive(id int, active bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := append([]domain.AutoTimer(nil), s.autoTimers...)
+	for i := range updated {
+		if updated[i].ID == id {
+			updated[i].Active = active
+			return s.persistLocked(updated)
+		}
+	}
+	return domain.ErrNotFound
+}
+
+// GetAutoTimer returns a single autotimer by ID
+func (s *AutoTimerService) GetAutoTimer(id int) (domain.AutoTimer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, at := range s.autoTimers {
+		if at.ID == id {
+			return at, nil
+		}
+	}
+	return domain.AutoTimer{}, domain.ErrNotFound
+}
+
 // GetAutoTimers returns all autotimers
 func (s *AutoTimerService) GetAutoTimers() []domain.AutoTimer {
-	return s.autoTimers
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]domain.AutoTimer, len(s.autoTimers))
+	copy(out, s.autoTimers)
+	return out
 }
 
 // DeleteAutoTimer removes an autotimer
 func (s *AutoTimerService) DeleteAutoTimer(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	for i, at := range s.autoTimers {
 		if at.ID == id {
-			s.autoTimers = append(s.autoTimers[:i], s.autoTimers[i+1:]...)
-			return nil
+			updated := make([]domain.AutoTimer, 0, len(s.autoTimers)-1)
+			updated = append(updated, s.autoTimers[:i]...)
+			updated = append(updated, s.autoTimers[i+1:]...)
+			return s.persistLocked(updated)
 		}
 	}
 	return domain.ErrNotFound
 }
 
+// persistLocked writes the given list to the store (if configured) and makes it current.
+// The in-memory list is only replaced when persisting succeeded.
+func (s *AutoTimerService) persistLocked(autoTimers []domain.AutoTimer) error {
+	if s.store != nil {
+		if err := s.store.SaveAutoTimers(autoTimers); err != nil {
+			return err
+		}
+	}
+	s.autoTimers = autoTimers
+	return nil
+}
+
 // ProcessAutoTimers processes all active autotimers and creates timers
 func (s *AutoTimerService) ProcessAutoTimers(ctx context.Context) (int, error) {
+	s.processMu.Lock()
+	defer s.processMu.Unlock()
+
+	startedAt := time.Now()
+	created, err := s.processAutoTimers(ctx)
+
+	status := AutoTimerRunStatus{StartedAt: startedAt, EndedAt: time.Now(), Created: created}
+	if err != nil {
+		status.Err = err.Error()
+	}
+	s.statusMu.Lock()
+	s.lastRun = status
+	s.statusMu.Unlock()
+
+	return created, err
+}
+
+func (s *AutoTimerService) processAutoTimers(ctx context.Context) (int, error) {
 	// Get all EPG events
 	events, err := s.epgService.GetEPG(ctx, "", time.Time{})
 	if err != nil {
@@ -76,36 +238,198 @@ func (s *AutoTimerService) ProcessAutoTimers(ctx context.Context) (int, error) {
 		return 0, err
 	}
 
+	s.mu.RLock()
+	dedup := s.dedup
+	s.mu.RUnlock()
+	var check *DedupCheck
+	if dedup != nil {
+		if check, err = dedup.NewCheck(ctx, events, existingTimers); err != nil {
+			return 0, err
+		}
+	}
+
+	now := time.Now()
+	autoTimers := s.GetAutoTimers()
 	created := 0
+	var firstErr error
 
-	for _, at := range s.autoTimers {
+	for _, at := range autoTimers {
 		if !at.Active {
 			continue
 		}
 
-		matches := s.findMatches(events, at)
+		candidates := events
+		if !at.UseRegex {
+			if indexed, ok := s.epgService.index.candidates(at.Pattern, searchScopeFields(at.SearchIn)); ok {
+				candidates = indexed
+			}
+		}
+		matches := s.findMatches(candidates, at)
 
+		var scheduled []domain.EPGEvent
 		for _, event := range matches {
-			// Check if already recorded or scheduled
-			if s.alreadyDone(event.EventID, at.Done) {
+			if !event.Stop.IsZero() && event.Stop.Before(now) {
 				continue
 			}
 
+			// Check if already recorded or scheduled
 			if s.alreadyScheduled(event, existingTimers) {
 				continue
 			}
+			if _, duplicate := check.Duplicate(event, at.Dedup); duplicate {
+				continue
+			}
 
 			// Create timer
 			err := s.timerService.CreateTimerFromEPG(ctx, event, at.Priority, at.Lifetime, at.MarginStart, at.MarginEnd)
-			if err == nil {
-				created++
-				// Mark as done
-				at.Done = append(at.Done, event.EventID)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("autotimer %d: %w", at.ID, err)
+				}
+				continue
+			}
+			created++
+			existingTimers = append(existingTimers, domain.Timer{
+				ChannelID: event.ChannelID,
+				Start:     event.Start,
+				Stop:      event.Stop,
+				EventID:   event.EventID,
+			})
+			check.AddScheduled(event)
+			scheduled = append(scheduled, event)
+		}
+
+		// Mark as done
+		if dedup != nil {
+			if err := dedup.MarkDone(AutoTimerDoneSource(at.ID), scheduled...); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("done list: %w", err)
 			}
 		}
 	}
 
-	return created, nil
+	return created, firstErr
+}
+
+// LastRun returns the outcome of the most recent AutoTimer run.
+// The zero value means no run has happened yet.
+func (s *AutoTimerService) LastRun() AutoTimerRunStatus {
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+	return s.lastRun
+}
+
+// SetInterval updates the interval used by Run.
+// An interval <= 0 disables interval-based processing; EPG refreshes still trigger runs.
+func (s *AutoTimerService) SetInterval(interval time.Duration) {
+	s.statusMu.Lock()
+	changed := s.interval != interval
+	s.interval = interval
+	s.statusMu.Unlock()
+
+	if changed {
+		select {
+		case s.reconfig <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Trigger requests a background AutoTimer run.
+// Multiple triggers while a run is pending are coalesced.
+func (s *AutoTimerService) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes AutoTimers in the background until ctx is canceled.
+// A run happens every configured interval and after every EPG refresh.
+func (s *AutoTimerService) Run(ctx context.Context) {
+	s.listenMu.Lock()
+	if !s.listening && s.epgService != nil {
+		s.epgService.OnRefresh(s.Trigger)
+		s.listening = true
+	}
+	s.listenMu.Unlock()
+
+	for {
+		s.statusMu.RLock()
+		interval := s.interval
+		s.statusMu.RUnlock()
+
+		var tick <-chan time.Time
+		var timer *time.Timer
+		if interval > 0 {
+			timer = time.NewTimer(interval)
+			tick = timer.C
+		}
+
+		run := false
+		select {
+		case <-ctx.Done():
+		case <-s.reconfig:
+		case <-s.trigger:
+			run = true
+		case <-tick:
+			run = true
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !run {
+			continue
+		}
+
+		_, _ = s.ProcessAutoTimers(ctx)
+
+		// The run itself may have refreshed the EPG cache. Drop that notification,
+		// otherwise every run would immediately schedule another one.
+		select {
+		case <-s.trigger:
+		default:
+		}
+	}
+}
+
+func validateAutoTimer(at domain.AutoTimer) error {
+	if strings.TrimSpace(at.Pattern) == "" {
+		return fmt.Errorf("%w: pattern required", domain.ErrInvalidInput)
+	}
+
+	// Validate regex if enabled
+	if at.UseRegex {
+		if _, err := regexp.Compile(at.Pattern); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+		}
+	}
+
+	switch at.SearchIn {
+	case domain.SearchTitle, domain.SearchTitleSubtitle, domain.SearchAll:
+	default:
+		return fmt.Errorf("%w: invalid search scope", domain.ErrInvalidInput)
+	}
+
+	if at.Priority < 0 || at.Priority > 99 {
+		return fmt.Errorf("%w: priority must be between 0 and 99", domain.ErrInvalidInput)
+	}
+	if at.Lifetime < 0 || at.Lifetime > 99 {
+		return fmt.Errorf("%w: lifetime must be between 0 and 99", domain.ErrInvalidInput)
+	}
+	if at.MarginStart < 0 || at.MarginEnd < 0 {
+		return fmt.Errorf("%w: margins must not be negative", domain.ErrInvalidInput)
+	}
+
+	switch at.Dedup {
+	case "", domain.DedupAll, domain.DedupRecordings, domain.DedupTimers, domain.DedupNone:
+	default:
+		return fmt.Errorf("%w: invalid dedup scope", domain.ErrInvalidInput)
+	}
+
+	return nil
 }
 
 // findMatches finds EPG events matching an autotimer
@@ -218,20 +542,48 @@ func (s *AutoTimerService) patternMatches(event domain.EPGEvent, pattern string,
 	return false
 }
 
-func (s *AutoTimerService) alreadyDone(eventID int, done []int) bool {
-	for _, id := range done {
-		if id == eventID {
-			return true
-		}
+// searchScopeFields returns the indexed fields of an AutoTimer search scope.
+func searchScopeFields(scope domain.SearchScope) epgField {
+	switch scope {
+	case domain.SearchTitle:
+		return epgFieldTitle
+	case domain.SearchTitleSubtitle:
+		return epgFieldTitle | epgFieldSubtitle
+	default:
+		return epgFieldsAll
 	}
-	return false
 }
 
 func (s *AutoTimerService) alreadyScheduled(event domain.EPGEvent, timers []domain.Timer) bool {
+	return eventScheduled(event, timers)
+}
+
+// eventScheduled reports whether one of the timers records the event.
+func eventScheduled(event domain.EPGEvent, timers []domain.Timer) bool {
 	for _, timer := range timers {
-		if timer.EventID == event.EventID {
+		if timer.EventID > 0 && timer.EventID == event.EventID {
+			return true
+		}
+		// LSTT does not report event IDs, so also treat a timer on the same channel
+		// that covers the whole event (timers usually include margins) as scheduled.
+		if timerChannelMatchesEvent(timer, event) &&
+			!timer.Start.IsZero() && !timer.Stop.IsZero() &&
+			!timer.Start.After(event.Start) && !timer.Stop.Before(event.Stop) {
 			return true
 		}
 	}
 	return false
 }
+
+// timerChannelMatchesEvent reports whether a timer records the channel of an event.
+// Timers may reference channels by VDR channel ID or by channel number.
+func timerChannelMatchesEvent(timer domain.Timer, event domain.EPGEvent) bool {
+	chID := strings.TrimSpace(timer.ChannelID)
+	if chID == "" {
+		return false
+	}
+	if chID == event.ChannelID {
+		return true
+	}
+	return event.ChannelNumber > 0 && chID == strconv.Itoa(event.ChannelNumber)
+}
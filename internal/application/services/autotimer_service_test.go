@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+type memAutoTimerStore struct {
+	saved   []domain.AutoTimer
+	saves   int
+	saveErr error
+}
+
+func (m *memAutoTimerStore) LoadAutoTimers() ([]domain.AutoTimer, error) {
+	return append([]domain.AutoTimer(nil), m.saved...), nil
+}
+
+func (m *memAutoTimerStore) SaveAutoTimers(autoTimers []domain.AutoTimer) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saves++
+	m.saved = append([]domain.AutoTimer(nil), autoTimers...)
+	return nil
+}
+
+// memAutoTimerIDStore also keeps the highest ID ever saved, like the file store.
+type memAutoTimerIDStore struct {
+	memAutoTimerStore
+	lastID int
+}
+
+func (m *memAutoTimerIDStore) SaveAutoTimers(autoTimers []domain.AutoTimer) error {
+	if err := m.memAutoTimerStore.SaveAutoTimers(autoTimers); err != nil {
+		return err
+	}
+	for _, at := range autoTimers {
+		m.lastID = max(m.lastID, at.ID)
+	}
+	return nil
+}
+
+func (m *memAutoTimerIDStore) LastAutoTimerID() (int, error) {
+	return m.lastID, nil
+}
+
+func newTestAutoTimerService(mock *ports.MockVDRClient) *AutoTimerService {
+	epgSvc := NewEPGService(mock, 0)
+	timerSvc := NewTimerService(mock)
+	return NewAutoTimerService(mock, timerSvc, epgSvc)
+}
+
+func TestAutoTimerService_IDsAreNotReusedAfterDelete(t *testing.T) {
+	svc := newTestAutoTimerService(ports.NewMockVDRClient())
+
+	id1, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "a"})
+	if err != nil {
+		t.Fatalf("AddAutoTimer(a): %v", err)
+	}
+	id2, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "b"})
+	if err != nil {
+		t.Fatalf("AddAutoTimer(b): %v", err)
+	}
+	if err := svc.DeleteAutoTimer(id1); err != nil {
+		t.Fatalf("DeleteAutoTimer: %v", err)
+	}
+	id3, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "c"})
+	if err != nil {
+		t.Fatalf("AddAutoTimer(c): %v", err)
+	}
+	if id3 == id1 || id3 == id2 {
+		t.Fatalf("expected a fresh ID, got %d (existing %d, %d)", id3, id1, id2)
+	}
+
+	// Deleting the newest AutoTimer must not free its ID either.
+	if err := svc.DeleteAutoTimer(id3); err != nil {
+		t.Fatalf("DeleteAutoTimer: %v", err)
+	}
+	id4, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "d"})
+	if err != nil {
+		t.Fatalf("AddAutoTimer(d): %v", err)
+	}
+	if id4 <= id3 {
+		t.Fatalf("expected ID > %d, got %d", id3, id4)
+	}
+}
+
+func TestAutoTimerService_IDsAreNotReusedAfterRestart(t *testing.T) {
+	store := &memAutoTimerIDStore{}
+	svc := newTestAutoTimerService(ports.NewMockVDRClient())
+	if err := svc.SetStore(store); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	if _, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "a"}); err != nil {
+		t.Fatalf("AddAutoTimer(a): %v", err)
+	}
+	id2, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "b"})
+	if err != nil {
+		t.Fatalf("AddAutoTimer(b): %v", err)
+	}
+	if err := svc.DeleteAutoTimer(id2); err != nil {
+		t.Fatalf("DeleteAutoTimer: %v", err)
+	}
+
+	restarted := newTestAutoTimerService(ports.NewMockVDRClient())
+	if err := restarted.SetStore(store); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	id3, err := restarted.AddAutoTimer(domain.AutoTimer{Pattern: "c"})
+	if err != nil {
+		t.Fatalf("AddAutoTimer(c): %v", err)
+	}
+	if id3 <= id2 {
+		t.Fatalf("expected ID > %d after restart, got %d", id2, id3)
+	}
+}
+
+func TestAutoTimerService_Validation(t *testing.T) {
+	svc := newTestAutoTimerService(ports.NewMockVDRClient())
+
+	cases := []domain.AutoTimer{
+		{Pattern: "  "},
+		{Pattern: "(", UseRegex: true},
+		{Pattern: "x", Priority: 100},
+		{Pattern: "x", Lifetime: -1},
+		{Pattern: "x", MarginStart: -1},
+	}
+	for i, at := range cases {
+		if _, err := svc.AddAutoTimer(at); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("case %d: expected ErrInvalidInput, got %v", i, err)
+		}
+	}
+	if got := len(svc.GetAutoTimers()); got != 0 {
+		t.Fatalf("expected no AutoTimers, got %d", got)
+	}
+}
+
+func TestAutoTimerService_PersistsChanges(t *testing.T) {
+	store := &memAutoTimerStore{saved: []domain.AutoTimer{{ID: 7, Pattern: "Tatort", Active: true}}}
+	svc := newTestAutoTimerService(ports.NewMockVDRClient())
+	if err := svc.SetStore(store); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+
+	if got := svc.GetAutoTimers(); len(got) != 1 || got[0].ID != 7 {
+		t.Fatalf("expected loaded AutoTimer 7, got %+v", got)
+	}
+
+	id, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "News", Active: true})
+	if err != nil {
+		t.Fatalf("AddAutoTimer: %v", err)
+	}
+	if id != 8 {
+		t.Fatalf("expected ID 8 after loading ID 7, got %d", id)
+	}
+	if err := svc.SetAutoTimerActive(7, false); err != nil {
+		t.Fatalf("SetAutoTimerActive: %v", err)
+	}
+	if len(store.saved) != 2 || store.saved[0].Active {
+		t.Fatalf("unexpected stored AutoTimers: %+v", store.saved)
+	}
+
+	store.saveErr = errors.New("disk full")
+	if err := svc.DeleteAutoTimer(7); err == nil {
+		t.Fatalf("expected store error")
+	}
+	if got := len(svc.GetAutoTimers()); got != 2 {
+		t.Fatalf("failed save must not change in-memory state, got %d AutoTimers", got)
+	}
+}
+
+func TestAutoTimerService_ProcessRecordsDone(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	mock := ports.NewMockVDRClient().WithEPGEvents([]domain.EPGEvent{
+		{EventID: 100, ChannelID: "C-1-2-3", ChannelNumber: 1, Title: "Tatort", Subtitle: "Borowski", Start: start, Stop: start.Add(90 * time.Minute)},
+		{EventID: 101, ChannelID: "C-1-2-3", ChannelNumber: 1, Title: "News", Start: start.Add(2 * time.Hour), Stop: start.Add(150 * time.Minute)},
+	})
+	doneStore := &memDoneStore{}
+	dedup := NewDedupService()
+	if err := dedup.SetStore(doneStore); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	svc := newTestAutoTimerService(mock)
+	svc.SetDedup(dedup)
+	id, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "tatort", Active: true, Priority: 50, Lifetime: 99, Dedup: domain.DedupAll})
+	if err != nil {
+		t.Fatalf("AddAutoTimer: %v", err)
+	}
+
+	created, err := svc.ProcessAutoTimers(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessAutoTimers: %v", err)
+	}
+	if created != 1 {
+		t.Fatalf("expected 1 created timer, got %d", created)
+	}
+	if len(doneStore.saved) != 1 || doneStore.saved[0].EventID != 100 || doneStore.saved[0].Source != AutoTimerDoneSource(id) {
+		t.Fatalf("expected the episode to be persisted as done, got %+v", doneStore.saved)
+	}
+
+	// Deleting the timer does not bring the episode back.
+	if err := mock.DeleteTimer(context.Background(), 1); err != nil {
+		t.Fatalf("DeleteTimer: %v", err)
+	}
+	created, err = svc.ProcessAutoTimers(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessAutoTimers(2): %v", err)
+	}
+	if created != 0 {
+		t.Fatalf("expected no new timers on second run, got %d", created)
+	}
+	if last := svc.LastRun(); last.EndedAt.IsZero() || last.Created != 0 || last.Err != "" {
+		t.Fatalf("unexpected last run: %+v", last)
+	}
+
+	// Unless the AutoTimer ignores the done list.
+	at, err := svc.GetAutoTimer(id)
+	if err != nil {
+		t.Fatalf("GetAutoTimer: %v", err)
+	}
+	at.Dedup = domain.DedupRecordings
+	if err := svc.UpdateAutoTimer(at); err != nil {
+		t.Fatalf("UpdateAutoTimer: %v", err)
+	}
+	if created, err := svc.ProcessAutoTimers(context.Background()); err != nil || created != 1 {
+		t.Fatalf("ProcessAutoTimers(3) = %d, %v; want 1 timer", created, err)
+	}
+}
+
+func TestAutoTimerService_SkipsEventsScheduledByChannelNumber(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	mock := ports.NewMockVDRClient().
+		WithEPGEvents([]domain.EPGEvent{
+			{EventID: 100, ChannelID: "C-1-2-3", ChannelNumber: 5, Title: "Tatort", Start: start, Stop: start.Add(90 * time.Minute)},
+		}).
+		WithTimers([]domain.Timer{
+			// LSTT timers carry the channel number and no event ID.
+			{ID: 1, ChannelID: "5", Start: start.Add(-2 * time.Minute), Stop: start.Add(100 * time.Minute), Active: true},
+		})
+	svc := newTestAutoTimerService(mock)
+	if _, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "Tatort", Active: true}); err != nil {
+		t.Fatalf("AddAutoTimer: %v", err)
+	}
+
+	created, err := svc.ProcessAutoTimers(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessAutoTimers: %v", err)
+	}
+	if created != 0 {
+		t.Fatalf("expected event to be treated as scheduled, created %d", created)
+	}
+}
+
+func TestAutoTimerService_RunProcessesAfterEPGRefresh(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	mock := ports.NewMockVDRClient().WithEPGEvents([]domain.EPGEvent{
+		{EventID: 100, ChannelID: "C-1-2-3", ChannelNumber: 1, Title: "Tatort", Start: start, Stop: start.Add(90 * time.Minute)},
+	})
+	epgSvc := NewEPGService(mock, time.Minute)
+	svc := NewAutoTimerService(mock, NewTimerService(mock), epgSvc)
+	if _, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "Tatort", Active: true}); err != nil {
+		t.Fatalf("AddAutoTimer: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Wait until Run registered its refresh listener, then refresh the EPG.
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		epgSvc.refreshMu.RLock()
+		n := len(epgSvc.refreshListeners)
+		epgSvc.refreshMu.RUnlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, err := epgSvc.GetEPG(context.Background(), "", time.Time{}); err != nil {
+		t.Fatalf("GetEPG: %v", err)
+	}
+
+	for time.Now().Before(deadline) {
+		if svc.LastRun().Created == 1 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected background run after EPG refresh, last run: %+v", svc.LastRun())
+}
+
+func TestAutoTimerService_RecordsDailyShowsWithoutSubtitle(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour).Truncate(time.Minute)
+	airing := func(id int, day int) domain.EPGEvent {
+		s := start.AddDate(0, 0, day)
+		return domain.EPGEvent{EventID: id, ChannelID: "C-1-2-3", ChannelNumber: 1, Title: "Tagesschau", Start: s, Stop: s.Add(15 * time.Minute)}
+	}
+	mock := ports.NewMockVDRClient().WithEPGEvents([]domain.EPGEvent{airing(100, 0), airing(101, 1)})
+	dedup := NewDedupService()
+	if err := dedup.SetStore(&memDoneStore{}); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	svc := newTestAutoTimerService(mock)
+	svc.SetDedup(dedup)
+	if _, err := svc.AddAutoTimer(domain.AutoTimer{Pattern: "tagesschau", Active: true, Dedup: domain.DedupAll}); err != nil {
+		t.Fatalf("AddAutoTimer: %v", err)
+	}
+
+	// Airings without subtitle cannot be told apart, so none is a repeat.
+	created, err := svc.ProcessAutoTimers(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessAutoTimers: %v", err)
+	}
+	if created != 2 {
+		t.Fatalf("expected both airings to be recorded, got %d timers", created)
+	}
+
+	mock.WithEPGEvents([]domain.EPGEvent{airing(100, 0), airing(101, 1), airing(102, 2)})
+	created, err = svc.ProcessAutoTimers(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessAutoTimers(2): %v", err)
+	}
+	if created != 1 {
+		t.Fatalf("expected the new airing to be recorded despite the done list, got %d timers", created)
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// Backend is one VDR managed by vdradmin-go together with the services bound
+// to its SVDRP connection.
+type Backend struct {
+	Name       string
+	VideoDir   string
+	Client     ports.VDRClient
+	EPG        *EPGService
+	Timers     *TimerService
+	Recordings *RecordingService
+	// Watchdog moves the backend's timers along with its EPG; nil if not watched.
+	Watchdog *TimerWatchdog
+}
+
+// Backends is the set of configured VDR backends. The first one is the primary
+// backend; features not aware of several VDRs (EPG search, autotimers, the JSON
+// API, ...) keep using it.
+type Backends struct {
+	list []*Backend
+}
+
+// NewBackends creates a backend set with primary as its first member.
+func NewBackends(primary *Backend, others ...*Backend) *Backends {
+	list := make([]*Backend, 0, 1+len(others))
+	list = append(list, primary)
+	list = append(list, others...)
+	return &Backends{list: list}
+}
+
+// Primary returns the primary backend.
+func (b *Backends) Primary() *Backend {
+	return b.list[0]
+}
+
+// All returns all backends, the primary one first.
+func (b *Backends) All() []*Backend {
+	return b.list
+}
+
+// Multiple reports whether more than one backend is configured.
+func (b *Backends) Multiple() bool {
+	return len(b.list) > 1
+}
+
+// Get returns the backend with the given name. An empty name selects the
+// primary backend.
+func (b *Backends) Get(name string) (*Backend, error) {
+	if name == "" {
+		return b.Primary(), nil
+	}
+	for _, be := range b.list {
+		if be.Name == name {
+			return be, nil
+		}
+	}
+	return nil, fmt.Errorf("backend %q: %w", name, domain.ErrNotFound)
+}
+
+// Select returns the named backend, or all backends for an empty name.
+func (b *Backends) Select(name string) ([]*Backend, error) {
+	if name == "" {
+		return b.list, nil
+	}
+	be, err := b.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return []*Backend{be}, nil
+}
+
+// Timers returns the timers of the given backends. With several backends
+// configured, each timer is tagged with the name of its backend. Backends that
+// fail are skipped; their errors are joined into the returned error, which is
+// only fatal if no backend answered.
+func (b *Backends) Timers(ctx context.Context, list []*Backend) ([]domain.Timer, error) {
+	var out []domain.Timer
+	var errs []error
+	ok := 0
+	for _, be := range list {
+		timers, err := be.Timers.GetAllTimers(ctx)
+		if err != nil {
+			errs = append(errs, b.backendError(be, err))
+			continue
+		}
+		ok++
+		for _, t := range timers {
+			if b.Multiple() {
+				t.Backend = be.Name
+			}
+			out = append(out, t)
+		}
+	}
+	return out, partialError(ok, errs)
+}
+
+// Recordings returns the recordings of the given backends, tagged like Timers.
+func (b *Backends) Recordings(ctx context.Context, list []*Backend) ([]domain.Recording, error) {
+	var out []domain.Recording
+	var errs []error
+	ok := 0
+	for _, be := range list {
+		recs, err := be.Recordings.GetAllRecordings(ctx)
+		if err != nil {
+			errs = append(errs, b.backendError(be, err))
+			continue
+		}
+		ok++
+		for _, rec := range recs {
+			if b.Multiple() {
+				rec.Backend = be.Name
+			}
+			out = append(out, rec)
+		}
+	}
+	return out, partialError(ok, errs)
+}
+
+// Channels returns the union of the channels of the given backends by channel ID,
+// in the order they are first seen.
+func (b *Backends) Channels(ctx context.Context, list []*Backend) ([]domain.Channel, error) {
+	var out []domain.Channel
+	var errs []error
+	seen := map[string]bool{}
+	ok := 0
+	for _, be := range list {
+		chs, err := be.EPG.GetChannels(ctx)
+		if err != nil {
+			errs = append(errs, b.backendError(be, err))
+			continue
+		}
+		ok++
+		for _, ch := range chs {
+			if seen[ch.ID] {
+				continue
+			}
+			seen[ch.ID] = true
+			out = append(out, ch)
+		}
+	}
+	return out, partialError(ok, errs)
+}
+
+// backendError names the failing backend when several are configured.
+func (b *Backends) backendError(be *Backend, err error) error {
+	if !b.Multiple() {
+		return err
+	}
+	return fmt.Errorf("backend %s: %w", be.Name, err)
+}
+
+// PartialError is returned by the merging Backends methods when some, but not
+// all, backends failed. The merged result is still usable.
+type PartialError struct {
+	Err error
+}
+
+func (e *PartialError) Error() string { return e.Err.Error() }
+
+func (e *PartialError) Unwrap() error { return e.Err }
+
+func partialError(ok int, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if ok == 0 && len(errs) == 1 {
+		return errs[0]
+	}
+	err := errors.Join(errs...)
+	if ok == 0 {
+		return err
+	}
+	return &PartialError{Err: err}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func testBackend(name string, client *ports.MockVDRClient) *Backend {
+	return &Backend{
+		Name:       name,
+		Client:     client,
+		EPG:        NewEPGService(client, 0),
+		Timers:     NewTimerService(client),
+		Recordings: NewRecordingService(client, 0),
+	}
+}
+
+func TestBackends_SingleBackendDoesNotTag(t *testing.T) {
+	client := ports.NewMockVDRClient().WithTimers([]domain.Timer{{ID: 1, Title: "A"}})
+	set := NewBackends(testBackend("main", client))
+
+	if set.Multiple() {
+		t.Fatalf("expected a single backend")
+	}
+	timers, err := set.Timers(context.Background(), set.All())
+	if err != nil {
+		t.Fatalf("Timers: %v", err)
+	}
+	if len(timers) != 1 || timers[0].Backend != "" {
+		t.Fatalf("expected one untagged timer, got %+v", timers)
+	}
+	if b, err := set.Get(""); err != nil || b.Name != "main" {
+		t.Fatalf("expected empty name to select the primary backend, got %v, %v", b, err)
+	}
+}
+
+func TestBackends_MergesAndTags(t *testing.T) {
+	main := ports.NewMockVDRClient().
+		WithTimers([]domain.Timer{{ID: 1, Title: "News"}}).
+		WithRecordings([]domain.Recording{{Path: "1", Title: "Film"}}).
+		WithChannels([]domain.Channel{{ID: "C-1", Number: 1, Name: "One"}, {ID: "C-2", Number: 2, Name: "Two"}})
+	bedroom := ports.NewMockVDRClient().
+		WithTimers([]domain.Timer{{ID: 1, Title: "Sports"}}).
+		WithRecordings([]domain.Recording{{Path: "1", Title: "Show"}}).
+		WithChannels([]domain.Channel{{ID: "C-2", Number: 7, Name: "Two"}, {ID: "C-3", Number: 8, Name: "Three"}})
+	set := NewBackends(testBackend("main", main), testBackend("bedroom", bedroom))
+	ctx := context.Background()
+
+	timers, err := set.Timers(ctx, set.All())
+	if err != nil {
+		t.Fatalf("Timers: %v", err)
+	}
+	if len(timers) != 2 || timers[0].Backend != "main" || timers[1].Backend != "bedroom" {
+		t.Fatalf("expected timers tagged with their backend, got %+v", timers)
+	}
+
+	recs, err := set.Recordings(ctx, set.All())
+	if err != nil {
+		t.Fatalf("Recordings: %v", err)
+	}
+	if len(recs) != 2 || recs[0].Backend != "main" || recs[1].Backend != "bedroom" {
+		t.Fatalf("expected recordings tagged with their backend, got %+v", recs)
+	}
+
+	chs, err := set.Channels(ctx, set.All())
+	if err != nil {
+		t.Fatalf("Channels: %v", err)
+	}
+	if len(chs) != 3 || chs[0].ID != "C-1" || chs[1].ID != "C-2" || chs[2].ID != "C-3" {
+		t.Fatalf("expected the union of channels, got %+v", chs)
+	}
+
+	only, err := set.Select("bedroom")
+	if err != nil || len(only) != 1 || only[0].Name != "bedroom" {
+		t.Fatalf("expected to select the bedroom backend, got %v, %v", only, err)
+	}
+	if _, err := set.Select("kitchen"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected unknown backend to be not found, got %v", err)
+	}
+}
+
+func TestBackends_PartialFailure(t *testing.T) {
+	main := ports.NewMockVDRClient().WithTimers([]domain.Timer{{ID: 1, Title: "News"}})
+	bedroom := ports.NewMockVDRClient()
+	bedroom.GetTimersFunc = func(ctx context.Context) ([]domain.Timer, error) {
+		return nil, domain.ErrConnection
+	}
+	set := NewBackends(testBackend("main", main), testBackend("bedroom", bedroom))
+
+	timers, err := set.Timers(context.Background(), set.All())
+	var partial *PartialError
+	if !errors.As(err, &partial) || !errors.Is(err, domain.ErrConnection) {
+		t.Fatalf("expected a partial connection error, got %v", err)
+	}
+	if len(timers) != 1 || timers[0].Backend != "main" {
+		t.Fatalf("expected the reachable backend's timers, got %+v", timers)
+	}
+
+	_, err = set.Timers(context.Background(), []*Backend{set.All()[1]})
+	if errors.As(err, &partial) || !errors.Is(err, domain.ErrConnection) {
+		t.Fatalf("expected a plain error when no backend answered, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+import "sync/atomic"
+
+// CacheStats counts cache lookups of a service.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRatio returns the share of lookups served from the cache, 0 without lookups.
+func (s CacheStats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type cacheCounter struct {
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func (c *cacheCounter) hit()  { c.hits.Add(1) }
+func (c *cacheCounter) miss() { c.misses.Add(1) }
+
+func (c *cacheCounter) stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
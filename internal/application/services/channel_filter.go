@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// ChannelFilter narrows the channels of a request to a favourites list. It
+// applies on top of the wanted channels, and channels keep the list's order.
+// A nil filter allows all channels.
+type ChannelFilter struct {
+	name string
+	pos  map[string]int
+}
+
+// NewChannelFilter returns a filter allowing the given channel IDs, in that order.
+func NewChannelFilter(name string, channelIDs []string) *ChannelFilter {
+	pos := make(map[string]int, len(channelIDs))
+	for _, id := range channelIDs {
+		if _, ok := pos[id]; ok || id == "" {
+			continue
+		}
+		pos[id] = len(pos)
+	}
+	return &ChannelFilter{name: name, pos: pos}
+}
+
+// Name returns the name of the favourites list.
+func (f *ChannelFilter) Name() string {
+	if f == nil {
+		return ""
+	}
+	return f.name
+}
+
+// Allows reports whether the filter lets the channel through.
+func (f *ChannelFilter) Allows(channelID string) bool {
+	if f == nil {
+		return true
+	}
+	_, ok := f.pos[channelID]
+	return ok
+}
+
+// channels returns the allowed channels in list order.
+func (f *ChannelFilter) channels(chs []domain.Channel) []domain.Channel {
+	if f == nil {
+		return chs
+	}
+	out := make([]domain.Channel, 0, len(f.pos))
+	for _, ch := range chs {
+		if f.Allows(ch.ID) {
+			out = append(out, ch)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return f.pos[out[i].ID] < f.pos[out[j].ID] })
+	return out
+}
+
+// events returns the events on allowed channels. ordered sorts them by list
+// position, e.g. for one event per channel; otherwise the order is kept.
+func (f *ChannelFilter) events(evs []domain.EPGEvent, ordered bool) []domain.EPGEvent {
+	if f == nil {
+		return evs
+	}
+	out := make([]domain.EPGEvent, 0, len(evs))
+	for _, ev := range evs {
+		if f.Allows(ev.ChannelID) {
+			out = append(out, ev)
+		}
+	}
+	if ordered {
+		sort.SliceStable(out, func(i, j int) bool { return f.pos[out[i].ChannelID] < f.pos[out[j].ChannelID] })
+	}
+	return out
+}
+
+type channelFilterKey struct{}
+
+// WithChannelFilter returns a context whose EPGService lookups are narrowed to f.
+func WithChannelFilter(ctx context.Context, f *ChannelFilter) context.Context {
+	return context.WithValue(ctx, channelFilterKey{}, f)
+}
+
+// ChannelFilterFromContext returns the filter set with WithChannelFilter, or nil.
+func ChannelFilterFromContext(ctx context.Context) *ChannelFilter {
+	f, _ := ctx.Value(channelFilterKey{}).(*ChannelFilter)
+	return f
+}
+
+// ChannelGroup is a group of channels.conf, e.g. a starting point for a
+// favourites list.
+type ChannelGroup struct {
+	Name     string
+	Channels []domain.Channel
+}
+
+// GetChannelGroups returns the channels.conf groups holding wanted channels.
+// VDR clients that cannot tell the groups yield none.
+func (s *EPGService) GetChannelGroups(ctx context.Context) ([]ChannelGroup, error) {
+	lister, supported := s.vdrClient.(ports.ChannelGroupLister)
+	if !supported {
+		return nil, nil
+	}
+	chs, err := lister.GetChannelGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var groups []ChannelGroup
+	index := map[string]int{}
+	for _, ch := range chs {
+		if strings.TrimSpace(ch.Group) == "" || !s.isWantedChannel(ch.ID) {
+			continue
+		}
+		i, ok := index[ch.Group]
+		if !ok {
+			i = len(groups)
+			index[ch.Group] = i
+			groups = append(groups, ChannelGroup{Name: ch.Group})
+		}
+		groups[i].Channels = append(groups[i].Channels, ch)
+	}
+	return groups, nil
+}
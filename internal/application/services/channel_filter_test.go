@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestEPGService_ChannelFilter_NarrowsAndOrdersPerRequest(t *testing.T) {
+	now := time.Now()
+	client := &ports.MockVDRClient{
+		GetChannelsFunc: func(ctx context.Context) ([]domain.Channel, error) {
+			return []domain.Channel{
+				{ID: "C-1-1-1", Number: 1, Name: "One"},
+				{ID: "C-2-2-2", Number: 2, Name: "Two"},
+				{ID: "C-3-3-3", Number: 3, Name: "Three"},
+			}, nil
+		},
+		GetEPGFunc: func(ctx context.Context, channelID string, at time.Time) ([]domain.EPGEvent, error) {
+			return []domain.EPGEvent{
+				{EventID: 1, ChannelID: "C-1-1-1", ChannelNumber: 1, Title: "A", Start: now.Add(-time.Minute), Stop: now.Add(time.Hour)},
+				{EventID: 2, ChannelID: "C-2-2-2", ChannelNumber: 2, Title: "B", Start: now.Add(-time.Minute), Stop: now.Add(time.Hour)},
+				{EventID: 3, ChannelID: "C-3-3-3", ChannelNumber: 3, Title: "C", Start: now.Add(-time.Minute), Stop: now.Add(time.Hour)},
+			}, nil
+		},
+	}
+	svc := NewEPGService(client, time.Minute)
+	svc.SetWantedChannels([]string{"C-1-1-1", "C-3-3-3"})
+
+	// The favourites list names an unwanted channel; the wanted channels still apply.
+	ctx := WithChannelFilter(context.Background(), NewChannelFilter("Kids", []string{"C-3-3-3", "C-2-2-2", "C-1-1-1"}))
+
+	chs, err := svc.GetChannels(ctx)
+	if err != nil {
+		t.Fatalf("GetChannels: %v", err)
+	}
+	if got := channelIDs(chs); got != "C-3-3-3,C-1-1-1" {
+		t.Fatalf("channels = %s, want the list's order", got)
+	}
+
+	programs, err := svc.GetCurrentPrograms(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentPrograms: %v", err)
+	}
+	if len(programs) != 2 || programs[0].Title != "C" || programs[1].Title != "A" {
+		t.Fatalf("programs = %+v", programs)
+	}
+
+	// Another request without a filter gets the cached programs of all wanted channels.
+	programs, err = svc.GetCurrentPrograms(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentPrograms: %v", err)
+	}
+	if len(programs) != 2 || programs[0].Title != "A" || programs[1].Title != "C" {
+		t.Fatalf("unfiltered programs = %+v", programs)
+	}
+
+	one := WithChannelFilter(context.Background(), NewChannelFilter("One", []string{"C-1-1-1"}))
+	if _, err := svc.GetEPG(context.Background(), "", time.Time{}); err != nil {
+		t.Fatalf("GetEPG: %v", err)
+	}
+	events, err := svc.SearchEPG(one, "")
+	if err != nil {
+		t.Fatalf("SearchEPG: %v", err)
+	}
+	if len(events) != 1 || events[0].ChannelID != "C-1-1-1" {
+		t.Fatalf("search results = %+v", events)
+	}
+	if events, _ := svc.GetEPG(one, "C-3-3-3", time.Time{}); len(events) != 0 {
+		t.Fatalf("expected no EPG for a channel outside the list, got %+v", events)
+	}
+	if all, _ := svc.GetEPG(context.Background(), "", time.Time{}); len(all) != 2 {
+		t.Fatalf("filtering changed the cached EPG: %+v", all)
+	}
+}
+
+// groupsVDR reports the channels.conf groups of its channels.
+type groupsVDR struct {
+	*ports.MockVDRClient
+	grouped []domain.Channel
+}
+
+func (v groupsVDR) GetChannelGroups(ctx context.Context) ([]domain.Channel, error) {
+	return v.grouped, nil
+}
+
+func TestEPGService_GetChannelGroups(t *testing.T) {
+	svc := NewEPGService(ports.NewMockVDRClient(), time.Minute)
+	if groups, err := svc.GetChannelGroups(context.Background()); err != nil || groups != nil {
+		t.Fatalf("without group support: groups = %+v, err = %v", groups, err)
+	}
+
+	svc = NewEPGService(groupsVDR{ports.NewMockVDRClient(), []domain.Channel{
+		{ID: "C-0-0-0", Name: "Loose"},
+		{ID: "C-1-1-1", Name: "Das Erste", Group: "News"},
+		{ID: "C-2-2-2", Name: "KiKA", Group: "Kids"},
+		{ID: "C-3-3-3", Name: "tagesschau24", Group: "News"},
+		{ID: "C-4-4-4", Name: "Unwanted", Group: "Shopping"},
+	}}, time.Minute)
+	svc.SetWantedChannels([]string{"C-0-0-0", "C-1-1-1", "C-2-2-2", "C-3-3-3"})
+
+	groups, err := svc.GetChannelGroups(context.Background())
+	if err != nil {
+		t.Fatalf("GetChannelGroups: %v", err)
+	}
+	if len(groups) != 2 || groups[0].Name != "News" || groups[1].Name != "Kids" {
+		t.Fatalf("groups = %+v", groups)
+	}
+	if got := channelIDs(groups[0].Channels); got != "C-1-1-1,C-3-3-3" {
+		t.Fatalf("News channels = %s", got)
+	}
+}
+
+func channelIDs(chs []domain.Channel) string {
+	ids := make([]string, 0, len(chs))
+	for _, ch := range chs {
+		ids = append(ids, ch.ID)
+	}
+	return strings.Join(ids, ",")
+}
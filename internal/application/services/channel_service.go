@@ -0,0 +1,400 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// ChannelService edits VDR's channel list. VDR addresses channels by number,
+// so every change looks the channels up by ID in a fresh LSTC listing first.
+type ChannelService struct {
+	vdrClient  ports.VDRClient
+	epgService *EPGService
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// ChannelImportResult tells what restoring a channels.conf snapshot changed.
+type ChannelImportResult struct {
+	Created int
+	Updated int
+	Deleted int
+	Moved   int
+	// Groups counts the group separators of the snapshot, which SVDRP cannot create.
+	Groups int
+	// Failed holds VDR's refusals, e.g. deleting a channel a timer records from.
+	Failed []error
+}
+
+// NewChannelService creates a channel service. epgService may be nil; its
+// channel and EPG caches are dropped after every change.
+func NewChannelService(vdrClient ports.VDRClient, epgService *EPGService) *ChannelService {
+	return &ChannelService{vdrClient: vdrClient, epgService: epgService, now: time.Now}
+}
+
+// GetChannels returns VDR's channel list, bypassing the EPG service's cache.
+func (s *ChannelService) GetChannels(ctx context.Context) ([]domain.Channel, error) {
+	return s.vdrClient.GetChannels(ctx)
+}
+
+// RenameChannel replaces the name of a channel and keeps its short name and provider.
+func (s *ChannelService) RenameChannel(ctx context.Context, channelID, name string) (err error) {
+	ctx, span := startSpan(ctx, "ChannelService.RenameChannel")
+	defer func() { endSpan(span, err) }()
+
+	name = strings.TrimSpace(name)
+	if name == "" || strings.ContainsAny(name, ",;\r\n") {
+		return fmt.Errorf("%w: invalid channel name %q", domain.ErrInvalidInput, name)
+	}
+	ch, err := s.channel(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	settings, err := renameChannelSettings(ch.Settings, name)
+	if err != nil {
+		return err
+	}
+	if err := s.vdrClient.UpdateChannel(ctx, ch.Number, settings); err != nil {
+		return fmt.Errorf("channel %s: %w", ch.Name, err)
+	}
+	s.invalidate()
+	return nil
+}
+
+// MoveChannel gives a channel the number of the channel currently at
+// position to (1-based); the channels in between shift by one.
+func (s *ChannelService) MoveChannel(ctx context.Context, channelID string, to int) (err error) {
+	ctx, span := startSpan(ctx, "ChannelService.MoveChannel")
+	defer func() { endSpan(span, err) }()
+
+	chs, err := s.vdrClient.GetChannels(ctx)
+	if err != nil {
+		return err
+	}
+	from := slices.IndexFunc(chs, func(ch domain.Channel) bool { return ch.ID == channelID })
+	if from < 0 {
+		return fmt.Errorf("%w: channel %s", domain.ErrNotFound, channelID)
+	}
+	if to < 1 || to > len(chs) {
+		return fmt.Errorf("%w: position %d out of range", domain.ErrInvalidInput, to)
+	}
+	if from == to-1 {
+		return nil
+	}
+	if err := s.vdrClient.MoveChannel(ctx, chs[from].Number, chs[to-1].Number); err != nil {
+		return fmt.Errorf("channel %s: %w", chs[from].Name, err)
+	}
+	s.invalidate()
+	return nil
+}
+
+// CreateChannel appends a channel given as a channels.conf line and returns its number.
+func (s *ChannelService) CreateChannel(ctx context.Context, settings string) (number int, err error) {
+	ctx, span := startSpan(ctx, "ChannelService.CreateChannel")
+	defer func() { endSpan(span, err) }()
+
+	settings, err = cleanChannelSettings(settings)
+	if err != nil {
+		return 0, err
+	}
+	number, err = s.vdrClient.CreateChannel(ctx, settings)
+	if err != nil {
+		return 0, err
+	}
+	s.invalidate()
+	return number, nil
+}
+
+// DeleteChannels deletes the given channels. It tries every channel and
+// returns how many were deleted along with the joined errors of the others.
+func (s *ChannelService) DeleteChannels(ctx context.Context, channelIDs []string) (deleted int, err error) {
+	ctx, span := startSpan(ctx, "ChannelService.DeleteChannels")
+	defer func() { endSpan(span, err) }()
+
+	if len(channelIDs) == 0 {
+		return 0, fmt.Errorf("%w: no channels selected", domain.ErrInvalidInput)
+	}
+	chs, err := s.vdrClient.GetChannels(ctx)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[string]domain.Channel, len(chs))
+	for _, ch := range chs {
+		byID[ch.ID] = ch
+	}
+
+	var errs []error
+	var doomed []domain.Channel
+	for _, id := range channelIDs {
+		ch, ok := byID[id]
+		if !ok {
+			errs = append(errs, fmt.Errorf("channel %s: %w", id, domain.ErrNotFound))
+			continue
+		}
+		doomed = append(doomed, ch)
+	}
+	deleted, delErrs := s.deleteChannels(ctx, doomed)
+	if deleted > 0 {
+		s.invalidate()
+	}
+	return deleted, errors.Join(append(errs, delErrs...)...)
+}
+
+// deleteChannels deletes channels from the highest number down, so the
+// numbers of the channels still to delete stay valid.
+func (s *ChannelService) deleteChannels(ctx context.Context, chs []domain.Channel) (int, []error) {
+	sort.Slice(chs, func(i, j int) bool { return chs[i].Number > chs[j].Number })
+	deleted := 0
+	var errs []error
+	for _, ch := range chs {
+		if err := s.vdrClient.DeleteChannel(ctx, ch.Number); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", ch.Name, err))
+			continue
+		}
+		deleted++
+	}
+	return deleted, errs
+}
+
+// ExportChannels writes VDR's channel list as a channels.conf file.
+func (s *ChannelService) ExportChannels(ctx context.Context, w io.Writer) (err error) {
+	ctx, span := startSpan(ctx, "ChannelService.ExportChannels")
+	defer func() { endSpan(span, err) }()
+
+	chs, err := s.vdrClient.GetChannels(ctx)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# channels.conf exported by vdradmin-go at %s\n", s.now().Format(time.RFC3339))
+	for _, ch := range chs {
+		if ch.Settings == "" {
+			return fmt.Errorf("channel %s: VDR did not list its settings", ch.Name)
+		}
+		fmt.Fprintln(bw, ch.Settings)
+	}
+	return bw.Flush()
+}
+
+// ImportChannels makes VDR's channel list match a channels.conf snapshot:
+// channels missing from the snapshot are deleted, changed ones updated,
+// missing ones created, and all of them put in the snapshot's order. Channels
+// VDR refuses to delete stay behind the snapshot's channels.
+func (s *ChannelService) ImportChannels(ctx context.Context, r io.Reader) (res ChannelImportResult, err error) {
+	ctx, span := startSpan(ctx, "ChannelService.ImportChannels")
+	defer func() { endSpan(span, err) }()
+
+	snapshot, groups, err := ParseChannelsConf(r)
+	if err != nil {
+		return res, err
+	}
+	res.Groups = groups
+	defer func() {
+		if res.Created+res.Updated+res.Deleted+res.Moved > 0 {
+			s.invalidate()
+		}
+	}()
+
+	current, err := s.vdrClient.GetChannels(ctx)
+	if err != nil {
+		return res, err
+	}
+	wanted := make(map[string]string, len(snapshot))
+	for _, ch := range snapshot {
+		wanted[ch.ID] = ch.Settings
+	}
+	var doomed []domain.Channel
+	for _, ch := range current {
+		if _, ok := wanted[ch.ID]; !ok {
+			doomed = append(doomed, ch)
+		}
+	}
+	res.Deleted, res.Failed = s.deleteChannels(ctx, doomed)
+
+	if current, err = s.vdrClient.GetChannels(ctx); err != nil {
+		return res, err
+	}
+	have := make(map[string]bool, len(current))
+	for _, ch := range current {
+		have[ch.ID] = true
+		settings, ok := wanted[ch.ID]
+		if !ok || settings == ch.Settings {
+			continue
+		}
+		if err := s.vdrClient.UpdateChannel(ctx, ch.Number, settings); err != nil {
+			res.Failed = append(res.Failed, fmt.Errorf("channel %s: %w", ch.Name, err))
+			continue
+		}
+		res.Updated++
+	}
+	for _, ch := range snapshot {
+		if have[ch.ID] {
+			continue
+		}
+		if _, err := s.vdrClient.CreateChannel(ctx, ch.Settings); err != nil {
+			res.Failed = append(res.Failed, fmt.Errorf("channel %s: %w", ch.Name, err))
+			continue
+		}
+		res.Created++
+	}
+
+	if current, err = s.vdrClient.GetChannels(ctx); err != nil {
+		return res, err
+	}
+	res.Moved, err = s.reorder(ctx, current, snapshot)
+	return res, err
+}
+
+// reorder moves the channels into the order of want. Channels not in want
+// end up behind the others.
+func (s *ChannelService) reorder(ctx context.Context, current, want []domain.Channel) (int, error) {
+	numbers := make([]int, len(current))
+	order := make([]string, len(current))
+	for i, ch := range current {
+		numbers[i] = ch.Number
+		order[i] = ch.ID
+	}
+	moved := 0
+	pos := 0
+	for _, ch := range want {
+		j := slices.Index(order[pos:], ch.ID)
+		if j < 0 {
+			continue
+		}
+		j += pos
+		if j != pos {
+			// MOVC puts a channel moved to a lower number in front of the
+			// channel that had it.
+			if err := s.vdrClient.MoveChannel(ctx, numbers[j], numbers[pos]); err != nil {
+				return moved, fmt.Errorf("channel %s: %w", ch.Name, err)
+			}
+			id := order[j]
+			copy(order[pos+1:j+1], order[pos:j])
+			order[pos] = id
+			moved++
+		}
+		pos++
+	}
+	return moved, nil
+}
+
+func (s *ChannelService) channel(ctx context.Context, channelID string) (domain.Channel, error) {
+	chs, err := s.vdrClient.GetChannels(ctx)
+	if err != nil {
+		return domain.Channel{}, err
+	}
+	for _, ch := range chs {
+		if ch.ID == channelID {
+			return ch, nil
+		}
+	}
+	return domain.Channel{}, fmt.Errorf("%w: channel %s", domain.ErrNotFound, channelID)
+}
+
+func (s *ChannelService) invalidate() {
+	if s.epgService != nil {
+		s.epgService.InvalidateAllCaches()
+	}
+}
+
+// ParseChannelsConf reads the channels of a channels.conf file in order and
+// counts its group separators (":name" lines). Comments and empty lines are
+// skipped.
+func ParseChannelsConf(r io.Reader) (chs []domain.Channel, groups int, err error) {
+	seen := make(map[string]int)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, ":"):
+			groups++
+			continue
+		}
+		settings, err := cleanChannelSettings(line)
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", n, err)
+		}
+		id := ChannelSettingsID(settings)
+		if first, dup := seen[id]; dup {
+			return nil, 0, fmt.Errorf("%w: line %d repeats the channel of line %d", domain.ErrInvalidInput, n, first)
+		}
+		seen[id] = n
+		chs = append(chs, domain.Channel{ID: id, Name: channelSettingsName(settings), Settings: settings})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(chs) == 0 {
+		return nil, 0, fmt.Errorf("%w: no channels found", domain.ErrInvalidInput)
+	}
+	return chs, groups, nil
+}
+
+// ChannelSettingsID returns the channel ID (source-NID-TID-SID) of a
+// channels.conf line, as the SVDRP client derives it from LSTC.
+func ChannelSettingsID(settings string) string {
+	f := strings.Split(settings, ":")
+	if len(f) < 12 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", strings.TrimSpace(f[3]), strings.TrimSpace(f[10]), strings.TrimSpace(f[11]), strings.TrimSpace(f[9]))
+}
+
+// cleanChannelSettings validates a channels.conf line: 13 fields, a name and
+// the IDs that make up the channel ID.
+func cleanChannelSettings(settings string) (string, error) {
+	settings = strings.TrimSpace(settings)
+	if strings.ContainsAny(settings, "\r\n") {
+		return "", fmt.Errorf("%w: channel settings span several lines", domain.ErrInvalidInput)
+	}
+	f := strings.Split(settings, ":")
+	if len(f) != 13 {
+		return "", fmt.Errorf("%w: channel settings need 13 fields, got %d", domain.ErrInvalidInput, len(f))
+	}
+	if channelSettingsName(settings) == "" {
+		return "", fmt.Errorf("%w: channel settings without a name", domain.ErrInvalidInput)
+	}
+	for _, i := range []int{3, 9, 10, 11} {
+		if strings.TrimSpace(f[i]) == "" {
+			return "", fmt.Errorf("%w: channel settings without source, SID, NID or TID", domain.ErrInvalidInput)
+		}
+	}
+	return settings, nil
+}
+
+// channelSettingsName returns the name of a channels.conf line without its
+// short name and provider.
+func channelSettingsName(settings string) string {
+	field, _, _ := strings.Cut(settings, ":")
+	name, _, _ := strings.Cut(field, ";")
+	name, _, _ = strings.Cut(name, ",")
+	return strings.TrimSpace(name)
+}
+
+// renameChannelSettings replaces the name in a channels.conf line. VDR
+// stores ':' in names as '|'.
+func renameChannelSettings(settings, name string) (string, error) {
+	if settings == "" {
+		return "", errors.New("VDR did not list the channel's settings")
+	}
+	field, rest, _ := strings.Cut(settings, ":")
+	end := strings.IndexAny(field, ",;")
+	if end < 0 {
+		end = len(field)
+	}
+	return strings.ReplaceAll(name, ":", "|") + field[end:] + ":" + rest, nil
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// channelListVDR derives the channel IDs of the mock's LSTC listing from the
+// settings, like the SVDRP client does.
+type channelListVDR struct {
+	*ports.MockVDRClient
+}
+
+func (v channelListVDR) GetChannels(ctx context.Context) ([]domain.Channel, error) {
+	chs, err := v.MockVDRClient.GetChannels(ctx)
+	out := make([]domain.Channel, len(chs))
+	for i, ch := range chs {
+		ch.ID = ChannelSettingsID(ch.Settings)
+		ch.Name = channelSettingsName(ch.Settings)
+		out[i] = ch
+	}
+	return out, err
+}
+
+// channelConf returns a channels.conf line of a DVB-S channel with the given SID.
+func channelConf(name, sid string) string {
+	return name + ";ARD:11494:hC23M5O35S1:S19.2E:22000:5101=27:5102=deu@3:5104:0:" + sid + ":1:1019:0"
+}
+
+func newChannelVDR(lines ...string) channelListVDR {
+	chs := make([]domain.Channel, 0, len(lines))
+	for i, l := range lines {
+		chs = append(chs, domain.Channel{ID: ChannelSettingsID(l), Number: i + 1, Name: channelSettingsName(l), Settings: l})
+	}
+	return channelListVDR{ports.NewMockVDRClient().WithChannels(chs)}
+}
+
+func channelNames(t *testing.T, vdr ports.VDRClient) string {
+	t.Helper()
+	chs, err := vdr.GetChannels(context.Background())
+	if err != nil {
+		t.Fatalf("GetChannels: %v", err)
+	}
+	names := make([]string, 0, len(chs))
+	for _, ch := range chs {
+		names = append(names, ch.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestParseChannelsConf(t *testing.T) {
+	conf := "# snapshot\n:News\n" + channelConf("Das Erste HD", "10301") + "\n\n:@10 Movies\n" + channelConf("arte HD", "10302") + "\n"
+	chs, groups, err := ParseChannelsConf(strings.NewReader(conf))
+	if err != nil {
+		t.Fatalf("ParseChannelsConf: %v", err)
+	}
+	if groups != 2 {
+		t.Fatalf("groups = %d, want 2", groups)
+	}
+	if len(chs) != 2 || chs[0].Name != "Das Erste HD" || chs[1].ID != "S19.2E-1-1019-10302" {
+		t.Fatalf("channels = %+v", chs)
+	}
+
+	for name, conf := range map[string]string{
+		"empty":     "# nothing\n",
+		"fields":    "Das Erste HD;ARD:11494:hC23M5O35S1:S19.2E\n",
+		"duplicate": channelConf("A", "1") + "\n" + channelConf("B", "1") + "\n",
+		"no sid":    channelConf("A", "") + "\n",
+	} {
+		if _, _, err := ParseChannelsConf(strings.NewReader(conf)); !errors.Is(err, domain.ErrInvalidInput) {
+			t.Errorf("%s: err = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
+
+func TestChannelService_RenameChannelKeepsShortNameAndProvider(t *testing.T) {
+	vdr := newChannelVDR("Das Erste HD,ARD;ARD:11494:hC23M5O35S1:S19.2E:22000:5101=27:5102=deu@3:5104:0:10301:1:1019:0")
+	var got string
+	vdr.UpdateChannelFunc = func(ctx context.Context, number int, settings string) error {
+		if number != 1 {
+			t.Errorf("number = %d, want 1", number)
+		}
+		got = settings
+		return nil
+	}
+
+	svc := NewChannelService(vdr, nil)
+	if err := svc.RenameChannel(context.Background(), "S19.2E-1-1019-10301", "Erste: HD"); err != nil {
+		t.Fatalf("RenameChannel: %v", err)
+	}
+	if want := "Erste| HD,ARD;ARD:11494:hC23M5O35S1:S19.2E:22000:5101=27:5102=deu@3:5104:0:10301:1:1019:0"; got != want {
+		t.Fatalf("settings = %q, want %q", got, want)
+	}
+	if err := svc.RenameChannel(context.Background(), "S19.2E-1-1019-10301", "A;B"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("name with ';': err = %v, want ErrInvalidInput", err)
+	}
+	if err := svc.RenameChannel(context.Background(), "S19.2E-1-1019-1", "X"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("unknown channel: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestChannelService_MoveChannel(t *testing.T) {
+	vdr := newChannelVDR(channelConf("A", "1"), channelConf("B", "2"), channelConf("C", "3"))
+	svc := NewChannelService(vdr, nil)
+
+	if err := svc.MoveChannel(context.Background(), "S19.2E-1-1019-3", 1); err != nil {
+		t.Fatalf("MoveChannel: %v", err)
+	}
+	if got := channelNames(t, vdr); got != "C,A,B" {
+		t.Fatalf("order = %s, want C,A,B", got)
+	}
+	if err := svc.MoveChannel(context.Background(), "S19.2E-1-1019-3", 4); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("position 4: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestChannelService_DeleteChannels(t *testing.T) {
+	lines := []string{channelConf("A", "1"), channelConf("B OBSOLETE", "2"), channelConf("C OBSOLETE", "3"), channelConf("D", "4")}
+	dead := []string{"S19.2E-1-1019-2", "S19.2E-1-1019-3"}
+
+	t.Run("HighestNumberFirst", func(t *testing.T) {
+		vdr := newChannelVDR(lines...)
+		deleted, err := NewChannelService(vdr, nil).DeleteChannels(context.Background(), dead)
+		if err != nil || deleted != 2 {
+			t.Fatalf("DeleteChannels = %d, %v; want 2, nil", deleted, err)
+		}
+		// Deleting channel 2 first would have made D channel 3.
+		if got := channelNames(t, vdr); got != "A,D" {
+			t.Fatalf("channels = %s, want A,D", got)
+		}
+	})
+
+	t.Run("ChannelInUse", func(t *testing.T) {
+		vdr := newChannelVDR(lines...)
+		vdr.WithTimers([]domain.Timer{{ID: 1, ChannelID: "S19.2E-1-1019-2"}})
+		deleted, err := NewChannelService(vdr, nil).DeleteChannels(context.Background(), dead)
+		if deleted != 1 || !errors.Is(err, domain.ErrChannelInUse) {
+			t.Fatalf("DeleteChannels = %d, %v; want 1, ErrChannelInUse", deleted, err)
+		}
+		if got := channelNames(t, vdr); got != "A,B OBSOLETE,D" {
+			t.Fatalf("channels = %s, want A,B OBSOLETE,D", got)
+		}
+	})
+}
+
+func TestChannelService_ImportChannelsRestoresSnapshot(t *testing.T) {
+	a, b, c, d := channelConf("A", "1"), channelConf("B", "2"), channelConf("C", "3"), channelConf("D", "4")
+	snapshot := strings.Join([]string{"# snapshot", ":Group", c, a, d, b}, "\n")
+
+	// After a bad scan: C has moved transponder data, D is gone and two new
+	// channels showed up, one of them recorded by a timer.
+	vdr := newChannelVDR(a, channelConf("X", "9"), b, strings.Replace(c, "11494", "11523", 1), channelConf("Y", "8"))
+	vdr.WithTimers([]domain.Timer{{ID: 1, ChannelID: "S19.2E-1-1019-8"}})
+
+	res, err := NewChannelService(vdr, nil).ImportChannels(context.Background(), strings.NewReader(snapshot))
+	if err != nil {
+		t.Fatalf("ImportChannels: %v", err)
+	}
+	if res.Deleted != 1 || res.Updated != 1 || res.Created != 1 || res.Groups != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	if len(res.Failed) != 1 || !errors.Is(res.Failed[0], domain.ErrChannelInUse) {
+		t.Fatalf("failed = %v, want Y in use", res.Failed)
+	}
+	if got := channelNames(t, vdr); got != "C,A,D,B,Y" {
+		t.Fatalf("order = %s, want C,A,D,B,Y", got)
+	}
+	chs, _ := vdr.GetChannels(context.Background())
+	if chs[0].Settings != c {
+		t.Fatalf("C settings = %q, want %q", chs[0].Settings, c)
+	}
+}
+
+func TestChannelService_ExportChannelsRoundTrips(t *testing.T) {
+	vdr := newChannelVDR(channelConf("A", "1"), channelConf("B", "2"))
+	svc := NewChannelService(vdr, nil)
+	svc.now = func() time.Time { return time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC) }
+
+	var buf bytes.Buffer
+	if err := svc.ExportChannels(context.Background(), &buf); err != nil {
+		t.Fatalf("ExportChannels: %v", err)
+	}
+	want := "# channels.conf exported by vdradmin-go at 2026-10-16T20:00:00Z\n" + channelConf("A", "1") + "\n" + channelConf("B", "2") + "\n"
+	if buf.String() != want {
+		t.Fatalf("export = %q, want %q", buf.String(), want)
+	}
+
+	res, err := svc.ImportChannels(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("ImportChannels: %v", err)
+	}
+	if res.Created+res.Updated+res.Deleted+res.Moved != 0 || len(res.Failed) != 0 {
+		t.Fatalf("re-importing the export changed channels: %+v", res)
+	}
+}
@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// Rules for comparing titles and subtitles of episodes.
+const (
+	DedupMatchExact      = "exact"
+	DedupMatchNormalized = "normalized"
+	DedupMatchFuzzy      = "fuzzy"
+)
+
+// dedupFingerprintWords is the number of description words a fingerprint covers.
+// Broadcasters tend to cut long descriptions differently, the start is stable.
+const dedupFingerprintWords = 40
+
+// DedupService recognises episodes that were recorded or scheduled before, so
+// search timers and AutoTimers do not record every repeat. It compares title
+// and subtitle, optionally a fingerprint of the description, with timers,
+// recordings and a persisted done list of the episodes they scheduled.
+type DedupService struct {
+	mu          sync.RWMutex
+	store       ports.DoneStore
+	done        []domain.DoneEntry
+	match       string
+	description bool
+	recordings  RecordingLister
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// NewDedupService creates a de-duplication service comparing normalized titles and subtitles.
+func NewDedupService() *DedupService {
+	return &DedupService{
+		match: DedupMatchNormalized,
+		now:   time.Now,
+	}
+}
+
+// SetStore configures persistent storage and loads the stored done list.
+// Subsequent changes are written back to the store.
+func (s *DedupService) SetStore(store ports.DoneStore) error {
+	done, err := store.LoadDone()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	s.done = done
+	return nil
+}
+
+// SetRules sets how titles and subtitles are compared (DedupMatch*) and
+// whether description fingerprints are compared as well.
+// Unknown match rules fall back to DedupMatchNormalized.
+func (s *DedupService) SetRules(match string, description bool) {
+	switch match {
+	case DedupMatchExact, DedupMatchFuzzy:
+	default:
+		match = DedupMatchNormalized
+	}
+	s.mu.Lock()
+	s.match = match
+	s.description = description
+	s.mu.Unlock()
+}
+
+// SetRecordingLister sets the source of recordings. Without one, episodes are
+// not compared with recordings.
+func (s *DedupService) SetRecordingLister(l RecordingLister) {
+	s.mu.Lock()
+	s.recordings = l
+	s.mu.Unlock()
+}
+
+// Done returns the done list, oldest entry first.
+func (s *DedupService) Done() []domain.DoneEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]domain.DoneEntry(nil), s.done...)
+}
+
+// MarkDone adds scheduled events to the done list.
+func (s *DedupService) MarkDone(source string, events ...domain.EPGEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	added := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	updated := append([]domain.DoneEntry(nil), s.done...)
+	for _, ev := range events {
+		updated = append(updated, domain.DoneEntry{
+			Title:       ev.Title,
+			Subtitle:    ev.Subtitle,
+			Fingerprint: DescriptionFingerprint(ev.Description),
+			ChannelID:   ev.ChannelID,
+			Start:       ev.Start,
+			EventID:     ev.EventID,
+			Source:      source,
+			Added:       added,
+		})
+	}
+	return s.persistLocked(updated)
+}
+
+// SearchDoneSource is the done list source of episodes scheduled by a saved search.
+func SearchDoneSource(id int) string {
+	return "search " + strconv.Itoa(id)
+}
+
+// AutoTimerDoneSource is the done list source of episodes scheduled by an AutoTimer.
+func AutoTimerDoneSource(id int) string {
+	return "autotimer " + strconv.Itoa(id)
+}
+
+// RemoveDone removes the entry at index (as returned by Done), so the episode
+// may be recorded again.
+func (s *DedupService) RemoveDone(index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index < 0 || index >= len(s.done) {
+		return domain.ErrNotFound
+	}
+	updated := make([]domain.DoneEntry, 0, len(s.done)-1)
+	updated = append(updated, s.done[:index]...)
+	updated = append(updated, s.done[index+1:]...)
+	return s.persistLocked(updated)
+}
+
+// persistLocked writes the given list to the store (if configured) and makes it current.
+// The in-memory list is only replaced when persisting succeeded.
+func (s *DedupService) persistLocked(done []domain.DoneEntry) error {
+	if s.store != nil {
+		if err := s.store.SaveDone(done); err != nil {
+			return err
+		}
+	}
+	s.done = done
+	return nil
+}
+
+// NewCheck collects the episodes of timers, recordings and the done list for
+// one run. Timers are looked up in events for their subtitle and description.
+func (s *DedupService) NewCheck(ctx context.Context, events []domain.EPGEvent, timers []domain.Timer) (*DedupCheck, error) {
+	s.mu.RLock()
+	c := &DedupCheck{match: s.match, description: s.description}
+	recordings := s.recordings
+	done := s.done
+	s.mu.RUnlock()
+
+	byChannel := make(map[string][]domain.EPGEvent)
+	for _, ev := range events {
+		byChannel[ev.ChannelID] = append(byChannel[ev.ChannelID], ev)
+		if ev.ChannelNumber > 0 {
+			n := strconv.Itoa(ev.ChannelNumber)
+			byChannel[n] = append(byChannel[n], ev)
+		}
+	}
+	for _, t := range timers {
+		if ev, ok := timerEvent(t, byChannel[strings.TrimSpace(t.ChannelID)]); ok {
+			c.timers.add(c.episode(ev.Title, ev.Subtitle, ev.Description))
+			continue
+		}
+		// Without its event, the timer only tells the title (behind the folder).
+		title := t.Title
+		if i := strings.LastIndex(title, FolderSeparator); i >= 0 {
+			title = title[i+len(FolderSeparator):]
+		}
+		c.timers.add(c.episode(title, "", ""))
+	}
+
+	if recordings != nil {
+		recs, err := recordings.GetAllRecordings(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("recordings: %w", err)
+		}
+		for _, rec := range recs {
+			if !rec.IsFolder {
+				c.recordings.add(c.episode(rec.Title, rec.Subtitle, rec.Description))
+			}
+		}
+	}
+
+	for _, e := range done {
+		ep := c.episode(e.Title, e.Subtitle, "")
+		ep.fingerprint = e.Fingerprint
+		c.done.add(ep)
+	}
+	return c, nil
+}
+
+// timerEvent returns the event a timer records among the events of its channel.
+func timerEvent(t domain.Timer, events []domain.EPGEvent) (domain.EPGEvent, bool) {
+	if t.EventID > 0 {
+		for _, ev := range events {
+			if ev.EventID == t.EventID {
+				return ev, true
+			}
+		}
+	}
+	if t.Start.IsZero() || t.Stop.IsZero() {
+		return domain.EPGEvent{}, false
+	}
+	for _, ev := range events {
+		if !t.Start.After(ev.Start) && !t.Stop.Before(ev.Stop) {
+			return ev, true
+		}
+	}
+	return domain.EPGEvent{}, false
+}
+
+// DedupCheck tells whether events are episodes recorded or scheduled before.
+// It is built by DedupService.NewCheck for a single run.
+type DedupCheck struct {
+	match       string
+	description bool
+	timers      dedupSet
+	recordings  dedupSet
+	done        dedupSet
+}
+
+// Duplicate reports whether ev is an episode recorded or scheduled before,
+// comparing it with what scope selects, and why.
+// An empty scope is the default, domain.DedupTimers.
+func (c *DedupCheck) Duplicate(ev domain.EPGEvent, scope domain.DedupScope) (string, bool) {
+	if c == nil || scope == domain.DedupNone {
+		return "", false
+	}
+	if scope == "" {
+		scope = domain.DedupTimers
+	}
+	ep := c.episode(ev.Title, ev.Subtitle, ev.Description)
+	if ep.title == "" {
+		return "", false
+	}
+	if c.contains(&c.timers, ep) {
+		return SkipReasonScheduled, true
+	}
+	if scope == domain.DedupTimers {
+		return "", false
+	}
+	if c.contains(&c.recordings, ep) {
+		return SkipReasonRecorded, true
+	}
+	if scope == domain.DedupRecordings {
+		return "", false
+	}
+	if c.contains(&c.done, ep) {
+		return SkipReasonDone, true
+	}
+	return "", false
+}
+
+// AddScheduled counts ev as scheduled for the rest of the run.
+func (c *DedupCheck) AddScheduled(ev domain.EPGEvent) {
+	if c != nil {
+		c.timers.add(c.episode(ev.Title, ev.Subtitle, ev.Description))
+	}
+}
+
+// dedupEpisode is an episode with title and subtitle prepared for the match rule.
+type dedupEpisode struct {
+	title       string
+	subtitle    string
+	fingerprint string
+}
+
+// dedupSet holds episodes by title.
+type dedupSet struct {
+	byTitle map[string][]dedupEpisode
+}
+
+func (set *dedupSet) add(ep dedupEpisode) {
+	if ep.title == "" {
+		return
+	}
+	if set.byTitle == nil {
+		set.byTitle = make(map[string][]dedupEpisode)
+	}
+	set.byTitle[ep.title] = append(set.byTitle[ep.title], ep)
+}
+
+func (c *DedupCheck) episode(title, subtitle, description string) dedupEpisode {
+	ep := dedupEpisode{title: c.key(title), subtitle: c.key(subtitle)}
+	if c.description {
+		ep.fingerprint = DescriptionFingerprint(description)
+	}
+	return ep
+}
+
+// key prepares a title or subtitle for comparison.
+func (c *DedupCheck) key(s string) string {
+	if c.match == DedupMatchExact {
+		return strings.TrimSpace(s)
+	}
+	return strings.Join(epgTokens(s), " ")
+}
+
+func (c *DedupCheck) contains(set *dedupSet, ep dedupEpisode) bool {
+	if c.match != DedupMatchFuzzy {
+		for _, other := range set.byTitle[ep.title] {
+			if c.sameEpisode(ep, other) {
+				return true
+			}
+		}
+		return false
+	}
+	for title, others := range set.byTitle {
+		if !fuzzyEqual(ep.title, title) {
+			continue
+		}
+		for _, other := range others {
+			if c.sameEpisode(ep, other) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameEpisode compares episodes with the same title. Subtitles must match
+// when both have one, as must description fingerprints when compared. Without
+// subtitles on either side, only matching descriptions make them the same:
+// otherwise a single recording without subtitle would stop a whole series, and
+// a daily show without subtitles would be recorded once only.
+func (c *DedupCheck) sameEpisode(a, b dedupEpisode) bool {
+	compared := false
+	if a.subtitle != "" && b.subtitle != "" {
+		if !c.equal(a.subtitle, b.subtitle) {
+			return false
+		}
+		compared = true
+	}
+	if c.description && a.fingerprint != "" && b.fingerprint != "" {
+		if a.fingerprint != b.fingerprint {
+			return false
+		}
+		compared = true
+	}
+	return compared
+}
+
+func (c *DedupCheck) equal(a, b string) bool {
+	if c.match == DedupMatchFuzzy {
+		return fuzzyEqual(a, b)
+	}
+	return a == b
+}
+
+// fuzzyEqual tolerates one typo in short texts and one more per 16 letters.
+func fuzzyEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	n := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if n < 4 {
+		return false
+	}
+	maxDist := 1 + n/16
+	return levenshtein([]rune(a), []rune(b), maxDist) <= maxDist
+}
+
+// DescriptionFingerprint identifies a description by the folded words it
+// starts with. Descriptions of only a few words yield no fingerprint.
+func DescriptionFingerprint(description string) string {
+	words := epgTokens(description)
+	if len(words) < 5 {
+		return ""
+	}
+	if len(words) > dedupFingerprintWords {
+		words = words[:dedupFingerprintWords]
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join(words, " ")))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+type memDoneStore struct {
+	saved   []domain.DoneEntry
+	saveErr error
+}
+
+func (m *memDoneStore) LoadDone() ([]domain.DoneEntry, error) {
+	return append([]domain.DoneEntry(nil), m.saved...), nil
+}
+
+func (m *memDoneStore) SaveDone(entries []domain.DoneEntry) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved = append([]domain.DoneEntry(nil), entries...)
+	return nil
+}
+
+func TestDedupCheck_MatchRules(t *testing.T) {
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{
+		{Title: "Tatort", Subtitle: "Der Fluch des Geldes"},
+		{Title: "Sherlock", Subtitle: ""},
+		{Title: "Krimis", IsFolder: true},
+	})
+	tests := []struct {
+		match string
+		ev    domain.EPGEvent
+		want  bool
+	}{
+		{DedupMatchExact, domain.EPGEvent{Title: "Tatort", Subtitle: "Der Fluch des Geldes"}, true},
+		{DedupMatchExact, domain.EPGEvent{Title: "TATORT", Subtitle: "Der Fluch des Geldes"}, false},
+		{DedupMatchNormalized, domain.EPGEvent{Title: "TATORT", Subtitle: "Der Fluch des Geldes!"}, true},
+		{DedupMatchNormalized, domain.EPGEvent{Title: "Tatort", Subtitle: "Der Fluch des Gelds"}, false},
+		{DedupMatchFuzzy, domain.EPGEvent{Title: "Tatort", Subtitle: "Der Fluch des Gelds"}, true},
+		{DedupMatchFuzzy, domain.EPGEvent{Title: "Tatort", Subtitle: "Der Fluch der Karibik"}, false},
+		// A recording without subtitle does not stop a series.
+		{DedupMatchNormalized, domain.EPGEvent{Title: "Sherlock", Subtitle: "Ein Skandal in Belgravia"}, false},
+		// Nor does it stop a daily show without subtitles.
+		{DedupMatchNormalized, domain.EPGEvent{Title: "Sherlock"}, false},
+		{DedupMatchNormalized, domain.EPGEvent{Title: "Krimis"}, false},
+	}
+	for _, tt := range tests {
+		svc := NewDedupService()
+		svc.SetRules(tt.match, false)
+		svc.SetRecordingLister(NewRecordingService(mock, time.Minute))
+		check, err := svc.NewCheck(context.Background(), nil, nil)
+		if err != nil {
+			t.Fatalf("NewCheck: %v", err)
+		}
+		reason, got := check.Duplicate(tt.ev, domain.DedupAll)
+		if got != tt.want {
+			t.Errorf("%s %q / %q: duplicate = %v, want %v", tt.match, tt.ev.Title, tt.ev.Subtitle, got, tt.want)
+		}
+		if got && reason != SkipReasonRecorded {
+			t.Errorf("%s %q: reason = %q", tt.match, tt.ev.Title, reason)
+		}
+	}
+}
+
+func TestDedupCheck_DescriptionFingerprint(t *testing.T) {
+	desc := "Kommissar Borowski ermittelt in Kiel, wo ein Toter im Hafen gefunden wurde."
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{{Title: "Tatort", Description: desc}})
+	svc := NewDedupService()
+	svc.SetRecordingLister(NewRecordingService(mock, time.Minute))
+
+	// Without subtitles and description rule, episodes cannot be told apart.
+	check, err := svc.NewCheck(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("NewCheck: %v", err)
+	}
+	other := domain.EPGEvent{Title: "Tatort", Description: "Die Kommissare Ballauf und Schenk ermitteln in einem Kölner Hochhaus."}
+	if _, dup := check.Duplicate(other, domain.DedupAll); dup {
+		t.Fatalf("episodes without subtitles must not match by title alone")
+	}
+
+	svc.SetRules(DedupMatchNormalized, true)
+	check, err = svc.NewCheck(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("NewCheck: %v", err)
+	}
+	if _, dup := check.Duplicate(other, domain.DedupAll); dup {
+		t.Fatalf("different descriptions must not match")
+	}
+	same := domain.EPGEvent{Title: "Tatort", Description: "KOMMISSAR Borowski ermittelt in Kiel - wo ein Toter im Hafen gefunden wurde"}
+	if _, dup := check.Duplicate(same, domain.DedupAll); !dup {
+		t.Fatalf("expected the fingerprints to match")
+	}
+	if DescriptionFingerprint("Zu kurz.") != "" {
+		t.Fatalf("short descriptions must not have a fingerprint")
+	}
+}
+
+func TestDedupCheck_Scopes(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	events := []domain.EPGEvent{
+		{EventID: 1, ChannelID: "C-1", ChannelNumber: 1, Title: "Tatort", Subtitle: "Geplant", Start: base, Stop: base.Add(time.Hour)},
+	}
+	timers := []domain.Timer{
+		// LSTT timers name the channel by number and carry no event ID.
+		{ID: 1, ChannelID: "1", Title: "Krimi~Tatort", Start: base.Add(-5 * time.Minute), Stop: base.Add(70 * time.Minute)},
+	}
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{{Title: "Tatort", Subtitle: "Aufgenommen"}})
+	svc := NewDedupService()
+	svc.SetRecordingLister(NewRecordingService(mock, time.Minute))
+	if err := svc.MarkDone(SearchDoneSource(3), domain.EPGEvent{Title: "Tatort", Subtitle: "Erledigt"}); err != nil {
+		t.Fatalf("MarkDone: %v", err)
+	}
+	check, err := svc.NewCheck(context.Background(), events, timers)
+	if err != nil {
+		t.Fatalf("NewCheck: %v", err)
+	}
+
+	repeat := func(subtitle string) domain.EPGEvent {
+		return domain.EPGEvent{EventID: 9, ChannelID: "C-2", Title: "Tatort", Subtitle: subtitle}
+	}
+	tests := []struct {
+		subtitle string
+		scope    domain.DedupScope
+		want     string
+	}{
+		{"Geplant", domain.DedupTimers, SkipReasonScheduled},
+		{"Aufgenommen", domain.DedupTimers, ""},
+		{"Aufgenommen", domain.DedupRecordings, SkipReasonRecorded},
+		{"Erledigt", domain.DedupRecordings, ""},
+		{"Erledigt", domain.DedupAll, SkipReasonDone},
+		{"Erledigt", "", ""},
+		{"Geplant", "", SkipReasonScheduled},
+		{"Geplant", domain.DedupNone, ""},
+		{"Neu", domain.DedupAll, ""},
+	}
+	for _, tt := range tests {
+		if reason, _ := check.Duplicate(repeat(tt.subtitle), tt.scope); reason != tt.want {
+			t.Errorf("%s with scope %q: reason = %q, want %q", tt.subtitle, tt.scope, reason, tt.want)
+		}
+	}
+
+	check.AddScheduled(repeat("Neu"))
+	if reason, _ := check.Duplicate(repeat("Neu"), domain.DedupTimers); reason != SkipReasonScheduled {
+		t.Fatalf("episodes scheduled during the run must count, got %q", reason)
+	}
+}
+
+func TestDedupService_DoneListPersistence(t *testing.T) {
+	store := &memDoneStore{saved: []domain.DoneEntry{{Title: "Alt"}}}
+	svc := NewDedupService()
+	svc.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+	if err := svc.SetStore(store); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+
+	ev := domain.EPGEvent{EventID: 7, ChannelID: "C-1", Title: "Tatort", Subtitle: "Borowski", Description: "Kommissar Borowski ermittelt wieder in Kiel."}
+	if err := svc.MarkDone(SearchDoneSource(2), ev); err != nil {
+		t.Fatalf("MarkDone: %v", err)
+	}
+	if len(store.saved) != 2 {
+		t.Fatalf("expected 2 stored entries, got %+v", store.saved)
+	}
+	e := store.saved[1]
+	if e.Title != "Tatort" || e.Subtitle != "Borowski" || e.EventID != 7 || e.Source != "search 2" || e.Fingerprint == "" || !e.Added.Equal(svc.now()) {
+		t.Fatalf("stored entry = %+v", e)
+	}
+
+	store.saveErr = errors.New("disk full")
+	if err := svc.RemoveDone(0); err == nil {
+		t.Fatalf("expected store error")
+	}
+	if got := len(svc.Done()); got != 2 {
+		t.Fatalf("failed save must not change in-memory state, got %d entries", got)
+	}
+	store.saveErr = nil
+	if err := svc.RemoveDone(0); err != nil {
+		t.Fatalf("RemoveDone: %v", err)
+	}
+	if done := svc.Done(); len(done) != 1 || done[0].Title != "Tatort" {
+		t.Fatalf("done list = %+v", done)
+	}
+	if err := svc.RemoveDone(5); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,454 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// epgField flags the event texts a term occurs in.
+type epgField uint8
+
+const (
+	epgFieldTitle epgField = 1 << iota
+	epgFieldSubtitle
+	epgFieldDesc
+
+	epgFieldsAll = epgFieldTitle | epgFieldSubtitle | epgFieldDesc
+)
+
+// weight ranks a match by the most important text it is in.
+func (f epgField) weight() float64 {
+	switch {
+	case f&epgFieldTitle != 0:
+		return 3
+	case f&epgFieldSubtitle != 0:
+		return 2
+	case f&epgFieldDesc != 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Quality of a term matching a query word.
+const (
+	epgMatchExact  = 1.0
+	epgMatchPrefix = 0.7
+	epgMatchInfix  = 0.4
+	epgMatchFuzzy  = 0.3
+)
+
+// epgPosting is an indexed event containing a term.
+type epgPosting struct {
+	doc    int32
+	fields epgField
+}
+
+// epgDocKey identifies an event across EPG refreshes.
+type epgDocKey struct {
+	channelID string
+	eventID   int
+	start     int64
+}
+
+type epgDoc struct {
+	event domain.EPGEvent
+	key   epgDocKey
+	// pos is the position of the event in the last indexed EPG.
+	pos int
+	// gen is the refresh that last saw the event.
+	gen  uint64
+	live bool
+	// title and subtitle are folded, for ranking phrase matches.
+	title    string
+	subtitle string
+}
+
+// epgIndex is an in-memory inverted index over the title, subtitle and
+// description of the EPG events. Texts are lower-cased and folded (ä → a,
+// ß → ss, é → e, ...) and split into words of letters and digits.
+//
+// update applies a new EPG snapshot incrementally: unchanged events keep
+// their postings, changed and vanished ones are marked dead and the postings
+// are compacted once half of the events are dead. The zero value is an empty
+// index.
+type epgIndex struct {
+	mu       sync.RWMutex
+	docs     []epgDoc
+	byKey    map[epgDocKey]int32
+	postings map[string][]epgPosting
+	// vocab holds the terms of postings in sorted order, for prefix lookups.
+	vocab      []string
+	vocabDirty bool
+	dead       int
+	gen        uint64
+}
+
+// update makes events the indexed EPG.
+func (x *epgIndex) update(events []domain.EPGEvent) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.byKey == nil {
+		x.byKey = make(map[epgDocKey]int32, len(events))
+		x.postings = make(map[string][]epgPosting)
+	}
+	x.gen++
+	for pos, ev := range events {
+		key := epgDocKey{channelID: ev.ChannelID, eventID: ev.EventID, start: ev.Start.Unix()}
+		if id, ok := x.byKey[key]; ok {
+			d := &x.docs[id]
+			if d.gen == x.gen {
+				// The same event twice in one snapshot.
+				continue
+			}
+			if d.event.Title == ev.Title && d.event.Subtitle == ev.Subtitle && d.event.Description == ev.Description {
+				d.event, d.pos, d.gen = ev, pos, x.gen
+				continue
+			}
+			x.remove(id)
+		}
+		x.add(ev, key, pos)
+	}
+	for id := range x.docs {
+		if x.docs[id].live && x.docs[id].gen != x.gen {
+			x.remove(int32(id))
+		}
+	}
+	if x.dead > 0 && x.dead*2 >= len(x.docs) {
+		x.compact()
+	}
+	if x.vocabDirty {
+		x.vocab = make([]string, 0, len(x.postings))
+		for term := range x.postings {
+			x.vocab = append(x.vocab, term)
+		}
+		sort.Strings(x.vocab)
+		x.vocabDirty = false
+	}
+}
+
+func (x *epgIndex) add(ev domain.EPGEvent, key epgDocKey, pos int) {
+	id := int32(len(x.docs))
+	terms := map[string]epgField{}
+	for _, f := range []struct {
+		text  string
+		field epgField
+	}{{ev.Title, epgFieldTitle}, {ev.Subtitle, epgFieldSubtitle}, {ev.Description, epgFieldDesc}} {
+		for _, term := range epgTokens(f.text) {
+			terms[term] |= f.field
+		}
+	}
+	for term, fields := range terms {
+		if len(x.postings[term]) == 0 {
+			x.vocabDirty = true
+		}
+		x.postings[term] = append(x.postings[term], epgPosting{doc: id, fields: fields})
+	}
+	x.docs = append(x.docs, epgDoc{
+		event:    ev,
+		key:      key,
+		pos:      pos,
+		gen:      x.gen,
+		live:     true,
+		title:    strings.Join(epgTokens(ev.Title), " "),
+		subtitle: strings.Join(epgTokens(ev.Subtitle), " "),
+	})
+	x.byKey[key] = id
+}
+
+func (x *epgIndex) remove(id int32) {
+	d := &x.docs[id]
+	d.live = false
+	x.dead++
+	if cur, ok := x.byKey[d.key]; ok && cur == id {
+		delete(x.byKey, d.key)
+	}
+}
+
+// compact drops dead events and renumbers the live ones, keeping postings sorted.
+func (x *epgIndex) compact() {
+	remap := make([]int32, len(x.docs))
+	docs := make([]epgDoc, 0, len(x.docs)-x.dead)
+	for id, d := range x.docs {
+		remap[id] = -1
+		if d.live {
+			remap[id] = int32(len(docs))
+			docs = append(docs, d)
+		}
+	}
+	for term, postings := range x.postings {
+		kept := postings[:0]
+		for _, p := range postings {
+			if n := remap[p.doc]; n >= 0 {
+				kept = append(kept, epgPosting{doc: n, fields: p.fields})
+			}
+		}
+		if len(kept) == 0 {
+			delete(x.postings, term)
+			x.vocabDirty = true
+			continue
+		}
+		x.postings[term] = kept
+	}
+	x.docs = docs
+	x.byKey = make(map[epgDocKey]int32, len(docs))
+	for id, d := range docs {
+		x.byKey[d.key] = int32(id)
+	}
+	x.dead = 0
+}
+
+// search returns the events containing every word of query, best matches
+// first. ok is false when the query has no words to look up.
+func (x *epgIndex) search(query string) (events []domain.EPGEvent, ok bool) {
+	words := epgTokens(query)
+	if len(words) == 0 {
+		return nil, false
+	}
+	phrase := strings.Join(words, " ")
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	docs, scores := x.match(words, epgFieldsAll, true)
+	for i, id := range docs {
+		d := &x.docs[id]
+		switch {
+		case d.title == phrase:
+			scores[i] += 4
+		case strings.Contains(d.title, phrase):
+			scores[i] += 2
+		case strings.Contains(d.subtitle, phrase):
+			scores[i]++
+		}
+	}
+	order := make([]int, len(docs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		ea, eb := &x.docs[docs[a]].event, &x.docs[docs[b]].event
+		if !ea.Start.Equal(eb.Start) {
+			return ea.Start.Before(eb.Start)
+		}
+		return x.docs[docs[a]].pos < x.docs[docs[b]].pos
+	})
+	events = make([]domain.EPGEvent, 0, len(docs))
+	for _, i := range order {
+		events = append(events, x.docs[docs[i]].event)
+	}
+	return events, true
+}
+
+// candidates returns, in EPG order, the events whose fields contain every
+// word of pattern within a word, which includes every event containing
+// pattern as a substring (case-insensitively). Callers check the returned
+// events with their exact rules. ok is false when pattern has no words, so
+// the candidates cannot be narrowed.
+func (x *epgIndex) candidates(pattern string, fields epgField) (events []domain.EPGEvent, ok bool) {
+	words := epgTokens(pattern)
+	if len(words) == 0 {
+		return nil, false
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	docs, _ := x.match(words, fields, false)
+	sort.Slice(docs, func(i, j int) bool { return x.docs[docs[i]].pos < x.docs[docs[j]].pos })
+	events = make([]domain.EPGEvent, 0, len(docs))
+	for _, id := range docs {
+		events = append(events, x.docs[id].event)
+	}
+	return events, true
+}
+
+// match returns the live events with every word in one of fields, and their
+// scores. Each word scores its best matching term; fuzzy enables typo
+// matches for words without any other match.
+func (x *epgIndex) match(words []string, fields epgField, fuzzy bool) ([]int32, []float64) {
+	n := len(x.docs)
+	total := make([]float64, n)
+	best := make([]float64, n)
+	// matched counts the words an event matched so far; only events that
+	// matched all previous words can match the next one.
+	matched := make([]int32, n)
+	var hits []int32
+
+	seen := map[string]bool{}
+	i := int32(0)
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+
+		hits = hits[:0]
+		for _, tm := range x.expand(word, fuzzy) {
+			for _, p := range x.postings[tm.term] {
+				f := p.fields & fields
+				if f == 0 || matched[p.doc] != i || !x.docs[p.doc].live {
+					continue
+				}
+				score := tm.quality * f.weight()
+				if best[p.doc] == 0 {
+					hits = append(hits, p.doc)
+				}
+				if score > best[p.doc] {
+					best[p.doc] = score
+				}
+			}
+		}
+		if len(hits) == 0 {
+			return nil, nil
+		}
+		for _, id := range hits {
+			total[id] += best[id]
+			best[id] = 0
+			matched[id]++
+		}
+		i++
+	}
+
+	docs := append([]int32(nil), hits...)
+	scores := make([]float64, len(docs))
+	for k, id := range docs {
+		scores[k] = total[id]
+	}
+	return docs, scores
+}
+
+type epgTermMatch struct {
+	term    string
+	quality float64
+}
+
+// expand returns the indexed terms matching word: equal to it, starting with
+// it or containing it, or, when fuzzy is set and none does, within a small
+// edit distance.
+func (x *epgIndex) expand(word string, fuzzy bool) []epgTermMatch {
+	var out []epgTermMatch
+	i := sort.SearchStrings(x.vocab, word)
+	for ; i < len(x.vocab) && strings.HasPrefix(x.vocab[i], word); i++ {
+		q := epgMatchPrefix
+		if x.vocab[i] == word {
+			q = epgMatchExact
+		}
+		out = append(out, epgTermMatch{term: x.vocab[i], quality: q})
+	}
+	for _, term := range x.vocab {
+		if len(term) > len(word) && !strings.HasPrefix(term, word) && strings.Contains(term, word) {
+			out = append(out, epgTermMatch{term: term, quality: epgMatchInfix})
+		}
+	}
+	if len(out) > 0 || !fuzzy {
+		return out
+	}
+
+	maxDist := epgFuzzyDistance(word)
+	if maxDist == 0 {
+		return nil
+	}
+	w := []rune(word)
+	for _, term := range x.vocab {
+		if d := utf8.RuneCountInString(term) - len(w); d > maxDist || d < -maxDist {
+			continue
+		}
+		if levenshtein(w, []rune(term), maxDist) <= maxDist {
+			out = append(out, epgTermMatch{term: term, quality: epgMatchFuzzy})
+		}
+	}
+	return out
+}
+
+// epgFuzzyDistance is the number of typos tolerated in a word: none in short
+// words, where nearly everything is one edit away.
+func epgFuzzyDistance(word string) int {
+	switch n := utf8.RuneCountInString(word); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// levenshtein returns the edit distance of a and b, or maxDist+1 once it
+// exceeds maxDist.
+func levenshtein(a, b []rune, maxDist int) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > maxDist {
+			return maxDist + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// epgFolds maps letters to their unaccented lower-case spelling.
+var epgFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ĺ': "l", 'ľ': "l", 'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe", 'ŕ': "r", 'ř': "r", 'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s", 'ș': "s",
+	'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// epgTokens returns the folded words of s. Folding is done rune by rune, so
+// a string containing another one (ignoring case) still does after folding.
+func epgTokens(s string) []string {
+	var words []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			words = append(words, b.String())
+			b.Reset()
+		}
+	}
+	for _, r := range s {
+		r = unicode.ToLower(r)
+		switch {
+		case epgFolds[r] != "":
+			b.WriteString(epgFolds[r])
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining accents of decomposed letters.
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
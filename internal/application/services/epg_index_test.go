@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestEPGTokens_FoldsCaseAndAccents(t *testing.T) {
+	got := strings.Join(epgTokens("Märchen-Straße: CAFÉ Ørsted, 2. Teil"), "|")
+	if want := "marchen|strasse|cafe|orsted|2|teil"; got != want {
+		t.Fatalf("epgTokens = %q, want %q", got, want)
+	}
+	// Decomposed accents fold like precomposed ones.
+	if got := epgTokens("Café"); len(got) != 1 || got[0] != "cafe" {
+		t.Fatalf("decomposed = %q", got)
+	}
+}
+
+func TestEPGService_SearchEPG_RanksIndexedMatches(t *testing.T) {
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	events := []domain.EPGEvent{
+		{EventID: 1, ChannelID: "C-1", Title: "Dokumentation", Description: "Ein Tatort-Darsteller erzählt.", Start: base},
+		{EventID: 2, ChannelID: "C-1", Title: "Tatort", Subtitle: "Märchenwald", Start: base.Add(2 * time.Hour)},
+		{EventID: 3, ChannelID: "C-2", Title: "Tatort: Münster", Start: base.Add(time.Hour)},
+		{EventID: 4, ChannelID: "C-2", Title: "Polizeiruf 110", Description: "Krimi aus München", Start: base.Add(3 * time.Hour)},
+	}
+	svc := NewEPGService(ports.NewMockVDRClient().WithEPGEvents(events), time.Minute)
+	ctx := context.Background()
+
+	ids := func(query string) string {
+		t.Helper()
+		got, err := svc.SearchEPG(ctx, query)
+		if err != nil {
+			t.Fatalf("SearchEPG(%q): %v", query, err)
+		}
+		var out []string
+		for _, ev := range got {
+			out = append(out, fmt.Sprint(ev.EventID))
+		}
+		return strings.Join(out, ",")
+	}
+
+	for _, tc := range []struct {
+		query, want string
+	}{
+		// The exact title first, then the title starting with it, then the description.
+		{"tatort", "2,3,1"},
+		// All words must match, in any text.
+		{"tatort munster", "3"},
+		{"Maerchenwald", "2"},
+		{"MÜNCHEN", "4"},
+		// Prefixes and word parts.
+		{"polizei", "4"},
+		{"zeiruf", "4"},
+		// A typo when nothing else matches.
+		{"tatorf", "3,2,1"},
+		{"krimmi", "4"},
+		{"nothing", ""},
+		// No words to look up: a plain substring search.
+		{"110", "4"},
+		{":", "3"},
+	} {
+		if got := ids(tc.query); got != tc.want {
+			t.Errorf("SearchEPG(%q) = %s, want %s", tc.query, got, tc.want)
+		}
+	}
+
+	all, err := svc.SearchEPG(ctx, "")
+	if err != nil || len(all) != len(events) {
+		t.Fatalf("empty query = %d events, %v", len(all), err)
+	}
+}
+
+func TestEPGIndex_UpdatesIncrementally(t *testing.T) {
+	base := time.Unix(1_700_000_000, 0)
+	events := []domain.EPGEvent{
+		{EventID: 1, ChannelID: "C-1", Title: "Alpha", Start: base},
+		{EventID: 2, ChannelID: "C-1", Title: "Beta", Start: base.Add(time.Hour)},
+		{EventID: 3, ChannelID: "C-2", Title: "Gamma", Start: base},
+	}
+	var x epgIndex
+	x.update(events)
+
+	find := func(word string) int {
+		t.Helper()
+		got, ok := x.search(word)
+		if !ok {
+			t.Fatalf("search(%q) not indexed", word)
+		}
+		return len(got)
+	}
+	if find("beta") != 1 || find("gamma") != 1 {
+		t.Fatalf("initial index misses events")
+	}
+
+	// Beta is renamed, Gamma vanishes and Delta is new.
+	events = []domain.EPGEvent{
+		events[0],
+		{EventID: 2, ChannelID: "C-1", Title: "Beta Reloaded", Start: base.Add(time.Hour)},
+		{EventID: 4, ChannelID: "C-2", Title: "Delta", Start: base},
+	}
+	x.update(events)
+	if find("reloaded") != 1 || find("delta") != 1 || find("alpha") != 1 {
+		t.Fatalf("update misses new texts")
+	}
+	if find("gamma") != 0 || find("beta") != 1 {
+		t.Fatalf("update keeps stale events")
+	}
+	if x.dead != 2 || len(x.docs) != 5 {
+		t.Fatalf("expected dead postings until compaction, dead=%d docs=%d", x.dead, len(x.docs))
+	}
+
+	// A refresh without changes keeps all postings.
+	x.update(events)
+	if x.dead != 2 || len(x.docs) != 5 {
+		t.Fatalf("unchanged refresh rebuilt the index, dead=%d docs=%d", x.dead, len(x.docs))
+	}
+
+	// Once half of the events are dead the postings are compacted.
+	x.update(events[:1])
+	if x.dead != 0 || len(x.docs) != 1 || find("alpha") != 1 || find("delta") != 0 {
+		t.Fatalf("expected a compaction, dead=%d docs=%d", x.dead, len(x.docs))
+	}
+	for _, term := range x.vocab {
+		if term == "gamma" || term == "delta" {
+			t.Fatalf("compaction keeps terms of vanished events")
+		}
+	}
+}
+
+func TestEPGService_ExecuteSavedSearch_MatchesLinearSearch(t *testing.T) {
+	events := syntheticEPG(20, 3)
+	svc := NewEPGService(ports.NewMockVDRClient().WithEPGEvents(events), time.Minute)
+	ctx := context.Background()
+
+	order := map[string]int{}
+	for i := 0; i < 20; i++ {
+		order[fmt.Sprintf("C-1-%d-1", i)] = i + 1
+	}
+	searches := []config.EPGSearch{
+		{Pattern: "Tatort"},
+		{Pattern: "ort: M", InTitle: true},
+		{Pattern: "münster", InTitle: true, InDesc: true},
+		{Pattern: "Kommissar", InDesc: true},
+		{Pattern: "KOMMISSAR", MatchCase: true, InDesc: true},
+		{Pattern: "die", InSubtitle: true},
+		{Pattern: "Folge 1", UseChannel: "range", ChannelFrom: "C-1-2-1", ChannelTo: "C-1-5-1"},
+		{Pattern: "^Tat.*ster$", Mode: "regex", InTitle: true},
+		{Pattern: "--"},
+	}
+	for _, search := range searches {
+		want, err := ExecuteSavedEPGSearch(events, search, order)
+		if err != nil {
+			t.Fatalf("ExecuteSavedEPGSearch(%q): %v", search.Pattern, err)
+		}
+		got, err := svc.ExecuteSavedSearch(ctx, search, order, SavedSearchEnv{})
+		if err != nil {
+			t.Fatalf("ExecuteSavedSearch(%q): %v", search.Pattern, err)
+		}
+		if len(want) == 0 && !search.MatchCase && search.Pattern != "--" {
+			t.Fatalf("search %q matches nothing, the test data is off", search.Pattern)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("search %q: indexed %d events, linear %d", search.Pattern, len(got), len(want))
+		}
+	}
+
+	if _, err := svc.ExecuteSavedSearch(ctx, config.EPGSearch{Pattern: "(", Mode: "regex"}, order, SavedSearchEnv{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for an invalid regex, got %v", err)
+	}
+}
+
+// syntheticEPG returns a two-week style EPG: days of 30 events per channel.
+func syntheticEPG(channels, days int) []domain.EPGEvent {
+	rng := rand.New(rand.NewSource(1))
+	titles := []string{"Tatort: Münster", "Tagesschau", "Polizeiruf 110", "Die Sendung mit der Maus", "Sportschau", "Wetter", "Terra X", "Der Bergdoktor", "Rote Rosen", "Quarks"}
+	subtitles := []string{"", "Folge 1", "Folge 12", "Die Rückkehr", "Das Geheimnis", "Spätvorstellung"}
+	words := strings.Fields("der die das und mit von einem Kommissar Ermittlungen Stadt Leben Geschichte Natur Wissenschaft Familie Berlin München Hamburg Zeit Reise Nacht Tier Welt Krimi Serie Spiel Fußball Bundesliga Wetterlage Reportage Menschen")
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := make([]domain.EPGEvent, 0, channels*days*30)
+	for c := 0; c < channels; c++ {
+		at := start
+		for i := 0; i < days*30; i++ {
+			desc := make([]string, 40)
+			for j := range desc {
+				desc[j] = words[rng.Intn(len(words))]
+			}
+			length := time.Duration(20+rng.Intn(60)) * time.Minute
+			events = append(events, domain.EPGEvent{
+				EventID:       i + 1,
+				ChannelID:     fmt.Sprintf("C-1-%d-1", c),
+				ChannelNumber: c + 1,
+				Title:         titles[rng.Intn(len(titles))],
+				Subtitle:      subtitles[rng.Intn(len(subtitles))],
+				Description:   strings.Join(desc, " ") + ".",
+				Start:         at,
+				Stop:          at.Add(length),
+			})
+			at = at.Add(length)
+		}
+	}
+	return events
+}
+
+// The benchmarks search a two-week EPG of 100 channels, once scanning all
+// events like before the index and once through the index.
+
+func benchmarkEPGService(b *testing.B) (*EPGService, []domain.EPGEvent) {
+	b.Helper()
+	events := syntheticEPG(100, 14)
+	svc := NewEPGService(ports.NewMockVDRClient().WithEPGEvents(events), time.Hour)
+	if _, err := svc.GetEPG(context.Background(), "", time.Time{}); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	return svc, events
+}
+
+func BenchmarkSearchEPG_Linear(b *testing.B) {
+	_, events := benchmarkEPGService(b)
+	for i := 0; i < b.N; i++ {
+		searchEventsLinear(events, "bergdoktor")
+	}
+}
+
+func BenchmarkSearchEPG_Indexed(b *testing.B) {
+	svc, _ := benchmarkEPGService(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.SearchEPG(context.Background(), "bergdoktor"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+var benchmarkSavedSearch = config.EPGSearch{Pattern: "rückkehr", InTitle: true, InSubtitle: true, InDesc: true}
+
+func BenchmarkSavedSearch_Linear(b *testing.B) {
+	_, events := benchmarkEPGService(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := ExecuteSavedEPGSearch(events, benchmarkSavedSearch, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSavedSearch_Indexed(b *testing.B) {
+	svc, _ := benchmarkEPGService(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.ExecuteSavedSearch(context.Background(), benchmarkSavedSearch, nil, SavedSearchEnv{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEPGIndex_UnchangedRefresh(b *testing.B) {
+	svc, events := benchmarkEPGService(b)
+	for i := 0; i < b.N; i++ {
+		svc.index.update(events)
+	}
+}
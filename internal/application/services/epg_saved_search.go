@@ -1,17 +1,34 @@
 package services
 
 import (
+	"fmt"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/githubixx/vdradmin-go/internal/domain"
 	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
 )
 
+// SavedSearchEnv is what the filters of saved EPG searches compare events with.
+type SavedSearchEnv struct {
+	// Now anchors the date horizon; zero means time.Now().
+	Now time.Time
+	// Recorded reports whether an event was recorded already, for searches
+	// with NotRecorded. Nil treats no event as recorded.
+	Recorded func(domain.EPGEvent) bool
+}
+
 // ExecuteSavedEPGSearch runs a saved EPG search definition against the provided events.
 // It returns matching events sorted by start time then channel number.
 func ExecuteSavedEPGSearch(events []domain.EPGEvent, search config.EPGSearch, channelOrder map[string]int) ([]domain.EPGEvent, error) {
+	return ExecuteSavedEPGSearchEnv(events, search, channelOrder, SavedSearchEnv{})
+}
+
+// ExecuteSavedEPGSearchEnv is ExecuteSavedEPGSearch with the environment of the
+// time, duration, weekday, horizon and recording filters.
+func ExecuteSavedEPGSearchEnv(events []domain.EPGEvent, search config.EPGSearch, channelOrder map[string]int, env SavedSearchEnv) ([]domain.EPGEvent, error) {
 	pattern := strings.TrimSpace(search.Pattern)
 	if pattern == "" {
 		return []domain.EPGEvent{}, nil
@@ -42,14 +59,42 @@ func ExecuteSavedEPGSearch(events []domain.EPGEvent, search config.EPGSearch, ch
 		re = compiled
 	}
 
+	exclude := strings.TrimSpace(search.Exclude)
+	var excludeRe *regexp.Regexp
+	if mode == "regex" && exclude != "" {
+		flags := ""
+		if !search.MatchCase {
+			flags = "(?i)"
+		}
+		compiled, err := regexp.Compile(flags + exclude)
+		if err != nil {
+			return nil, err
+		}
+		excludeRe = compiled
+	}
+
+	filter, err := newSavedSearchFilter(search, env)
+	if err != nil {
+		return nil, err
+	}
+
 	matches := make([]domain.EPGEvent, 0, 64)
 	for _, ev := range events {
 		if !savedSearchChannelMatches(ev, search, channelOrder) {
 			continue
 		}
+		if !filter.matches(ev) {
+			continue
+		}
 		if !savedSearchTextMatches(ev, pattern, mode, search.MatchCase, useTitle, useSubtitle, useDesc, re) {
 			continue
 		}
+		if exclude != "" && savedSearchTextMatches(ev, exclude, mode, search.MatchCase, useTitle, useSubtitle, useDesc, excludeRe) {
+			continue
+		}
+		if search.NotRecorded && env.Recorded != nil && env.Recorded(ev) {
+			continue
+		}
 		matches = append(matches, ev)
 	}
 
@@ -144,3 +189,121 @@ func savedSearchChannelMatches(ev domain.EPGEvent, search config.EPGSearch, orde
 		return true
 	}
 }
+
+// savedSearchFilter holds the parsed time, duration, weekday and horizon
+// filters of a saved search.
+type savedSearchFilter struct {
+	// from and to are minutes of the day, -1 when unset.
+	from, to     int
+	minDuration  time.Duration
+	maxDuration  time.Duration
+	weekdays     map[time.Weekday]bool
+	hdOnly       bool
+	horizonUntil time.Time
+}
+
+func newSavedSearchFilter(search config.EPGSearch, env SavedSearchEnv) (savedSearchFilter, error) {
+	f := savedSearchFilter{
+		from:        -1,
+		to:          -1,
+		minDuration: time.Duration(search.MinDuration) * time.Minute,
+		maxDuration: time.Duration(search.MaxDuration) * time.Minute,
+		hdOnly:      search.HDOnly,
+	}
+	for _, v := range []struct {
+		value string
+		dst   *int
+	}{{search.StartFrom, &f.from}, {search.StartTo, &f.to}} {
+		if strings.TrimSpace(v.value) == "" {
+			continue
+		}
+		t, err := time.Parse("15:04", strings.TrimSpace(v.value))
+		if err != nil {
+			return f, fmt.Errorf("invalid start time %q (expected HH:MM)", v.value)
+		}
+		*v.dst = t.Hour()*60 + t.Minute()
+	}
+	if len(search.Weekdays) > 0 {
+		f.weekdays = make(map[time.Weekday]bool, len(search.Weekdays))
+		for _, name := range search.Weekdays {
+			d, ok := config.ParseWeekday(name)
+			if !ok {
+				return f, fmt.Errorf("invalid weekday %q", name)
+			}
+			f.weekdays[d] = true
+		}
+	}
+	if search.HorizonDays > 0 {
+		now := env.Now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		f.horizonUntil = now.AddDate(0, 0, search.HorizonDays)
+	}
+	return f, nil
+}
+
+func (f savedSearchFilter) matches(ev domain.EPGEvent) bool {
+	start := ev.Start.In(time.Local)
+	if f.from >= 0 || f.to >= 0 {
+		m := start.Hour()*60 + start.Minute()
+		switch {
+		case f.from >= 0 && f.to >= 0 && f.from > f.to:
+			// The window spans midnight.
+			if m < f.from && m > f.to {
+				return false
+			}
+		case f.from >= 0 && m < f.from, f.to >= 0 && m > f.to:
+			return false
+		}
+	}
+	if f.weekdays != nil && !f.weekdays[start.Weekday()] {
+		return false
+	}
+	if f.minDuration > 0 || f.maxDuration > 0 {
+		d := ev.Stop.Sub(ev.Start)
+		if ev.Duration > 0 {
+			d = ev.Duration
+		}
+		if d < f.minDuration || (f.maxDuration > 0 && d > f.maxDuration) {
+			return false
+		}
+	}
+	if f.hdOnly && !ev.Video.HD {
+		return false
+	}
+	if !f.horizonUntil.IsZero() && ev.Start.After(f.horizonUntil) {
+		return false
+	}
+	return true
+}
+
+// RecordedMatcher returns a function reporting whether an event was recorded
+// already among recs: a recording has the same title and, when both have
+// one, the same subtitle. Case and accents are ignored.
+func RecordedMatcher(recs []domain.Recording) func(domain.EPGEvent) bool {
+	subtitles := make(map[string][]string, len(recs))
+	for _, rec := range recs {
+		if rec.IsFolder {
+			continue
+		}
+		title := strings.Join(epgTokens(rec.Title), " ")
+		if title == "" {
+			continue
+		}
+		subtitles[title] = append(subtitles[title], strings.Join(epgTokens(rec.Subtitle), " "))
+	}
+	return func(ev domain.EPGEvent) bool {
+		recorded, ok := subtitles[strings.Join(epgTokens(ev.Title), " ")]
+		if !ok {
+			return false
+		}
+		subtitle := strings.Join(epgTokens(ev.Subtitle), " ")
+		for _, s := range recorded {
+			if s == "" || subtitle == "" || s == subtitle {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -54,3 +56,97 @@ func TestExecuteSavedEPGSearch_Regex_ChannelRange(t *testing.T) {
 		t.Fatalf("unexpected channels: %q, %q", got[0].ChannelID, got[1].ChannelID)
 	}
 }
+
+func TestExecuteSavedEPGSearchEnv_Filters(t *testing.T) {
+	loc := time.Local
+	// 2026-03-02 is a Monday.
+	at := func(day, hour, minute int) time.Time { return time.Date(2026, 3, day, hour, minute, 0, 0, loc) }
+	ev := func(id int, title string, start time.Time, minutes int) domain.EPGEvent {
+		return domain.EPGEvent{EventID: id, ChannelID: "C-1", Title: title, Start: start, Stop: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+	events := []domain.EPGEvent{
+		ev(1, "Krimi", at(2, 20, 15), 90),
+		ev(2, "Krimi", at(2, 23, 30), 90),
+		ev(3, "Krimi", at(3, 1, 0), 45),
+		ev(4, "Krimi (Wiederholung)", at(3, 23, 0), 90),
+		ev(5, "Krimi", at(7, 22, 0), 90),
+		ev(6, "Krimi", at(20, 22, 0), 90),
+	}
+	events[4].Video.HD = true
+	events[5].Subtitle = "Der Fall"
+	env := SavedSearchEnv{Now: at(1, 12, 0)}
+
+	ids := func(search config.EPGSearch, env SavedSearchEnv) string {
+		t.Helper()
+		search.Pattern = "krimi"
+		config.NormalizeEPGSearch(&search)
+		got, err := ExecuteSavedEPGSearchEnv(events, search, nil, env)
+		if err != nil {
+			t.Fatalf("ExecuteSavedEPGSearchEnv: %v", err)
+		}
+		var out []string
+		for _, e := range got {
+			out = append(out, strconv.Itoa(e.EventID))
+		}
+		return strings.Join(out, ",")
+	}
+
+	for _, tc := range []struct {
+		name   string
+		search config.EPGSearch
+		want   string
+	}{
+		{"window", config.EPGSearch{StartFrom: "20:00", StartTo: "23:00"}, "1,4,5,6"},
+		{"window across midnight", config.EPGSearch{StartFrom: "23:00", StartTo: "01:00"}, "2,3,4"},
+		{"earliest start only", config.EPGSearch{StartFrom: "23:00"}, "2,4"},
+		{"weekdays", config.EPGSearch{Weekdays: []string{"mon", "sat"}}, "1,2,5"},
+		{"minimum duration", config.EPGSearch{MinDuration: 60}, "1,2,4,5,6"},
+		{"maximum duration", config.EPGSearch{MaxDuration: 60}, "3"},
+		{"exclusion", config.EPGSearch{Exclude: "wiederholung"}, "1,2,3,5,6"},
+		{"hd only", config.EPGSearch{HDOnly: true}, "5"},
+		{"horizon", config.EPGSearch{HorizonDays: 7}, "1,2,3,4,5"},
+	} {
+		if got := ids(tc.search, env); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	if got := ids(config.EPGSearch{NotRecorded: true}, env); got != "1,2,3,4,5,6" {
+		t.Fatalf("without recordings nothing counts as recorded, got %s", got)
+	}
+}
+
+func TestRecordedMatcher_TitleAndSubtitle(t *testing.T) {
+	recorded := RecordedMatcher([]domain.Recording{
+		{Title: "Serie", Subtitle: "Folge 1"},
+		{Title: "Der Film"},
+		{Title: "Ordner", IsFolder: true},
+	})
+	for _, tc := range []struct {
+		ev   domain.EPGEvent
+		want bool
+	}{
+		{domain.EPGEvent{Title: "Serie", Subtitle: "folge 1"}, true},
+		{domain.EPGEvent{Title: "Serie", Subtitle: "Folge 2"}, false},
+		// Without a subtitle on either side the title decides.
+		{domain.EPGEvent{Title: "Serie"}, true},
+		{domain.EPGEvent{Title: "DER FILM", Subtitle: "Spielfilm, USA 1999"}, true},
+		{domain.EPGEvent{Title: "Ordner"}, false},
+		{domain.EPGEvent{Title: "Neu"}, false},
+	} {
+		if got := recorded(tc.ev); got != tc.want {
+			t.Errorf("recorded(%q, %q) = %v, want %v", tc.ev.Title, tc.ev.Subtitle, got, tc.want)
+		}
+	}
+
+	events := []domain.EPGEvent{
+		{EventID: 1, Title: "Serie", Subtitle: "Folge 1"},
+		{EventID: 2, Title: "Serie", Subtitle: "Folge 2"},
+	}
+	search := config.EPGSearch{Pattern: "serie", NotRecorded: true}
+	config.NormalizeEPGSearch(&search)
+	got, err := ExecuteSavedEPGSearchEnv(events, search, nil, SavedSearchEnv{Recorded: recorded})
+	if err != nil || len(got) != 1 || got[0].EventID != 2 {
+		t.Fatalf("not recorded = %+v, %v", got, err)
+	}
+}
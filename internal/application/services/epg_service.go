@@ -2,12 +2,17 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
 	"github.com/githubixx/vdradmin-go/internal/ports"
 )
 
@@ -30,6 +35,14 @@ type EPGService struct {
 	channelsCache     []domain.Channel
 	channelsExpiresAt time.Time
 	channelsExpiry    time.Duration
+
+	refreshMu        sync.RWMutex
+	refreshListeners []func()
+
+	// index is the full-text index of the cached full EPG.
+	index epgIndex
+
+	cacheStats cacheCounter
 }
 
 // SetCacheExpiry updates the EPG cache expiry used for GetEPG.
@@ -58,7 +71,8 @@ func NewEPGService(vdrClient ports.VDRClient, cacheExpiry time.Duration) *EPGSer
 }
 
 // SetWantedChannels configures which channels are considered "wanted" globally.
-// An empty list means "all channels".
+// An empty list means "all channels". A ChannelFilter in the request context
+// (see WithChannelFilter) narrows them further.
 func (s *EPGService) SetWantedChannels(channelIDs []string) {
 	set := make(map[string]struct{}, len(channelIDs))
 	for _, id := range channelIDs {
@@ -73,14 +87,16 @@ func (s *EPGService) SetWantedChannels(channelIDs []string) {
 	s.wantedMu.Unlock()
 }
 
-// GetChannels returns the channels list in channels.conf order (as reported by VDR).
+// GetChannels returns the channels list in channels.conf order (as reported by VDR),
+// or in the order of the request's favourites list.
 func (s *EPGService) GetChannels(ctx context.Context) ([]domain.Channel, error) {
 	chs, err := s.getAllChannelsCached(ctx)
 	if err != nil {
 		return nil, err
 	}
+	filter := ChannelFilterFromContext(ctx)
 	if !s.wantedEnabled() {
-		return chs, nil
+		return filter.channels(chs), nil
 	}
 
 	out := make([]domain.Channel, 0, len(chs))
@@ -89,7 +105,7 @@ func (s *EPGService) GetChannels(ctx context.Context) ([]domain.Channel, error)
 			out = append(out, ch)
 		}
 	}
-	return out, nil
+	return filter.channels(out), nil
 }
 
 // GetAllChannels returns channels without applying the wanted-channel filter.
@@ -123,8 +139,12 @@ func (s *EPGService) getAllChannelsCached(ctx context.Context) ([]domain.Channel
 }
 
 // GetEPG retrieves EPG data with caching
-func (s *EPGService) GetEPG(ctx context.Context, channelID string, at time.Time) ([]domain.EPGEvent, error) {
-	if channelID != "" && !s.isWantedChannel(channelID) {
+func (s *EPGService) GetEPG(ctx context.Context, channelID string, at time.Time) (evs []domain.EPGEvent, err error) {
+	ctx, span := startSpan(ctx, "EPGService.GetEPG", attribute.String("channel.id", channelID))
+	defer func() { endSpan(span, err) }()
+
+	filter := ChannelFilterFromContext(ctx)
+	if channelID != "" && (!s.isWantedChannel(channelID) || !filter.Allows(channelID)) {
 		return []domain.EPGEvent{}, nil
 	}
 	cacheKey := s.getCacheKey(channelID, at)
@@ -133,9 +153,13 @@ func (s *EPGService) GetEPG(ctx context.Context, channelID string, at time.Time)
 	s.cacheMu.RLock()
 	if cached, ok := s.cache[cacheKey]; ok && time.Now().Before(cached.expiresAt) {
 		s.cacheMu.RUnlock()
-		return cached.events, nil
+		s.cacheStats.hit()
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return filter.events(cached.events, false), nil
 	}
 	s.cacheMu.RUnlock()
+	s.cacheStats.miss()
+	span.SetAttributes(attribute.Bool("cache.hit", false))
 
 	// Fetch from VDR
 	events, err := s.vdrClient.GetEPG(ctx, channelID, at)
@@ -164,11 +188,40 @@ func (s *EPGService) GetEPG(ctx context.Context, channelID string, at time.Time)
 	}
 	s.cacheMu.Unlock()
 
-	return events, nil
+	if channelID == "" && at.IsZero() {
+		s.index.update(events)
+		s.notifyRefresh()
+	}
+
+	return filter.events(events, false), nil
+}
+
+// OnRefresh registers fn to be called after the full EPG was fetched from VDR.
+// Listeners are called synchronously and must not block.
+func (s *EPGService) OnRefresh(fn func()) {
+	if fn == nil {
+		return
+	}
+	s.refreshMu.Lock()
+	s.refreshListeners = append(s.refreshListeners, fn)
+	s.refreshMu.Unlock()
+}
+
+func (s *EPGService) notifyRefresh() {
+	s.refreshMu.RLock()
+	listeners := append([]func(){}, s.refreshListeners...)
+	s.refreshMu.RUnlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
 }
 
 // GetCurrentPrograms returns what's currently playing on all channels
-func (s *EPGService) GetCurrentPrograms(ctx context.Context) ([]domain.EPGEvent, error) {
+func (s *EPGService) GetCurrentPrograms(ctx context.Context) (programs []domain.EPGEvent, err error) {
+	ctx, span := startSpan(ctx, "EPGService.GetCurrentPrograms")
+	defer func() { endSpan(span, err) }()
+
 	now := time.Now()
 
 	// Fast path: serve cached summary.
@@ -177,9 +230,13 @@ func (s *EPGService) GetCurrentPrograms(ctx context.Context) ([]domain.EPGEvent,
 		cached := make([]domain.EPGEvent, len(s.currentPrograms))
 		copy(cached, s.currentPrograms)
 		s.currentMu.RUnlock()
-		return cached, nil
+		s.cacheStats.hit()
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		return ChannelFilterFromContext(ctx).events(cached, true), nil
 	}
 	s.currentMu.RUnlock()
+	s.cacheStats.miss()
+	span.SetAttributes(attribute.Bool("cache.hit", false))
 
 	// Slow path: fetch EPG once and derive the currently-running event per channel.
 	// Using one SVDRP request is significantly faster than calling LSTE per channel.
@@ -254,7 +311,7 @@ func (s *EPGService) GetCurrentPrograms(ctx context.Context) ([]domain.EPGEvent,
 	s.currentExpiresAt = time.Now().Add(s.currentExpiry)
 	s.currentMu.Unlock()
 
-	return currentPrograms, nil
+	return ChannelFilterFromContext(ctx).events(currentPrograms, true), nil
 }
 
 // GetProgramsAt returns what's playing on all channels at the provided time.
@@ -336,18 +393,98 @@ func (s *EPGService) GetProgramsAt(ctx context.Context, at time.Time) ([]domain.
 		return programs[i].Start.Before(programs[j].Start)
 	})
 
-	return programs, nil
+	return ChannelFilterFromContext(ctx).events(programs, true), nil
 }
 
-// SearchEPG searches for programs matching criteria
-func (s *EPGService) SearchEPG(ctx context.Context, query string) ([]domain.EPGEvent, error) {
-	// Get all EPG data
+// GetEPGWindow returns the events of the given channels that overlap the
+// window [from, to), grouped by channel ID and sorted by start time. It reads
+// the cached full EPG, so paging through time windows and channel blocks costs
+// no further SVDRP requests.
+func (s *EPGService) GetEPGWindow(ctx context.Context, channelIDs []string, from, to time.Time) (map[string][]domain.EPGEvent, error) {
 	events, err := s.GetEPG(ctx, "", time.Time{})
 	if err != nil {
 		return nil, err
 	}
+	window := make(map[string][]domain.EPGEvent, len(channelIDs))
+	for _, id := range channelIDs {
+		window[id] = nil
+	}
+	for _, ev := range events {
+		evs, ok := window[ev.ChannelID]
+		if !ok || !ev.Stop.After(from) || !ev.Start.Before(to) {
+			continue
+		}
+		window[ev.ChannelID] = append(evs, ev)
+	}
+	for id, evs := range window {
+		sort.SliceStable(evs, func(i, j int) bool { return evs[i].Start.Before(evs[j].Start) })
+		window[id] = evs
+	}
+	return window, nil
+}
+
+// SearchEPG returns the events whose title, subtitle or description contain
+// every word of query, best matches first. Words match in full, as the start
+// or part of a longer word or, failing that, with a typo; case and accents
+// are ignored. An empty query returns all events.
+func (s *EPGService) SearchEPG(ctx context.Context, query string) (results []domain.EPGEvent, err error) {
+	ctx, span := startSpan(ctx, "EPGService.SearchEPG")
+	defer func() { endSpan(span, err) }()
 
-	// Simple search in title and description
+	events, err := s.GetEPG(ctx, "", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(query) == "" {
+		return events, nil
+	}
+
+	hits, ok := s.index.search(query)
+	if !ok {
+		// Nothing but punctuation to look up.
+		return searchEventsLinear(events, query), nil
+	}
+	return ChannelFilterFromContext(ctx).events(hits, false), nil
+}
+
+// ExecuteSavedSearch runs a saved EPG search against the cached EPG, see
+// ExecuteSavedEPGSearchEnv. Phrase searches only check the events the index
+// finds for the words of the pattern; regular expressions check all events.
+// Invalid searches yield a domain.ErrInvalidInput error.
+func (s *EPGService) ExecuteSavedSearch(ctx context.Context, search config.EPGSearch, channelOrder map[string]int, env SavedSearchEnv) ([]domain.EPGEvent, error) {
+	events, err := s.GetEPG(ctx, "", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(search.Mode), "regex") {
+		fields := epgField(0)
+		if search.InTitle {
+			fields |= epgFieldTitle
+		}
+		if search.InSubtitle {
+			fields |= epgFieldSubtitle
+		}
+		if search.InDesc {
+			fields |= epgFieldDesc
+		}
+		if fields == 0 {
+			fields = epgFieldsAll
+		}
+		if candidates, ok := s.index.candidates(search.Pattern, fields); ok {
+			events = ChannelFilterFromContext(ctx).events(candidates, false)
+		}
+	}
+
+	matches, err := ExecuteSavedEPGSearchEnv(events, search, channelOrder, env)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidInput, err)
+	}
+	return matches, nil
+}
+
+// searchEventsLinear is the unindexed search: events with query in their
+// title, subtitle or description.
+func searchEventsLinear(events []domain.EPGEvent, query string) []domain.EPGEvent {
 	var results []domain.EPGEvent
 	queryLower := toLower(query)
 
@@ -358,8 +495,13 @@ func (s *EPGService) SearchEPG(ctx context.Context, query string) ([]domain.EPGE
 			results = append(results, event)
 		}
 	}
+	return results
+}
 
-	return results, nil
+// CacheStats returns the lookups of EPG data (GetEPG and GetCurrentPrograms)
+// served from the cache or fetched from VDR.
+func (s *EPGService) CacheStats() CacheStats {
+	return s.cacheStats.stats()
 }
 
 // InvalidateCache clears the EPG cache
@@ -135,3 +135,41 @@ func TestEPGService_WantedChannels_SkipsBackendForUnwantedChannel(t *testing.T)
 		t.Fatalf("expected 0 backend calls, got %d", got)
 	}
 }
+
+func TestEPGService_GetEPGWindow(t *testing.T) {
+	var calls int32
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	client := &ports.MockVDRClient{
+		GetEPGFunc: func(ctx context.Context, channelID string, at time.Time) ([]domain.EPGEvent, error) {
+			atomic.AddInt32(&calls, 1)
+			return []domain.EPGEvent{
+				{EventID: 1, ChannelID: "C-1", Title: "Before", Start: base.Add(-2 * time.Hour), Stop: base},
+				{EventID: 3, ChannelID: "C-1", Title: "Late", Start: base.Add(90 * time.Minute), Stop: base.Add(3 * time.Hour)},
+				{EventID: 2, ChannelID: "C-1", Title: "Running", Start: base.Add(-30 * time.Minute), Stop: base.Add(90 * time.Minute)},
+				{EventID: 4, ChannelID: "C-1", Title: "After", Start: base.Add(3 * time.Hour), Stop: base.Add(4 * time.Hour)},
+				{EventID: 5, ChannelID: "C-2", Title: "Other block", Start: base, Stop: base.Add(time.Hour)},
+			}, nil
+		},
+	}
+	svc := NewEPGService(client, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		window, err := svc.GetEPGWindow(context.Background(), []string{"C-1", "C-3"}, base, base.Add(3*time.Hour))
+		if err != nil {
+			t.Fatalf("GetEPGWindow: %v", err)
+		}
+		if len(window) != 2 {
+			t.Fatalf("expected an entry per requested channel, got %+v", window)
+		}
+		evs := window["C-1"]
+		if len(evs) != 2 || evs[0].Title != "Running" || evs[1].Title != "Late" {
+			t.Fatalf("C-1 window = %+v", evs)
+		}
+		if len(window["C-3"]) != 0 {
+			t.Fatalf("C-3 window = %+v", window["C-3"])
+		}
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("expected the full EPG to be fetched once, got %d calls", got)
+	}
+}
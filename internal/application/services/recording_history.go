@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+const (
+	// recordingHistoryInterval is how often Run looks at the timers.
+	recordingHistoryInterval = time.Minute
+	// recordingHistoryGrace is how long after its stop the recording of a timer
+	// is looked for, so VDR has finished writing it.
+	recordingHistoryGrace = 2 * time.Minute
+	// recordingShortTolerance is how much shorter than its timer a recording may
+	// be and still count as complete: VDR reports lengths in whole minutes and
+	// starts recording a moment after the timer.
+	recordingShortTolerance = 3 * time.Minute
+)
+
+// RecordingHistoryService keeps a history of the timers that fired and tells
+// whether they left a complete recording. Timers are remembered while they
+// record; after they stopped, they are matched with the recording that
+// appeared by channel, time and title.
+type RecordingHistoryService struct {
+	timerService *TimerService
+	recordings   *RecordingService
+
+	mu         sync.RWMutex
+	channels   ChannelLister
+	store      ports.RecordingHistoryStore
+	entries    []domain.RecordingHistoryEntry
+	keep       time.Duration
+	onFinished []func(domain.RecordingHistoryEntry)
+
+	// processMu serializes checks (background and tests).
+	processMu sync.Mutex
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// RecordingHistorySummary counts the results of the timers that finished in a period.
+type RecordingHistorySummary struct {
+	Complete int
+	Short    int
+	Missing  int
+	Errored  int
+	// Failures are the short, missing and errored entries, newest first.
+	Failures []domain.RecordingHistoryEntry
+}
+
+// Failed returns the number of timers that did not leave a complete recording.
+func (s RecordingHistorySummary) Failed() int {
+	return s.Short + s.Missing + s.Errored
+}
+
+// NewRecordingHistoryService creates a history of the timers of timerService
+// and the recordings of recordingService.
+func NewRecordingHistoryService(timerService *TimerService, recordingService *RecordingService) *RecordingHistoryService {
+	return &RecordingHistoryService{
+		timerService: timerService,
+		recordings:   recordingService,
+		now:          time.Now,
+	}
+}
+
+// SetChannelLister sets the source of channels used to resolve the channel
+// numbers of timers. Without it, timers and recordings are matched without channel.
+func (s *RecordingHistoryService) SetChannelLister(l ChannelLister) {
+	s.mu.Lock()
+	s.channels = l
+	s.mu.Unlock()
+}
+
+// SetStore configures persistent storage and loads the stored history.
+// Subsequent changes are written back to the store.
+func (s *RecordingHistoryService) SetStore(store ports.RecordingHistoryStore) error {
+	entries, err := store.LoadHistory()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store = store
+	s.entries = entries
+	return nil
+}
+
+// SetKeepDays sets how many days finished timers stay in the history.
+// 0 keeps them forever.
+func (s *RecordingHistoryService) SetKeepDays(days int) {
+	if days < 0 {
+		days = 0
+	}
+	s.mu.Lock()
+	s.keep = time.Duration(days) * 24 * time.Hour
+	s.mu.Unlock()
+}
+
+// OnFinished registers a callback invoked for every timer whose result was determined.
+func (s *RecordingHistoryService) OnFinished(fn func(domain.RecordingHistoryEntry)) {
+	s.mu.Lock()
+	s.onFinished = append(s.onFinished, fn)
+	s.mu.Unlock()
+}
+
+// Entries returns the history, newest timer first.
+func (s *RecordingHistoryService) Entries() []domain.RecordingHistoryEntry {
+	s.mu.RLock()
+	out := append([]domain.RecordingHistoryEntry(nil), s.entries...)
+	s.mu.RUnlock()
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.After(out[j].Start) })
+	return out
+}
+
+// Summary counts the results of the timers that started since the given time.
+func (s *RecordingHistoryService) Summary(since time.Time) RecordingHistorySummary {
+	var sum RecordingHistorySummary
+	for _, e := range s.Entries() {
+		if e.Start.Before(since) {
+			continue
+		}
+		switch e.Result {
+		case domain.RecordingComplete:
+			sum.Complete++
+		case domain.RecordingShort:
+			sum.Short++
+		case domain.RecordingMissing:
+			sum.Missing++
+		case domain.RecordingErrored:
+			sum.Errored++
+		}
+		if e.Result.Failed() {
+			sum.Failures = append(sum.Failures, e)
+		}
+	}
+	return sum
+}
+
+// Check remembers the timers recording now and determines the result of the
+// timers that stopped.
+func (s *RecordingHistoryService) Check(ctx context.Context) error {
+	s.processMu.Lock()
+	defer s.processMu.Unlock()
+
+	timers, err := s.timerService.GetAllTimers(ctx)
+	if err != nil {
+		return fmt.Errorf("timers: %w", err)
+	}
+
+	s.mu.RLock()
+	entries := append([]domain.RecordingHistoryEntry(nil), s.entries...)
+	channelLister := s.channels
+	keep := s.keep
+	callbacks := append([]func(domain.RecordingHistoryEntry){}, s.onFinished...)
+	s.mu.RUnlock()
+
+	channels := map[string]domain.Channel{}
+	if channelLister != nil {
+		// Without channels, timers are matched by time and title only.
+		if list, err := channelLister.GetAllChannels(ctx); err == nil {
+			for _, ch := range list {
+				channels[ch.ID] = ch
+				channels[strconv.Itoa(ch.Number)] = ch
+			}
+		}
+	}
+
+	now := s.now()
+	changed := false
+	for _, t := range timers {
+		if !t.Active {
+			continue
+		}
+		for _, occ := range TimerOccurrences(t, now.AddDate(0, 0, -1), now.AddDate(0, 0, 1)) {
+			if occ.Start.After(now) || !occ.Stop.After(now) {
+				continue
+			}
+			entry := domain.RecordingHistoryEntry{
+				TimerID:   t.ID,
+				Title:     t.Title,
+				ChannelID: strings.TrimSpace(t.ChannelID),
+				Start:     occ.Start,
+				Stop:      occ.Stop,
+				Result:    domain.RecordingPending,
+			}
+			if ch, ok := channels[entry.ChannelID]; ok {
+				entry.ChannelID, entry.ChannelName = ch.ID, ch.Name
+			}
+			if !historyContains(entries, entry) {
+				entries = append(entries, entry)
+				changed = true
+			}
+		}
+	}
+
+	var finished []domain.RecordingHistoryEntry
+	var checkErr error
+	if historyDue(entries, now) {
+		// The recording only just appeared; do not trust the cache.
+		s.recordings.InvalidateCache()
+		recs, err := s.recordings.GetAllRecordings(ctx)
+		if err != nil {
+			checkErr = fmt.Errorf("recordings: %w", err)
+		} else {
+			for i, e := range entries {
+				if e.Result != domain.RecordingPending || now.Before(e.Stop.Add(recordingHistoryGrace)) {
+					continue
+				}
+				entries[i] = classifyRecording(e, recs)
+				entries[i].Checked = now
+				finished = append(finished, entries[i])
+				changed = true
+			}
+		}
+	}
+
+	if keep > 0 {
+		kept := entries[:0]
+		for _, e := range entries {
+			if e.Result != domain.RecordingPending && e.Stop.Before(now.Add(-keep)) {
+				changed = true
+				continue
+			}
+			kept = append(kept, e)
+		}
+		entries = kept
+	}
+
+	if changed {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Start.Before(entries[j].Start) })
+		s.mu.Lock()
+		err := s.persistLocked(entries)
+		s.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, e := range finished {
+		for _, fn := range callbacks {
+			fn(e)
+		}
+	}
+	return checkErr
+}
+
+// persistLocked writes the given history to the store (if configured) and makes it current.
+// The in-memory history is only replaced when persisting succeeded.
+func (s *RecordingHistoryService) persistLocked(entries []domain.RecordingHistoryEntry) error {
+	if s.store != nil {
+		if err := s.store.SaveHistory(entries); err != nil {
+			return err
+		}
+	}
+	s.entries = entries
+	return nil
+}
+
+// Run checks the timers in the background every minute until ctx is canceled.
+func (s *RecordingHistoryService) Run(ctx context.Context) {
+	ticker := time.NewTicker(recordingHistoryInterval)
+	defer ticker.Stop()
+	for {
+		_ = s.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func historyContains(entries []domain.RecordingHistoryEntry, e domain.RecordingHistoryEntry) bool {
+	for _, have := range entries {
+		if have.ChannelID == e.ChannelID && have.Title == e.Title && have.Start.Equal(e.Start) && have.Stop.Equal(e.Stop) {
+			return true
+		}
+	}
+	return false
+}
+
+// historyDue reports whether a pending timer stopped long enough ago to look for its recording.
+func historyDue(entries []domain.RecordingHistoryEntry, now time.Time) bool {
+	for _, e := range entries {
+		if e.Result == domain.RecordingPending && !now.Before(e.Stop.Add(recordingHistoryGrace)) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyRecording matches a finished timer with its recording and rates the result.
+func classifyRecording(e domain.RecordingHistoryEntry, recs []domain.Recording) domain.RecordingHistoryEntry {
+	rec, ok := timerRecording(e, recs)
+	if !ok {
+		e.Result = domain.RecordingMissing
+		return e
+	}
+	e.Recording = rec.Name
+	if e.Recording == "" {
+		e.Recording = rec.Title
+	}
+	e.Length = rec.Length
+	e.Errors = rec.Errors
+	switch {
+	case rec.Errors > 0:
+		e.Result = domain.RecordingErrored
+	case rec.Length < e.Stop.Sub(e.Start)-recordingShortTolerance:
+		e.Result = domain.RecordingShort
+	default:
+		e.Result = domain.RecordingComplete
+	}
+	return e
+}
+
+// timerRecording returns the recording a timer made: one that started while the
+// timer was running, on its channel (when both tell it) and under its title.
+// When VDR split the recording, the longest part is returned.
+func timerRecording(e domain.RecordingHistoryEntry, recs []domain.Recording) (domain.Recording, bool) {
+	var best domain.Recording
+	found := false
+	for _, rec := range recs {
+		if rec.IsFolder {
+			continue
+		}
+		// VDR reports recording start times in whole minutes.
+		if rec.Date.Before(e.Start.Add(-time.Minute)) || !rec.Date.Before(e.Stop) {
+			continue
+		}
+		if channelKnown(e.ChannelID) && rec.ChannelID != "" && e.ChannelID != rec.ChannelID {
+			continue
+		}
+		if !recordingNamedAfter(rec, e.Title) {
+			continue
+		}
+		if !found || rec.Length > best.Length {
+			best, found = rec, true
+		}
+	}
+	return best, found
+}
+
+// channelKnown reports whether a timer channel is a channel ID rather than a
+// channel number that could not be resolved.
+func channelKnown(channel string) bool {
+	if channel == "" {
+		return false
+	}
+	_, err := strconv.Atoi(channel)
+	return err != nil
+}
+
+// recordingNamedAfter reports whether a recording carries the title of a timer.
+// VDR names recordings after the timer, folders included.
+func recordingNamedAfter(rec domain.Recording, timerTitle string) bool {
+	key := func(s string) string { return strings.Join(epgTokens(s), " ") }
+	last := func(s string) string {
+		if i := strings.LastIndex(s, FolderSeparator); i >= 0 {
+			return s[i+len(FolderSeparator):]
+		}
+		return s
+	}
+	title := key(last(timerTitle))
+	if title == "" {
+		return false
+	}
+	return key(rec.Name) == key(timerTitle) || key(last(rec.Name)) == title || key(rec.Title) == title
+}
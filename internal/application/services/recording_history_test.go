@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+type memHistoryStore struct {
+	saved   []domain.RecordingHistoryEntry
+	saveErr error
+}
+
+func (m *memHistoryStore) LoadHistory() ([]domain.RecordingHistoryEntry, error) {
+	return append([]domain.RecordingHistoryEntry(nil), m.saved...), nil
+}
+
+func (m *memHistoryStore) SaveHistory(entries []domain.RecordingHistoryEntry) error {
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	m.saved = append([]domain.RecordingHistoryEntry(nil), entries...)
+	return nil
+}
+
+func TestRecordingHistoryService_ClassifiesFinishedTimers(t *testing.T) {
+	start := time.Date(2026, 3, 1, 20, 13, 0, 0, time.Local)
+	channels := []domain.Channel{
+		{ID: "C-1", Number: 1, Name: "Das Erste HD"},
+		{ID: "C-2", Number: 2, Name: "ZDF HD"},
+	}
+	timer := func(id int, channel, title string, minutes int) domain.Timer {
+		return domain.Timer{ID: id, Active: true, ChannelID: channel, Title: title, Start: start, Stop: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+	// LSTT timers name the channel by number.
+	timers := []domain.Timer{
+		timer(1, "1", "Krimi~Tatort", 102),
+		timer(2, "2", "Sportschau", 60),
+		timer(3, "C-1", "Tagesthemen", 40),
+		timer(4, "2", "heute journal", 30),
+		timer(5, "2", "Inaktiv", 30),
+	}
+	timers[4].Active = false
+	mock := ports.NewMockVDRClient().WithChannels(channels).WithTimers(timers)
+
+	store := &memHistoryStore{}
+	svc := NewRecordingHistoryService(NewTimerService(mock), NewRecordingService(mock, time.Hour))
+	svc.SetChannelLister(NewEPGService(mock, time.Minute))
+	if err := svc.SetStore(store); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	var finished []domain.RecordingHistoryEntry
+	svc.OnFinished(func(e domain.RecordingHistoryEntry) { finished = append(finished, e) })
+
+	// While recording, the timers are remembered.
+	svc.now = func() time.Time { return start.Add(10 * time.Minute) }
+	if err := svc.Check(context.Background()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(store.saved) != 4 {
+		t.Fatalf("expected the 4 active timers to be pending, got %+v", store.saved)
+	}
+	for _, e := range store.saved {
+		if e.Result != domain.RecordingPending {
+			t.Fatalf("expected pending entries, got %+v", e)
+		}
+		if e.Title == "Krimi~Tatort" && (e.ChannelID != "C-1" || e.ChannelName != "Das Erste HD") {
+			t.Fatalf("expected the channel number to be resolved, got %+v", e)
+		}
+	}
+
+	// VDR removes one-time timers after they finished.
+	mock.WithTimers(nil).WithRecordings([]domain.Recording{
+		{Name: "Krimi~Tatort", Title: "Tatort", ChannelID: "C-1", Date: start, Length: 101 * time.Minute},
+		{Name: "Sportschau", Title: "Sportschau", ChannelID: "C-2", Date: start.Add(time.Minute), Length: 20 * time.Minute},
+		{Name: "Tagesthemen", Title: "Tagesthemen", ChannelID: "C-1", Date: start, Length: 40 * time.Minute, Errors: 7},
+		// Same title, other channel: not the recording of timer 4.
+		{Name: "heute journal", Title: "heute journal", ChannelID: "C-1", Date: start, Length: 30 * time.Minute},
+		// An older recording of the Tatort does not count.
+		{Name: "Krimi~Tatort", Title: "Tatort", ChannelID: "C-1", Date: start.AddDate(0, 0, -7), Length: 102 * time.Minute},
+	})
+
+	// Before the grace period, nothing is decided.
+	svc.now = func() time.Time { return start.Add(103 * time.Minute) }
+	if err := svc.Check(context.Background()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(finished) != 3 {
+		t.Fatalf("expected the 3 timers that stopped 2 minutes ago to be decided, got %+v", finished)
+	}
+
+	svc.now = func() time.Time { return start.Add(2 * time.Hour) }
+	if err := svc.Check(context.Background()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	want := map[string]domain.RecordingResult{
+		"Krimi~Tatort":  domain.RecordingComplete,
+		"Sportschau":    domain.RecordingShort,
+		"Tagesthemen":   domain.RecordingErrored,
+		"heute journal": domain.RecordingMissing,
+	}
+	entries := svc.Entries()
+	if len(entries) != len(want) || len(finished) != len(want) {
+		t.Fatalf("entries = %+v", entries)
+	}
+	for _, e := range entries {
+		if e.Result != want[e.Title] {
+			t.Errorf("%s: result = %s, want %s", e.Title, e.Result, want[e.Title])
+		}
+	}
+	for _, e := range entries {
+		if e.Title == "Tagesthemen" && (e.Errors != 7 || e.Recording != "Tagesthemen" || e.Length != 40*time.Minute) {
+			t.Fatalf("errored entry = %+v", e)
+		}
+	}
+
+	sum := svc.Summary(start.Add(-time.Hour))
+	if sum.Complete != 1 || sum.Short != 1 || sum.Missing != 1 || sum.Errored != 1 || sum.Failed() != 3 || len(sum.Failures) != 3 {
+		t.Fatalf("summary = %+v", sum)
+	}
+	if sum := svc.Summary(start.Add(time.Hour)); sum.Failed() != 0 {
+		t.Fatalf("expected no failures after the timers started, got %+v", sum)
+	}
+}
+
+func TestRecordingHistoryService_KeepsAndPersists(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.Local)
+	old := domain.RecordingHistoryEntry{Title: "Alt", Start: now.AddDate(0, 0, -40), Stop: now.AddDate(0, 0, -40).Add(time.Hour), Result: domain.RecordingComplete}
+	recent := domain.RecordingHistoryEntry{Title: "Neu", Start: now.AddDate(0, 0, -2), Stop: now.AddDate(0, 0, -2).Add(time.Hour), Result: domain.RecordingMissing}
+	store := &memHistoryStore{saved: []domain.RecordingHistoryEntry{old, recent}}
+
+	mock := ports.NewMockVDRClient()
+	svc := NewRecordingHistoryService(NewTimerService(mock), NewRecordingService(mock, 0))
+	svc.now = func() time.Time { return now }
+	if err := svc.SetStore(store); err != nil {
+		t.Fatalf("SetStore: %v", err)
+	}
+	svc.SetKeepDays(30)
+
+	store.saveErr = errors.New("disk full")
+	if err := svc.Check(context.Background()); err == nil {
+		t.Fatalf("expected store error")
+	}
+	if got := len(svc.Entries()); got != 2 {
+		t.Fatalf("failed save must not change in-memory state, got %d entries", got)
+	}
+
+	store.saveErr = nil
+	if err := svc.Check(context.Background()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if entries := svc.Entries(); len(entries) != 1 || entries[0].Title != "Neu" || len(store.saved) != 1 {
+		t.Fatalf("expected entries older than 30 days to be dropped, got %+v", entries)
+	}
+}
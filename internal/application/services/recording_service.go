@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
@@ -9,17 +11,31 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/githubixx/vdradmin-go/internal/domain"
 	"github.com/githubixx/vdradmin-go/internal/ports"
 )
 
+// ArchiveJobChecker reports archive jobs that are still working on a recording.
+// *archive.JobManager implements it.
+type ArchiveJobChecker interface {
+	ActiveJobIDForRecording(recordingID string) (string, bool)
+}
+
 // RecordingService handles recording-related operations
 type RecordingService struct {
 	vdrClient   ports.VDRClient
+	videoDir    string
+	archiveJobs ArchiveJobChecker
 	cache       []domain.Recording
 	cacheMu     sync.RWMutex
 	cacheExpiry time.Duration
 	cacheTime   time.Time
+	cacheStats  cacheCounter
+
+	replayMu sync.Mutex
+	replay   *domain.ReplayStatus
 }
 
 // SetCacheExpiry updates the recordings cache expiry.
@@ -32,6 +48,28 @@ func (s *RecordingService) SetCacheExpiry(expiry time.Duration) {
 	s.cacheMu.Unlock()
 }
 
+// SetVideoDir sets VDR's video directory, which lets recordings be placed in
+// folders by their directory structure.
+func (s *RecordingService) SetVideoDir(dir string) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	if dir == s.videoDir {
+		return
+	}
+	// Cached recordings carry folders derived from the old directory.
+	s.videoDir = dir
+	s.cache = nil
+	s.cacheTime = time.Time{}
+}
+
+// SetArchiveJobs sets the archive jobs that keep recordings from being moved
+// while they are read.
+func (s *RecordingService) SetArchiveJobs(jobs ArchiveJobChecker) {
+	s.cacheMu.Lock()
+	s.archiveJobs = jobs
+	s.cacheMu.Unlock()
+}
+
 // NewRecordingService creates a new recording service
 func NewRecordingService(vdrClient ports.VDRClient, cacheExpiry time.Duration) *RecordingService {
 	return &RecordingService{
@@ -41,7 +79,10 @@ func NewRecordingService(vdrClient ports.VDRClient, cacheExpiry time.Duration) *
 }
 
 // GetAllRecordings retrieves all recordings with caching
-func (s *RecordingService) GetAllRecordings(ctx context.Context) ([]domain.Recording, error) {
+func (s *RecordingService) GetAllRecordings(ctx context.Context) (recs []domain.Recording, err error) {
+	ctx, span := startSpan(ctx, "RecordingService.GetAllRecordings")
+	defer func() { endSpan(span, err) }()
+
 	// Check cache expiry under lock
 	s.cacheMu.RLock()
 	cacheExpiry := s.cacheExpiry
@@ -49,7 +90,9 @@ func (s *RecordingService) GetAllRecordings(ctx context.Context) ([]domain.Recor
 
 	// If caching is disabled, always fetch fresh data.
 	if cacheExpiry <= 0 {
-		return s.vdrClient.GetRecordings(ctx)
+		s.cacheStats.miss()
+		span.SetAttributes(attribute.Bool("cache.hit", false))
+		return s.fetchRecordings(ctx)
 	}
 
 	// Check cache
@@ -58,6 +101,8 @@ func (s *RecordingService) GetAllRecordings(ctx context.Context) ([]domain.Recor
 		recordings := make([]domain.Recording, len(s.cache))
 		copy(recordings, s.cache)
 		s.cacheMu.RUnlock()
+		s.cacheStats.hit()
+		span.SetAttributes(attribute.Bool("cache.hit", true))
 
 		// If recordings are removed out-of-band (e.g. deleted on disk), the cached list
 		// can still contain entries. If we know the on-disk directory, prune missing ones
@@ -96,9 +141,11 @@ func (s *RecordingService) GetAllRecordings(ctx context.Context) ([]domain.Recor
 		return pruned, nil
 	}
 	s.cacheMu.RUnlock()
+	s.cacheStats.miss()
+	span.SetAttributes(attribute.Bool("cache.hit", false))
 
 	// Fetch from VDR
-	recordings, err := s.vdrClient.GetRecordings(ctx)
+	recordings, err := s.fetchRecordings(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -112,31 +159,35 @@ func (s *RecordingService) GetAllRecordings(ctx context.Context) ([]domain.Recor
 	return recordings, nil
 }
 
-// GetRecordingsByFolder retrieves recordings organized as a folder tree
-func (s *RecordingService) GetRecordingsByFolder(ctx context.Context) (*domain.Recording, error) {
-	recordings, err := s.GetAllRecordings(ctx)
+// fetchRecordings fetches the recordings from VDR and assigns their folders.
+func (s *RecordingService) fetchRecordings(ctx context.Context) ([]domain.Recording, error) {
+	recordings, err := s.vdrClient.GetRecordings(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	// Build folder tree
-	root := &domain.Recording{
-		Path:     "/",
-		Title:    "Recordings",
-		IsFolder: true,
-		Children: make([]*domain.Recording, 0),
-	}
-
-	// Simple folder organization - can be enhanced
+	s.cacheMu.RLock()
+	videoDir := s.videoDir
+	s.cacheMu.RUnlock()
 	for i := range recordings {
-		root.Children = append(root.Children, &recordings[i])
+		recordings[i].Folder = RecordingFolder(recordings[i], videoDir)
 	}
+	return recordings, nil
+}
 
-	return root, nil
+// GetRecordingsByFolder retrieves recordings organized as a folder tree (see BuildRecordingTree).
+func (s *RecordingService) GetRecordingsByFolder(ctx context.Context) (*domain.Recording, error) {
+	recordings, err := s.GetAllRecordings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return BuildRecordingTree(recordings), nil
 }
 
 // DeleteRecording deletes a recording and invalidates cache
-func (s *RecordingService) DeleteRecording(ctx context.Context, path string) error {
+func (s *RecordingService) DeleteRecording(ctx context.Context, path string) (err error) {
+	ctx, span := startSpan(ctx, "RecordingService.DeleteRecording")
+	defer func() { endSpan(span, err) }()
+
 	if path == "" {
 		return domain.ErrInvalidInput
 	}
@@ -151,53 +202,253 @@ func (s *RecordingService) DeleteRecording(ctx context.Context, path string) err
 	return nil
 }
 
+// PlayRecording starts the replay of a recording on the VDR output and
+// remembers it for ReplayStatus.
+func (s *RecordingService) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) (err error) {
+	ctx, span := startSpan(ctx, "RecordingService.PlayRecording")
+	defer func() { endSpan(span, err) }()
+
+	if start.Offset < 0 {
+		return fmt.Errorf("%w: negative replay offset", domain.ErrInvalidInput)
+	}
+	byPath, err := s.recordingsByPath(ctx)
+	if err != nil {
+		return err
+	}
+	rec, ok := byPath[strings.TrimSpace(path)]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	if err := s.vdrClient.PlayRecording(ctx, rec.Path, start); err != nil {
+		return err
+	}
+
+	s.replayMu.Lock()
+	s.replay = &domain.ReplayStatus{Recording: rec, Started: time.Now()}
+	s.replayMu.Unlock()
+	// Replaying clears the recording's "new" flag.
+	s.InvalidateCache()
+	return nil
+}
+
+// StopReplay forgets the recording started by PlayRecording, e.g. after the
+// replay was stopped with the remote.
+func (s *RecordingService) StopReplay() {
+	s.replayMu.Lock()
+	s.replay = nil
+	s.replayMu.Unlock()
+}
+
+// ReplayStatus returns the recording last started with PlayRecording, with its
+// resume point where the VDR client reports it. It returns false if no
+// replay was started.
+func (s *RecordingService) ReplayStatus(ctx context.Context) (status domain.ReplayStatus, ok bool, err error) {
+	ctx, span := startSpan(ctx, "RecordingService.ReplayStatus")
+	defer func() { endSpan(span, err) }()
+
+	s.replayMu.Lock()
+	if s.replay != nil {
+		status, ok = *s.replay, true
+	}
+	s.replayMu.Unlock()
+	if !ok {
+		return status, false, nil
+	}
+
+	reporter, supported := s.vdrClient.(ports.ResumePointReporter)
+	if !supported {
+		return status, true, nil
+	}
+	pos, err := reporter.ResumePoint(ctx, status.Recording.Path)
+	if errors.Is(err, domain.ErrNotFound) {
+		return status, true, nil
+	}
+	if err != nil {
+		return status, true, err
+	}
+	status.ResumePoint, status.HasResumePoint = pos, true
+	return status, true, nil
+}
+
+// MoveRecording moves a recording into folder, keeping its name. An empty
+// folder moves it to the top level; missing folders are created by VDR.
+func (s *RecordingService) MoveRecording(ctx context.Context, path, folder string) (err error) {
+	ctx, span := startSpan(ctx, "RecordingService.MoveRecording")
+	defer func() { endSpan(span, err) }()
+
+	_, err = s.MoveRecordings(ctx, []string{path}, folder)
+	return err
+}
+
+// MoveRecordings moves the given recordings into folder. It tries every
+// recording and returns how many were moved along with the joined errors of
+// the others.
+func (s *RecordingService) MoveRecordings(ctx context.Context, paths []string, folder string) (moved int, err error) {
+	ctx, span := startSpan(ctx, "RecordingService.MoveRecordings")
+	defer func() { endSpan(span, err) }()
+
+	folder, err = cleanRecordingFolder(folder)
+	if err != nil {
+		return 0, err
+	}
+	if len(paths) == 0 {
+		return 0, fmt.Errorf("%w: no recordings selected", domain.ErrInvalidInput)
+	}
+	byPath, err := s.recordingsByPath(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, path := range paths {
+		rec, err := s.movableRecording(byPath, path)
+		if err == nil {
+			_, name := SplitRecordingName(rec)
+			err = s.vdrClient.MoveRecording(ctx, rec.Path, joinFolders([]string{folder, name}))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recording %s: %w", path, err))
+			continue
+		}
+		moved++
+	}
+	if moved > 0 {
+		s.InvalidateCache()
+	}
+	return moved, errors.Join(errs...)
+}
+
+// RenameRecording gives a recording a new name within its folder.
+func (s *RecordingService) RenameRecording(ctx context.Context, path, name string) (err error) {
+	ctx, span := startSpan(ctx, "RecordingService.RenameRecording")
+	defer func() { endSpan(span, err) }()
+
+	name = strings.TrimSpace(name)
+	if name == "" || strings.Contains(name, FolderSeparator) || strings.ContainsAny(name, "\r\n") {
+		return fmt.Errorf("%w: invalid recording name %q", domain.ErrInvalidInput, name)
+	}
+	byPath, err := s.recordingsByPath(ctx)
+	if err != nil {
+		return err
+	}
+	rec, err := s.movableRecording(byPath, path)
+	if err != nil {
+		return err
+	}
+	folder, _ := SplitRecordingName(rec)
+	if err := s.vdrClient.MoveRecording(ctx, rec.Path, joinFolders([]string{folder, name})); err != nil {
+		return err
+	}
+	s.InvalidateCache()
+	return nil
+}
+
+func (s *RecordingService) recordingsByPath(ctx context.Context) (map[string]domain.Recording, error) {
+	recordings, err := s.GetAllRecordings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]domain.Recording, len(recordings))
+	for _, rec := range recordings {
+		byPath[rec.Path] = rec
+	}
+	return byPath, nil
+}
+
+// movableRecording returns the recording with the given path unless an archive
+// job is reading it.
+func (s *RecordingService) movableRecording(byPath map[string]domain.Recording, path string) (domain.Recording, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return domain.Recording{}, domain.ErrInvalidInput
+	}
+	rec, ok := byPath[path]
+	if !ok {
+		return domain.Recording{}, domain.ErrNotFound
+	}
+	s.cacheMu.RLock()
+	jobs := s.archiveJobs
+	s.cacheMu.RUnlock()
+	if jobs != nil {
+		if jobID, active := jobs.ActiveJobIDForRecording(path); active {
+			return domain.Recording{}, fmt.Errorf("%w: recording is being archived by job %s", domain.ErrConflict, jobID)
+		}
+	}
+	return rec, nil
+}
+
+// SplitRecordingName splits the VDR name of a recording into its folder and its
+// own name. Without a name from VDR it uses the folder and title.
+func SplitRecordingName(rec domain.Recording) (folder, name string) {
+	if rec.Name == "" {
+		return rec.Folder, rec.Title
+	}
+	if i := strings.LastIndex(rec.Name, FolderSeparator); i >= 0 {
+		return rec.Name[:i], rec.Name[i+len(FolderSeparator):]
+	}
+	return "", rec.Name
+}
+
+// cleanRecordingFolder normalizes a folder path given by a user. Both '~' and
+// '/' separate folders.
+func cleanRecordingFolder(folder string) (string, error) {
+	if strings.ContainsAny(folder, "\r\n") {
+		return "", fmt.Errorf("%w: invalid folder %q", domain.ErrInvalidInput, folder)
+	}
+	return joinFolders(strings.Split(strings.ReplaceAll(folder, "/", FolderSeparator), FolderSeparator)), nil
+}
+
 // SortRecordings sorts recordings by various criteria
 func (s *RecordingService) SortRecordings(recordings []domain.Recording, sortBy string) []domain.Recording {
 	sorted := make([]domain.Recording, len(recordings))
 	copy(sorted, recordings)
 
+	less := recordingLess(sortBy)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(&sorted[i], &sorted[j])
+	})
+	return sorted
+}
+
+// recordingLess returns the ordering used by SortRecordings.
+func recordingLess(sortBy string) func(a, b *domain.Recording) bool {
 	switch sortBy {
 	case "name":
-		sort.SliceStable(sorted, func(i, j int) bool {
-			if sorted[i].Title != sorted[j].Title {
-				return sorted[i].Title < sorted[j].Title
+		return func(a, b *domain.Recording) bool {
+			if a.Title != b.Title {
+				return a.Title < b.Title
 			}
-			return sorted[i].Path < sorted[j].Path
-		})
-	case "date":
-		// Newest -> oldest
-		sort.SliceStable(sorted, func(i, j int) bool {
-			if !sorted[i].Date.Equal(sorted[j].Date) {
-				return sorted[i].Date.After(sorted[j].Date)
-			}
-			return sorted[i].Path < sorted[j].Path
-		})
+			return a.Path < b.Path
+		}
 	case "date_oldest":
 		// Oldest -> newest
-		sort.SliceStable(sorted, func(i, j int) bool {
-			if !sorted[i].Date.Equal(sorted[j].Date) {
-				return sorted[i].Date.Before(sorted[j].Date)
+		return func(a, b *domain.Recording) bool {
+			if !a.Date.Equal(b.Date) {
+				return a.Date.Before(b.Date)
 			}
-			return sorted[i].Path < sorted[j].Path
-		})
+			return a.Path < b.Path
+		}
 	case "length":
-		sort.SliceStable(sorted, func(i, j int) bool {
-			if sorted[i].Length != sorted[j].Length {
-				return sorted[i].Length > sorted[j].Length
+		return func(a, b *domain.Recording) bool {
+			if a.Length != b.Length {
+				return a.Length > b.Length
 			}
-			return sorted[i].Path < sorted[j].Path
-		})
+			return a.Path < b.Path
+		}
 	default:
-		// Default to date (newest -> oldest)
-		sort.SliceStable(sorted, func(i, j int) bool {
-			if !sorted[i].Date.Equal(sorted[j].Date) {
-				return sorted[i].Date.After(sorted[j].Date)
+		// "date" and default: newest -> oldest
+		return func(a, b *domain.Recording) bool {
+			if !a.Date.Equal(b.Date) {
+				return a.Date.After(b.Date)
 			}
-			return sorted[i].Path < sorted[j].Path
-		})
+			return a.Path < b.Path
+		}
 	}
+}
 
-	return sorted
+// CacheStats returns the recording list lookups served from the cache or fetched from VDR.
+func (s *RecordingService) CacheStats() CacheStats {
+	return s.cacheStats.stats()
 }
 
 // InvalidateCache clears the recording cache
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"sync/atomic"
@@ -38,6 +39,30 @@ func TestRecordingService_GetAllRecordings_NoCacheWhenExpiryZero(t *testing.T) {
 	}
 }
 
+func TestRecordingService_CacheStats(t *testing.T) {
+	client := ports.NewMockVDRClient().WithRecordings([]domain.Recording{{Path: "1", Title: "A"}})
+	svc := NewRecordingService(client, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.GetAllRecordings(ctx); err != nil {
+			t.Fatalf("GetAllRecordings(%d): %v", i+1, err)
+		}
+	}
+	svc.InvalidateCache()
+	if _, err := svc.GetAllRecordings(ctx); err != nil {
+		t.Fatalf("GetAllRecordings after invalidate: %v", err)
+	}
+
+	stats := svc.CacheStats()
+	if stats.Hits != 2 || stats.Misses != 2 {
+		t.Fatalf("expected 2 hits and 2 misses, got %+v", stats)
+	}
+	if got := stats.HitRatio(); got != 0.5 {
+		t.Fatalf("expected hit ratio 0.5, got %v", got)
+	}
+}
+
 func TestRecordingService_SortRecordings_DefaultNewestFirst(t *testing.T) {
 	svc := NewRecordingService(ports.NewMockVDRClient(), 0)
 
@@ -117,3 +142,141 @@ func TestRecordingService_GetAllRecordings_PrunesMissingDiskPathOnCacheHit(t *te
 		t.Fatalf("expected no additional backend calls on cache hit, got %d", got)
 	}
 }
+
+type fakeArchiveJobs map[string]string
+
+func (f fakeArchiveJobs) ActiveJobIDForRecording(recordingID string) (string, bool) {
+	id, ok := f[recordingID]
+	return id, ok
+}
+
+func TestRecordingService_MoveAndRenameRecordings(t *testing.T) {
+	moves := map[string]string{}
+	client := ports.NewMockVDRClient().WithRecordings([]domain.Recording{
+		{Path: "1", Title: "Der Fall", Name: "Krimi~Der Fall"},
+		{Path: "2", Title: "Folge", Name: "Folge"},
+		{Path: "3", Title: "Film", Name: "Film"},
+	})
+	client.MoveRecordingFunc = func(ctx context.Context, path, newName string) error {
+		moves[path] = newName
+		return nil
+	}
+	svc := NewRecordingService(client, time.Minute)
+	svc.SetArchiveJobs(fakeArchiveJobs{"3": "job-1"})
+	ctx := context.Background()
+
+	if err := svc.MoveRecording(ctx, "1", " Serien / Tatort "); err != nil {
+		t.Fatalf("MoveRecording: %v", err)
+	}
+	if moves["1"] != "Serien~Tatort~Der Fall" {
+		t.Fatalf("unexpected new name %q", moves["1"])
+	}
+
+	if err := svc.RenameRecording(ctx, "1", "Neuer Fall"); err != nil {
+		t.Fatalf("RenameRecording: %v", err)
+	}
+	if moves["1"] != "Krimi~Neuer Fall" {
+		t.Fatalf("expected the rename to keep the folder, got %q", moves["1"])
+	}
+	if err := svc.RenameRecording(ctx, "1", "a~b"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected names with folders to be rejected, got %v", err)
+	}
+
+	moved, err := svc.MoveRecordings(ctx, []string{"2", "3", "9"}, "")
+	if moved != 1 || moves["2"] != "Folge" {
+		t.Fatalf("expected one recording to move to the top level, got %d (%v)", moved, moves)
+	}
+	if !errors.Is(err, domain.ErrConflict) || !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected the archived and the unknown recording to fail, got %v", err)
+	}
+	if _, ok := moves["3"]; ok {
+		t.Fatalf("expected a recording being archived not to move")
+	}
+}
+
+func TestRecordingService_MoveRecordingInvalidatesCache(t *testing.T) {
+	var calls int32
+	client := ports.NewMockVDRClient()
+	client.GetRecordingsFunc = func(ctx context.Context) ([]domain.Recording, error) {
+		atomic.AddInt32(&calls, 1)
+		return []domain.Recording{{Path: "1", Title: "A", Name: "A"}}, nil
+	}
+	client.MoveRecordingFunc = func(ctx context.Context, path, newName string) error { return nil }
+	svc := NewRecordingService(client, time.Minute)
+	ctx := context.Background()
+
+	if err := svc.MoveRecording(ctx, "1", "B"); err != nil {
+		t.Fatalf("MoveRecording: %v", err)
+	}
+	if _, err := svc.GetAllRecordings(ctx); err != nil {
+		t.Fatalf("GetAllRecordings: %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Fatalf("expected the move to drop the cache, got %d fetches", got)
+	}
+}
+
+// resumePointVDR reports a fixed resume point.
+type resumePointVDR struct {
+	*ports.MockVDRClient
+	resume time.Duration
+	err    error
+}
+
+func (c *resumePointVDR) ResumePoint(ctx context.Context, path string) (time.Duration, error) {
+	return c.resume, c.err
+}
+
+func TestRecordingService_PlayRecordingTracksReplay(t *testing.T) {
+	mock := ports.NewMockVDRClient().WithRecordings([]domain.Recording{{Path: "3", Title: "Tatort", Length: time.Hour}})
+	var got domain.ReplayStart
+	mock.PlayRecordingFunc = func(ctx context.Context, path string, start domain.ReplayStart) error {
+		got = start
+		return nil
+	}
+	client := &resumePointVDR{MockVDRClient: mock, err: domain.ErrNotFound}
+	svc := NewRecordingService(client, time.Minute)
+	ctx := context.Background()
+
+	if _, ok, err := svc.ReplayStatus(ctx); ok || err != nil {
+		t.Fatalf("expected no replay before PlayRecording, got %v, %v", ok, err)
+	}
+	if err := svc.PlayRecording(ctx, "3", domain.ReplayStart{Offset: 10 * time.Minute}); err != nil {
+		t.Fatalf("PlayRecording: %v", err)
+	}
+	if got.Offset != 10*time.Minute {
+		t.Fatalf("expected the offset to reach the client, got %+v", got)
+	}
+
+	status, ok, err := svc.ReplayStatus(ctx)
+	if err != nil || !ok || status.Recording.Title != "Tatort" || status.HasResumePoint {
+		t.Fatalf("expected a replay without resume point, got %+v, %v, %v", status, ok, err)
+	}
+
+	client.resume, client.err = 12*time.Minute, nil
+	status, _, err = svc.ReplayStatus(ctx)
+	if err != nil || !status.HasResumePoint || status.ResumePoint != 12*time.Minute {
+		t.Fatalf("expected the reported resume point, got %+v, %v", status, err)
+	}
+
+	svc.StopReplay()
+	if _, ok, _ := svc.ReplayStatus(ctx); ok {
+		t.Fatalf("expected no replay after StopReplay")
+	}
+}
+
+func TestRecordingService_PlayRecordingRejectsUnknownAndNegative(t *testing.T) {
+	client := ports.NewMockVDRClient().WithRecordings([]domain.Recording{{Path: "3", Title: "Tatort"}})
+	svc := NewRecordingService(client, 0)
+	ctx := context.Background()
+
+	if err := svc.PlayRecording(ctx, "4", domain.ReplayStart{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected an unknown recording to be not found, got %v", err)
+	}
+	if err := svc.PlayRecording(ctx, "3", domain.ReplayStart{Offset: -time.Second}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected a negative offset to be rejected, got %v", err)
+	}
+	if _, ok, _ := svc.ReplayStatus(ctx); ok {
+		t.Fatalf("expected no replay after failed starts")
+	}
+}
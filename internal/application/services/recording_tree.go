@@ -0,0 +1,189 @@
+package services
+
+import (
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// FolderSeparator separates folders in VDR recording names and folder paths.
+const FolderSeparator = "~"
+
+// RecordingFolder returns the folder path of a recording. It prefers the
+// directory structure below videoDir and falls back to VDR's '~'-separated
+// recording name. It returns "" for recordings at the top level.
+func RecordingFolder(rec domain.Recording, videoDir string) string {
+	if folder, ok := folderFromDisk(rec.DiskPath, videoDir); ok {
+		return folder
+	}
+	parts := strings.Split(rec.Name, FolderSeparator)
+	if len(parts) < 2 {
+		return ""
+	}
+	return joinFolders(parts[:len(parts)-1])
+}
+
+// folderFromDisk derives the folder of a recording from its directory, which
+// VDR lays out as <video dir>/<folder>/.../<name>/<date>.rec.
+func folderFromDisk(diskPath, videoDir string) (string, bool) {
+	diskPath = strings.TrimSpace(diskPath)
+	videoDir = strings.TrimSpace(videoDir)
+	if diskPath == "" || videoDir == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(filepath.Clean(videoDir), filepath.Clean(diskPath))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 2 || !strings.HasSuffix(parts[len(parts)-1], ".rec") {
+		return "", false
+	}
+	// Drop the <date>.rec directory and the recording's own name.
+	parts = parts[:len(parts)-2]
+	folders := make([]string, 0, len(parts))
+	for _, p := range parts {
+		folders = append(folders, decodeRecordingDirName(p))
+	}
+	return joinFolders(folders), true
+}
+
+// joinFolders joins folder names, skipping empty ones.
+func joinFolders(parts []string) string {
+	clean := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			clean = append(clean, p)
+		}
+	}
+	return strings.Join(clean, FolderSeparator)
+}
+
+// decodeRecordingDirName reverses VDR's directory name encoding: '_' stands for
+// a blank and "#XX" for the byte with hex value XX.
+func decodeRecordingDirName(name string) string {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c == '_':
+			b.WriteByte(' ')
+		case c == '#' && i+2 < len(name):
+			if v, err := strconv.ParseUint(name[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// BuildRecordingTree arranges recordings in folders by their Folder path. Every
+// folder carries the number of recordings, unwatched recordings, total size and
+// total length of its subtree, and the date of its newest recording.
+func BuildRecordingTree(recordings []domain.Recording) *domain.Recording {
+	root := &domain.Recording{
+		Path:     "",
+		Title:    "Recordings",
+		IsFolder: true,
+		Children: make([]*domain.Recording, 0),
+	}
+	folders := map[string]*domain.Recording{"": root}
+
+	var folderFor func(path string) *domain.Recording
+	folderFor = func(path string) *domain.Recording {
+		if f, ok := folders[path]; ok {
+			return f
+		}
+		parentPath := ""
+		title := path
+		if i := strings.LastIndex(path, FolderSeparator); i >= 0 {
+			parentPath = path[:i]
+			title = path[i+len(FolderSeparator):]
+		}
+		parent := folderFor(parentPath)
+		f := &domain.Recording{
+			Path:     path,
+			Title:    title,
+			Folder:   parentPath,
+			IsFolder: true,
+			Children: make([]*domain.Recording, 0),
+		}
+		parent.Children = append(parent.Children, f)
+		folders[path] = f
+		return f
+	}
+
+	for i := range recordings {
+		rec := recordings[i]
+		rec.Children = nil
+		rec.IsFolder = false
+		f := folderFor(rec.Folder)
+		f.Children = append(f.Children, &rec)
+		for path := rec.Folder; ; {
+			f := folders[path]
+			f.Count++
+			if rec.New {
+				f.NewCount++
+			}
+			f.Size += rec.Size
+			f.Length += rec.Length
+			if rec.Date.After(f.Date) {
+				f.Date = rec.Date
+			}
+			if path == "" {
+				break
+			}
+			path = f.Folder
+		}
+	}
+	return root
+}
+
+// FindRecordingFolder returns the folder with the given path below root and the
+// folders leading to it, starting with root.
+func FindRecordingFolder(root *domain.Recording, path string) (*domain.Recording, []*domain.Recording, error) {
+	trail := []*domain.Recording{root}
+	node := root
+	for path != node.Path {
+		var next *domain.Recording
+		for _, child := range node.Children {
+			if child.IsFolder && (path == child.Path || strings.HasPrefix(path, child.Path+FolderSeparator)) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil, nil, domain.ErrNotFound
+		}
+		node = next
+		trail = append(trail, node)
+	}
+	return node, trail, nil
+}
+
+// SortRecordingTree sorts the children of every folder below node like
+// SortRecordings. Folders come first; they sort by name, by the date of their
+// newest recording or by their total length.
+func SortRecordingTree(node *domain.Recording, sortBy string) {
+	less := recordingLess(sortBy)
+	sort.SliceStable(node.Children, func(i, j int) bool {
+		a, b := node.Children[i], node.Children[j]
+		if a.IsFolder != b.IsFolder {
+			return a.IsFolder
+		}
+		return less(a, b)
+	})
+	for _, child := range node.Children {
+		if child.IsFolder {
+			SortRecordingTree(child, sortBy)
+		}
+	}
+}
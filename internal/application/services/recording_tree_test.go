@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestRecordingFolder(t *testing.T) {
+	cases := []struct {
+		name     string
+		rec      domain.Recording
+		videoDir string
+		want     string
+	}{
+		{
+			name:     "disk layout with encoded names",
+			rec:      domain.Recording{Name: "ignored~Name", DiskPath: "/video/Krimi/Tatort#3A_Spezial/Der_Fall/2026-02-01.20.15.1-0.rec"},
+			videoDir: "/video/",
+			want:     "Krimi~Tatort: Spezial",
+		},
+		{
+			name:     "top level on disk",
+			rec:      domain.Recording{Name: "Serie~Folge", DiskPath: "/video/Nachrichten/2026-02-01.20.00.1-0.rec"},
+			videoDir: "/video",
+			want:     "",
+		},
+		{
+			name:     "outside the video dir falls back to the name",
+			rec:      domain.Recording{Name: "Serie~Staffel 1~Folge", DiskPath: "/other/x/2026-02-01.20.00.1-0.rec"},
+			videoDir: "/video",
+			want:     "Serie~Staffel 1",
+		},
+		{
+			name: "name without folder",
+			rec:  domain.Recording{Name: "Film"},
+			want: "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RecordingFolder(tc.rec, tc.videoDir); got != tc.want {
+				t.Fatalf("RecordingFolder=%q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBuildRecordingTree_Aggregates(t *testing.T) {
+	day := time.Date(2026, 2, 1, 20, 15, 0, 0, time.UTC)
+	root := BuildRecordingTree([]domain.Recording{
+		{Path: "1", Title: "Film", Date: day, Length: 90 * time.Minute, Size: 100},
+		{Path: "2", Title: "Folge 1", Folder: "Serie~Staffel 1", New: true, Date: day.Add(24 * time.Hour), Length: 45 * time.Minute, Size: 10},
+		{Path: "3", Title: "Folge 2", Folder: "Serie~Staffel 1", Date: day.Add(48 * time.Hour), Length: 45 * time.Minute, Size: 20},
+		{Path: "4", Title: "Special", Folder: "Serie", New: true, Date: day, Length: 30 * time.Minute, Size: 5},
+	})
+
+	if root.Count != 4 || root.NewCount != 2 || root.Size != 135 || root.Length != 210*time.Minute {
+		t.Fatalf("unexpected root totals: count=%d new=%d size=%d length=%v", root.Count, root.NewCount, root.Size, root.Length)
+	}
+	if !root.Date.Equal(day.Add(48 * time.Hour)) {
+		t.Fatalf("expected the root date to be the newest recording, got %v", root.Date)
+	}
+
+	serie, trail, err := FindRecordingFolder(root, "Serie")
+	if err != nil {
+		t.Fatalf("FindRecordingFolder: %v", err)
+	}
+	if len(trail) != 2 || serie.Title != "Serie" || serie.Count != 3 || serie.NewCount != 2 || serie.Size != 35 {
+		t.Fatalf("unexpected folder Serie: %+v (trail %d)", serie, len(trail))
+	}
+
+	season, trail, err := FindRecordingFolder(root, "Serie~Staffel 1")
+	if err != nil {
+		t.Fatalf("FindRecordingFolder: %v", err)
+	}
+	if len(trail) != 3 || season.Title != "Staffel 1" || season.Folder != "Serie" || season.Count != 2 || season.Length != 90*time.Minute {
+		t.Fatalf("unexpected folder Staffel 1: %+v (trail %d)", season, len(trail))
+	}
+
+	if _, _, err := FindRecordingFolder(root, "Serie~Staffel 2"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected a missing folder to be not found, got %v", err)
+	}
+}
+
+func TestSortRecordingTree_FoldersFirst(t *testing.T) {
+	day := time.Date(2026, 2, 1, 20, 15, 0, 0, time.UTC)
+	root := BuildRecordingTree([]domain.Recording{
+		{Path: "1", Title: "Alpha", Date: day.Add(72 * time.Hour)},
+		{Path: "2", Title: "x", Folder: "Zeta", Date: day},
+		{Path: "3", Title: "y", Folder: "Beta", Date: day.Add(24 * time.Hour)},
+	})
+
+	SortRecordingTree(root, "name")
+	if got := root.Children[0].Title + "," + root.Children[1].Title + "," + root.Children[2].Title; got != "Beta,Zeta,Alpha" {
+		t.Fatalf("name order = %s", got)
+	}
+
+	SortRecordingTree(root, "date")
+	if got := root.Children[0].Title + "," + root.Children[1].Title; got != "Beta,Zeta" {
+		t.Fatalf("expected folders by their newest recording, got %s", got)
+	}
+}
+
+func TestRecordingService_GetRecordingsByFolder_UsesVideoDir(t *testing.T) {
+	client := ports.NewMockVDRClient().WithRecordings([]domain.Recording{
+		{Path: "1", Title: "Der Fall", Name: "Der Fall", DiskPath: "/video/Krimi/Der_Fall/2026-02-01.20.15.1-0.rec"},
+		{Path: "2", Title: "Folge", Name: "Serie~Folge"},
+	})
+	svc := NewRecordingService(client, time.Minute)
+	svc.SetVideoDir("/video")
+
+	root, err := svc.GetRecordingsByFolder(context.Background())
+	if err != nil {
+		t.Fatalf("GetRecordingsByFolder: %v", err)
+	}
+	for _, path := range []string{"Krimi", "Serie"} {
+		folder, _, err := FindRecordingFolder(root, path)
+		if err != nil || folder.Count != 1 {
+			t.Fatalf("expected folder %q with one recording, got %+v, %v", path, folder, err)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+)
+
+// Actions of search timer log entries.
+const (
+	SearchTimerCreated = "created"
+	SearchTimerSkipped = "skipped"
+	SearchTimerFailed  = "failed"
+)
+
+// Reasons for skipping a matching event.
+const (
+	SkipReasonScheduled = "already scheduled"
+	SkipReasonRecorded  = "already recorded"
+	SkipReasonDone      = "in the done list"
+)
+
+// searchTimerRunsKept is the number of runs SearchTimerService keeps in its log.
+const searchTimerRunsKept = 10
+
+// RecordingLister provides the recordings matches are compared with.
+type RecordingLister interface {
+	GetAllRecordings(ctx context.Context) ([]domain.Recording, error)
+}
+
+// SearchTimerLogEntry records what a run did with one matching event.
+type SearchTimerLogEntry struct {
+	SearchID int
+	Pattern  string
+	Event    domain.EPGEvent
+	Action   string
+	Reason   string
+}
+
+// SearchTimerRun describes one run of the saved searches with AutoRecord.
+type SearchTimerRun struct {
+	StartedAt time.Time
+	EndedAt   time.Time
+	Created   int
+	Skipped   int
+	Failed    int
+	Entries   []SearchTimerLogEntry
+	Err       string
+}
+
+// SearchTimerService creates timers for new matches of saved EPG searches
+// with AutoRecord (search timers) after every EPG refresh.
+type SearchTimerService struct {
+	timerService *TimerService
+	epgService   *EPGService
+	searches     func() []config.EPGSearch
+
+	mu    sync.RWMutex
+	dedup *DedupService
+	runs  []SearchTimerRun
+
+	// processMu serializes runs (background and manual).
+	processMu sync.Mutex
+
+	trigger   chan struct{}
+	listenMu  sync.Mutex
+	listening bool
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// NewSearchTimerService creates a search timer service running the saved
+// searches returned by searches.
+func NewSearchTimerService(timerService *TimerService, epgService *EPGService, searches func() []config.EPGSearch) *SearchTimerService {
+	return &SearchTimerService{
+		timerService: timerService,
+		epgService:   epgService,
+		searches:     searches,
+		trigger:      make(chan struct{}, 1),
+		now:          time.Now,
+	}
+}
+
+// SetDedup sets the service recognising episodes recorded or scheduled before.
+// Without one, matches are only checked for timers recording the same event.
+func (s *SearchTimerService) SetDedup(dedup *DedupService) {
+	s.mu.Lock()
+	s.dedup = dedup
+	s.mu.Unlock()
+}
+
+// Runs returns the log of the most recent runs, newest first.
+func (s *SearchTimerService) Runs() []SearchTimerRun {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]SearchTimerRun, len(s.runs))
+	for i, run := range s.runs {
+		out[len(s.runs)-1-i] = run
+	}
+	return out
+}
+
+// Process runs all active saved searches with AutoRecord once and returns its log.
+func (s *SearchTimerService) Process(ctx context.Context) (SearchTimerRun, error) {
+	s.processMu.Lock()
+	defer s.processMu.Unlock()
+
+	run := SearchTimerRun{StartedAt: s.now()}
+	err := s.process(ctx, &run)
+	run.EndedAt = s.now()
+	if err != nil {
+		run.Err = err.Error()
+	}
+
+	s.mu.Lock()
+	s.runs = append(s.runs, run)
+	if len(s.runs) > searchTimerRunsKept {
+		s.runs = append([]SearchTimerRun(nil), s.runs[len(s.runs)-searchTimerRunsKept:]...)
+	}
+	s.mu.Unlock()
+
+	return run, err
+}
+
+func (s *SearchTimerService) process(ctx context.Context, run *SearchTimerRun) error {
+	var searches []config.EPGSearch
+	for _, search := range s.searches() {
+		if search.Active && search.AutoRecord {
+			searches = append(searches, search)
+		}
+	}
+	if len(searches) == 0 {
+		return nil
+	}
+
+	channels, err := s.epgService.GetChannels(ctx)
+	if err != nil {
+		return err
+	}
+	order := make(map[string]int, len(channels))
+	for i, ch := range channels {
+		if ch.ID != "" {
+			order[ch.ID] = i + 1
+		}
+	}
+
+	timers, err := s.timerService.GetAllTimers(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	dedup := s.dedup
+	s.mu.RUnlock()
+	var check *DedupCheck
+	if dedup != nil {
+		events, err := s.epgService.GetEPG(ctx, "", time.Time{})
+		if err != nil {
+			return err
+		}
+		if check, err = dedup.NewCheck(ctx, events, timers); err != nil {
+			return err
+		}
+	}
+
+	now := s.now()
+	env := SavedSearchEnv{Now: now}
+	var firstErr error
+	for _, search := range searches {
+		matches, err := s.epgService.ExecuteSavedSearch(ctx, search, order, env)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("search %d: %w", search.ID, err)
+			}
+			continue
+		}
+
+		var scheduled []domain.EPGEvent
+		for _, event := range matches {
+			// Running and past events are left alone.
+			if !event.Start.After(now) {
+				continue
+			}
+			entry := SearchTimerLogEntry{SearchID: search.ID, Pattern: search.Pattern, Event: event}
+			reason, duplicate := check.Duplicate(event, domain.DedupScope(search.Dedup))
+			switch {
+			case eventScheduled(event, timers):
+				entry.Action, entry.Reason = SearchTimerSkipped, SkipReasonScheduled
+				run.Skipped++
+			case duplicate:
+				entry.Action, entry.Reason = SearchTimerSkipped, reason
+				run.Skipped++
+			default:
+				opts := []TimerOption{WithTimerFolder(search.Folder), WithTimerAux(SearchTimerAux(search))}
+				if err := s.timerService.CreateTimerFromEPG(ctx, event, search.Priority, search.Lifetime, search.MarginStart, search.MarginEnd, opts...); err != nil {
+					entry.Action, entry.Reason = SearchTimerFailed, err.Error()
+					run.Failed++
+					if firstErr == nil {
+						firstErr = fmt.Errorf("search %d: %w", search.ID, err)
+					}
+					break
+				}
+				entry.Action = SearchTimerCreated
+				run.Created++
+				timers = append(timers, NewTimerFromEPG(event, search.Priority, search.Lifetime, search.MarginStart, search.MarginEnd))
+				check.AddScheduled(event)
+				scheduled = append(scheduled, event)
+			}
+			run.Entries = append(run.Entries, entry)
+		}
+		if dedup != nil {
+			if err := dedup.MarkDone(SearchDoneSource(search.ID), scheduled...); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("done list: %w", err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// Trigger requests a background run.
+// Multiple triggers while a run is pending are coalesced.
+func (s *SearchTimerService) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes the search timers after every EPG refresh until ctx is canceled.
+func (s *SearchTimerService) Run(ctx context.Context) {
+	s.listenMu.Lock()
+	if !s.listening && s.epgService != nil {
+		s.epgService.OnRefresh(s.Trigger)
+		s.listening = true
+	}
+	s.listenMu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.trigger:
+		}
+
+		_, _ = s.Process(ctx)
+
+		// The run itself may have refreshed the EPG cache. Drop that notification,
+		// otherwise every run would immediately schedule another one.
+		select {
+		case <-s.trigger:
+		default:
+		}
+	}
+}
+
+// searchTimerAuxRe finds the search ID in the aux data of search timers.
+var searchTimerAuxRe = regexp.MustCompile(`<search id="(\d+)">`)
+
+// SearchTimerAux returns the aux data of timers created by a saved search.
+func SearchTimerAux(search config.EPGSearch) string {
+	return fmt.Sprintf(`<vdradmin-go><search id="%d">%s</search></vdradmin-go>`, search.ID, html.EscapeString(search.Pattern))
+}
+
+// SearchIDFromAux returns the ID of the saved search that created a timer.
+func SearchIDFromAux(aux string) (int, bool) {
+	m := searchTimerAuxRe.FindStringSubmatch(aux)
+	if m == nil {
+		return 0, false
+	}
+	id, err := strconv.Atoi(m[1])
+	return id, err == nil
+}
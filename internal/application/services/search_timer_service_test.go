@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestSearchTimerService_CreatesAndSkips(t *testing.T) {
+	base := time.Now().Add(48 * time.Hour).Truncate(time.Hour)
+	channels := []domain.Channel{{ID: "C-1", Number: 1, Name: "Das Erste"}, {ID: "C-2", Number: 2, Name: "ZDF"}}
+	events := []domain.EPGEvent{
+		{EventID: 1, ChannelID: "C-1", Title: "Tatort", Subtitle: "Neu", Start: base, Stop: base.Add(90 * time.Minute)},
+		{EventID: 2, ChannelID: "C-1", Title: "Tatort", Subtitle: "Geplant", Start: base.Add(24 * time.Hour), Stop: base.Add(25 * time.Hour)},
+		{EventID: 3, ChannelID: "C-2", Title: "Tatort", Subtitle: "Alt", Start: base.Add(2 * time.Hour), Stop: base.Add(3 * time.Hour)},
+		{EventID: 4, ChannelID: "C-2", Title: "Tatort", Subtitle: "Vorbei", Start: time.Now().Add(-3 * time.Hour), Stop: time.Now().Add(-time.Hour)},
+		{EventID: 5, ChannelID: "C-2", Title: "Polizeiruf 110", Start: base.Add(4 * time.Hour), Stop: base.Add(5 * time.Hour)},
+	}
+	mock := ports.NewMockVDRClient().WithChannels(channels).WithEPGEvents(events).
+		WithTimers([]domain.Timer{{ID: 1, Active: true, ChannelID: "C-1", Title: "Tatort", EventID: 2, Start: events[1].Start, Stop: events[1].Stop}}).
+		WithRecordings([]domain.Recording{{Title: "Tatort", Subtitle: "Alt"}})
+
+	searches := []config.EPGSearch{
+		{ID: 7, Active: true, AutoRecord: true, Pattern: "tatort", InTitle: true, Priority: 60, Lifetime: 30, MarginStart: 5, MarginEnd: 10, Folder: "Krimi", Dedup: "all"},
+		{ID: 8, Active: true, Pattern: "Polizeiruf", InTitle: true},
+		{ID: 9, Active: false, AutoRecord: true, Pattern: "Polizeiruf", InTitle: true},
+	}
+	epgSvc := NewEPGService(mock, time.Minute)
+	svc := NewSearchTimerService(NewTimerService(mock), epgSvc, func() []config.EPGSearch { return searches })
+	dedup := NewDedupService()
+	dedup.SetRecordingLister(NewRecordingService(mock, time.Minute))
+	svc.SetDedup(dedup)
+
+	run, err := svc.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if run.Created != 1 || run.Skipped != 2 || len(run.Entries) != 3 {
+		t.Fatalf("run = created %d, skipped %d, entries %+v", run.Created, run.Skipped, run.Entries)
+	}
+	reasons := map[int]string{}
+	for _, e := range run.Entries {
+		if e.SearchID != 7 {
+			t.Fatalf("entry of search %d, only search 7 records", e.SearchID)
+		}
+		reasons[e.Event.EventID] = e.Action + " " + e.Reason
+	}
+	if reasons[1] != "created " || reasons[2] != "skipped already scheduled" || reasons[3] != "skipped already recorded" {
+		t.Fatalf("reasons = %q", reasons)
+	}
+
+	timers, _ := mock.GetTimers(context.Background())
+	if len(timers) != 2 {
+		t.Fatalf("expected one new timer, have %d", len(timers))
+	}
+	created := timers[1]
+	if created.Title != "Krimi~Tatort" || created.Priority != 60 || created.Lifetime != 30 || created.EventID != 1 {
+		t.Fatalf("created timer = %+v", created)
+	}
+	if !created.Start.Equal(base.Add(-5*time.Minute)) || !created.Stop.Equal(base.Add(100*time.Minute)) {
+		t.Fatalf("timer %s-%s lacks the margins", created.Start, created.Stop)
+	}
+	if id, ok := SearchIDFromAux(created.Aux); !ok || id != 7 {
+		t.Fatalf("aux %q does not name search 7", created.Aux)
+	}
+
+	// The next run finds the new timer.
+	run, err = svc.Process(context.Background())
+	if err != nil || run.Created != 0 || run.Skipped != 3 {
+		t.Fatalf("second run = %+v, %v", run, err)
+	}
+	if runs := svc.Runs(); len(runs) != 2 || runs[0].Skipped != 3 {
+		t.Fatalf("expected the newest run first, got %+v", runs)
+	}
+	if done := dedup.Done(); len(done) != 1 || done[0].EventID != 1 || done[0].Source != SearchDoneSource(7) {
+		t.Fatalf("done list = %+v", done)
+	}
+}
+
+func TestSearchTimerService_SkipsRepeats(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	events := []domain.EPGEvent{
+		{EventID: 1, ChannelID: "C-1", Title: "Tatort", Subtitle: "Borowski", Start: base, Stop: base.Add(90 * time.Minute)},
+		// The repeat of the same episode on another channel.
+		{EventID: 2, ChannelID: "C-2", Title: "Tatort", Subtitle: "Borowski", Start: base.Add(26 * time.Hour), Stop: base.Add(27 * time.Hour)},
+		{EventID: 3, ChannelID: "C-2", Title: "Tatort", Subtitle: "Erledigt", Start: base.Add(3 * time.Hour), Stop: base.Add(4 * time.Hour)},
+	}
+	mock := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{{ID: "C-1", Number: 1, Name: "Das Erste"}, {ID: "C-2", Number: 2, Name: "ONE"}}).
+		WithEPGEvents(events)
+	dedup := NewDedupService()
+	if err := dedup.MarkDone(SearchDoneSource(1), domain.EPGEvent{Title: "Tatort", Subtitle: "Erledigt"}); err != nil {
+		t.Fatalf("MarkDone: %v", err)
+	}
+
+	searches := []config.EPGSearch{{ID: 1, Active: true, AutoRecord: true, Pattern: "Tatort", InTitle: true, Dedup: "all"}}
+	svc := NewSearchTimerService(NewTimerService(mock), NewEPGService(mock, time.Minute), func() []config.EPGSearch { return searches })
+	svc.SetDedup(dedup)
+
+	run, err := svc.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	reasons := map[int]string{}
+	for _, e := range run.Entries {
+		reasons[e.Event.EventID] = e.Action + " " + e.Reason
+	}
+	if run.Created != 1 || reasons[1] != "created " || reasons[2] != "skipped already scheduled" || reasons[3] != "skipped in the done list" {
+		t.Fatalf("reasons = %q", reasons)
+	}
+
+	// Recording repeats is a matter of the dedup scope.
+	searches[0].Dedup = "none"
+	run, err = svc.Process(context.Background())
+	if err != nil || run.Created != 2 {
+		t.Fatalf("run without dedup = %+v, %v", run, err)
+	}
+}
+
+func TestSearchTimerService_LogsFailures(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	mock := ports.NewMockVDRClient().
+		WithChannels([]domain.Channel{{ID: "C-1", Number: 1, Name: "Das Erste"}}).
+		WithEPGEvents([]domain.EPGEvent{{EventID: 1, ChannelID: "C-1", Title: "Tatort", Start: base, Stop: base.Add(time.Hour)}})
+	mock.CreateTimerFunc = func(context.Context, *domain.Timer) error { return errors.New("VDR says no") }
+
+	searches := []config.EPGSearch{{ID: 1, Active: true, AutoRecord: true, Pattern: "Tatort"}}
+	svc := NewSearchTimerService(NewTimerService(mock), NewEPGService(mock, time.Minute), func() []config.EPGSearch { return searches })
+
+	run, err := svc.Process(context.Background())
+	if err == nil || run.Err == "" {
+		t.Fatalf("expected the failure to be reported")
+	}
+	if run.Failed != 1 || len(run.Entries) != 1 || run.Entries[0].Action != SearchTimerFailed || run.Entries[0].Reason != "VDR says no" {
+		t.Fatalf("run = %+v", run)
+	}
+}
+
+func TestSearchTimerAux_RoundTrip(t *testing.T) {
+	aux := SearchTimerAux(config.EPGSearch{ID: 12, Pattern: `<Tatort & "Co">`})
+	if id, ok := SearchIDFromAux(aux); !ok || id != 12 {
+		t.Fatalf("SearchIDFromAux(%q) = %d, %v", aux, id, ok)
+	}
+	if _, ok := SearchIDFromAux("<epgsearch><channel>1</channel></epgsearch>"); ok {
+		t.Fatalf("foreign aux data must not name a search")
+	}
+}
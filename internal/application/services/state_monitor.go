@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/events"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+// StateMonitor polls VDR for state changes SVDRP does not report (connectivity,
+// current channel, timers that start or stop recording) and publishes them to the event bus.
+// Recordings are derived from the active timers: a timer records while the current time
+// is inside one of its occurrences.
+type StateMonitor struct {
+	vdrClient        ports.VDRClient
+	bus              *events.Bus
+	recordingService *RecordingService
+
+	mu       sync.Mutex
+	interval time.Duration
+	reconfig chan struct{}
+	trigger  chan struct{}
+
+	// State of the previous poll; valid when known is set.
+	known     bool
+	connected bool
+	channel   string
+	recording map[string]events.RecordingPayload
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// NewStateMonitor creates a new state monitor publishing to bus.
+func NewStateMonitor(vdrClient ports.VDRClient, bus *events.Bus) *StateMonitor {
+	return &StateMonitor{
+		vdrClient: vdrClient,
+		bus:       bus,
+		reconfig:  make(chan struct{}, 1),
+		trigger:   make(chan struct{}, 1),
+		recording: map[string]events.RecordingPayload{},
+		now:       time.Now,
+	}
+}
+
+// SetRecordingService sets the recording service whose cache is invalidated
+// when a recording finishes.
+func (m *StateMonitor) SetRecordingService(r *RecordingService) {
+	m.mu.Lock()
+	m.recordingService = r
+	m.mu.Unlock()
+}
+
+// SetInterval sets how often VDR is polled. 0 disables polling.
+func (m *StateMonitor) SetInterval(interval time.Duration) {
+	m.mu.Lock()
+	changed := m.interval != interval
+	m.interval = interval
+	m.mu.Unlock()
+
+	if changed {
+		select {
+		case m.reconfig <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Trigger requests an immediate poll, e.g. after switching the channel.
+func (m *StateMonitor) Trigger() {
+	select {
+	case m.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run polls VDR every configured interval until ctx is canceled.
+// Polling pauses while the bus has no subscribers.
+func (m *StateMonitor) Run(ctx context.Context) {
+	for {
+		m.mu.Lock()
+		interval := m.interval
+		m.mu.Unlock()
+
+		var tick <-chan time.Time
+		var timer *time.Timer
+		if interval > 0 {
+			timer = time.NewTimer(interval)
+			tick = timer.C
+		}
+
+		run := false
+		select {
+		case <-ctx.Done():
+		case <-m.reconfig:
+		case <-m.trigger:
+			run = true
+		case <-tick:
+			run = true
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !run {
+			continue
+		}
+
+		if m.bus.SubscriberCount() == 0 {
+			// Nobody listens; start from a fresh baseline once somebody does.
+			m.Reset()
+			continue
+		}
+		m.Poll(ctx)
+	}
+}
+
+// Reset forgets the state of the previous poll. The next poll records a new
+// baseline; only an unreachable VDR is reported for it.
+func (m *StateMonitor) Reset() {
+	m.mu.Lock()
+	m.known = false
+	m.recording = map[string]events.RecordingPayload{}
+	m.mu.Unlock()
+}
+
+// Poll queries VDR once and publishes the changes since the previous poll.
+func (m *StateMonitor) Poll(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	baseline := !m.known
+	m.known = true
+
+	if err := m.vdrClient.Ping(ctx); err != nil {
+		if m.connected || baseline {
+			m.bus.Publish(events.VDRDisconnected, events.ConnectivityPayload{Error: err.Error()})
+		}
+		m.connected = false
+		return
+	}
+	if !m.connected && !baseline {
+		m.bus.Publish(events.VDRConnected, events.ConnectivityPayload{})
+	}
+	m.connected = true
+
+	if channel, err := m.vdrClient.GetCurrentChannel(ctx); err == nil && channel != m.channel {
+		if !baseline {
+			m.bus.Publish(events.ChannelChanged, events.ChannelPayload{ChannelID: channel, Previous: m.channel})
+		}
+		m.channel = channel
+	}
+
+	timers, err := m.vdrClient.GetTimers(ctx)
+	if err != nil {
+		return
+	}
+	current := recordingTimers(timers, m.now())
+	for key, rec := range current {
+		if _, ok := m.recording[key]; !ok && !baseline {
+			m.bus.Publish(events.RecordingStarted, rec)
+		}
+	}
+	finished := false
+	for key, rec := range m.recording {
+		if _, ok := current[key]; !ok {
+			m.bus.Publish(events.RecordingFinished, rec)
+			finished = true
+		}
+	}
+	m.recording = current
+	if finished && m.recordingService != nil {
+		m.recordingService.InvalidateCache()
+	}
+}
+
+// recordingTimers returns the occurrences of active timers that are recording at now,
+// keyed by timer ID and start time.
+func recordingTimers(timers []domain.Timer, now time.Time) map[string]events.RecordingPayload {
+	out := map[string]events.RecordingPayload{}
+	for _, t := range timers {
+		if !t.Active {
+			continue
+		}
+		// Recurring timers are only projected onto whole days, and may have
+		// started the day before when they span midnight.
+		for _, occ := range TimerOccurrences(t, now.Add(-24*time.Hour), now.Add(24*time.Hour)) {
+			if occ.Start.After(now) || !occ.Stop.After(now) {
+				continue
+			}
+			out[fmt.Sprintf("%d@%d", t.ID, occ.Start.Unix())] = events.RecordingPayload{
+				TimerID:   t.ID,
+				ChannelID: t.ChannelID,
+				Title:     t.Title,
+				Start:     occ.Start,
+				Stop:      occ.Stop,
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/application/events"
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func drainEvents(sub *events.Subscription) []events.Event {
+	var out []events.Event
+	for {
+		select {
+		case ev := <-sub.C:
+			out = append(out, ev)
+		default:
+			return out
+		}
+	}
+}
+
+func eventTypes(evs []events.Event) []events.Type {
+	out := make([]events.Type, 0, len(evs))
+	for _, ev := range evs {
+		out = append(out, ev.Type)
+	}
+	return out
+}
+
+func TestStateMonitor_PublishesChanges(t *testing.T) {
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	now := base.Add(-time.Minute)
+
+	var pingErr error
+	mock := ports.NewMockVDRClient().
+		WithCurrentChannel("C1").
+		WithTimers([]domain.Timer{{ID: 1, Active: true, ChannelID: "C2", Title: "Tatort", Start: base, Stop: base.Add(time.Hour)}})
+	mock.PingFunc = func(ctx context.Context) error { return pingErr }
+
+	bus := events.NewBus()
+	sub := bus.Subscribe(16)
+	defer sub.Close()
+	m := NewStateMonitor(mock, bus)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// The first poll only records a baseline.
+	m.Poll(ctx)
+	if evs := drainEvents(sub); len(evs) != 0 {
+		t.Fatalf("expected no events for the baseline, got %v", eventTypes(evs))
+	}
+
+	now = base.Add(time.Minute)
+	_ = mock.SetCurrentChannel(ctx, "C2")
+	m.Poll(ctx)
+	evs := drainEvents(sub)
+	if len(evs) != 2 || evs[0].Type != events.ChannelChanged || evs[1].Type != events.RecordingStarted {
+		t.Fatalf("expected channel change and recording start, got %v", eventTypes(evs))
+	}
+	if p := evs[0].Data.(events.ChannelPayload); p.ChannelID != "C2" || p.Previous != "C1" {
+		t.Fatalf("unexpected channel payload %+v", p)
+	}
+	if p := evs[1].Data.(events.RecordingPayload); p.TimerID != 1 || p.Title != "Tatort" {
+		t.Fatalf("unexpected recording payload %+v", p)
+	}
+
+	pingErr = domain.ErrConnection
+	m.Poll(ctx)
+	m.Poll(ctx)
+	if evs := drainEvents(sub); len(evs) != 1 || evs[0].Type != events.VDRDisconnected {
+		t.Fatalf("expected a single disconnect event, got %v", eventTypes(evs))
+	}
+
+	pingErr = nil
+	now = base.Add(2 * time.Hour)
+	m.Poll(ctx)
+	if evs := drainEvents(sub); len(evs) != 2 || evs[0].Type != events.VDRConnected || evs[1].Type != events.RecordingFinished {
+		t.Fatalf("expected reconnect and recording finish, got %v", eventTypes(evs))
+	}
+}
+
+func TestStateMonitor_RecurringTimers(t *testing.T) {
+	// Sunday 2026-03-01.
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	now := day.Add(19 * time.Hour)
+
+	mock := ports.NewMockVDRClient().WithTimers([]domain.Timer{
+		{ID: 1, Active: true, ChannelID: "C1", Title: "Tagesschau", DaySpec: "MTWTFSS", StartMinutes: 20 * 60, StopMinutes: 20*60 + 15},
+		// Spans midnight: started on Sunday, still recording on Monday.
+		{ID: 2, Active: true, ChannelID: "C2", Title: "Nachtcafe", DaySpec: "------S", StartMinutes: 23*60 + 30, StopMinutes: 30},
+	})
+	bus := events.NewBus()
+	sub := bus.Subscribe(16)
+	defer sub.Close()
+	m := NewStateMonitor(mock, bus)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.Poll(ctx)
+	drainEvents(sub)
+
+	now = day.Add(20*time.Hour + 5*time.Minute)
+	m.Poll(ctx)
+	evs := drainEvents(sub)
+	if len(evs) != 1 || evs[0].Type != events.RecordingStarted {
+		t.Fatalf("expected the recurring timer to start recording, got %v", eventTypes(evs))
+	}
+	if p := evs[0].Data.(events.RecordingPayload); p.TimerID != 1 || !p.Start.Equal(day.Add(20*time.Hour)) {
+		t.Fatalf("unexpected recording payload %+v", p)
+	}
+
+	now = day.Add(23*time.Hour + 45*time.Minute)
+	m.Poll(ctx)
+	evs = drainEvents(sub)
+	if len(evs) != 2 || evs[0].Type == evs[1].Type {
+		t.Fatalf("expected one recording to finish and one to start, got %v", eventTypes(evs))
+	}
+
+	// After midnight the Sunday occurrence is still recording.
+	now = day.Add(24*time.Hour + 10*time.Minute)
+	m.Poll(ctx)
+	if evs := drainEvents(sub); len(evs) != 0 {
+		t.Fatalf("expected the recording to go on past midnight, got %v", eventTypes(evs))
+	}
+
+	now = day.Add(24*time.Hour + 31*time.Minute)
+	m.Poll(ctx)
+	evs = drainEvents(sub)
+	if len(evs) != 1 || evs[0].Type != events.RecordingFinished {
+		t.Fatalf("expected the recording to finish, got %v", eventTypes(evs))
+	}
+	if p := evs[0].Data.(events.RecordingPayload); p.TimerID != 2 {
+		t.Fatalf("unexpected recording payload %+v", p)
+	}
+}
+
+func TestStateMonitor_RunSkipsWithoutSubscribers(t *testing.T) {
+	polls := make(chan struct{}, 8)
+	mock := ports.NewMockVDRClient()
+	mock.PingFunc = func(ctx context.Context) error {
+		polls <- struct{}{}
+		return errors.New("down")
+	}
+	bus := events.NewBus()
+	m := NewStateMonitor(mock, bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx)
+		close(done)
+	}()
+
+	m.Trigger()
+	time.Sleep(20 * time.Millisecond)
+	if len(polls) != 0 {
+		t.Fatalf("expected no polls without subscribers")
+	}
+
+	sub := bus.Subscribe(4)
+	defer sub.Close()
+	m.Trigger()
+	select {
+	case <-polls:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a poll after subscribing")
+	}
+	select {
+	case ev := <-sub.C:
+		if ev.Type != events.VDRDisconnected {
+			t.Fatalf("unexpected event %s", ev.Type)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected disconnect event")
+	}
+
+	cancel()
+	<-done
+}
+
+func TestTimerService_PublishesTimerEvents(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	mock := ports.NewMockVDRClient().WithTimers([]domain.Timer{
+		{ID: 1, Active: true, ChannelID: "C1", Title: "A", Start: start, Stop: start.Add(time.Hour), Priority: 50, Lifetime: 99},
+	})
+	bus := events.NewBus()
+	sub := bus.Subscribe(8)
+	defer sub.Close()
+	svc := NewTimerService(mock)
+	svc.SetEventBus(bus)
+	ctx := context.Background()
+
+	created := domain.Timer{Active: true, ChannelID: "C1", Title: "B", Start: start.Add(2 * time.Hour), Stop: start.Add(3 * time.Hour), Priority: 50, Lifetime: 99}
+	if err := svc.CreateTimer(ctx, &created); err != nil {
+		t.Fatalf("CreateTimer: %v", err)
+	}
+	if err := svc.ToggleTimer(ctx, 1); err != nil {
+		t.Fatalf("ToggleTimer: %v", err)
+	}
+	if err := svc.DeleteTimer(ctx, 1); err != nil {
+		t.Fatalf("DeleteTimer: %v", err)
+	}
+
+	evs := drainEvents(sub)
+	want := []events.Type{events.TimerCreated, events.TimerUpdated, events.TimerDeleted}
+	if got := eventTypes(evs); len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if p := evs[1].Data.(events.TimerPayload); p.ID != 1 || p.Active {
+		t.Fatalf("expected toggled timer 1 to be inactive, got %+v", p)
+	}
+
+	mock.DeleteTimerFunc = func(ctx context.Context, id int) error { return domain.ErrNotFound }
+	_ = svc.DeleteTimer(ctx, 2)
+	if evs := drainEvents(sub); len(evs) != 0 {
+		t.Fatalf("expected no event for failed delete, got %v", eventTypes(evs))
+	}
+}
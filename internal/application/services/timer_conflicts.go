@@ -0,0 +1,466 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// TimerOccurrence is a single concrete recording window of a (possibly recurring) timer.
+// Start and Stop are the timer times, i.e. they already include the recording margins.
+type TimerOccurrence struct {
+	TimerID int
+	Start   time.Time
+	Stop    time.Time
+}
+
+// TimerOccurrences projects a timer onto the concrete recording windows overlapping [from, to).
+// One-time timers yield at most one occurrence; recurring timers (weekday mask) one per matching day.
+func TimerOccurrences(t domain.Timer, from, to time.Time) []TimerOccurrence {
+	if from.After(to) {
+		return nil
+	}
+
+	// One-time timer with concrete timestamps.
+	if !t.Start.IsZero() && !t.Stop.IsZero() && t.Stop.After(t.Start) && !IsWeekdayMask(t.DaySpec) {
+		if t.Stop.Before(from) || !t.Start.Before(to) {
+			return nil
+		}
+		return []TimerOccurrence{{TimerID: t.ID, Start: t.Start, Stop: t.Stop}}
+	}
+
+	// Recurring timer: project onto each matching weekday in the window.
+	daySpec := strings.TrimSpace(t.DaySpec)
+	if !IsWeekdayMask(daySpec) {
+		return nil
+	}
+	if t.StartMinutes < 0 || t.StopMinutes < 0 {
+		return nil
+	}
+
+	loc := time.Local
+	startDay := time.Date(from.In(loc).Year(), from.In(loc).Month(), from.In(loc).Day(), 0, 0, 0, 0, loc)
+	endDay := time.Date(to.In(loc).Year(), to.In(loc).Month(), to.In(loc).Day(), 0, 0, 0, 0, loc)
+
+	var out []TimerOccurrence
+	for d := startDay; d.Before(endDay); d = d.AddDate(0, 0, 1) {
+		if !WeekdayMaskAllows(daySpec, d.Weekday()) {
+			continue
+		}
+		start := d.Add(time.Duration(t.StartMinutes) * time.Minute)
+		stop := d.Add(time.Duration(t.StopMinutes) * time.Minute)
+		if stop.Before(start) {
+			stop = stop.Add(24 * time.Hour)
+		}
+		if stop.Before(from) || !start.Before(to) {
+			continue
+		}
+		out = append(out, TimerOccurrence{TimerID: t.ID, Start: start, Stop: stop})
+	}
+	return out
+}
+
+// IsWeekdayMask reports whether daySpec is a VDR weekday mask like "MTWTF--".
+func IsWeekdayMask(daySpec string) bool {
+	daySpec = strings.TrimSpace(daySpec)
+	if len(daySpec) != 7 {
+		return false
+	}
+	for _, r := range daySpec {
+		switch r {
+		case 'M', 'T', 'W', 'F', 'S', '-', '.':
+			// ok
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WeekdayMaskAllows reports whether a weekday mask (Monday first) enables the given weekday.
+func WeekdayMaskAllows(daySpec string, wd time.Weekday) bool {
+	// time.Weekday starts at Sunday; VDR masks start at Monday.
+	idx := (int(wd) + 6) % 7
+	if idx >= len(daySpec) {
+		return false
+	}
+	c := daySpec[idx]
+	return c != '-' && c != '.'
+}
+
+// TransponderKey returns the part of a VDR channel ID identifying its transponder
+// (source, NID and TID). Channels with the same key can be recorded by one device.
+func TransponderKey(channelID string) string {
+	parts := strings.Split(channelID, "-")
+	if len(parts) >= 3 {
+		return strings.Join(parts[:3], "-")
+	}
+	return channelID
+}
+
+// TransponderKeyForTimer resolves the transponder of a timer.
+// Timers may reference their channel by number (LSTT), so channels is used to map numbers to IDs.
+// Unknown channels yield the raw channel reference, which then never shares a device.
+func TransponderKeyForTimer(t domain.Timer, channels []domain.Channel) string {
+	chID := strings.TrimSpace(t.ChannelID)
+	if LooksLikeVDRChannelID(chID) {
+		return TransponderKey(chID)
+	}
+	if n, err := strconv.Atoi(chID); err == nil {
+		for i := range channels {
+			if channels[i].Number == n {
+				if LooksLikeVDRChannelID(channels[i].ID) {
+					return TransponderKey(channels[i].ID)
+				}
+				break
+			}
+		}
+	}
+	return chID
+}
+
+// LooksLikeVDRChannelID reports whether s has the shape of a VDR channel ID (e.g. "S19.2E-1-1019-10301").
+func LooksLikeVDRChannelID(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.Contains(s, "-") && (strings.HasPrefix(s, "S") || strings.HasPrefix(s, "C") || strings.HasPrefix(s, "T") || strings.HasPrefix(s, "A"))
+}
+
+// AllocationOptions configures SimulateTimerAllocation.
+type AllocationOptions struct {
+	// DVBCards is the number of devices available for recording (at least 1).
+	DVBCards int
+	// From and To limit the simulated time window.
+	From time.Time
+	To   time.Time
+	// Now is used to keep inactive timers that are currently recording.
+	Now time.Time
+	// TransponderKey maps a timer to its transponder. Timers with the same key share a device.
+	TransponderKey func(domain.Timer) string
+}
+
+// TimerAllocation is the simulated outcome of one timer occurrence.
+type TimerAllocation struct {
+	TimerID     int
+	Priority    int
+	Start       time.Time
+	Stop        time.Time
+	Transponder string
+	// Device is the 1-based number of the device the occurrence recorded on first; 0 if it never got one.
+	Device int
+	// Shared is set when the device was shared with another timer on the same transponder.
+	Shared bool
+	// Missed is the part of the window that could not be recorded (late start or preemption).
+	Missed time.Duration
+	// PreemptedBy lists timers that took the device away from this occurrence.
+	PreemptedBy []int
+}
+
+// Failed reports whether the occurrence is not recorded completely.
+func (a TimerAllocation) Failed() bool {
+	return a.Missed > 0
+}
+
+// ConflictReport is the result of a device allocation simulation.
+type ConflictReport struct {
+	DVBCards    int
+	Allocations []TimerAllocation
+	// CollisionIDs are timers overlapping with timers on other transponders (more than one device needed).
+	CollisionIDs map[int]bool
+	// CriticalIDs are timers involved in an overlap that exceeds the available devices.
+	CriticalIDs map[int]bool
+	// FailedIDs are timers with at least one occurrence that is not recorded completely.
+	FailedIDs map[int]bool
+}
+
+// HasConflicts reports whether any timer fails to record.
+func (r ConflictReport) HasConflicts() bool {
+	return len(r.FailedIDs) > 0
+}
+
+// AllocationsForTimer returns the simulated occurrences of a single timer in start order.
+func (r ConflictReport) AllocationsForTimer(timerID int) []TimerAllocation {
+	var out []TimerAllocation
+	for _, a := range r.Allocations {
+		if a.TimerID == timerID {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// SimulateTimerAllocation simulates how VDR assigns devices to the given timers.
+//
+// Occurrences on the same transponder share a device. When no device is free, a
+// timer with a higher priority takes over the device whose recordings have the
+// lowest priority; otherwise it waits until a device becomes available. Inactive
+// timers are ignored unless they are recording at opts.Now.
+func SimulateTimerAllocation(timers []domain.Timer, opts AllocationOptions) ConflictReport {
+	dvbCards := opts.DVBCards
+	if dvbCards < 1 {
+		dvbCards = 1
+	}
+	keyFn := opts.TransponderKey
+	if keyFn == nil {
+		keyFn = func(t domain.Timer) string { return TransponderKey(strings.TrimSpace(t.ChannelID)) }
+	}
+
+	report := ConflictReport{
+		DVBCards:     dvbCards,
+		CollisionIDs: map[int]bool{},
+		CriticalIDs:  map[int]bool{},
+		FailedIDs:    map[int]bool{},
+	}
+
+	for _, t := range timers {
+		if !t.Active && !timerRecordingAt(t, opts.Now) {
+			continue
+		}
+		key := keyFn(t)
+		if key == "" {
+			key = "timer:" + strconv.Itoa(t.ID)
+		}
+		for _, occ := range TimerOccurrences(t, opts.From, opts.To) {
+			report.Allocations = append(report.Allocations, TimerAllocation{
+				TimerID:     t.ID,
+				Priority:    t.Priority,
+				Start:       occ.Start,
+				Stop:        occ.Stop,
+				Transponder: key,
+			})
+		}
+	}
+	allocs := report.Allocations
+	sort.SliceStable(allocs, func(i, j int) bool {
+		if !allocs[i].Start.Equal(allocs[j].Start) {
+			return allocs[i].Start.Before(allocs[j].Start)
+		}
+		return allocs[i].TimerID < allocs[j].TimerID
+	})
+
+	type device struct {
+		key     string
+		holders map[int]struct{}
+	}
+	devices := make([]device, dvbCards)
+	for i := range devices {
+		devices[i].holders = map[int]struct{}{}
+	}
+
+	// Per-occurrence runtime state.
+	deviceOf := make([]int, len(allocs))        // 0 = not recording, else device number
+	pendingAt := make([]time.Time, len(allocs)) // when the occurrence started waiting
+	waiting := map[int]struct{}{}
+	active := map[int]struct{}{}
+
+	// Event times: every start and stop.
+	times := make([]time.Time, 0, len(allocs)*2)
+	for _, a := range allocs {
+		times = append(times, a.Start, a.Stop)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	release := func(idx int) {
+		if d := deviceOf[idx]; d > 0 {
+			delete(devices[d-1].holders, idx)
+			if len(devices[d-1].holders) == 0 {
+				devices[d-1].key = ""
+			}
+			deviceOf[idx] = 0
+		}
+	}
+	assign := func(idx, d int, at time.Time) {
+		dev := &devices[d-1]
+		if len(dev.holders) > 0 {
+			allocs[idx].Shared = true
+			for h := range dev.holders {
+				allocs[h].Shared = true
+			}
+		}
+		dev.key = allocs[idx].Transponder
+		dev.holders[idx] = struct{}{}
+		deviceOf[idx] = d
+		if allocs[idx].Device == 0 {
+			allocs[idx].Device = d
+		}
+		if !pendingAt[idx].IsZero() {
+			allocs[idx].Missed += at.Sub(pendingAt[idx])
+			pendingAt[idx] = time.Time{}
+		}
+		delete(waiting, idx)
+	}
+	maxPriority := func(d int) int {
+		p := -1
+		for h := range devices[d].holders {
+			if allocs[h].Priority > p {
+				p = allocs[h].Priority
+			}
+		}
+		return p
+	}
+
+	var prev time.Time
+	for ti, at := range times {
+		if ti > 0 && at.Equal(prev) {
+			continue
+		}
+		prev = at
+
+		// Finish occurrences ending now (stops before starts at the same instant).
+		for idx := range active {
+			if !allocs[idx].Stop.After(at) {
+				release(idx)
+				if !pendingAt[idx].IsZero() {
+					allocs[idx].Missed += allocs[idx].Stop.Sub(pendingAt[idx])
+					pendingAt[idx] = time.Time{}
+				}
+				delete(waiting, idx)
+				delete(active, idx)
+			}
+		}
+		// Start occurrences beginning now.
+		for idx := range allocs {
+			if allocs[idx].Start.Equal(at) && allocs[idx].Stop.After(at) {
+				active[idx] = struct{}{}
+				waiting[idx] = struct{}{}
+				pendingAt[idx] = at
+			}
+		}
+
+		// Try to place waiting occurrences, highest priority first.
+		queue := make([]int, 0, len(waiting))
+		for idx := range waiting {
+			queue = append(queue, idx)
+		}
+		sort.Slice(queue, func(i, j int) bool {
+			a, b := allocs[queue[i]], allocs[queue[j]]
+			if a.Priority != b.Priority {
+				return a.Priority > b.Priority
+			}
+			if !a.Start.Equal(b.Start) {
+				return a.Start.Before(b.Start)
+			}
+			return queue[i] < queue[j]
+		})
+		for _, idx := range queue {
+			if _, still := waiting[idx]; !still {
+				continue
+			}
+			key := allocs[idx].Transponder
+
+			target := 0
+			for d := range devices {
+				if len(devices[d].holders) > 0 && devices[d].key == key {
+					target = d + 1
+					break
+				}
+			}
+			if target == 0 {
+				for d := range devices {
+					if len(devices[d].holders) == 0 {
+						target = d + 1
+						break
+					}
+				}
+			}
+			if target == 0 {
+				// Preempt the device with the lowest-priority recordings, if ours is higher.
+				victim, victimPrio := -1, allocs[idx].Priority
+				for d := range devices {
+					if p := maxPriority(d); p < victimPrio {
+						victim, victimPrio = d, p
+					}
+				}
+				if victim >= 0 {
+					for h := range devices[victim].holders {
+						allocs[h].PreemptedBy = append(allocs[h].PreemptedBy, allocs[idx].TimerID)
+						release(h)
+						waiting[h] = struct{}{}
+						pendingAt[h] = at
+					}
+					target = victim + 1
+				}
+			}
+			if target > 0 {
+				assign(idx, target, at)
+			}
+		}
+
+		// Mark timers involved in the current overlap.
+		keys := map[string]struct{}{}
+		for idx := range active {
+			keys[allocs[idx].Transponder] = struct{}{}
+		}
+		if len(keys) > 1 {
+			for idx := range active {
+				report.CollisionIDs[allocs[idx].TimerID] = true
+			}
+		}
+		if len(waiting) > 0 {
+			for idx := range active {
+				report.CriticalIDs[allocs[idx].TimerID] = true
+			}
+		}
+	}
+
+	for _, a := range allocs {
+		if a.Failed() {
+			report.FailedIDs[a.TimerID] = true
+			report.CriticalIDs[a.TimerID] = true
+		}
+	}
+	report.Allocations = allocs
+	return report
+}
+
+func timerRecordingAt(t domain.Timer, now time.Time) bool {
+	if now.IsZero() || t.Start.IsZero() || t.Stop.IsZero() {
+		return false
+	}
+	return !t.Start.After(now) && t.Stop.After(now)
+}
+
+// TimerConflictError reports timers that would fail to record because of a new or changed timer.
+type TimerConflictError struct {
+	// Timer is the new or changed timer.
+	Timer domain.Timer
+	// Failed are the timers (possibly including Timer) that would not be recorded completely.
+	Failed []domain.Timer
+	// Conflicting are the other timers competing for devices with Timer.
+	Conflicting []domain.Timer
+}
+
+func (e *TimerConflictError) Error() string {
+	names := make([]string, 0, len(e.Conflicting))
+	for _, t := range e.Conflicting {
+		names = append(names, timerLabel(t))
+	}
+	msg := "not enough DVB devices"
+	if len(names) > 0 {
+		msg += "; conflicts with " + strings.Join(names, ", ")
+	}
+	for _, t := range e.Failed {
+		if t.ID == e.Timer.ID {
+			return fmt.Sprintf("timer conflict: %q would not be recorded completely (%s)", e.Timer.Title, msg)
+		}
+	}
+	failed := make([]string, 0, len(e.Failed))
+	for _, t := range e.Failed {
+		failed = append(failed, timerLabel(t))
+	}
+	return fmt.Sprintf("timer conflict: %s would not be recorded completely (%s)", strings.Join(failed, ", "), msg)
+}
+
+func (e *TimerConflictError) Unwrap() error {
+	return domain.ErrConflict
+}
+
+func timerLabel(t domain.Timer) string {
+	if strings.TrimSpace(t.Title) != "" {
+		return fmt.Sprintf("%q", t.Title)
+	}
+	return fmt.Sprintf("timer %d", t.ID)
+}
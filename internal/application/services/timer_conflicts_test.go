@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func conflictTestTimer(id int, channelID string, start time.Time, dur time.Duration, priority int) domain.Timer {
+	return domain.Timer{
+		ID:        id,
+		Active:    true,
+		ChannelID: channelID,
+		Start:     start,
+		Stop:      start.Add(dur),
+		Priority:  priority,
+		Title:     "Timer",
+	}
+}
+
+func simulate(timers []domain.Timer, dvbCards int) ConflictReport {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	return SimulateTimerAllocation(timers, AllocationOptions{
+		DVBCards: dvbCards,
+		From:     from,
+		To:       from.Add(48 * time.Hour),
+	})
+}
+
+func TestSimulateTimerAllocation_SharesTransponder(t *testing.T) {
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	report := simulate([]domain.Timer{
+		conflictTestTimer(1, "S19.2E-1-1019-10301", base, time.Hour, 50),
+		conflictTestTimer(2, "S19.2E-1-1019-10302", base.Add(30*time.Minute), time.Hour, 50),
+	}, 1)
+
+	if report.HasConflicts() {
+		t.Fatalf("expected no failures, got %v", report.FailedIDs)
+	}
+	for _, a := range report.Allocations {
+		if a.Device != 1 || !a.Shared {
+			t.Fatalf("expected timer %d to share device 1, got %+v", a.TimerID, a)
+		}
+	}
+}
+
+func TestSimulateTimerAllocation_NotEnoughDevices(t *testing.T) {
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	report := simulate([]domain.Timer{
+		conflictTestTimer(1, "S19.2E-1-1019-10301", base, time.Hour, 50),
+		conflictTestTimer(2, "S19.2E-1-1011-11110", base.Add(30*time.Minute), time.Hour, 50),
+	}, 1)
+
+	if report.FailedIDs[1] || !report.FailedIDs[2] {
+		t.Fatalf("expected only timer 2 to fail, got %v", report.FailedIDs)
+	}
+	a := report.AllocationsForTimer(2)[0]
+	// Timer 2 waits until timer 1 frees the device at 21:00.
+	if a.Device != 1 || a.Missed != 30*time.Minute {
+		t.Fatalf("expected late start on device 1 missing 30m, got %+v", a)
+	}
+	if !report.CriticalIDs[1] || !report.CriticalIDs[2] {
+		t.Fatalf("expected both timers critical, got %v", report.CriticalIDs)
+	}
+
+	report = simulate([]domain.Timer{
+		conflictTestTimer(1, "S19.2E-1-1019-10301", base, time.Hour, 50),
+		conflictTestTimer(2, "S19.2E-1-1011-11110", base.Add(30*time.Minute), time.Hour, 50),
+	}, 2)
+	if report.HasConflicts() || len(report.CriticalIDs) != 0 {
+		t.Fatalf("expected no conflicts with two devices, got failed=%v critical=%v", report.FailedIDs, report.CriticalIDs)
+	}
+	if !report.CollisionIDs[1] || !report.CollisionIDs[2] {
+		t.Fatalf("expected both timers to collide, got %v", report.CollisionIDs)
+	}
+	if d1, d2 := report.AllocationsForTimer(1)[0].Device, report.AllocationsForTimer(2)[0].Device; d1 != 1 || d2 != 2 {
+		t.Fatalf("expected devices 1 and 2, got %d and %d", d1, d2)
+	}
+}
+
+func TestSimulateTimerAllocation_PriorityPreempts(t *testing.T) {
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	report := simulate([]domain.Timer{
+		conflictTestTimer(1, "S19.2E-1-1019-10301", base, 2*time.Hour, 10),
+		conflictTestTimer(2, "S19.2E-1-1011-11110", base.Add(30*time.Minute), time.Hour, 90),
+	}, 1)
+
+	if report.FailedIDs[2] {
+		t.Fatalf("expected high-priority timer to record, got %v", report.FailedIDs)
+	}
+	low := report.AllocationsForTimer(1)[0]
+	if !low.Failed() || low.Missed != time.Hour {
+		t.Fatalf("expected low-priority timer to miss 1h, got %+v", low)
+	}
+	if len(low.PreemptedBy) != 1 || low.PreemptedBy[0] != 2 {
+		t.Fatalf("expected preemption by timer 2, got %v", low.PreemptedBy)
+	}
+}
+
+func TestSimulateTimerAllocation_SkipsInactiveUnlessRecording(t *testing.T) {
+	base := time.Date(2026, 3, 1, 20, 0, 0, 0, time.Local)
+	inactive := conflictTestTimer(1, "S19.2E-1-1019-10301", base, time.Hour, 50)
+	inactive.Active = false
+	other := conflictTestTimer(2, "S19.2E-1-1011-11110", base, time.Hour, 50)
+
+	report := simulate([]domain.Timer{inactive, other}, 1)
+	if report.HasConflicts() {
+		t.Fatalf("expected inactive timer to be ignored, got %v", report.FailedIDs)
+	}
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	report = SimulateTimerAllocation([]domain.Timer{inactive, other}, AllocationOptions{
+		DVBCards: 1,
+		From:     from,
+		To:       from.Add(48 * time.Hour),
+		Now:      base.Add(10 * time.Minute),
+	})
+	if !report.HasConflicts() {
+		t.Fatalf("expected running inactive timer to occupy a device")
+	}
+}
+
+func TestSimulateTimerAllocation_RecurringTimer(t *testing.T) {
+	// 2026-03-02 is a Monday.
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)
+	weekly := domain.Timer{
+		ID:           1,
+		Active:       true,
+		ChannelID:    "S19.2E-1-1019-10301",
+		DaySpec:      "M------",
+		StartMinutes: 20 * 60,
+		StopMinutes:  21 * 60,
+		Priority:     50,
+	}
+	oneTime := conflictTestTimer(2, "S19.2E-1-1011-11110", monday.Add(20*time.Hour+15*time.Minute), time.Hour, 50)
+
+	report := SimulateTimerAllocation([]domain.Timer{weekly, oneTime}, AllocationOptions{
+		DVBCards: 1,
+		From:     monday,
+		To:       monday.Add(8 * 24 * time.Hour),
+	})
+	if got := len(report.AllocationsForTimer(1)); got != 2 {
+		t.Fatalf("expected two weekly occurrences, got %d", got)
+	}
+	if !report.FailedIDs[2] || report.FailedIDs[1] {
+		t.Fatalf("expected only the one-time timer to fail, got %v", report.FailedIDs)
+	}
+}
+
+func TestTimerService_ConflictPolicy(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	mock := ports.NewMockVDRClient().WithTimers([]domain.Timer{
+		conflictTestTimer(1, "S19.2E-1-1019-10301", base, time.Hour, 50),
+	})
+	svc := NewTimerService(mock)
+
+	clash := conflictTestTimer(0, "S19.2E-1-1011-11110", base.Add(15*time.Minute), time.Hour, 50)
+	clash.Title = "Clash"
+
+	conflict, err := svc.CheckTimerConflicts(context.Background(), &clash)
+	if err != nil {
+		t.Fatalf("CheckTimerConflicts: %v", err)
+	}
+	if conflict == nil || len(conflict.Conflicting) != 1 || conflict.Conflicting[0].ID != 1 {
+		t.Fatalf("expected conflict with timer 1, got %+v", conflict)
+	}
+
+	sameTransponder := conflictTestTimer(0, "S19.2E-1-1019-10302", base.Add(15*time.Minute), time.Hour, 50)
+	if conflict, err := svc.CheckTimerConflicts(context.Background(), &sameTransponder); err != nil || conflict != nil {
+		t.Fatalf("expected no conflict on shared transponder, got %+v (err=%v)", conflict, err)
+	}
+
+	// The default policy only warns; creation succeeds.
+	warned := clash
+	if err := svc.CreateTimer(context.Background(), &warned); err != nil {
+		t.Fatalf("CreateTimer (warn): %v", err)
+	}
+
+	svc.SetConflictPolicy(ConflictPolicyReject)
+	rejected := clash
+	rejected.Start = rejected.Start.Add(5 * time.Minute)
+	rejected.Stop = rejected.Stop.Add(5 * time.Minute)
+	err = svc.CreateTimer(context.Background(), &rejected)
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	var conflictErr *TimerConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected *TimerConflictError, got %T", err)
+	}
+
+	svc.SetDVBCards(3)
+	if err := svc.CreateTimer(context.Background(), &rejected); err != nil {
+		t.Fatalf("CreateTimer with enough devices: %v", err)
+	}
+}
+
+func TestTransponderKeyForTimer_ResolvesChannelNumber(t *testing.T) {
+	channels := []domain.Channel{{ID: "S19.2E-1-1019-10301", Number: 1}}
+	if got := TransponderKeyForTimer(domain.Timer{ChannelID: "1"}, channels); got != "S19.2E-1-1019" {
+		t.Fatalf("unexpected key %q", got)
+	}
+	if got := TransponderKeyForTimer(domain.Timer{ChannelID: "7"}, channels); got != "7" {
+		t.Fatalf("expected unknown channel to stay unique, got %q", got)
+	}
+}
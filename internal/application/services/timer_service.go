@@ -3,40 +3,192 @@ package services
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/githubixx/vdradmin-go/internal/application/events"
 	"github.com/githubixx/vdradmin-go/internal/domain"
 	"github.com/githubixx/vdradmin-go/internal/ports"
 )
 
+// Timer conflict policies applied when creating or updating timers.
+const (
+	ConflictPolicyOff    = "off"
+	ConflictPolicyWarn   = "warn"
+	ConflictPolicyReject = "reject"
+)
+
+// ChannelLister provides the channel list used to resolve timer transponders.
+type ChannelLister interface {
+	GetAllChannels(ctx context.Context) ([]domain.Channel, error)
+}
+
 // TimerService handles timer-related operations
 type TimerService struct {
 	vdrClient ports.VDRClient
+
+	mu             sync.RWMutex
+	dvbCards       int
+	channels       ChannelLister
+	conflictPolicy string
+	events         *events.Bus
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
 }
 
 // NewTimerService creates a new timer service
 func NewTimerService(vdrClient ports.VDRClient) *TimerService {
 	return &TimerService{
-		vdrClient: vdrClient,
+		vdrClient:      vdrClient,
+		dvbCards:       1,
+		conflictPolicy: ConflictPolicyWarn,
+		now:            time.Now,
+	}
+}
+
+// SetDVBCards sets the number of devices available for recording.
+func (s *TimerService) SetDVBCards(n int) {
+	if n < 1 {
+		n = 1
 	}
+	s.mu.Lock()
+	s.dvbCards = n
+	s.mu.Unlock()
+}
+
+// DVBCards returns the number of devices available for recording.
+func (s *TimerService) DVBCards() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dvbCards
+}
+
+// SetChannelLister sets the source of channels used to map timers to transponders.
+// Without it, channels are fetched from VDR directly.
+func (s *TimerService) SetChannelLister(l ChannelLister) {
+	s.mu.Lock()
+	s.channels = l
+	s.mu.Unlock()
+}
+
+// SetConflictPolicy configures how CreateTimer and UpdateTimer treat conflicts:
+// "reject" refuses timers that would make a recording fail; "warn" and "off" accept them.
+func (s *TimerService) SetConflictPolicy(policy string) {
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	switch policy {
+	case ConflictPolicyOff, ConflictPolicyReject:
+	default:
+		policy = ConflictPolicyWarn
+	}
+	s.mu.Lock()
+	s.conflictPolicy = policy
+	s.mu.Unlock()
+}
+
+// SetEventBus sets the bus timer changes are published to.
+func (s *TimerService) SetEventBus(bus *events.Bus) {
+	s.mu.Lock()
+	s.events = bus
+	s.mu.Unlock()
+}
+
+func (s *TimerService) publish(typ events.Type, timer domain.Timer) {
+	s.mu.RLock()
+	bus := s.events
+	s.mu.RUnlock()
+	bus.Publish(typ, events.TimerPayload{
+		ID:        timer.ID,
+		Active:    timer.Active,
+		ChannelID: timer.ChannelID,
+		Title:     timer.Title,
+		Start:     timer.Start,
+		Stop:      timer.Stop,
+		EventID:   timer.EventID,
+	})
+}
+
+// ConflictPolicy returns the configured conflict policy.
+func (s *TimerService) ConflictPolicy() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.conflictPolicy
 }
 
 // GetAllTimers retrieves all timers
-func (s *TimerService) GetAllTimers(ctx context.Context) ([]domain.Timer, error) {
-	return s.vdrClient.GetTimers(ctx)
+func (s *TimerService) GetAllTimers(ctx context.Context) (timers []domain.Timer, err error) {
+	ctx, span := startSpan(ctx, "TimerService.GetAllTimers")
+	defer func() { endSpan(span, err) }()
+
+	timers, err = s.vdrClient.GetTimers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// VDR does not report event IDs; timers created from an event keep it in their aux data.
+	// The client may share its slice, so the event IDs go into a copy.
+	timers = append([]domain.Timer(nil), timers...)
+	for i := range timers {
+		if timers[i].EventID == 0 {
+			if id, _, _, ok := TimerEventFromAux(timers[i].Aux); ok {
+				timers[i].EventID = id
+			}
+		}
+	}
+	return timers, nil
 }
 
 // CreateTimer creates a new timer
-func (s *TimerService) CreateTimer(ctx context.Context, timer *domain.Timer) error {
+func (s *TimerService) CreateTimer(ctx context.Context, timer *domain.Timer) (err error) {
+	ctx, span := startSpan(ctx, "TimerService.CreateTimer")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.validateTimer(timer); err != nil {
 		return fmt.Errorf("invalid timer: %w", err)
 	}
+	if err := s.rejectConflicts(ctx, timer); err != nil {
+		return err
+	}
 
-	return s.vdrClient.CreateTimer(ctx, timer)
+	if err := s.vdrClient.CreateTimer(ctx, timer); err != nil {
+		return err
+	}
+	s.publish(events.TimerCreated, *timer)
+	return nil
+}
+
+// TimerOption adjusts a timer built from an EPG event.
+type TimerOption func(*domain.Timer)
+
+// WithTimerFolder records into a '~'-separated folder instead of the top level.
+func WithTimerFolder(folder string) TimerOption {
+	return func(t *domain.Timer) {
+		if folder = strings.Trim(strings.TrimSpace(folder), FolderSeparator); folder != "" {
+			t.Title = folder + FolderSeparator + t.Title
+		}
+	}
+}
+
+// WithTimerAux sets the auxiliary data VDR stores with the timer.
+func WithTimerAux(aux string) TimerOption {
+	return func(t *domain.Timer) {
+		t.Aux = aux
+	}
 }
 
 // CreateTimerFromEPG creates a timer from an EPG event
-func (s *TimerService) CreateTimerFromEPG(ctx context.Context, event domain.EPGEvent, priority, lifetime, marginStart, marginEnd int) error {
+func (s *TimerService) CreateTimerFromEPG(ctx context.Context, event domain.EPGEvent, priority, lifetime, marginStart, marginEnd int, opts ...TimerOption) error {
+	timer := NewTimerFromEPG(event, priority, lifetime, marginStart, marginEnd, opts...)
+	return s.CreateTimer(ctx, &timer)
+}
+
+// NewTimerFromEPG builds the timer CreateTimerFromEPG would create for an event, without creating it.
+func NewTimerFromEPG(event domain.EPGEvent, priority, lifetime, marginStart, marginEnd int, opts ...TimerOption) domain.Timer {
 	start := event.Start.Add(-time.Duration(marginStart) * time.Minute)
 	stop := event.Stop.Add(time.Duration(marginEnd) * time.Minute)
 	// VDR's timer day spec is effectively the date of the timer start time.
@@ -45,7 +197,7 @@ func (s *TimerService) CreateTimerFromEPG(ctx context.Context, event domain.EPGE
 	startLocal := start.In(time.Local)
 	day := time.Date(startLocal.Year(), startLocal.Month(), startLocal.Day(), 0, 0, 0, 0, time.Local)
 
-	timer := &domain.Timer{
+	timer := domain.Timer{
 		Active:    true,
 		ChannelID: event.ChannelID,
 		Day:       day,
@@ -56,30 +208,85 @@ func (s *TimerService) CreateTimerFromEPG(ctx context.Context, event domain.EPGE
 		Title:     event.Title,
 		EventID:   event.EventID,
 	}
+	for _, opt := range opts {
+		opt(&timer)
+	}
+	if event.EventID > 0 {
+		timer.Aux = withTimerEventAux(timer.Aux, event.EventID, marginStart, marginEnd)
+	}
+	return timer
+}
+
+// timerEventAuxRe finds the event and margins in the aux data of timers created from an event.
+var timerEventAuxRe = regexp.MustCompile(`<event id="(\d+)" margins="(\d+),(\d+)"/>`)
+
+// withTimerEventAux sets the event a timer records and its margins in the aux data,
+// within the vdradmin-go element if there is one already.
+func withTimerEventAux(aux string, eventID, marginStart, marginEnd int) string {
+	elem := fmt.Sprintf(`<event id="%d" margins="%d,%d"/>`, eventID, marginStart, marginEnd)
+	if timerEventAuxRe.MatchString(aux) {
+		return timerEventAuxRe.ReplaceAllLiteralString(aux, elem)
+	}
+	if strings.Contains(aux, "<vdradmin-go>") {
+		return strings.Replace(aux, "<vdradmin-go>", "<vdradmin-go>"+elem, 1)
+	}
+	return aux + "<vdradmin-go>" + elem + "</vdradmin-go>"
+}
 
-	return s.CreateTimer(ctx, timer)
+// TimerEventFromAux returns the event ID and margins (in minutes) of a timer
+// created from an event.
+func TimerEventFromAux(aux string) (eventID, marginStart, marginEnd int, ok bool) {
+	m := timerEventAuxRe.FindStringSubmatch(aux)
+	if m == nil {
+		return 0, 0, 0, false
+	}
+	eventID, _ = strconv.Atoi(m[1])
+	marginStart, _ = strconv.Atoi(m[2])
+	marginEnd, _ = strconv.Atoi(m[3])
+	return eventID, marginStart, marginEnd, eventID > 0
 }
 
 // UpdateTimer updates an existing timer
-func (s *TimerService) UpdateTimer(ctx context.Context, timer *domain.Timer) error {
+func (s *TimerService) UpdateTimer(ctx context.Context, timer *domain.Timer) (err error) {
+	ctx, span := startSpan(ctx, "TimerService.UpdateTimer")
+	defer func() { endSpan(span, err) }()
+
 	if err := s.validateTimer(timer); err != nil {
 		return fmt.Errorf("invalid timer: %w", err)
 	}
+	span.SetAttributes(attribute.Int("timer.id", timer.ID))
+	if err := s.rejectConflicts(ctx, timer); err != nil {
+		return err
+	}
 
-	return s.vdrClient.UpdateTimer(ctx, timer)
+	if err := s.vdrClient.UpdateTimer(ctx, timer); err != nil {
+		return err
+	}
+	s.publish(events.TimerUpdated, *timer)
+	return nil
 }
 
 // DeleteTimer deletes a timer
-func (s *TimerService) DeleteTimer(ctx context.Context, timerID int) error {
+func (s *TimerService) DeleteTimer(ctx context.Context, timerID int) (err error) {
+	ctx, span := startSpan(ctx, "TimerService.DeleteTimer", attribute.Int("timer.id", timerID))
+	defer func() { endSpan(span, err) }()
+
 	if timerID <= 0 {
 		return domain.ErrInvalidInput
 	}
 
-	return s.vdrClient.DeleteTimer(ctx, timerID)
+	if err := s.vdrClient.DeleteTimer(ctx, timerID); err != nil {
+		return err
+	}
+	s.publish(events.TimerDeleted, domain.Timer{ID: timerID})
+	return nil
 }
 
 // ToggleTimer toggles a timer's active state
-func (s *TimerService) ToggleTimer(ctx context.Context, timerID int) error {
+func (s *TimerService) ToggleTimer(ctx context.Context, timerID int) (err error) {
+	ctx, span := startSpan(ctx, "TimerService.ToggleTimer", attribute.Int("timer.id", timerID))
+	defer func() { endSpan(span, err) }()
+
 	timers, err := s.vdrClient.GetTimers(ctx)
 	if err != nil {
 		return err
@@ -88,34 +295,195 @@ func (s *TimerService) ToggleTimer(ctx context.Context, timerID int) error {
 	for _, timer := range timers {
 		if timer.ID == timerID {
 			timer.Active = !timer.Active
-			return s.vdrClient.UpdateTimer(ctx, &timer)
+			if timer.Active {
+				if err := s.rejectConflicts(ctx, &timer); err != nil {
+					return err
+				}
+			}
+			if err := s.vdrClient.UpdateTimer(ctx, &timer); err != nil {
+				return err
+			}
+			s.publish(events.TimerUpdated, timer)
+			return nil
 		}
 	}
 
 	return domain.ErrNotFound
 }
 
-// CheckConflicts checks for timer conflicts
+// CheckConflicts returns the timers that compete with newTimer for a device
+// in a way that makes at least one of them fail to record.
 func (s *TimerService) CheckConflicts(ctx context.Context, newTimer *domain.Timer) ([]domain.Timer, error) {
+	conflict, err := s.CheckTimerConflicts(ctx, newTimer)
+	if err != nil || conflict == nil {
+		return nil, err
+	}
+	return conflict.Conflicting, nil
+}
+
+// CheckTimerConflicts simulates the device allocation with newTimer added (or replacing
+// the timer with the same ID) and reports timers that would no longer record completely.
+// It returns nil when newTimer does not introduce a new failure.
+func (s *TimerService) CheckTimerConflicts(ctx context.Context, newTimer *domain.Timer) (conflict *TimerConflictError, err error) {
+	ctx, span := startSpan(ctx, "TimerService.CheckTimerConflicts")
+	defer func() { endSpan(span, err) }()
+
+	if newTimer == nil {
+		return nil, domain.ErrInvalidInput
+	}
+	if !newTimer.Active {
+		return nil, nil
+	}
+
 	timers, err := s.vdrClient.GetTimers(ctx)
 	if err != nil {
 		return nil, err
 	}
+	channels, err := s.listChannels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.conflictsWith(timers, channels, newTimer), nil
+}
 
-	var conflicts []domain.Timer
-	for _, timer := range timers {
-		if timer.ID == newTimer.ID {
+// conflictsWith is CheckTimerConflicts on an already loaded timer and channel list.
+func (s *TimerService) conflictsWith(timers []domain.Timer, channels []domain.Channel, newTimer *domain.Timer) *TimerConflictError {
+	candidate := *newTimer
+	if candidate.ID <= 0 {
+		// Not yet known to VDR; use an ID that cannot clash with existing timers.
+		candidate.ID = -1
+	}
+	others := make([]domain.Timer, 0, len(timers))
+	for _, t := range timers {
+		if t.ID != candidate.ID {
+			others = append(others, t)
+		}
+	}
+
+	from, to := s.candidateWindow(candidate)
+	if !to.After(from) {
+		return nil
+	}
+	opts := s.allocationOptions(from, to, channels)
+
+	before := SimulateTimerAllocation(others, opts)
+	after := SimulateTimerAllocation(append(others, candidate), opts)
+
+	byID := make(map[int]domain.Timer, len(others)+1)
+	for _, t := range others {
+		byID[t.ID] = t
+	}
+	byID[candidate.ID] = candidate
+
+	var conflict TimerConflictError
+	conflict.Timer = *newTimer
+	for id := range after.FailedIDs {
+		if before.FailedIDs[id] {
 			continue
 		}
+		t := byID[id]
+		if id == candidate.ID {
+			t = *newTimer
+		}
+		conflict.Failed = append(conflict.Failed, t)
+	}
+	if len(conflict.Failed) == 0 {
+		return nil
+	}
+	for _, t := range others {
+		if after.CriticalIDs[t.ID] && overlapsAllocations(after.AllocationsForTimer(t.ID), after.AllocationsForTimer(candidate.ID)) {
+			conflict.Conflicting = append(conflict.Conflicting, t)
+		}
+	}
+	sortTimersByStart(conflict.Failed)
+	sortTimersByStart(conflict.Conflicting)
+	return &conflict
+}
+
+// SimulateConflicts simulates the device allocation of all VDR timers in [from, to).
+func (s *TimerService) SimulateConflicts(ctx context.Context, from, to time.Time) (report ConflictReport, err error) {
+	ctx, span := startSpan(ctx, "TimerService.SimulateConflicts")
+	defer func() { endSpan(span, err) }()
+
+	timers, err := s.vdrClient.GetTimers(ctx)
+	if err != nil {
+		return ConflictReport{}, err
+	}
+	channels, err := s.listChannels(ctx)
+	if err != nil {
+		return ConflictReport{}, err
+	}
+	return SimulateTimerAllocation(timers, s.allocationOptions(from, to, channels)), nil
+}
+
+func (s *TimerService) rejectConflicts(ctx context.Context, timer *domain.Timer) error {
+	if s.ConflictPolicy() != ConflictPolicyReject {
+		return nil
+	}
+	conflict, err := s.CheckTimerConflicts(ctx, timer)
+	if err != nil {
+		return err
+	}
+	if conflict != nil {
+		return conflict
+	}
+	return nil
+}
+
+func (s *TimerService) allocationOptions(from, to time.Time, channels []domain.Channel) AllocationOptions {
+	s.mu.RLock()
+	dvbCards := s.dvbCards
+	s.mu.RUnlock()
+	return AllocationOptions{
+		DVBCards: dvbCards,
+		From:     from,
+		To:       to,
+		Now:      s.now(),
+		TransponderKey: func(t domain.Timer) string {
+			return TransponderKeyForTimer(t, channels)
+		},
+	}
+}
+
+// candidateWindow returns the window to simulate for a timer. Recurring timers are
+// checked for the coming week; the window is widened by a day on each side so that
+// timers already running into it are taken into account.
+func (s *TimerService) candidateWindow(t domain.Timer) (time.Time, time.Time) {
+	if IsWeekdayMask(t.DaySpec) {
+		now := s.now()
+		return now.Add(-24 * time.Hour), now.Add(8 * 24 * time.Hour)
+	}
+	return t.Start.Add(-24 * time.Hour), t.Stop.Add(24 * time.Hour)
+}
 
-		// Check if timers overlap
-		if timer.ChannelID == newTimer.ChannelID &&
-			timersOverlap(&timer, newTimer) {
-			conflicts = append(conflicts, timer)
+func (s *TimerService) listChannels(ctx context.Context) ([]domain.Channel, error) {
+	s.mu.RLock()
+	lister := s.channels
+	s.mu.RUnlock()
+	if lister != nil {
+		return lister.GetAllChannels(ctx)
+	}
+	return s.vdrClient.GetChannels(ctx)
+}
+
+func overlapsAllocations(a, b []TimerAllocation) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x.Start.Before(y.Stop) && y.Start.Before(x.Stop) {
+				return true
+			}
 		}
 	}
+	return false
+}
 
-	return conflicts, nil
+func sortTimersByStart(timers []domain.Timer) {
+	sort.SliceStable(timers, func(i, j int) bool {
+		if !timers[i].Start.Equal(timers[j].Start) {
+			return timers[i].Start.Before(timers[j].Start)
+		}
+		return timers[i].ID < timers[j].ID
+	})
 }
 
 func (s *TimerService) validateTimer(timer *domain.Timer) error {
@@ -141,7 +509,3 @@ func (s *TimerService) validateTimer(timer *domain.Timer) error {
 
 	return nil
 }
-
-func timersOverlap(t1, t2 *domain.Timer) bool {
-	return t1.Start.Before(t2.Stop) && t2.Start.Before(t1.Stop)
-}
@@ -40,7 +40,22 @@ func (s *timerCreateSpyVDR) GetRecordingDir(ctx context.Context, recordingID str
 	return "", nil
 }
 func (s *timerCreateSpyVDR) DeleteRecording(ctx context.Context, path string) error { return nil }
-func (s *timerCreateSpyVDR) GetCurrentChannel(ctx context.Context) (string, error)  { return "", nil }
+func (s *timerCreateSpyVDR) MoveRecording(ctx context.Context, path, newName string) error {
+	return nil
+}
+func (s *timerCreateSpyVDR) CutRecording(ctx context.Context, path string) error { return nil }
+func (s *timerCreateSpyVDR) UpdateChannel(ctx context.Context, number int, settings string) error {
+	return nil
+}
+func (s *timerCreateSpyVDR) MoveChannel(ctx context.Context, number, to int) error { return nil }
+func (s *timerCreateSpyVDR) CreateChannel(ctx context.Context, settings string) (int, error) {
+	return 0, nil
+}
+func (s *timerCreateSpyVDR) DeleteChannel(ctx context.Context, number int) error { return nil }
+func (s *timerCreateSpyVDR) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error {
+	return nil
+}
+func (s *timerCreateSpyVDR) GetCurrentChannel(ctx context.Context) (string, error) { return "", nil }
 func (s *timerCreateSpyVDR) SetCurrentChannel(ctx context.Context, channelID string) error {
 	return nil
 }
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+)
+
+// Actions of timer watchdog changes.
+const (
+	TimerWatchMoved    = "moved"
+	TimerWatchVanished = "vanished"
+	TimerWatchFailed   = "failed"
+)
+
+// timerWatchChangesKept is the number of changes TimerWatchdog keeps in its log.
+const timerWatchChangesKept = 50
+
+// TimerWatchChange records what the watchdog did with a timer.
+type TimerWatchChange struct {
+	At        time.Time
+	TimerID   int
+	EventID   int
+	Title     string
+	ChannelID string
+	Action    string
+	// Backend names the VDR of the timer when several are configured.
+	Backend string
+	// OldStart and OldStop are the timer's times before the change,
+	// NewStart and NewStop the times it was moved to.
+	OldStart time.Time
+	OldStop  time.Time
+	NewStart time.Time
+	NewStop  time.Time
+	Err      string
+}
+
+// TimerWatchdog keeps timers created from EPG events in line with their event:
+// when the broadcaster moves the event, the timer is moved along with its
+// margins; when the event disappears from the EPG, the timer is flagged.
+// Recurring timers and timers without event (manual timers) are left alone.
+// Each VDR backend has its own watchdog, as timers and EPG belong to a backend.
+type TimerWatchdog struct {
+	timerService *TimerService
+	epgService   *EPGService
+
+	mu          sync.RWMutex
+	marginStart int
+	marginEnd   int
+	changes     []TimerWatchChange
+	vanished    map[int]TimerWatchChange
+	onChange    []func(TimerWatchChange)
+
+	// processMu serializes checks (background and manual).
+	processMu sync.Mutex
+
+	interval  time.Duration
+	trigger   chan struct{}
+	reconfig  chan struct{}
+	listenMu  sync.Mutex
+	listening bool
+
+	// now is used to make time-dependent behavior testable.
+	now func() time.Time
+}
+
+// NewTimerWatchdog creates a watchdog for the timers of timerService.
+func NewTimerWatchdog(timerService *TimerService, epgService *EPGService) *TimerWatchdog {
+	return &TimerWatchdog{
+		timerService: timerService,
+		epgService:   epgService,
+		vanished:     map[int]TimerWatchChange{},
+		trigger:      make(chan struct{}, 1),
+		reconfig:     make(chan struct{}, 1),
+		now:          time.Now,
+	}
+}
+
+// SetDefaultMargins sets the margins (minutes) of timers that do not tell
+// the margins they were created with.
+func (w *TimerWatchdog) SetDefaultMargins(marginStart, marginEnd int) {
+	w.mu.Lock()
+	w.marginStart = marginStart
+	w.marginEnd = marginEnd
+	w.mu.Unlock()
+}
+
+// OnChange registers a callback invoked for every change, after the check.
+func (w *TimerWatchdog) OnChange(fn func(TimerWatchChange)) {
+	w.mu.Lock()
+	w.onChange = append(w.onChange, fn)
+	w.mu.Unlock()
+}
+
+// Changes returns the most recent changes, newest first.
+func (w *TimerWatchdog) Changes() []TimerWatchChange {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	out := make([]TimerWatchChange, len(w.changes))
+	for i, c := range w.changes {
+		out[len(w.changes)-1-i] = c
+	}
+	return out
+}
+
+// Vanished returns the flagged timers whose event is no longer in the EPG, by timer ID.
+func (w *TimerWatchdog) Vanished() map[int]TimerWatchChange {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	out := make(map[int]TimerWatchChange, len(w.vanished))
+	for id, c := range w.vanished {
+		out[id] = c
+	}
+	return out
+}
+
+// Check compares all watched timers with the EPG once and returns the changes.
+func (w *TimerWatchdog) Check(ctx context.Context) ([]TimerWatchChange, error) {
+	w.processMu.Lock()
+	defer w.processMu.Unlock()
+
+	changes, err := w.check(ctx)
+
+	w.mu.Lock()
+	w.changes = append(w.changes, changes...)
+	if len(w.changes) > timerWatchChangesKept {
+		w.changes = append([]TimerWatchChange(nil), w.changes[len(w.changes)-timerWatchChangesKept:]...)
+	}
+	callbacks := append([]func(TimerWatchChange){}, w.onChange...)
+	w.mu.Unlock()
+
+	for _, c := range changes {
+		for _, fn := range callbacks {
+			fn(c)
+		}
+	}
+	return changes, err
+}
+
+func (w *TimerWatchdog) check(ctx context.Context) ([]TimerWatchChange, error) {
+	timers, err := w.timerService.GetAllTimers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events, err := w.epgService.GetEPG(ctx, "", time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	byChannel := make(map[string][]domain.EPGEvent)
+	for _, ev := range events {
+		byChannel[ev.ChannelID] = append(byChannel[ev.ChannelID], ev)
+		if ev.ChannelNumber > 0 {
+			n := strconv.Itoa(ev.ChannelNumber)
+			byChannel[n] = append(byChannel[n], ev)
+		}
+	}
+
+	w.mu.RLock()
+	defStart, defEnd := w.marginStart, w.marginEnd
+	flagged := w.vanished
+	w.mu.RUnlock()
+
+	now := w.now()
+	vanished := map[int]TimerWatchChange{}
+	var changes []TimerWatchChange
+	var firstErr error
+	for _, t := range timers {
+		if !timerWatched(t, now) {
+			continue
+		}
+		change := TimerWatchChange{
+			At:        now,
+			TimerID:   t.ID,
+			EventID:   t.EventID,
+			Title:     t.Title,
+			ChannelID: t.ChannelID,
+			OldStart:  t.Start,
+			OldStop:   t.Stop,
+		}
+
+		channelEvents := byChannel[strings.TrimSpace(t.ChannelID)]
+		ev, ok := findEvent(channelEvents, t.EventID)
+		if !ok {
+			// Without EPG data for the timer's time, the event may just not be loaded.
+			if !epgCovers(channelEvents, t.Start, t.Stop) {
+				continue
+			}
+			if prev, ok := flagged[t.ID]; ok && prev.EventID == t.EventID {
+				vanished[t.ID] = prev
+				continue
+			}
+			change.Action = TimerWatchVanished
+			vanished[t.ID] = change
+			changes = append(changes, change)
+			continue
+		}
+
+		marginStart, marginEnd := defStart, defEnd
+		if _, ms, me, ok := TimerEventFromAux(t.Aux); ok {
+			marginStart, marginEnd = ms, me
+		}
+		moved := movedTimer(t, ev, marginStart, marginEnd)
+		// VDR keeps timer times in whole minutes.
+		if sameMinute(moved.Start, t.Start) && sameMinute(moved.Stop, t.Stop) {
+			continue
+		}
+		moved.Active = t.Active
+		change.NewStart, change.NewStop = moved.Start, moved.Stop
+		change.Action = TimerWatchMoved
+		if err := w.timerService.UpdateTimer(ctx, &moved); err != nil {
+			change.Action, change.Err = TimerWatchFailed, err.Error()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	w.mu.Lock()
+	w.vanished = vanished
+	w.mu.Unlock()
+	return changes, firstErr
+}
+
+// timerWatched reports whether the watchdog follows the event of a timer:
+// one-time timers created from an event that have not started yet.
+func timerWatched(t domain.Timer, now time.Time) bool {
+	return t.EventID > 0 && !IsWeekdayMask(t.DaySpec) && !t.Start.IsZero() && t.Start.After(now)
+}
+
+func sameMinute(a, b time.Time) bool {
+	return a.Truncate(time.Minute).Equal(b.Truncate(time.Minute))
+}
+
+func findEvent(events []domain.EPGEvent, eventID int) (domain.EPGEvent, bool) {
+	for _, ev := range events {
+		if ev.EventID == eventID {
+			return ev, true
+		}
+	}
+	return domain.EPGEvent{}, false
+}
+
+// epgCovers reports whether events overlap the time from start to stop.
+func epgCovers(events []domain.EPGEvent, start, stop time.Time) bool {
+	for _, ev := range events {
+		if ev.Start.Before(stop) && ev.Stop.After(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetInterval updates the interval used by Run.
+// An interval <= 0 disables interval-based checks; EPG refreshes still trigger them.
+func (w *TimerWatchdog) SetInterval(interval time.Duration) {
+	w.mu.Lock()
+	changed := w.interval != interval
+	w.interval = interval
+	w.mu.Unlock()
+
+	if changed {
+		select {
+		case w.reconfig <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Trigger requests a background check.
+// Multiple triggers while a check is pending are coalesced.
+func (w *TimerWatchdog) Trigger() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run checks the timers in the background until ctx is canceled.
+// A check happens every configured interval and after every EPG refresh.
+func (w *TimerWatchdog) Run(ctx context.Context) {
+	w.listenMu.Lock()
+	if !w.listening && w.epgService != nil {
+		w.epgService.OnRefresh(w.Trigger)
+		w.listening = true
+	}
+	w.listenMu.Unlock()
+
+	for {
+		w.mu.RLock()
+		interval := w.interval
+		w.mu.RUnlock()
+
+		var tick <-chan time.Time
+		var timer *time.Timer
+		if interval > 0 {
+			timer = time.NewTimer(interval)
+			tick = timer.C
+		}
+
+		run := false
+		select {
+		case <-ctx.Done():
+		case <-w.reconfig:
+		case <-w.trigger:
+			run = true
+		case <-tick:
+			run = true
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if !run {
+			continue
+		}
+
+		_, _ = w.Check(ctx)
+
+		// The check itself may have refreshed the EPG cache. Drop that notification,
+		// otherwise every check would immediately schedule another one.
+		select {
+		case <-w.trigger:
+		default:
+		}
+	}
+}
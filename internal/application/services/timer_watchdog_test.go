@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/githubixx/vdradmin-go/internal/domain"
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+	"github.com/githubixx/vdradmin-go/internal/ports"
+)
+
+func TestTimerWatchdog_FollowsEPG(t *testing.T) {
+	base := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	planned := []domain.EPGEvent{
+		{EventID: 1, ChannelID: "C-1", ChannelNumber: 1, Title: "Tatort", Start: base, Stop: base.Add(90 * time.Minute)},
+		{EventID: 2, ChannelID: "C-1", ChannelNumber: 1, Title: "Tagesthemen", Start: base.Add(2 * time.Hour), Stop: base.Add(150 * time.Minute)},
+		{EventID: 3, ChannelID: "C-2", ChannelNumber: 2, Title: "Sportschau", Start: base, Stop: base.Add(time.Hour)},
+		{EventID: 4, ChannelID: "C-3", ChannelNumber: 3, Title: "Nachtcafé", Start: base, Stop: base.Add(time.Hour)},
+	}
+	moved := NewTimerFromEPG(planned[0], 50, 99, 5, 10)
+	moved.ID, moved.Active = 1, false
+	unchanged := NewTimerFromEPG(planned[1], 50, 99, 2, 10)
+	unchanged.ID = 2
+	vanished := NewTimerFromEPG(planned[2], 50, 99, 2, 10)
+	vanished.ID = 3
+	recurring := NewTimerFromEPG(planned[0], 50, 99, 2, 10)
+	recurring.ID, recurring.DaySpec = 4, "MTWTFSS"
+	manual := domain.Timer{ID: 5, Active: true, ChannelID: "C-1", Start: base, Stop: base.Add(time.Hour), Title: "Manuell"}
+	noEPG := NewTimerFromEPG(planned[3], 50, 99, 2, 10)
+	noEPG.ID = 6
+
+	// The broadcaster moves the Tatort by 30 minutes, cancels the Sportschau and
+	// the EPG of channel 3 is not loaded.
+	current := []domain.EPGEvent{
+		{EventID: 1, ChannelID: "C-1", ChannelNumber: 1, Title: "Tatort", Start: base.Add(30 * time.Minute), Stop: base.Add(2 * time.Hour)},
+		planned[1],
+		{EventID: 30, ChannelID: "C-2", ChannelNumber: 2, Title: "Brennpunkt", Start: base, Stop: base.Add(15 * time.Minute)},
+	}
+	mock := ports.NewMockVDRClient().WithEPGEvents(current).
+		WithTimers([]domain.Timer{moved, unchanged, vanished, recurring, manual, noEPG})
+
+	w := NewTimerWatchdog(NewTimerService(mock), NewEPGService(mock, time.Minute))
+	var logged []TimerWatchChange
+	w.OnChange(func(c TimerWatchChange) { logged = append(logged, c) })
+
+	changes, err := w.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(changes) != 2 || len(logged) != 2 {
+		t.Fatalf("expected a move and a vanished event, got %+v", changes)
+	}
+	if c := changes[0]; c.TimerID != 1 || c.Action != TimerWatchMoved || !c.OldStart.Equal(moved.Start) ||
+		!c.NewStart.Equal(base.Add(25*time.Minute)) || !c.NewStop.Equal(base.Add(130*time.Minute)) {
+		t.Fatalf("move = %+v", c)
+	}
+	if c := changes[1]; c.TimerID != 3 || c.Action != TimerWatchVanished || c.Title != "Sportschau" {
+		t.Fatalf("vanished = %+v", c)
+	}
+
+	timers, _ := mock.GetTimers(context.Background())
+	got := timers[0]
+	if !got.Start.Equal(base.Add(25*time.Minute)) || !got.Stop.Equal(base.Add(130*time.Minute)) || got.Active || got.Title != "Tatort" {
+		t.Fatalf("moved timer = %+v", got)
+	}
+	if id, ms, me, ok := TimerEventFromAux(got.Aux); !ok || id != 1 || ms != 5 || me != 10 {
+		t.Fatalf("moved timer lost its event: %q", got.Aux)
+	}
+	if flags := w.Vanished(); len(flags) != 1 || flags[3].EventID != 3 {
+		t.Fatalf("flags = %+v", flags)
+	}
+
+	// The next check has nothing new to report, the timer stays flagged.
+	changes, err = w.Check(context.Background())
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("second check = %+v, %v", changes, err)
+	}
+	if _, ok := w.Vanished()[3]; !ok {
+		t.Fatalf("expected timer 3 to stay flagged")
+	}
+	if log := w.Changes(); len(log) != 2 || log[0].TimerID != 3 {
+		t.Fatalf("expected the newest change first, got %+v", log)
+	}
+}
+
+func TestTimerEventAux(t *testing.T) {
+	ev := domain.EPGEvent{EventID: 42, ChannelID: "C-1", Title: "Tatort", Start: time.Now(), Stop: time.Now().Add(time.Hour)}
+	timer := NewTimerFromEPG(ev, 50, 99, 3, 12, WithTimerAux(SearchTimerAux(config.EPGSearch{ID: 7, Pattern: "Tatort"})))
+	if id, ms, me, ok := TimerEventFromAux(timer.Aux); !ok || id != 42 || ms != 3 || me != 12 {
+		t.Fatalf("TimerEventFromAux(%q) = %d, %d, %d, %v", timer.Aux, id, ms, me, ok)
+	}
+	if id, ok := SearchIDFromAux(timer.Aux); !ok || id != 7 {
+		t.Fatalf("aux %q lost the search", timer.Aux)
+	}
+
+	aux := withTimerEventAux(timer.Aux, 43, 0, 5)
+	if id, ms, me, _ := TimerEventFromAux(aux); id != 43 || ms != 0 || me != 5 {
+		t.Fatalf("expected the event to be replaced, got %q", aux)
+	}
+	if _, _, _, ok := TimerEventFromAux("<epgsearch><eventid>42</eventid></epgsearch>"); ok {
+		t.Fatalf("foreign aux data must not name an event")
+	}
+
+	// VDR does not report event IDs; the timer service takes them from the aux data.
+	mock := ports.NewMockVDRClient().WithTimers([]domain.Timer{{ID: 1, Aux: timer.Aux}})
+	timers, err := NewTimerService(mock).GetAllTimers(context.Background())
+	if err != nil || len(timers) != 1 || timers[0].EventID != 42 {
+		t.Fatalf("GetAllTimers = %+v, %v", timers, err)
+	}
+}
@@ -21,6 +21,18 @@ var (
 	// ErrConnection indicates a connection failure
 	ErrConnection = errors.New("connection failed")
 
+	// ErrBusy indicates VDR refused the connection because it is serving another client
+	ErrBusy = errors.New("vdr busy")
+
+	// ErrChannelInUse indicates VDR refused to delete a channel a timer records from
+	ErrChannelInUse = errors.New("channel in use by a timer")
+
+	// ErrChannelExists indicates VDR refused channel settings that duplicate another channel
+	ErrChannelExists = errors.New("channel settings not unique")
+
+	// ErrChannelsLocked indicates VDR refused to change channels while they are edited elsewhere
+	ErrChannelsLocked = errors.New("channels are being edited")
+
 	// ErrTimeout indicates an operation timeout
 	ErrTimeout = errors.New("timeout")
 
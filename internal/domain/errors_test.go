@@ -18,6 +18,10 @@ func TestErrorConstants(t *testing.T) {
 		{"ErrForbidden", ErrForbidden, "forbidden"},
 		{"ErrConflict", ErrConflict, "conflict"},
 		{"ErrConnection", ErrConnection, "connection failed"},
+		{"ErrBusy", ErrBusy, "vdr busy"},
+		{"ErrChannelInUse", ErrChannelInUse, "channel in use by a timer"},
+		{"ErrChannelExists", ErrChannelExists, "channel settings not unique"},
+		{"ErrChannelsLocked", ErrChannelsLocked, "channels are being edited"},
 		{"ErrTimeout", ErrTimeout, "timeout"},
 		{"ErrInternal", ErrInternal, "internal error"},
 	}
@@ -67,6 +71,10 @@ func TestErrorUniqueness(t *testing.T) {
 		ErrForbidden,
 		ErrConflict,
 		ErrConnection,
+		ErrBusy,
+		ErrChannelInUse,
+		ErrChannelExists,
+		ErrChannelsLocked,
 		ErrTimeout,
 		ErrInternal,
 	}
@@ -90,6 +98,10 @@ func TestErrorMessages(t *testing.T) {
 		ErrForbidden,
 		ErrConflict,
 		ErrConnection,
+		ErrBusy,
+		ErrChannelInUse,
+		ErrChannelExists,
+		ErrChannelsLocked,
 		ErrTimeout,
 		ErrInternal,
 	}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Channel represents a VDR channel
 type Channel struct {
@@ -11,6 +14,14 @@ type Channel struct {
 	Freq     string
 	Source   string
 	Group    string
+	// Settings is the channel's channels.conf line, as listed by LSTC.
+	Settings string
+}
+
+// Obsolete reports whether VDR marked the channel OBSOLETE because a channel
+// scan no longer found it on its transponder.
+func (c Channel) Obsolete() bool {
+	return strings.HasSuffix(c.Name, "OBSOLETE") || strings.HasSuffix(c.Provider, "OBSOLETE")
 }
 
 // EPGEvent represents an electronic program guide entry
@@ -28,6 +39,37 @@ type EPGEvent struct {
 	VPS           *time.Time
 	Video         VideoInfo
 	Audio         []AudioInfo
+	// Genres holds the content descriptions of the event (e.g. "Movie/Drama").
+	Genres []string
+	// ParentalRating is the minimum recommended age; 0 means unrated.
+	ParentalRating int
+}
+
+// HasSurround reports whether any audio stream carries more than two channels.
+func (e EPGEvent) HasSurround() bool {
+	for _, a := range e.Audio {
+		if a.Channels > 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// AudioLanguages returns the distinct audio languages in stream order.
+func (e EPGEvent) AudioLanguages() []string {
+	out := make([]string, 0, len(e.Audio))
+	seen := make(map[string]struct{}, len(e.Audio))
+	for _, a := range e.Audio {
+		if a.Language == "" {
+			continue
+		}
+		if _, ok := seen[a.Language]; ok {
+			continue
+		}
+		seen[a.Language] = struct{}{}
+		out = append(out, a.Language)
+	}
+	return out
 }
 
 // VideoInfo contains video stream information
@@ -62,6 +104,8 @@ type Timer struct {
 	Title        string
 	Aux          string
 	EventID      int
+	// Backend names the VDR holding the timer when several are configured.
+	Backend string
 }
 
 // Recording represents a completed recording
@@ -79,6 +123,43 @@ type Recording struct {
 	Size        int64
 	IsFolder    bool
 	Children    []*Recording
+	// Backend names the VDR holding the recording when several are configured.
+	Backend string
+	// Name is VDR's full recording name; folders are separated by '~'.
+	Name string
+	// Folder is the '~'-separated path of the folder holding the recording
+	// (or, for folders, of the parent folder). It is empty at the top level.
+	Folder string
+	// New is set for recordings that have not been watched yet.
+	New bool
+	// Count and NewCount are the number of recordings and unwatched recordings
+	// in a folder, including its subfolders. Size and Length are totals for folders.
+	Count    int
+	NewCount int
+	// ChannelID is the channel the recording was made from, as told by its info file.
+	ChannelID string
+	// Errors is the number of errors VDR 2.6 and later counted while recording.
+	Errors int
+}
+
+// ReplayStart tells where the replay of a recording starts. The zero value
+// resumes where the last replay stopped.
+type ReplayStart struct {
+	// FromBeginning ignores the resume point.
+	FromBeginning bool
+	// Offset starts the replay at the given position when positive.
+	Offset time.Duration
+}
+
+// ReplayStatus describes the recording last started for replay on the VDR output.
+type ReplayStatus struct {
+	Recording Recording
+	Started   time.Time
+	// ResumePoint is where VDR last saved the replay position of the recording.
+	// VDR writes it when the replay stops or pauses, so while the replay runs it
+	// lags behind the current position. HasResumePoint is false if there is none.
+	ResumePoint    time.Duration
+	HasResumePoint bool
 }
 
 // AutoTimer represents an automatic timer based on search patterns
@@ -96,7 +177,8 @@ type AutoTimer struct {
 	MarginStart   int // minutes
 	MarginEnd     int // minutes
 	Active        bool
-	Done          []int // event IDs already recorded
+	// Dedup selects what matches are compared with to skip episodes recorded before.
+	Dedup DedupScope
 }
 
 // SearchScope defines where to search for AutoTimer patterns
@@ -107,3 +189,73 @@ const (
 	SearchTitleSubtitle
 	SearchAll
 )
+
+// DedupScope selects what automatic searches compare their matches with to
+// skip episodes that were recorded or scheduled before.
+type DedupScope string
+
+const (
+	// DedupAll compares with timers, recordings and the done list.
+	DedupAll DedupScope = "all"
+	// DedupRecordings compares with timers and recordings.
+	DedupRecordings DedupScope = "recordings"
+	// DedupTimers compares with timers only. It is the default.
+	DedupTimers DedupScope = "timers"
+	// DedupNone only skips events that already have a timer.
+	DedupNone DedupScope = "none"
+)
+
+// DoneEntry is an episode an automatic search scheduled. The done list keeps
+// them, so later airings are not recorded again, even after the timer and
+// the recording are gone.
+type DoneEntry struct {
+	Title    string
+	Subtitle string
+	// Fingerprint identifies the description; empty without one.
+	Fingerprint string
+	ChannelID   string
+	Start       time.Time
+	EventID     int
+	// Source names what scheduled the episode, e.g. "search 3" or "autotimer 5".
+	Source string
+	Added  time.Time
+}
+
+// RecordingResult classifies what a timer recorded.
+type RecordingResult string
+
+const (
+	// RecordingPending is a timer that has not finished yet.
+	RecordingPending RecordingResult = "pending"
+	// RecordingComplete is a recording as long as its timer.
+	RecordingComplete RecordingResult = "complete"
+	// RecordingShort is a recording considerably shorter than its timer.
+	RecordingShort RecordingResult = "short"
+	// RecordingMissing is a timer that left no recording.
+	RecordingMissing RecordingResult = "missing"
+	// RecordingErrored is a recording VDR counted errors in.
+	RecordingErrored RecordingResult = "errored"
+)
+
+// Failed reports whether the result is a recording that went wrong.
+func (r RecordingResult) Failed() bool {
+	return r == RecordingShort || r == RecordingMissing || r == RecordingErrored
+}
+
+// RecordingHistoryEntry is a timer that fired and the recording it left.
+type RecordingHistoryEntry struct {
+	TimerID int
+	Title   string
+	// ChannelID is the channel the timer recorded from; ChannelName its name, if known.
+	ChannelID   string
+	ChannelName string
+	Start       time.Time
+	Stop        time.Time
+	Result      RecordingResult
+	// Recording is the name of the recording matched to the timer, empty when there is none.
+	Recording string
+	Length    time.Duration
+	Errors    int
+	// Checked is when the result was determined.
+	Checked time.Time
+}
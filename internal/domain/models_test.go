@@ -53,6 +53,23 @@ func TestChannel(t *testing.T) {
 	})
 }
 
+func TestChannel_Obsolete(t *testing.T) {
+	tests := []struct {
+		ch   Channel
+		want bool
+	}{
+		{Channel{Name: "Das Erste HD", Provider: "ARD"}, false},
+		{Channel{Name: "Sky Cinema OBSOLETE", Provider: "Sky"}, true},
+		{Channel{Name: "Sky Cinema", Provider: "Sky OBSOLETE"}, true},
+		{Channel{Name: "OBSOLETE News", Provider: "x"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.ch.Obsolete(); got != tt.want {
+			t.Errorf("Obsolete(%q;%q) = %v, want %v", tt.ch.Name, tt.ch.Provider, got, tt.want)
+		}
+	}
+}
+
 // TestEPGEvent tests the EPGEvent struct
 func TestEPGEvent(t *testing.T) {
 	t.Run("ValidEvent", func(t *testing.T) {
@@ -155,6 +172,39 @@ func TestVideoInfo(t *testing.T) {
 	}
 }
 
+// TestEPGEventAudioHelpers tests the audio helpers used for UI badges
+func TestEPGEventAudioHelpers(t *testing.T) {
+	tests := []struct {
+		name         string
+		audio        []AudioInfo
+		wantSurround bool
+		wantLangs    []string
+	}{
+		{"None", nil, false, []string{}},
+		{"Stereo", []AudioInfo{{Language: "deu", Channels: 2}}, false, []string{"deu"}},
+		{"Surround", []AudioInfo{{Language: "deu", Channels: 2}, {Language: "deu", Channels: 6}, {Language: "eng", Channels: 2}}, true, []string{"deu", "eng"}},
+		{"NoLanguage", []AudioInfo{{Channels: 1}}, false, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := EPGEvent{Audio: tt.audio}
+			if got := ev.HasSurround(); got != tt.wantSurround {
+				t.Errorf("HasSurround: got %v, want %v", got, tt.wantSurround)
+			}
+			got := ev.AudioLanguages()
+			if len(got) != len(tt.wantLangs) {
+				t.Fatalf("AudioLanguages: got %v, want %v", got, tt.wantLangs)
+			}
+			for i := range got {
+				if got[i] != tt.wantLangs[i] {
+					t.Errorf("AudioLanguages: got %v, want %v", got, tt.wantLangs)
+				}
+			}
+		})
+	}
+}
+
 // TestAudioInfo tests audio stream information
 func TestAudioInfo(t *testing.T) {
 	tests := []struct {
@@ -317,7 +367,7 @@ func TestAutoTimer(t *testing.T) {
 			SearchIn:      SearchTitle,
 			ChannelFilter: []string{"Das Erste HD", "ZDF HD"},
 			DayOfWeek:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
-			Done:          []int{1001, 1002},
+			Dedup:         DedupRecordings,
 		}
 
 		if at.Pattern != "Tagesschau" {
@@ -335,8 +385,8 @@ func TestAutoTimer(t *testing.T) {
 		if len(at.DayOfWeek) != 5 {
 			t.Errorf("Expected 5 days, got %d", len(at.DayOfWeek))
 		}
-		if len(at.Done) != 2 {
-			t.Errorf("Expected 2 done events, got %d", len(at.Done))
+		if at.Dedup != DedupRecordings {
+			t.Errorf("Dedup: got %v, want %v", at.Dedup, DedupRecordings)
 		}
 	})
 
@@ -2,8 +2,11 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 
@@ -12,14 +15,130 @@ import (
 
 // Config represents the application configuration
 type Config struct {
-	Server  ServerConfig  `yaml:"server"`
-	VDR     VDRConfig     `yaml:"vdr"`
-	Auth    AuthConfig    `yaml:"auth"`
-	Cache   CacheConfig   `yaml:"cache"`
-	Timer   TimerConfig   `yaml:"timer"`
-	EPG     EPGConfig     `yaml:"epg"`
-	Archive ArchiveConfig `yaml:"archive"`
-	UI      UIConfig      `yaml:"ui"`
+	Server     ServerConfig     `yaml:"server"`
+	VDR        VDRConfig        `yaml:"vdr"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Cache      CacheConfig      `yaml:"cache"`
+	Timer      TimerConfig      `yaml:"timer"`
+	EPG        EPGConfig        `yaml:"epg"`
+	Archive    ArchiveConfig    `yaml:"archive"`
+	AutoTimer  AutoTimerConfig  `yaml:"autotimer"`
+	Dedup      DedupConfig      `yaml:"dedup"`
+	History    HistoryConfig    `yaml:"history"`
+	Events     EventsConfig     `yaml:"events"`
+	Metrics    MetricsConfig    `yaml:"metrics"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+	SVDRPProxy SVDRPProxyConfig `yaml:"svdrp_proxy"`
+	UI         UIConfig         `yaml:"ui"`
+}
+
+// EventsConfig contains settings for the live event stream (/events).
+type EventsConfig struct {
+	// PollInterval controls how often VDR is polled for state SVDRP does not report
+	// (connectivity, current channel, recordings in progress) while clients are subscribed.
+	// Set to 0 to disable polling; timer and archive events are still published.
+	PollInterval time.Duration `yaml:"poll_interval"`
+}
+
+// MetricsConfig contains settings for the Prometheus endpoint (/metrics).
+type MetricsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Token protects /metrics with its own bearer token instead of the regular
+	// authentication. Leave empty to use the same authentication as the web UI.
+	Token string `yaml:"token"`
+}
+
+// TracingConfig contains settings for OpenTelemetry tracing.
+// Changes take effect after a restart.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Exporter is "otlp" (OTLP over HTTP to Endpoint) or "stdout" (pretty-printed spans, for debugging).
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the host:port of the OTLP/HTTP collector.
+	Endpoint string `yaml:"endpoint"`
+	// Insecure sends OTLP over plain HTTP instead of HTTPS.
+	Insecure bool `yaml:"insecure"`
+	// SampleRatio is the share of new traces that are recorded (0..1).
+	SampleRatio float64 `yaml:"sample_ratio"`
+	ServiceName string  `yaml:"service_name"`
+}
+
+// SVDRPProxyConfig contains settings for the SVDRP-compatible port that lets other
+// tools (svdrpsend, scripts) share the VDR connection of vdradmin-go.
+// Changes take effect after a restart.
+type SVDRPProxyConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Listen is the host:port the proxy accepts SVDRP clients on.
+	Listen string `yaml:"listen"`
+	// Timeout closes client connections that sent no command for this long. 0 disables it.
+	Timeout time.Duration `yaml:"timeout"`
+	// Allow lists the client addresses that may connect. Clients matching no entry are refused.
+	Allow []SVDRPProxyClient `yaml:"allow"`
+}
+
+// SVDRPProxyClient allows an address or network to use the SVDRP proxy.
+type SVDRPProxyClient struct {
+	// Address is an IP address or a network in CIDR notation.
+	Address string `yaml:"address"`
+	// Commands optionally restricts the client to these SVDRP commands (e.g. LSTT, LSTE).
+	// Empty allows all commands.
+	Commands []string `yaml:"commands"`
+}
+
+// svdrpVerbRe matches an SVDRP command verb.
+var svdrpVerbRe = regexp.MustCompile(`^[A-Z]{4}$`)
+
+// ParseAddressOrPrefix parses an IP address or a CIDR network as a prefix.
+// A single address yields a prefix matching only that address.
+func ParseAddressOrPrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return p.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// AutoTimerConfig contains settings for the AutoTimer background processing.
+type AutoTimerConfig struct {
+	// File is the path of the file the AutoTimer definitions are stored in.
+	// Relative paths are resolved against the directory of the config file.
+	File string `yaml:"file"`
+	// Interval controls how often AutoTimers are processed in the background.
+	// AutoTimers are additionally processed after every EPG refresh.
+	// Set to 0 to only process them after EPG refreshes.
+	Interval time.Duration `yaml:"interval"`
+}
+
+// DedupConfig controls how search timers and AutoTimers recognise episodes
+// that were recorded or scheduled before.
+type DedupConfig struct {
+	// File is the path of the done list, the episodes search timers and
+	// AutoTimers scheduled. Relative paths are resolved against the directory of the config file.
+	File string `yaml:"file"`
+	// Match compares titles and subtitles "exact", "normalized" (ignoring case,
+	// accents and punctuation; the default) or "fuzzy" (normalized, tolerating a few typos).
+	Match string `yaml:"match"`
+	// Description also compares a fingerprint of the descriptions when both episodes have one.
+	Description bool `yaml:"description"`
+}
+
+// HistoryConfig contains settings for the recording history, which tells
+// whether finished timers left a complete recording.
+type HistoryConfig struct {
+	// File is the path of the recording history.
+	// Relative paths are resolved against the directory of the config file.
+	File string `yaml:"file"`
+	// KeepDays is how many days finished timers stay in the history. 0 keeps them forever.
+	KeepDays int `yaml:"keep_days"`
 }
 
 // ArchiveProfileConfig defines a destination profile for archiving recordings.
@@ -67,7 +186,56 @@ type EPGSearch struct {
 	ChannelID   string `yaml:"channel_id"`   // when UseChannel=="single"
 	ChannelFrom string `yaml:"channel_from"` // when UseChannel=="range"
 	ChannelTo   string `yaml:"channel_to"`   // when UseChannel=="range"
-	// Reserved for future fields (time range, duration, day-of-week).
+	// StartFrom and StartTo ("HH:MM", inclusive) limit the start time of events.
+	// A window whose StartFrom is after StartTo spans midnight. Empty means any time.
+	StartFrom string `yaml:"start_from"`
+	StartTo   string `yaml:"start_to"`
+	// MinDuration and MaxDuration limit the event length in minutes; 0 means no limit.
+	MinDuration int `yaml:"min_duration"`
+	MaxDuration int `yaml:"max_duration"`
+	// Weekdays limits the day events start on ("mon" ... "sun"); empty means every day.
+	Weekdays []string `yaml:"weekdays"`
+	// Exclude skips events whose searched texts contain it ("but not containing").
+	// It is a regular expression in regex mode.
+	Exclude string `yaml:"exclude"`
+	// HDOnly keeps only events with an HD video component.
+	HDOnly bool `yaml:"hd_only"`
+	// HorizonDays keeps only events starting within that many days; 0 means no limit.
+	HorizonDays int `yaml:"horizon_days"`
+	// NotRecorded skips events that were recorded already.
+	NotRecorded bool `yaml:"not_recorded"`
+	// AutoRecord creates timers for new matches after every EPG refresh
+	// (a search timer), using the timer settings below.
+	AutoRecord  bool `yaml:"auto_record"`
+	Priority    int  `yaml:"priority"`
+	Lifetime    int  `yaml:"lifetime"`
+	MarginStart int  `yaml:"margin_start"` // minutes
+	MarginEnd   int  `yaml:"margin_end"`   // minutes
+	// Folder is the recording folder of created timers ("Krimi~Tatort"); empty means none.
+	Folder string `yaml:"folder"`
+	// Dedup selects what auto-record compares matches with to skip episodes
+	// recorded before: "all" (timers, recordings and the done list),
+	// "recordings" (timers and recordings), "timers" (the default) or "none".
+	Dedup string `yaml:"dedup"`
+}
+
+// weekdayNames are the short weekday names of EPGSearch.Weekdays, indexed by time.Weekday.
+var weekdayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseWeekday returns the weekday of a short name like "mon".
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for i, n := range weekdayNames {
+		if n == name {
+			return time.Weekday(i), true
+		}
+	}
+	return 0, false
+}
+
+// WeekdayName returns the short name of a weekday, as used in EPGSearch.Weekdays.
+func WeekdayName(d time.Weekday) string {
+	return weekdayNames[d%7]
 }
 
 // NormalizeEPGSearch normalizes a single EPG search definition in-place.
@@ -88,6 +256,33 @@ func NormalizeEPGSearch(s *EPGSearch) {
 	if !s.InTitle && !s.InSubtitle && !s.InDesc {
 		s.InTitle, s.InSubtitle, s.InDesc = true, true, true
 	}
+	s.StartFrom = strings.TrimSpace(s.StartFrom)
+	s.StartTo = strings.TrimSpace(s.StartTo)
+	s.Exclude = strings.TrimSpace(s.Exclude)
+	s.Folder = strings.Trim(strings.ReplaceAll(strings.TrimSpace(s.Folder), "/", "~"), "~")
+	s.Dedup = strings.ToLower(strings.TrimSpace(s.Dedup))
+	if s.Dedup == "" {
+		s.Dedup = "timers"
+	}
+	if len(s.Weekdays) > 0 {
+		// Keep week order (Monday first) and drop duplicates; unknown names are left for validation.
+		seen := map[time.Weekday]bool{}
+		var unknown []string
+		for _, d := range s.Weekdays {
+			if wd, ok := ParseWeekday(d); ok {
+				seen[wd] = true
+			} else {
+				unknown = append(unknown, strings.TrimSpace(d))
+			}
+		}
+		days := make([]string, 0, len(seen)+len(unknown))
+		for i := 1; i <= 7; i++ {
+			if wd := time.Weekday(i % 7); seen[wd] {
+				days = append(days, weekdayNames[wd])
+			}
+		}
+		s.Weekdays = append(days, unknown...)
+	}
 }
 
 // ValidateEPGSearch validates an EPG search definition.
@@ -103,6 +298,50 @@ func ValidateEPGSearch(s EPGSearch) error {
 	if s.Mode != "phrase" && s.Mode != "regex" {
 		return fmt.Errorf("invalid mode: %q", s.Mode)
 	}
+	for _, v := range []struct{ name, value string }{{"start_from", s.StartFrom}, {"start_to", s.StartTo}} {
+		if v.value == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", v.value); err != nil {
+			return fmt.Errorf("invalid %s: %q (expected HH:MM)", v.name, v.value)
+		}
+	}
+	if s.MinDuration < 0 || s.MaxDuration < 0 {
+		return fmt.Errorf("durations must not be negative")
+	}
+	if s.MaxDuration > 0 && s.MinDuration > s.MaxDuration {
+		return fmt.Errorf("min_duration %d exceeds max_duration %d", s.MinDuration, s.MaxDuration)
+	}
+	for _, d := range s.Weekdays {
+		if _, ok := ParseWeekday(d); !ok {
+			return fmt.Errorf("invalid weekday: %q", d)
+		}
+	}
+	if s.HorizonDays < 0 {
+		return fmt.Errorf("invalid horizon_days: %d", s.HorizonDays)
+	}
+	if s.Mode == "regex" && s.Exclude != "" {
+		if _, err := regexp.Compile(s.Exclude); err != nil {
+			return fmt.Errorf("invalid exclude: %w", err)
+		}
+	}
+	if s.Priority < 0 || s.Priority > 99 {
+		return fmt.Errorf("priority must be between 0 and 99")
+	}
+	if s.Lifetime < 0 || s.Lifetime > 99 {
+		return fmt.Errorf("lifetime must be between 0 and 99")
+	}
+	if s.MarginStart < 0 || s.MarginEnd < 0 {
+		return fmt.Errorf("margins must not be negative")
+	}
+	if strings.ContainsAny(s.Folder, "\r\n") {
+		return fmt.Errorf("invalid folder: %q", s.Folder)
+	}
+	switch s.Dedup {
+	case "all", "recordings", "timers", "none":
+	default:
+		return fmt.Errorf("invalid dedup: %q", s.Dedup)
+	}
 	return nil
 }
 
@@ -113,6 +352,12 @@ type UIConfig struct {
 	// LoginPage controls which page is shown after login / when clicking the top-left brand.
 	// It must be a known path like "/timers".
 	LoginPage string `yaml:"login_page"`
+	// LogoDir is the directory with channel logos, e.g. the logos of a VDR skin.
+	// Leave empty to show monograms instead of logos.
+	LogoDir string `yaml:"logo_dir"`
+	// LogoMap is an optional YAML file mapping channel names or IDs to logo
+	// files in LogoDir, for logos that follow no naming convention.
+	LogoMap string `yaml:"logo_map"`
 }
 
 // ServerConfig contains HTTP server settings
@@ -134,14 +379,25 @@ type TLSConfig struct {
 
 // VDRConfig contains VDR connection settings
 type VDRConfig struct {
+	// Name identifies this VDR in the UI when further backends are configured.
+	Name           string        `yaml:"name"`
 	Host           string        `yaml:"host"`
 	Port           int           `yaml:"port"`
 	Timeout        time.Duration `yaml:"timeout"`
 	VideoDir       string        `yaml:"video_dir"`
 	ConfigDir      string        `yaml:"config_dir"`
 	ReconnectDelay time.Duration `yaml:"reconnect_delay"`
+	// IdleTimeout releases the SVDRP connection after it has been unused this long,
+	// so svdrpsend and other tools can reach VDR in between. 0 keeps it open.
+	// It should stay well below events.poll_interval: otherwise the polls keep
+	// the connection busy and it is never released.
+	IdleTimeout    time.Duration `yaml:"idle_timeout"`
 	DVBCards       int           `yaml:"dvb_cards"`
 	WantedChannels []string      `yaml:"wanted_channels"`
+	// Favourites are named, ordered channel lists (e.g. "Kids" or "Sports")
+	// the channel-based pages can be narrowed to. They apply on top of
+	// WantedChannels.
+	Favourites []FavouritesList `yaml:"favourites"`
 	// StreamURLTemplate optionally enables "stream URL" mode on the Watch TV page.
 	// If set, /watch will embed an <img> that points to this URL and substitutes
 	// occurrences of "{channel}" with the selected VDR channel ID.
@@ -151,6 +407,26 @@ type VDRConfig struct {
 	// If set (e.g. "http://127.0.0.1:3000/{channel}"), /watch/stream/{channel}/index.m3u8
 	// will transcode from this source using ffmpeg.
 	StreamdevBackendURL string `yaml:"streamdev_backend_url"`
+	// Backends lists further VDRs (e.g. a client with its own tuners) managed
+	// next to the one configured above. Their timers, recordings and channels
+	// are shown merged or per backend.
+	Backends []VDRBackendConfig `yaml:"backends"`
+}
+
+// FavouritesList is a named list of channel IDs, in display order.
+type FavouritesList struct {
+	Name     string   `yaml:"name"`
+	Channels []string `yaml:"channels"`
+}
+
+// VDRBackendConfig describes an additional VDR backend.
+type VDRBackendConfig struct {
+	Name     string        `yaml:"name"`
+	Host     string        `yaml:"host"`
+	Port     int           `yaml:"port"`
+	Timeout  time.Duration `yaml:"timeout"`
+	VideoDir string        `yaml:"video_dir"`
+	DVBCards int           `yaml:"dvb_cards"`
 }
 
 // AuthConfig contains authentication settings
@@ -176,6 +452,13 @@ type TimerConfig struct {
 	DefaultLifetime    int `yaml:"default_lifetime"`
 	DefaultMarginStart int `yaml:"default_margin_start"`
 	DefaultMarginEnd   int `yaml:"default_margin_end"`
+	// ConflictCheck controls how timer conflicts are handled when timers are created or changed:
+	// "warn" (default) accepts the timer and shows a warning, "reject" refuses it, "off" disables the check.
+	ConflictCheck string `yaml:"conflict_check"`
+	// EPGWatch is how often timers created from EPG events are checked for
+	// schedule changes of their event. Timers are also checked after every EPG refresh.
+	// Set to 0 to only check them after EPG refreshes.
+	EPGWatch time.Duration `yaml:"epg_watch"`
 }
 
 // Load loads configuration from a YAML file
@@ -190,12 +473,14 @@ func Load(path string) (*Config, error) {
 			MaxHeaderBytes: 1 << 20, // 1 MB
 		},
 		VDR: VDRConfig{
+			Name:                "main",
 			Host:                "localhost",
 			Port:                6419,
 			Timeout:             10 * time.Second,
 			VideoDir:            "/var/lib/video.00",
 			ConfigDir:           "/etc/vdr",
 			ReconnectDelay:      5 * time.Second,
+			IdleTimeout:         3 * time.Second,
 			DVBCards:            1,
 			WantedChannels:      []string{},
 			StreamURLTemplate:   "",
@@ -217,6 +502,8 @@ func Load(path string) (*Config, error) {
 			DefaultLifetime:    99,
 			DefaultMarginStart: 2,
 			DefaultMarginEnd:   10,
+			ConflictCheck:      "warn",
+			EPGWatch:           15 * time.Minute,
 		},
 		EPG: EPGConfig{
 			Searches: []EPGSearch{},
@@ -226,6 +513,36 @@ func Load(path string) (*Config, error) {
 			Profiles:   nil,
 			FFMpegArgs: "-vaapi_device /dev/dri/renderD128 -vf format=nv12,hwupload -map 0:0 -c:v hevc_vaapi -rc_mode CQP -global_quality 23 -profile:v main -map 0:a -c:a copy",
 		},
+		AutoTimer: AutoTimerConfig{
+			File:     "autotimers.yaml",
+			Interval: 30 * time.Minute,
+		},
+		Dedup: DedupConfig{
+			File:  "done.yaml",
+			Match: "normalized",
+		},
+		History: HistoryConfig{
+			File:     "history.yaml",
+			KeepDays: 90,
+		},
+		Events: EventsConfig{
+			PollInterval: 10 * time.Second,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "otlp",
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			SampleRatio: 1,
+			ServiceName: "vdradmin-go",
+		},
+		SVDRPProxy: SVDRPProxyConfig{
+			Listen:  "127.0.0.1:6420",
+			Timeout: 300 * time.Second,
+			Allow: []SVDRPProxyClient{
+				{Address: "127.0.0.1"},
+				{Address: "::1"},
+			},
+		},
 		UI: UIConfig{
 			Theme:     "system",
 			LoginPage: "/timers",
@@ -269,25 +586,69 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("VDR host is required")
 	}
 
+	if c.VDR.IdleTimeout < 0 {
+		return fmt.Errorf("invalid vdr idle_timeout: %s (must not be negative)", c.VDR.IdleTimeout)
+	}
+
 	if c.VDR.DVBCards < 1 || c.VDR.DVBCards > 99 {
 		return fmt.Errorf("invalid vdr dvb_cards: %d (must be 1-99)", c.VDR.DVBCards)
 	}
 
+	c.VDR.Name = strings.TrimSpace(c.VDR.Name)
+	if c.VDR.Name == "" {
+		c.VDR.Name = "main"
+	}
+	names := map[string]bool{c.VDR.Name: true}
+	for i := range c.VDR.Backends {
+		b := &c.VDR.Backends[i]
+		b.Name = strings.TrimSpace(b.Name)
+		b.Host = strings.TrimSpace(b.Host)
+		if b.Name == "" {
+			return fmt.Errorf("vdr backends[%d]: name is required", i)
+		}
+		if names[b.Name] {
+			return fmt.Errorf("vdr backends[%d]: duplicate name %q", i, b.Name)
+		}
+		names[b.Name] = true
+		if b.Host == "" {
+			return fmt.Errorf("vdr backend %q: host is required", b.Name)
+		}
+		if b.Port == 0 {
+			b.Port = 6419
+		}
+		if b.Port < 1 || b.Port > 65535 {
+			return fmt.Errorf("vdr backend %q: invalid port: %d", b.Name, b.Port)
+		}
+		if b.Timeout == 0 {
+			b.Timeout = c.VDR.Timeout
+		}
+		if b.Timeout < 0 {
+			return fmt.Errorf("vdr backend %q: invalid timeout: %s (must not be negative)", b.Name, b.Timeout)
+		}
+		if b.DVBCards == 0 {
+			b.DVBCards = 1
+		}
+		if b.DVBCards < 1 || b.DVBCards > 99 {
+			return fmt.Errorf("vdr backend %q: invalid dvb_cards: %d (must be 1-99)", b.Name, b.DVBCards)
+		}
+	}
+
 	// Normalize wanted channels: empty means "all channels".
-	seen := map[string]struct{}{}
-	clean := make([]string, 0, len(c.VDR.WantedChannels))
-	for _, raw := range c.VDR.WantedChannels {
-		v := strings.TrimSpace(raw)
-		if v == "" {
-			continue
+	c.VDR.WantedChannels = cleanChannelIDs(c.VDR.WantedChannels)
+
+	favourites := map[string]bool{}
+	for i := range c.VDR.Favourites {
+		f := &c.VDR.Favourites[i]
+		f.Name = strings.TrimSpace(f.Name)
+		if f.Name == "" {
+			return fmt.Errorf("vdr favourites: list %d has no name", i+1)
 		}
-		if _, ok := seen[v]; ok {
-			continue
+		if favourites[strings.ToLower(f.Name)] {
+			return fmt.Errorf("vdr favourites: duplicate list name %q", f.Name)
 		}
-		seen[v] = struct{}{}
-		clean = append(clean, v)
+		favourites[strings.ToLower(f.Name)] = true
+		f.Channels = cleanChannelIDs(f.Channels)
 	}
-	c.VDR.WantedChannels = clean
 
 	if c.Server.TLS.Enabled {
 		if c.Server.TLS.CertFile == "" {
@@ -315,6 +676,22 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid default lifetime: %d (must be 0-99)", c.Timer.DefaultLifetime)
 	}
 
+	c.Timer.ConflictCheck = strings.ToLower(strings.TrimSpace(c.Timer.ConflictCheck))
+	switch c.Timer.ConflictCheck {
+	case "":
+		c.Timer.ConflictCheck = "warn"
+	case "warn", "reject", "off":
+		// ok
+	default:
+		return fmt.Errorf("invalid timer.conflict_check: %q (must be warn, reject or off)", c.Timer.ConflictCheck)
+	}
+	if c.Timer.EPGWatch < 0 {
+		return fmt.Errorf("invalid timer.epg_watch: %s (must not be negative)", c.Timer.EPGWatch)
+	}
+	if c.Timer.EPGWatch > 0 && c.Timer.EPGWatch < time.Minute {
+		return fmt.Errorf("invalid timer.epg_watch: %s (must be 0 or at least 1m)", c.Timer.EPGWatch)
+	}
+
 	switch c.UI.Theme {
 	case "", "system", "light", "dark":
 		// Built-in themes: ok
@@ -336,6 +713,11 @@ func (c *Config) Validate() error {
 	default:
 		return fmt.Errorf("invalid ui.login_page: %q", c.UI.LoginPage)
 	}
+	c.UI.LogoDir = strings.TrimSpace(c.UI.LogoDir)
+	c.UI.LogoMap = strings.TrimSpace(c.UI.LogoMap)
+	if c.UI.LogoMap != "" && c.UI.LogoDir == "" {
+		return fmt.Errorf("ui.logo_map requires ui.logo_dir")
+	}
 
 	// Normalize/validate saved EPG searches.
 	maxID := 0
@@ -414,9 +796,123 @@ func (c *Config) Validate() error {
 	}
 	// Allow empty ffmpeg args; execution layer may still add required flags.
 
+	// AutoTimer
+	c.AutoTimer.File = strings.TrimSpace(c.AutoTimer.File)
+	if c.AutoTimer.File == "" {
+		c.AutoTimer.File = "autotimers.yaml"
+	}
+	if c.AutoTimer.Interval < 0 {
+		return fmt.Errorf("invalid autotimer.interval: %s (must not be negative)", c.AutoTimer.Interval)
+	}
+	if c.AutoTimer.Interval > 0 && c.AutoTimer.Interval < time.Minute {
+		return fmt.Errorf("invalid autotimer.interval: %s (must be 0 or at least 1m)", c.AutoTimer.Interval)
+	}
+
+	// Dedup
+	c.Dedup.File = strings.TrimSpace(c.Dedup.File)
+	if c.Dedup.File == "" {
+		c.Dedup.File = "done.yaml"
+	}
+	c.Dedup.Match = strings.ToLower(strings.TrimSpace(c.Dedup.Match))
+	switch c.Dedup.Match {
+	case "", "normalised":
+		c.Dedup.Match = "normalized"
+	case "exact", "normalized", "fuzzy":
+	default:
+		return fmt.Errorf("invalid dedup.match: %q (must be exact, normalized or fuzzy)", c.Dedup.Match)
+	}
+
+	// History
+	c.History.File = strings.TrimSpace(c.History.File)
+	if c.History.File == "" {
+		c.History.File = "history.yaml"
+	}
+	if c.History.KeepDays < 0 {
+		return fmt.Errorf("invalid history.keep_days: %d (must not be negative)", c.History.KeepDays)
+	}
+
+	// Events
+	if c.Events.PollInterval < 0 {
+		return fmt.Errorf("invalid events.poll_interval: %s (must not be negative)", c.Events.PollInterval)
+	}
+	if c.Events.PollInterval > 0 && c.Events.PollInterval < time.Second {
+		return fmt.Errorf("invalid events.poll_interval: %s (must be 0 or at least 1s)", c.Events.PollInterval)
+	}
+
+	// Tracing
+	if c.Tracing.Enabled {
+		switch c.Tracing.Exporter {
+		case "otlp":
+			if strings.TrimSpace(c.Tracing.Endpoint) == "" {
+				return fmt.Errorf("tracing.endpoint is required for the otlp exporter")
+			}
+		case "stdout":
+		default:
+			return fmt.Errorf("invalid tracing.exporter: %q (must be otlp or stdout)", c.Tracing.Exporter)
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("invalid tracing.sample_ratio: %g (must be between 0 and 1)", c.Tracing.SampleRatio)
+	}
+
+	// SVDRP proxy
+	if c.SVDRPProxy.Enabled {
+		if _, _, err := net.SplitHostPort(c.SVDRPProxy.Listen); err != nil {
+			return fmt.Errorf("invalid svdrp_proxy.listen: %q (must be host:port)", c.SVDRPProxy.Listen)
+		}
+	}
+	if c.SVDRPProxy.Timeout < 0 {
+		return fmt.Errorf("invalid svdrp_proxy.timeout: %s (must not be negative)", c.SVDRPProxy.Timeout)
+	}
+	for i := range c.SVDRPProxy.Allow {
+		a := &c.SVDRPProxy.Allow[i]
+		a.Address = strings.TrimSpace(a.Address)
+		if _, err := ParseAddressOrPrefix(a.Address); err != nil {
+			return fmt.Errorf("invalid svdrp_proxy.allow[%d].address: %q (must be an IP address or CIDR network)", i, a.Address)
+		}
+		for j, cmd := range a.Commands {
+			cmd = strings.ToUpper(strings.TrimSpace(cmd))
+			if !svdrpVerbRe.MatchString(cmd) {
+				return fmt.Errorf("invalid svdrp_proxy.allow[%d].commands[%d]: %q (must be an SVDRP command like LSTT)", i, j, a.Commands[j])
+			}
+			a.Commands[j] = cmd
+		}
+	}
+
 	return nil
 }
 
+// cleanChannelIDs trims channel IDs and drops empty and repeated ones, keeping
+// the order.
+func cleanChannelIDs(ids []string) []string {
+	seen := map[string]struct{}{}
+	clean := make([]string, 0, len(ids))
+	for _, raw := range ids {
+		v := strings.TrimSpace(raw)
+		if v == "" {
+			continue
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		clean = append(clean, v)
+	}
+	return clean
+}
+
+// Favourite returns the favourites list with the given name, compared
+// case-insensitively.
+func (c VDRConfig) Favourite(name string) (FavouritesList, bool) {
+	name = strings.TrimSpace(name)
+	for _, f := range c.Favourites {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return FavouritesList{}, false
+}
+
 // Save saves the configuration to a YAML file
 func (c *Config) Save(path string) error {
 	data, err := yaml.Marshal(c)
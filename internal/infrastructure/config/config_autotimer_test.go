@@ -0,0 +1,37 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate_AutoTimer(t *testing.T) {
+	cfg := minimalConfig()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected defaults to be valid: %v", err)
+	}
+	if cfg.AutoTimer.File != "autotimers.yaml" {
+		t.Fatalf("expected default autotimer file, got %q", cfg.AutoTimer.File)
+	}
+
+	cfg.AutoTimer.Interval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected interval 0 to be valid: %v", err)
+	}
+
+	cfg.AutoTimer.Interval = 15 * time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected interval 15m to be valid: %v", err)
+	}
+
+	cfg.AutoTimer.Interval = 10 * time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected interval below 1m to fail validation")
+	}
+
+	cfg.AutoTimer.Interval = -time.Minute
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected negative interval to fail validation")
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate_VDRBackends(t *testing.T) {
+	cfg := minimalConfig()
+	cfg.VDR.Timeout = 5 * time.Second
+
+	cfg.VDR.Backends = []VDRBackendConfig{{Name: " bedroom ", Host: "bedroom.local"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected backend to be valid: %v", err)
+	}
+	if cfg.VDR.Name != "main" {
+		t.Fatalf("expected default primary name, got %q", cfg.VDR.Name)
+	}
+	b := cfg.VDR.Backends[0]
+	if b.Name != "bedroom" || b.Port != 6419 || b.DVBCards != 1 || b.Timeout != 5*time.Second {
+		t.Fatalf("expected backend defaults, got %+v", b)
+	}
+
+	cfg.VDR.Backends[0].Name = "main"
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected backend named like the primary VDR to be rejected")
+	}
+	cfg.VDR.Backends[0].Name = "bedroom"
+	cfg.VDR.Backends[0].Host = ""
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected backend without host to be rejected")
+	}
+	cfg.VDR.Backends[0].Host = "bedroom.local"
+	cfg.VDR.Backends[0].DVBCards = 100
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected too many dvb cards to be rejected")
+	}
+}
@@ -0,0 +1,32 @@
+package config
+
+import "testing"
+
+func TestConfigValidate_Dedup(t *testing.T) {
+	cfg := minimalConfig()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected defaults to be valid: %v", err)
+	}
+	if cfg.Dedup.File != "done.yaml" || cfg.Dedup.Match != "normalized" {
+		t.Fatalf("expected dedup defaults, got %+v", cfg.Dedup)
+	}
+
+	cfg.Dedup.Match = " Normalised "
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected British spelling to be valid: %v", err)
+	}
+	if cfg.Dedup.Match != "normalized" {
+		t.Fatalf("expected normalized value, got %q", cfg.Dedup.Match)
+	}
+
+	cfg.Dedup.Match = "FUZZY"
+	if err := cfg.Validate(); err != nil || cfg.Dedup.Match != "fuzzy" {
+		t.Fatalf("expected fuzzy to be valid, got %q: %v", cfg.Dedup.Match, err)
+	}
+
+	cfg.Dedup.Match = "similar"
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected unknown match rule to fail validation")
+	}
+}
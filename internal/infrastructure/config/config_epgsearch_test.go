@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEPGSearch_Filters(t *testing.T) {
+	s := EPGSearch{Pattern: "x", StartFrom: " 22:00 ", Weekdays: []string{"SUN", "mon", " sat", "mon"}, Exclude: " Wiederholung ", Folder: " /Krimi/Tatort/ "}
+	NormalizeEPGSearch(&s)
+	if got := strings.Join(s.Weekdays, ","); got != "mon,sat,sun" {
+		t.Fatalf("weekdays = %q", got)
+	}
+	if s.StartFrom != "22:00" || s.Exclude != "Wiederholung" {
+		t.Fatalf("expected trimmed filters, got %q and %q", s.StartFrom, s.Exclude)
+	}
+	if s.Folder != "Krimi~Tatort" {
+		t.Fatalf("folder = %q, want VDR's ~ separators", s.Folder)
+	}
+	if s.Dedup != "timers" {
+		t.Fatalf("dedup = %q, want timers by default", s.Dedup)
+	}
+	if err := ValidateEPGSearch(s); err != nil {
+		t.Fatalf("expected valid search: %v", err)
+	}
+}
+
+func TestValidateEPGSearch_Filters(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		edit func(*EPGSearch)
+		want string
+	}{
+		{"start time", func(s *EPGSearch) { s.StartTo = "25:00" }, "invalid start_to"},
+		{"negative duration", func(s *EPGSearch) { s.MinDuration = -1 }, "durations must not be negative"},
+		{"min above max", func(s *EPGSearch) { s.MinDuration, s.MaxDuration = 90, 30 }, "exceeds max_duration"},
+		{"weekday", func(s *EPGSearch) { s.Weekdays = []string{"mon", "funday"} }, `invalid weekday: "funday"`},
+		{"horizon", func(s *EPGSearch) { s.HorizonDays = -3 }, "invalid horizon_days"},
+		{"exclude regex", func(s *EPGSearch) { s.Mode, s.Exclude = "regex", "(" }, "invalid exclude"},
+		{"priority", func(s *EPGSearch) { s.Priority = 100 }, "priority must be between 0 and 99"},
+		{"lifetime", func(s *EPGSearch) { s.Lifetime = -1 }, "lifetime must be between 0 and 99"},
+		{"margin", func(s *EPGSearch) { s.MarginEnd = -5 }, "margins must not be negative"},
+		{"dedup", func(s *EPGSearch) { s.Dedup = "always" }, `invalid dedup: "always"`},
+	} {
+		s := EPGSearch{Pattern: "x"}
+		NormalizeEPGSearch(&s)
+		tc.edit(&s)
+		err := ValidateEPGSearch(s)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+
+	// A phrase exclusion is plain text.
+	s := EPGSearch{Pattern: "x", Exclude: "("}
+	NormalizeEPGSearch(&s)
+	if err := ValidateEPGSearch(s); err != nil {
+		t.Fatalf("expected a phrase exclusion to be valid: %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate_EventsPollInterval(t *testing.T) {
+	cfg := minimalConfig()
+
+	for _, valid := range []time.Duration{0, time.Second, 10 * time.Second} {
+		cfg.Events.PollInterval = valid
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected poll interval %s to be valid: %v", valid, err)
+		}
+	}
+	for _, invalid := range []time.Duration{-time.Second, 500 * time.Millisecond} {
+		cfg.Events.PollInterval = invalid
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("expected poll interval %s to be rejected", invalid)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+func TestConfigValidate_Favourites(t *testing.T) {
+	cfg := &Config{}
+	cfg.Server.Port = 8080
+	cfg.VDR.Host = "localhost"
+	cfg.VDR.Port = 6419
+	cfg.VDR.DVBCards = 1
+	cfg.UI.Theme = "system"
+	cfg.VDR.Favourites = []FavouritesList{
+		{Name: " Kids ", Channels: []string{" S19.2E-1-1101-28006", "", "S19.2E-1-1101-28006", "S19.2E-1-1079-28007"}},
+		{Name: "Sports"},
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected favourites to be valid: %v", err)
+	}
+	kids := cfg.VDR.Favourites[0]
+	if kids.Name != "Kids" {
+		t.Fatalf("expected trimmed name, got %q", kids.Name)
+	}
+	if len(kids.Channels) != 2 || kids.Channels[0] != "S19.2E-1-1101-28006" || kids.Channels[1] != "S19.2E-1-1079-28007" {
+		t.Fatalf("expected cleaned channels in order, got %q", kids.Channels)
+	}
+	if f, ok := cfg.VDR.Favourite("kids"); !ok || f.Name != "Kids" {
+		t.Fatalf("expected case-insensitive lookup, got %+v, %v", f, ok)
+	}
+	if _, ok := cfg.VDR.Favourite("News"); ok {
+		t.Fatalf("expected unknown list not to be found")
+	}
+
+	cfg.VDR.Favourites = append(cfg.VDR.Favourites, FavouritesList{Name: "SPORTS"})
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected duplicate list names to fail validation")
+	}
+
+	cfg.VDR.Favourites = []FavouritesList{{Name: "  "}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected a list without name to fail validation")
+	}
+}
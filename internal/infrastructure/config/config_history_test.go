@@ -0,0 +1,23 @@
+package config
+
+import "testing"
+
+func TestConfigValidate_History(t *testing.T) {
+	cfg := minimalConfig()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected defaults to be valid: %v", err)
+	}
+	if cfg.History.File != "history.yaml" {
+		t.Fatalf("expected default history file, got %q", cfg.History.File)
+	}
+
+	cfg.History.KeepDays = 30
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected 30 days to be valid: %v", err)
+	}
+	cfg.History.KeepDays = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected negative keep_days to fail validation")
+	}
+}
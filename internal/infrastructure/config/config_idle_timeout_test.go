@@ -0,0 +1,21 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate_VDRIdleTimeout(t *testing.T) {
+	cfg := minimalConfig()
+
+	for _, valid := range []time.Duration{0, 10 * time.Second} {
+		cfg.VDR.IdleTimeout = valid
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("expected idle timeout %s to be valid: %v", valid, err)
+		}
+	}
+	cfg.VDR.IdleTimeout = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected negative idle timeout to be rejected")
+	}
+}
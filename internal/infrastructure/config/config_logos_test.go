@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestConfigValidate_Logos(t *testing.T) {
+	cfg := &Config{}
+	cfg.Server.Port = 8080
+	cfg.VDR.Host = "localhost"
+	cfg.VDR.Port = 6419
+	cfg.VDR.DVBCards = 1
+	cfg.UI.Theme = "system"
+
+	cfg.UI.LogoDir = "  /usr/share/vdr/logos "
+	cfg.UI.LogoMap = " /etc/vdradmin/logos.yaml"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected logo settings to be valid: %v", err)
+	}
+	if cfg.UI.LogoDir != "/usr/share/vdr/logos" || cfg.UI.LogoMap != "/etc/vdradmin/logos.yaml" {
+		t.Fatalf("expected trimmed logo settings, got %q and %q", cfg.UI.LogoDir, cfg.UI.LogoMap)
+	}
+
+	cfg.UI.LogoDir = ""
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected logo_map without logo_dir to fail validation")
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import "testing"
+
+func TestConfigValidate_SVDRPProxy(t *testing.T) {
+	cfg := minimalConfig()
+
+	cfg.SVDRPProxy.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected missing listen address to be rejected")
+	}
+	cfg.SVDRPProxy.Listen = "127.0.0.1:6420"
+	cfg.SVDRPProxy.Allow = []SVDRPProxyClient{
+		{Address: " 192.168.1.0/24 ", Commands: []string{"lstt", " LSTE"}},
+		{Address: "::1"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected proxy config to be valid: %v", err)
+	}
+	if a := cfg.SVDRPProxy.Allow[0]; a.Address != "192.168.1.0/24" || a.Commands[0] != "LSTT" || a.Commands[1] != "LSTE" {
+		t.Fatalf("expected normalized allow entry, got %+v", a)
+	}
+
+	cfg.SVDRPProxy.Allow[1].Address = "vdr.local"
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected host name to be rejected")
+	}
+	cfg.SVDRPProxy.Allow[1].Address = "::1"
+	cfg.SVDRPProxy.Allow[0].Commands = []string{"LIST TIMERS"}
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected invalid command to be rejected")
+	}
+}
@@ -0,0 +1,12 @@
+package config
+
+// minimalConfig returns a configuration with just the settings Validate requires.
+func minimalConfig() *Config {
+	cfg := &Config{}
+	cfg.Server.Port = 8080
+	cfg.VDR.Host = "localhost"
+	cfg.VDR.Port = 6419
+	cfg.VDR.DVBCards = 1
+	cfg.UI.Theme = "system"
+	return cfg
+}
@@ -0,0 +1,27 @@
+package config
+
+import "testing"
+
+func TestConfigValidate_TimerConflictCheck(t *testing.T) {
+	cfg := minimalConfig()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected defaults to be valid: %v", err)
+	}
+	if cfg.Timer.ConflictCheck != "warn" {
+		t.Fatalf("expected default conflict check warn, got %q", cfg.Timer.ConflictCheck)
+	}
+
+	cfg.Timer.ConflictCheck = " Reject "
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected reject to be valid: %v", err)
+	}
+	if cfg.Timer.ConflictCheck != "reject" {
+		t.Fatalf("expected normalized value, got %q", cfg.Timer.ConflictCheck)
+	}
+
+	cfg.Timer.ConflictCheck = "maybe"
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected unknown conflict check to fail validation")
+	}
+}
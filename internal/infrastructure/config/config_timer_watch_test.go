@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate_TimerEPGWatch(t *testing.T) {
+	cfg := minimalConfig()
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected 0 (only after EPG refresh) to be valid: %v", err)
+	}
+	cfg.Timer.EPGWatch = 15 * time.Minute
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected 15m to be valid: %v", err)
+	}
+	cfg.Timer.EPGWatch = 30 * time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected 30s to fail validation")
+	}
+	cfg.Timer.EPGWatch = -time.Minute
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected negative interval to fail validation")
+	}
+}
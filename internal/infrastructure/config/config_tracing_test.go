@@ -0,0 +1,31 @@
+package config
+
+import "testing"
+
+func TestConfigValidate_Tracing(t *testing.T) {
+	cfg := minimalConfig()
+
+	// Disabled tracing ignores the exporter settings.
+	cfg.Tracing.Exporter = "jaeger"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected disabled tracing to be valid: %v", err)
+	}
+
+	cfg.Tracing.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected unknown exporter to be rejected")
+	}
+	cfg.Tracing.Exporter = "otlp"
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected otlp without endpoint to be rejected")
+	}
+	cfg.Tracing.Endpoint = "localhost:4318"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected otlp with endpoint to be valid: %v", err)
+	}
+	cfg.Tracing.Exporter = "stdout"
+	cfg.Tracing.SampleRatio = 1.5
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected sample ratio above 1 to be rejected")
+	}
+}
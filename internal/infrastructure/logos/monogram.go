@@ -0,0 +1,52 @@
+package logos
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// monogramColors are the backgrounds of generated monograms; white text is
+// readable on all of them.
+var monogramColors = []string{
+	"#1f6feb", "#8250df", "#bf3989", "#cf222e", "#bc4c00",
+	"#9a6700", "#1a7f37", "#0e7490", "#57606a", "#6639ba",
+}
+
+// Monogram returns up to two initials of a channel name: the first letters of
+// its first two words, or its first two characters for single words.
+// Trailing "HD" and "SD" markers are ignored.
+func Monogram(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if n := len(words); n > 1 && (strings.EqualFold(words[n-1], "hd") || strings.EqualFold(words[n-1], "sd")) {
+		words = words[:n-1]
+	}
+	switch len(words) {
+	case 0:
+		return "?"
+	case 1:
+		r := []rune(words[0])
+		if len(r) > 2 {
+			r = r[:2]
+		}
+		return strings.ToUpper(string(r))
+	default:
+		return strings.ToUpper(string([]rune(words[0])[:1]) + string([]rune(words[1])[:1]))
+	}
+}
+
+// MonogramSVG renders the monogram of a channel name as an SVG image. The
+// color is derived from the name, so a channel keeps its color across pages.
+func MonogramSVG(name string) []byte {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	color := monogramColors[h.Sum32()%uint32(len(monogramColors))]
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 40" role="img" aria-label="%s">`+
+		`<rect width="64" height="40" rx="6" fill="%s"/>`+
+		`<text x="32" y="27" text-anchor="middle" font-family="sans-serif" font-size="18" font-weight="bold" fill="#fff">%s</text>`+
+		`</svg>`, html.EscapeString(name), color, html.EscapeString(Monogram(name))))
+}
@@ -0,0 +1,198 @@
+// Package logos maps VDR channels to logo files in a directory, the way VDR
+// skins like skinflatplus or skinnopacity find their channel logos.
+package logos
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules a logo can be found by, in the order they are tried.
+const (
+	RuleMapping    = "mapping"
+	RuleChannelID  = "channel ID"
+	RuleName       = "name"
+	RuleNormalized = "normalized name"
+)
+
+// extensions lists the supported logo formats, preferred first.
+var extensions = []string{".png", ".svg", ".jpg", ".jpeg", ".webp"}
+
+// Match is the logo file found for a channel.
+type Match struct {
+	// File is the logo's path relative to the logo directory.
+	File string
+	// Rule tells how the file was found, e.g. RuleName.
+	Rule string
+}
+
+// Store finds channel logos in a directory. A channel's logo is named after
+// its channel ID or its name, lowercased with "/" replaced by "~" as VDR skins
+// expect; files named after the normalized name ("daserstehd.png") match too.
+// An optional YAML mapping file assigns logo files to channel names or IDs
+// that follow no convention.
+type Store struct {
+	mu        sync.RWMutex
+	dir       string
+	mapFile   string
+	byName    map[string]string
+	byNorm    map[string]string
+	overrides map[string]string
+}
+
+// NewStore creates a store for the logos in dir. mapFile is optional.
+func NewStore(dir, mapFile string) *Store {
+	return &Store{dir: dir, mapFile: mapFile}
+}
+
+// Enabled reports whether a logo directory is configured.
+func (s *Store) Enabled() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dir != ""
+}
+
+// SetPaths changes the logo directory and mapping file; Load picks them up.
+func (s *Store) SetPaths(dir, mapFile string) {
+	s.mu.Lock()
+	s.dir, s.mapFile = dir, mapFile
+	s.mu.Unlock()
+}
+
+// Load scans the logo directory and reads the mapping file. Logos added later
+// are found after the next Load.
+func (s *Store) Load() error {
+	s.mu.RLock()
+	dir, mapFile := s.dir, s.mapFile
+	s.mu.RUnlock()
+
+	byName := map[string]string{}
+	byNorm := map[string]string{}
+	overrides := map[string]string{}
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read logo directory: %w", err)
+		}
+		for _, e := range entries {
+			ext := strings.ToLower(filepath.Ext(e.Name()))
+			if e.IsDir() || extensionRank(ext) < 0 {
+				continue
+			}
+			base := strings.ToLower(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())))
+			addPreferred(byName, base, e.Name())
+			if norm := Normalize(base); norm != "" {
+				addPreferred(byNorm, norm, e.Name())
+			}
+		}
+	}
+	if mapFile != "" {
+		data, err := os.ReadFile(mapFile)
+		if err != nil {
+			return fmt.Errorf("failed to read logo mapping: %w", err)
+		}
+		var m map[string]string
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return fmt.Errorf("failed to parse logo mapping: %w", err)
+		}
+		for k, v := range m {
+			v = filepath.ToSlash(strings.TrimSpace(v))
+			if !filepath.IsLocal(v) {
+				return fmt.Errorf("logo mapping %q: %q is not inside the logo directory", k, v)
+			}
+			overrides[strings.ToLower(strings.TrimSpace(k))] = v
+		}
+	}
+
+	s.mu.Lock()
+	s.byName, s.byNorm, s.overrides = byName, byNorm, overrides
+	s.mu.Unlock()
+	return nil
+}
+
+// Lookup returns the logo of the channel with the given ID and name.
+func (s *Store) Lookup(channelID, name string) (Match, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.dir == "" {
+		return Match{}, false
+	}
+	for _, key := range []string{channelID, name} {
+		if f, ok := s.overrides[strings.ToLower(strings.TrimSpace(key))]; ok && key != "" {
+			return Match{File: f, Rule: RuleMapping}, true
+		}
+	}
+	if f, ok := s.byName[strings.ToLower(channelID)]; ok && channelID != "" {
+		return Match{File: f, Rule: RuleChannelID}, true
+	}
+	if f, ok := s.byName[SkinName(name)]; ok && name != "" {
+		return Match{File: f, Rule: RuleName}, true
+	}
+	if norm := Normalize(name); norm != "" {
+		if f, ok := s.byNorm[norm]; ok {
+			return Match{File: f, Rule: RuleNormalized}, true
+		}
+	}
+	return Match{}, false
+}
+
+// Path returns the file system path of a logo file found by Lookup.
+func (s *Store) Path(file string) (string, error) {
+	s.mu.RLock()
+	dir := s.dir
+	s.mu.RUnlock()
+	if dir == "" || !filepath.IsLocal(file) {
+		return "", fmt.Errorf("invalid logo file %q", file)
+	}
+	return filepath.Join(dir, filepath.FromSlash(file)), nil
+}
+
+// SkinName returns the file name (without extension) VDR skins look up for a
+// channel name: lowercased, with "/" replaced by "~".
+func SkinName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "/", "~"))
+}
+
+// transliterations spell out characters that differ between logo packs.
+var transliterations = strings.NewReplacer(
+	"&", "and", "+", "plus", "*", "star",
+	"ä", "a", "ö", "o", "ü", "u", "ß", "ss",
+	"à", "a", "á", "a", "â", "a", "é", "e", "è", "e", "ê", "e",
+	"í", "i", "ì", "i", "ó", "o", "ò", "o", "ô", "o", "ú", "u", "ù", "u", "ç", "c", "ñ", "n",
+)
+
+// Normalize returns the normalized form of a channel name used by picon-style
+// logo packs: lowercase letters and digits only ("Das Erste HD" becomes
+// "daserstehd", "RTL+" becomes "rtlplus").
+func Normalize(name string) string {
+	name = transliterations.Replace(strings.ToLower(name))
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func extensionRank(ext string) int {
+	for i, e := range extensions {
+		if e == ext {
+			return i
+		}
+	}
+	return -1
+}
+
+// addPreferred stores file under key unless a file in a preferred format is there.
+func addPreferred(m map[string]string, key, file string) {
+	if prev, ok := m[key]; ok && extensionRank(strings.ToLower(filepath.Ext(prev))) <= extensionRank(strings.ToLower(filepath.Ext(file))) {
+		return
+	}
+	m[key] = file
+}
@@ -0,0 +1,132 @@
+package logos
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeLogos(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("logo"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStore_Lookup(t *testing.T) {
+	dir := t.TempDir()
+	writeLogos(t, dir,
+		"das erste hd.png", "das erste hd.svg", // png is preferred
+		"s19.2e-1-1019-10301.svg",
+		"n-tv.png",
+		"prosieben maxx.jpg",
+		"rtlplus.png",
+		"ac~dc.png",
+		"kika.webp",
+		"readme.txt",
+		"custom.png",
+	)
+	mapFile := filepath.Join(t.TempDir(), "logos.yaml")
+	if err := os.WriteFile(mapFile, []byte("\"Super RTL\": custom.png\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewStore(dir, mapFile)
+	if err := s.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		id, name  string
+		file      string
+		rule      string
+		wantFound bool
+	}{
+		{"S19.2E-1-1019-10301", "3sat", "s19.2e-1-1019-10301.svg", RuleChannelID, true},
+		{"C-1", "Das Erste HD", "das erste hd.png", RuleName, true},
+		{"C-2", "N-TV", "n-tv.png", RuleName, true},
+		{"C-3", "AC/DC", "ac~dc.png", RuleName, true},
+		{"C-4", "RTL+", "rtlplus.png", RuleNormalized, true},
+		{"C-5", "ProSieben MAXX", "prosieben maxx.jpg", RuleName, true},
+		{"C-6", "KiKA", "kika.webp", RuleName, true},
+		{"C-7", "super rtl", "custom.png", RuleMapping, true},
+		{"C-8", "Readme", "", "", false},
+		{"C-9", "Unknown", "", "", false},
+	}
+	for _, tt := range tests {
+		m, ok := s.Lookup(tt.id, tt.name)
+		if ok != tt.wantFound || m.File != tt.file || m.Rule != tt.rule {
+			t.Errorf("Lookup(%q, %q) = %+v, %v; want %q by %q", tt.id, tt.name, m, ok, tt.file, tt.rule)
+		}
+	}
+
+	p, err := s.Path("n-tv.png")
+	if err != nil || p != filepath.Join(dir, "n-tv.png") {
+		t.Fatalf("Path = %q, %v", p, err)
+	}
+	if _, err := s.Path("../etc/passwd"); err == nil {
+		t.Fatal("expected Path to reject files outside the directory")
+	}
+}
+
+func TestStore_LoadRejectsMappingOutsideDirectory(t *testing.T) {
+	mapFile := filepath.Join(t.TempDir(), "logos.yaml")
+	if err := os.WriteFile(mapFile, []byte("Das Erste: ../secret.png\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := NewStore(t.TempDir(), mapFile).Load()
+	if err == nil || !strings.Contains(err.Error(), "not inside the logo directory") {
+		t.Fatalf("Load error = %v", err)
+	}
+}
+
+func TestStore_Disabled(t *testing.T) {
+	s := NewStore("", "")
+	if err := s.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.Enabled() {
+		t.Fatal("store without a directory should be disabled")
+	}
+	if _, ok := s.Lookup("C-1", "Das Erste"); ok {
+		t.Fatal("disabled store found a logo")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Das Erste HD":    "daserstehd",
+		"RTL+":            "rtlplus",
+		"Sky Cinema *":    "skycinemastar",
+		"Bibel TV & Co":   "bibeltvandco",
+		"ZDFinfo":         "zdfinfo",
+		"Welt der Wunder": "weltderwunder",
+		"Münchenтв":       "munchen",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMonogram(t *testing.T) {
+	tests := map[string]string{
+		"Das Erste HD": "DE",
+		"arte":         "AR",
+		"3sat":         "3S",
+		"N-TV":         "NT",
+		"":             "?",
+	}
+	for in, want := range tests {
+		if got := Monogram(in); got != want {
+			t.Errorf("Monogram(%q) = %q, want %q", in, got, want)
+		}
+	}
+	svg := string(MonogramSVG("Tom & Jerry"))
+	if !strings.Contains(svg, ">TJ</text>") || !strings.Contains(svg, `aria-label="Tom &amp; Jerry"`) {
+		t.Fatalf("svg = %s", svg)
+	}
+}
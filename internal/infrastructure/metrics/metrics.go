@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// Metrics holds the instrumented metrics of vdradmin-go. Scrape-time gauges
+// (caches, streams, jobs, timers, disk) are registered on Registry by their owners.
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	Registry *Registry
+
+	httpRequests *CounterVec
+	httpDuration *HistogramVec
+
+	svdrpCommands   *CounterVec
+	svdrpErrors     *CounterVec
+	svdrpDuration   *HistogramVec
+	svdrpReconnects *CounterVec
+}
+
+// New creates the metrics on a new registry.
+func New() *Metrics {
+	r := NewRegistry()
+	return &Metrics{
+		Registry:        r,
+		httpRequests:    r.NewCounterVec("vdradmin_http_requests_total", "HTTP requests by route, method and status code.", "route", "method", "code"),
+		httpDuration:    r.NewHistogramVec("vdradmin_http_request_duration_seconds", "HTTP request latency by route and method.", DefaultBuckets, "route", "method"),
+		svdrpCommands:   r.NewCounterVec("vdradmin_svdrp_commands_total", "SVDRP commands sent to VDR by verb.", "verb"),
+		svdrpErrors:     r.NewCounterVec("vdradmin_svdrp_command_errors_total", "SVDRP commands that failed or were answered with an error reply, by verb.", "verb"),
+		svdrpDuration:   r.NewHistogramVec("vdradmin_svdrp_command_duration_seconds", "SVDRP command latency by verb.", DefaultBuckets, "verb"),
+		svdrpReconnects: r.NewCounterVec("vdradmin_svdrp_reconnects_total", "SVDRP reconnects after a broken connection, by the verb that failed.", "verb"),
+	}
+}
+
+// ObserveHTTPRequest records a served HTTP request. route is the matched
+// ServeMux pattern without the method, e.g. "/api/v1/timers/{id}".
+func (m *Metrics) ObserveHTTPRequest(route, method string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpRequests.Inc(route, method, strconv.Itoa(status))
+	m.httpDuration.Observe(d.Seconds(), route, method)
+}
+
+// ObserveCommand records an SVDRP command round trip.
+func (m *Metrics) ObserveCommand(verb string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.svdrpCommands.Inc(verb)
+	m.svdrpDuration.Observe(d.Seconds(), verb)
+	if err != nil {
+		m.svdrpErrors.Inc(verb)
+	}
+}
+
+// ObserveReconnect records a reconnect forced by a broken SVDRP connection.
+func (m *Metrics) ObserveReconnect(verb string) {
+	if m == nil {
+		return
+	}
+	m.svdrpReconnects.Inc(verb)
+}
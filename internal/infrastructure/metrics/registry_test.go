@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if err := r.WriteText(context.Background(), &b); err != nil {
+		t.Fatalf("WriteText: %v", err)
+	}
+	return b.String()
+}
+
+func TestRegistry_WriteText(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_requests_total", "Requests.\nSecond line", "path")
+	c.Inc("/a")
+	c.Add(2, `/b"\`)
+	h := r.NewHistogramVec("test_duration_seconds", "Latency.", []float64{1, 0.1})
+	h.Observe(0.05)
+	h.Observe(0.5)
+	h.Observe(3)
+	r.NewGaugeFunc("test_jobs", "Jobs by status.", []string{"status"}, func(ctx context.Context, emit func(float64, ...string)) {
+		emit(2, "running")
+		emit(0, "failed")
+	})
+
+	want := `# HELP test_duration_seconds Latency.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.1"} 1
+test_duration_seconds_bucket{le="1"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 3.55
+test_duration_seconds_count 3
+# HELP test_jobs Jobs by status.
+# TYPE test_jobs gauge
+test_jobs{status="failed"} 0
+test_jobs{status="running"} 2
+# HELP test_requests_total Requests.\nSecond line
+# TYPE test_requests_total counter
+test_requests_total{path="/a"} 1
+test_requests_total{path="/b\"\\"} 2
+`
+	if got := scrape(t, r); got != want {
+		t.Fatalf("unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_DuplicateNamePanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("dup_total", "x")
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for duplicate registration")
+		}
+	}()
+	r.NewGaugeFunc("dup_total", "x", nil, func(context.Context, func(float64, ...string)) {})
+}
+
+func TestMetrics_Observe(t *testing.T) {
+	m := New()
+	m.ObserveHTTPRequest("/timers", "GET", 200, 20*time.Millisecond)
+	m.ObserveCommand("LSTT", 5*time.Millisecond, nil)
+	m.ObserveCommand("LSTT", 5*time.Millisecond, errors.New("broken pipe"))
+	m.ObserveReconnect("LSTT")
+
+	if got := m.svdrpCommands.Value("LSTT"); got != 2 {
+		t.Fatalf("expected 2 commands, got %v", got)
+	}
+	if got := m.svdrpErrors.Value("LSTT"); got != 1 {
+		t.Fatalf("expected 1 error, got %v", got)
+	}
+	if got := m.svdrpDuration.Count("LSTT"); got != 2 {
+		t.Fatalf("expected 2 observations, got %d", got)
+	}
+
+	out := scrape(t, m.Registry)
+	for _, s := range []string{
+		`vdradmin_http_requests_total{route="/timers",method="GET",code="200"} 1`,
+		`vdradmin_http_request_duration_seconds_bucket{route="/timers",method="GET",le="0.025"} 1`,
+		`vdradmin_svdrp_reconnects_total{verb="LSTT"} 1`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Fatalf("expected %q in:\n%s", s, out)
+		}
+	}
+
+	var nilMetrics *Metrics
+	nilMetrics.ObserveCommand("LSTT", time.Millisecond, nil)
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/githubixx/vdradmin-go/internal/infrastructure/config"
+)
+
+func TestSetup_DisabledIsNoop(t *testing.T) {
+	shutdown, err := Setup(context.Background(), config.TracingConfig{}, "test", nil)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestSetup_StdoutExporter(t *testing.T) {
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var out bytes.Buffer
+	cfg := config.TracingConfig{Enabled: true, Exporter: "stdout", SampleRatio: 1}
+	shutdown, err := Setup(context.Background(), cfg, "1.2.3", &out)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test-span")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	for _, s := range []string{`"Name": "test-span"`, `"Value": "vdradmin-go"`, `"Value": "1.2.3"`} {
+		if !strings.Contains(out.String(), s) {
+			t.Fatalf("expected %s in exported spans:\n%s", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package ports
+
+import "github.com/githubixx/vdradmin-go/internal/domain"
+
+// AutoTimerStore defines the interface for persisting AutoTimer definitions.
+type AutoTimerStore interface {
+	// LoadAutoTimers returns all stored AutoTimers.
+	// A store that has never been written returns an empty list.
+	LoadAutoTimers() ([]domain.AutoTimer, error)
+
+	// SaveAutoTimers replaces all stored AutoTimers.
+	SaveAutoTimers(autoTimers []domain.AutoTimer) error
+}
+
+// AutoTimerIDStore is implemented by AutoTimer stores that also keep the
+// highest ID ever saved, so the IDs of deleted AutoTimers are not handed out
+// again after a restart.
+type AutoTimerIDStore interface {
+	// LastAutoTimerID returns the highest ID saved so far, 0 if none.
+	LastAutoTimerID() (int, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import "github.com/githubixx/vdradmin-go/internal/domain"
+
+// DoneStore defines the interface for persisting the done list of episodes
+// scheduled by automatic searches.
+type DoneStore interface {
+	// LoadDone returns all stored entries.
+	// A store that has never been written returns an empty list.
+	LoadDone() ([]domain.DoneEntry, error)
+
+	// SaveDone replaces all stored entries.
+	SaveDone(entries []domain.DoneEntry) error
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +31,10 @@ type MockVDRClient struct {
 	CloseFunc             func() error
 	PingFunc              func(ctx context.Context) error
 	GetChannelsFunc       func(ctx context.Context) ([]domain.Channel, error)
+	UpdateChannelFunc     func(ctx context.Context, number int, settings string) error
+	MoveChannelFunc       func(ctx context.Context, number, to int) error
+	CreateChannelFunc     func(ctx context.Context, settings string) (int, error)
+	DeleteChannelFunc     func(ctx context.Context, number int) error
 	GetEPGFunc            func(ctx context.Context, channelID string, at time.Time) ([]domain.EPGEvent, error)
 	GetTimersFunc         func(ctx context.Context) ([]domain.Timer, error)
 	CreateTimerFunc       func(ctx context.Context, timer *domain.Timer) error
@@ -38,6 +43,9 @@ type MockVDRClient struct {
 	GetRecordingsFunc     func(ctx context.Context) ([]domain.Recording, error)
 	GetRecordingDirFunc   func(ctx context.Context, recordingID string) (string, error)
 	DeleteRecordingFunc   func(ctx context.Context, path string) error
+	MoveRecordingFunc     func(ctx context.Context, path, newName string) error
+	PlayRecordingFunc     func(ctx context.Context, path string, start domain.ReplayStart) error
+	CutRecordingFunc      func(ctx context.Context, path string) error
 	GetCurrentChannelFunc func(ctx context.Context) (string, error)
 	SetCurrentChannelFunc func(ctx context.Context, channelID string) error
 	SendKeyFunc           func(ctx context.Context, key string) error
@@ -139,7 +147,8 @@ func (m *MockVDRClient) GetTimers(ctx context.Context) ([]domain.Timer, error) {
 	}
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.timers, nil
+	// Callers get their own copy, like from a real VDR, so they cannot change the mock's state.
+	return append([]domain.Timer(nil), m.timers...), nil
 }
 
 func (m *MockVDRClient) CreateTimer(ctx context.Context, timer *domain.Timer) error {
@@ -201,6 +210,101 @@ func (m *MockVDRClient) GetRecordings(ctx context.Context) ([]domain.Recording,
 	return m.recordings, nil
 }
 
+func (m *MockVDRClient) UpdateChannel(ctx context.Context, number int, settings string) error {
+	if m.UpdateChannelFunc != nil {
+		return m.UpdateChannelFunc(ctx, number, settings)
+	}
+	if number <= 0 || settings == "" {
+		return domain.ErrInvalidInput
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.channelIndexLocked(number)
+	if i < 0 {
+		return domain.ErrNotFound
+	}
+	m.channels[i].Settings = settings
+	return nil
+}
+
+func (m *MockVDRClient) MoveChannel(ctx context.Context, number, to int) error {
+	if m.MoveChannelFunc != nil {
+		return m.MoveChannelFunc(ctx, number, to)
+	}
+	if number <= 0 || to <= 0 {
+		return domain.ErrInvalidInput
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from := m.channelIndexLocked(number)
+	if from < 0 || to > len(m.channels) {
+		return domain.ErrNotFound
+	}
+	ch := m.channels[from]
+	m.channels = append(m.channels[:from], m.channels[from+1:]...)
+	m.channels = append(m.channels[:to-1], append([]domain.Channel{ch}, m.channels[to-1:]...)...)
+	m.renumberChannelsLocked()
+	return nil
+}
+
+func (m *MockVDRClient) CreateChannel(ctx context.Context, settings string) (int, error) {
+	if m.CreateChannelFunc != nil {
+		return m.CreateChannelFunc(ctx, settings)
+	}
+	if settings == "" {
+		return 0, domain.ErrInvalidInput
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.channels {
+		if ch.Settings == settings {
+			return 0, domain.ErrChannelExists
+		}
+	}
+	name, _, _ := strings.Cut(settings, ":")
+	m.channels = append(m.channels, domain.Channel{Name: name, Settings: settings})
+	m.renumberChannelsLocked()
+	return len(m.channels), nil
+}
+
+func (m *MockVDRClient) DeleteChannel(ctx context.Context, number int) error {
+	if m.DeleteChannelFunc != nil {
+		return m.DeleteChannelFunc(ctx, number)
+	}
+	if number <= 0 {
+		return domain.ErrInvalidInput
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.channelIndexLocked(number)
+	if i < 0 {
+		return domain.ErrNotFound
+	}
+	for _, t := range m.timers {
+		if t.ChannelID != "" && t.ChannelID == m.channels[i].ID {
+			return domain.ErrChannelInUse
+		}
+	}
+	m.channels = append(m.channels[:i], m.channels[i+1:]...)
+	m.renumberChannelsLocked()
+	return nil
+}
+
+func (m *MockVDRClient) channelIndexLocked(number int) int {
+	for i, ch := range m.channels {
+		if ch.Number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *MockVDRClient) renumberChannelsLocked() {
+	for i := range m.channels {
+		m.channels[i].Number = i + 1
+	}
+}
+
 func (m *MockVDRClient) GetRecordingDir(ctx context.Context, recordingID string) (string, error) {
 	if m.GetRecordingDirFunc != nil {
 		return m.GetRecordingDirFunc(ctx, recordingID)
@@ -229,6 +333,58 @@ func (m *MockVDRClient) DeleteRecording(ctx context.Context, path string) error
 	return domain.ErrNotFound
 }
 
+func (m *MockVDRClient) PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error {
+	if m.PlayRecordingFunc != nil {
+		return m.PlayRecordingFunc(ctx, path, start)
+	}
+	if path == "" || start.Offset < 0 {
+		return domain.ErrInvalidInput
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, r := range m.recordings {
+		if r.Path == path {
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (m *MockVDRClient) CutRecording(ctx context.Context, path string) error {
+	if m.CutRecordingFunc != nil {
+		return m.CutRecordingFunc(ctx, path)
+	}
+	if path == "" {
+		return domain.ErrInvalidInput
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, r := range m.recordings {
+		if r.Path == path {
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (m *MockVDRClient) MoveRecording(ctx context.Context, path, newName string) error {
+	if m.MoveRecordingFunc != nil {
+		return m.MoveRecordingFunc(ctx, path, newName)
+	}
+	if path == "" || newName == "" {
+		return domain.ErrInvalidInput
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, r := range m.recordings {
+		if r.Path == path {
+			m.recordings[i].Name = newName
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
 func (m *MockVDRClient) GetCurrentChannel(ctx context.Context) (string, error) {
 	if m.GetCurrentChannelFunc != nil {
 		return m.GetCurrentChannelFunc(ctx)
@@ -0,0 +1,14 @@
+package ports
+
+import "github.com/githubixx/vdradmin-go/internal/domain"
+
+// RecordingHistoryStore defines the interface for persisting the history of
+// timers and the recordings they left.
+type RecordingHistoryStore interface {
+	// LoadHistory returns all stored entries.
+	// A store that has never been written returns an empty list.
+	LoadHistory() ([]domain.RecordingHistoryEntry, error)
+
+	// SaveHistory replaces all stored entries.
+	SaveHistory(entries []domain.RecordingHistoryEntry) error
+}
@@ -21,6 +21,21 @@ type VDRClient interface {
 	// GetChannels retrieves all channels
 	GetChannels(ctx context.Context) ([]domain.Channel, error)
 
+	// UpdateChannel replaces the channels.conf settings of the channel with
+	// the given number (SVDRP `MODC`).
+	UpdateChannel(ctx context.Context, number int, settings string) error
+
+	// MoveChannel moves a channel to another number (SVDRP `MOVC`).
+	MoveChannel(ctx context.Context, number, to int) error
+
+	// CreateChannel appends a channel given as a channels.conf line and
+	// returns its number (SVDRP `NEWC`).
+	CreateChannel(ctx context.Context, settings string) (int, error)
+
+	// DeleteChannel deletes the channel with the given number (SVDRP `DELC`).
+	// It returns domain.ErrChannelInUse if a timer records from the channel.
+	DeleteChannel(ctx context.Context, number int) error
+
 	// GetEPG retrieves EPG data for a channel or all channels
 	GetEPG(ctx context.Context, channelID string, at time.Time) ([]domain.EPGEvent, error)
 
@@ -46,6 +61,19 @@ type VDRClient interface {
 	// DeleteRecording deletes a recording
 	DeleteRecording(ctx context.Context, path string) error
 
+	// PlayRecording starts the replay of a recording on VDR's primary output
+	// (SVDRP `PLAY`). The replay is controlled with SendKey.
+	PlayRecording(ctx context.Context, path string, start domain.ReplayStart) error
+
+	// MoveRecording gives a recording a new name (SVDRP `MOVR`). '~' in the
+	// name separates folders, so this also moves recordings between folders.
+	MoveRecording(ctx context.Context, path, newName string) error
+
+	// CutRecording starts VDR's cutter on a recording (SVDRP `EDIT`). The
+	// cutter runs in the background and keeps the parts between the
+	// recording's editing marks.
+	CutRecording(ctx context.Context, path string) error
+
 	// GetCurrentChannel returns the current channel
 	GetCurrentChannel(ctx context.Context) (string, error)
 
@@ -55,3 +83,41 @@ type VDRClient interface {
 	// SendKey sends a remote control key
 	SendKey(ctx context.Context, key string) error
 }
+
+// ResumePointReporter is implemented by VDR clients that can tell the resume
+// point VDR saved for a recording. It returns domain.ErrNotFound if there is none.
+type ResumePointReporter interface {
+	ResumePoint(ctx context.Context, path string) (time.Duration, error)
+}
+
+// ChannelGroupLister is implemented by VDR clients that can tell the
+// channels.conf group of each channel. It returns the channels with Group set.
+type ChannelGroupLister interface {
+	GetChannelGroups(ctx context.Context) ([]domain.Channel, error)
+}
+
+// ConnectionState describes the connection a VDR client currently holds.
+// VDR serves one SVDRP client at a time, so clients release idle connections
+// and report when and why they did.
+type ConnectionState struct {
+	Address        string
+	Connected      bool
+	ConnectedSince time.Time
+	LastUsed       time.Time
+	// IdleTimeout is how long an unused connection is kept open; 0 keeps it open.
+	IdleTimeout time.Duration
+
+	LastDisconnect       time.Time
+	LastDisconnectReason string
+
+	// LastBusy is when VDR last refused a connection because it was serving
+	// another client; BusyCount counts those refusals.
+	LastBusy  time.Time
+	BusyCount int
+}
+
+// ConnectionStateReporter is implemented by VDR clients that can report their
+// connection state, e.g. for the configurations page.
+type ConnectionStateReporter interface {
+	ConnectionState() ConnectionState
+}
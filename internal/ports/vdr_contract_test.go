@@ -133,6 +133,54 @@ func testChannels(t *testing.T, factory ClientFactory) {
 			}
 		}
 	})
+
+	t.Run("UpdateChannel_WithEmptySettings", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.UpdateChannel(ctx, 1, ""); err == nil {
+			t.Error("UpdateChannel with empty settings should return an error")
+		}
+	})
+
+	t.Run("MoveChannel_WithZeroNumber", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.MoveChannel(ctx, 0, 1); err == nil {
+			t.Error("MoveChannel with number 0 should return an error")
+		}
+	})
+
+	t.Run("CreateChannel_WithEmptySettings", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := client.CreateChannel(ctx, ""); err == nil {
+			t.Error("CreateChannel with empty settings should return an error")
+		}
+	})
+
+	t.Run("DeleteChannel_WithZeroNumber", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.DeleteChannel(ctx, 0); err == nil {
+			t.Error("DeleteChannel with number 0 should return an error")
+		}
+	})
 }
 
 // testEPG validates EPG retrieval behavior
@@ -403,6 +451,66 @@ func testRecordings(t *testing.T, factory ClientFactory) {
 		// Implementation should handle empty path gracefully
 		_ = err
 	})
+
+	t.Run("PlayRecording_FromBeginning", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := client.PlayRecording(ctx, "1", domain.ReplayStart{FromBeginning: true})
+		// Implementation may return error for non-existent recording, that's valid
+		_ = err
+	})
+
+	t.Run("PlayRecording_WithEmptyPath", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.PlayRecording(ctx, "", domain.ReplayStart{}); err == nil {
+			t.Error("PlayRecording with an empty path should return an error")
+		}
+	})
+
+	t.Run("MoveRecording_WithValidName", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := client.MoveRecording(ctx, "1", "Folder~Recording")
+		// Implementation may return error for non-existent recording, that's valid
+		_ = err
+	})
+
+	t.Run("MoveRecording_WithEmptyName", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.MoveRecording(ctx, "1", ""); err == nil {
+			t.Error("MoveRecording with an empty name should return an error")
+		}
+	})
+
+	t.Run("CutRecording_WithEmptyPath", func(t *testing.T) {
+		client, cleanup := factory()
+		defer cleanup()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.CutRecording(ctx, ""); err == nil {
+			t.Error("CutRecording with an empty path should return an error")
+		}
+	})
 }
 
 // testCurrentChannel validates current channel operations
@@ -0,0 +1,59 @@
+// Live page updates via the /events Server-Sent Events stream.
+//
+// An element with data-live-events="<event types, space separated>" is reloaded from
+// the current URL whenever one of the events arrives. data-live-select names the
+// element to replace (defaults to the element's id).
+(() => {
+    if (!window.EventSource) return;
+
+    const el = document.querySelector('[data-live-events]');
+    if (!el) return;
+
+    const types = String(el.getAttribute('data-live-events') || '').split(/\s+/).filter(Boolean);
+    if (types.length === 0) return;
+    const selector = el.getAttribute('data-live-select') || (el.id ? '#' + el.id : '');
+    if (!selector) return;
+
+    let pending = null;
+    let loading = false;
+
+    async function reload() {
+        pending = null;
+        if (loading) {
+            schedule();
+            return;
+        }
+        // Don't replace content while the user is typing in it.
+        const current = document.querySelector(selector);
+        if (!current || (document.activeElement && current.contains(document.activeElement) && document.activeElement.matches('input, textarea, select'))) {
+            return;
+        }
+        loading = true;
+        try {
+            const resp = await fetch(window.location.href, { credentials: 'same-origin' });
+            if (!resp.ok) return;
+            const doc = new DOMParser().parseFromString(await resp.text(), 'text/html');
+            const fresh = doc.querySelector(selector);
+            const target = document.querySelector(selector);
+            if (fresh && target) {
+                target.replaceWith(fresh);
+                if (window.htmx) window.htmx.process(fresh);
+            }
+        } catch (_) {
+            // Best-effort; the next event retries.
+        } finally {
+            loading = false;
+        }
+    }
+
+    function schedule() {
+        if (pending) return;
+        pending = setTimeout(reload, 500);
+    }
+
+    const groups = Array.from(new Set(types.map((t) => t.split('.')[0])));
+    const source = new EventSource('/events?types=' + encodeURIComponent(groups.join(',')));
+    for (const t of types) {
+        source.addEventListener(t, schedule);
+    }
+})();